@@ -24,6 +24,31 @@ import (
 // KubeTemplateSpec defines the desired state of KubeTemplate.
 type KubeTemplateSpec struct {
 	Templates []Template `json:"templates"`
+	// +optional
+	// ApplyPriority orders processing whenever templates wait in the work queue, e.g. on startup
+	// resync. Templates with a higher value are processed first, so foundational templates (e.g.
+	// shared Namespaces) can be applied before the templates that depend on them; equal priorities
+	// are processed in the order they became due.
+	// Default: 0
+	// +kubebuilder:validation:Minimum=-1000
+	// +kubebuilder:validation:Maximum=1000
+	ApplyPriority int `json:"applyPriority,omitempty"`
+	// +optional
+	// Prune deletes the resources of earlier applies whose templates were removed from the spec.
+	// Only resources that still carry this template's tracking labels or owner reference are deleted.
+	// Default: false
+	Prune bool `json:"prune,omitempty"`
+	// +optional
+	// DryRun validates the templates and applies them with server-side dry-run only, recording
+	// what the apply would do in status.dryRunResults instead of changing the cluster.
+	// The template settles in the DryRunCompleted phase.
+	// Default: false
+	DryRun bool `json:"dryRun,omitempty"`
+	// +optional
+	// Parameters are substituted for ${name} placeholders in the template objects, so that one
+	// KubeTemplate can be reused across environments. With parameters set, every placeholder must
+	// name one of them; write $${ for a literal ${.
+	Parameters map[string]string `json:"parameters,omitempty"`
 }
 
 // Template defines a template to be rendered.
@@ -37,12 +62,55 @@ type Template struct {
 	// When true, the policy will be added as an owner reference to the created resource.
 	// Default: false
 	Referenced bool `json:"referenced,omitempty"`
+	// +optional
+	// Adopt allows the template to take over an existing resource that KubeTemplater does not
+	// manage yet, when the policy sets protectUnmanagedResources.
+	// Default: false
+	Adopt bool `json:"adopt,omitempty"`
+	// +optional
+	// FieldManager overrides the server-side apply field manager the object is applied with,
+	// to share field ownership with another controller or keep it deliberately separate.
+	// Default: kubetemplater
+	// +kubebuilder:validation:MaxLength=128
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9][A-Za-z0-9._:/-]*$`
+	FieldManager string `json:"fieldManager,omitempty"`
+	// +optional
+	// ApplyStatus applies the status set in the object through the status subresource after
+	// the object. Without it, such a status is dropped before applying.
+	// Default: false
+	ApplyStatus bool `json:"applyStatus,omitempty"`
+	// +optional
+	// Order sets when the object is applied relative to the other templates: lower orders
+	// first, templates with the same order by kind (the operator's kind order, Namespaces
+	// first and workloads last by default), then in list order. Use it when the kind order
+	// does not create what the other objects need first.
+	// Default: 0
+	Order int `json:"order,omitempty"`
+	// +optional
+	// WaitForReady makes the worker wait after applying the object until it is ready, before
+	// applying the templates ordered after it and marking the KubeTemplate Completed. An object
+	// that doesn't become ready within the timeout fails the KubeTemplate.
+	WaitForReady *WaitForReady `json:"waitForReady,omitempty"`
+}
+
+// WaitForReady defines when an applied object is ready
+type WaitForReady struct {
+	// Condition is a CEL expression evaluated against the live object as 'object', such as
+	// "object.status.readyReplicas == object.spec.replicas". A condition that can't be
+	// evaluated yet, e.g. because the status hasn't been written, is not ready.
+	// +kubebuilder:validation:MinLength=1
+	Condition string `json:"condition"`
+	// +optional
+	// Timeout is how long to wait for the condition to hold, at most 1h.
+	// Default: 5m
+	// +kubebuilder:validation:XValidation:rule="duration(self) > duration('0s') && duration(self) <= duration('1h')",message="timeout must be greater than 0s and at most 1h"
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
 }
 
 // KubeTemplateStatus defines the observed state of KubeTemplate.
 type KubeTemplateStatus struct {
 	Status              string       `json:"status,omitempty"`
-	ProcessingPhase     string       `json:"processingPhase,omitempty"` // Queued, Processing, Completed, Failed, Paused
+	ProcessingPhase     string       `json:"processingPhase,omitempty"` // Queued, Processing, Completed, DryRunCompleted, Failed, Paused
 	QueuedAt            *metav1.Time `json:"queuedAt,omitempty"`
 	ProcessedAt         *metav1.Time `json:"processedAt,omitempty"`
 	RetryCount          int          `json:"retryCount,omitempty"`
@@ -59,11 +127,121 @@ type KubeTemplateStatus struct {
 	PausedReason string       `json:"pausedReason,omitempty"`
 	// PausedAt is the timestamp when the template was paused
 	PausedAt *metav1.Time `json:"pausedAt,omitempty"`
+	// NextRetryAt is when the work queue retries the failed template; unset while no retry is scheduled
+	// +optional
+	NextRetryAt *metav1.Time `json:"nextRetryAt,omitempty"`
+	// AppliedResources lists the resources of the last apply and what the apply did to each
+	AppliedResources []AppliedResource `json:"appliedResources,omitempty"`
+	// ObservedGeneration is the metadata.generation of the spec that was last completed
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// AppliedPolicyVersion is the resourceVersion of the policy the last completed apply was validated against
+	AppliedPolicyVersion string `json:"appliedPolicyVersion,omitempty"`
+	// DryRunResults lists what applying the spec would do to each resource, for dry-run templates
+	DryRunResults []DryRunResult `json:"dryRunResults,omitempty"`
+	// PolicyMatches lists, for each template of the last processed spec that a policy rule
+	// matched, the rule it was validated against
+	// +optional
+	PolicyMatches []PolicyMatch `json:"policyMatches,omitempty"`
+	// Summary is a one-line description of the template's health, e.g. "3/3 synced, 0 drift"
+	Summary string `json:"summary,omitempty"`
+	// Conditions describe the latest observations of the template: Ready, Applied and
+	// PolicyValidated. Status mirrors the message of the Ready condition.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// Condition types of a KubeTemplate
+const (
+	// ConditionReady is True when the spec was applied, or dry-run, and the template is healthy
+	ConditionReady = "Ready"
+	// ConditionApplied is True when every resource of the spec was applied
+	ConditionApplied = "Applied"
+	// ConditionPolicyValidated is True when the policy allowed every resource of the spec
+	ConditionPolicyValidated = "PolicyValidated"
+)
+
+// Condition reasons of a KubeTemplate
+const (
+	ReasonQueued             = "Queued"
+	ReasonProcessing         = "Processing"
+	ReasonDeferred           = "Deferred"
+	ReasonCompleted          = "Completed"
+	ReasonDryRunCompleted    = "DryRunCompleted"
+	ReasonPaused             = "Paused"
+	ReasonValidated          = "Validated"
+	ReasonRejected           = "Rejected"
+	ReasonPolicyUnavailable  = "PolicyUnavailable"
+	ReasonPolicyNotFound     = "PolicyNotFound"
+	ReasonApplied            = "Applied"
+	ReasonDryRun             = "DryRun"
+	ReasonApplyFailed        = "ApplyFailed"
+	ReasonApplyTimedOut      = "ApplyTimedOut"
+	ReasonRBACDenied         = "RBACDenied"
+	ReasonPruneFailed        = "PruneFailed"
+	ReasonVerificationFailed = "VerificationFailed"
+	ReasonNotReady           = "NotReady"
+	ReasonWaitingForReady    = "WaitingForReady"
+)
+
+// ApplyAction describes the effect of applying a resource.
+// +kubebuilder:validation:Enum=Created;Updated;Unchanged
+type ApplyAction string
+
+const (
+	ApplyActionCreated   ApplyAction = "Created"
+	ApplyActionUpdated   ApplyAction = "Updated"
+	ApplyActionUnchanged ApplyAction = "Unchanged"
+)
+
+// AppliedResource identifies an applied resource and the action taken on it.
+type AppliedResource struct {
+	APIVersion string      `json:"apiVersion"`
+	Kind       string      `json:"kind"`
+	Namespace  string      `json:"namespace,omitempty"`
+	Name       string      `json:"name"`
+	Action     ApplyAction `json:"action"`
+}
+
+// PolicyMatch records which validation rule of the policy a template was validated against.
+type PolicyMatch struct {
+	// Template is the index of the template in spec.templates
+	Template   int    `json:"template"`
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+	// Policy is the name of the KubeTemplatePolicy the template was validated against
+	Policy string `json:"policy"`
+	// Rule is the index of the matching rule in spec.validationRules of the policy
+	Rule int `json:"rule"`
+	// Wildcard is true when the rule matched through kind "*" rather than by naming the kind
+	// +optional
+	Wildcard bool `json:"wildcard,omitempty"`
+}
+
+// DryRunResult describes what applying a resource would do, as reported by a server-side dry-run.
+type DryRunResult struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+	// Action is what the apply would do to the resource, empty if the dry-run failed
+	// +optional
+	Action ApplyAction `json:"action,omitempty"`
+	// ChangedFields lists the paths of the fields the apply would change on an existing resource
+	// +optional
+	ChangedFields []string `json:"changedFields,omitempty"`
+	// Error is why the API server refused the dry-run apply
+	// +optional
+	Error string `json:"error,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.processingPhase`
+// +kubebuilder:printcolumn:name="Summary",type=string,JSONPath=`.status.summary`
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:printcolumn:name="Retry Cycle",type=integer,JSONPath=`.status.retryCycle`,priority=1
 // +kubebuilder:printcolumn:name="Resources",type=string,JSONPath=`.status.resourcesSynced`,priority=1
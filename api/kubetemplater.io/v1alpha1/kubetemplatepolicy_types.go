@@ -25,14 +25,114 @@ type KubeTemplatePolicySpec struct {
 	// SourceNamespace is the namespace where KubeTemplates are allowed to use this policy.
 	SourceNamespace string `json:"sourceNamespace"`
 
+	// ServiceAccountName is a ServiceAccount in the policy's namespace that the operator
+	// impersonates when applying resources of this policy's templates, so that its RBAC
+	// permissions bound what the templates can do. If empty, the operator's own identity is used.
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// FieldManager is the server-side apply field manager of this policy's templates that don't
+	// set their own, overriding the operator's FIELD_MANAGER.
+	// +optional
+	// +kubebuilder:validation:MaxLength=128
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9][A-Za-z0-9._:/-]*$`
+	FieldManager string `json:"fieldManager,omitempty"`
+
+	// MaintenanceWindows restricts when changed templates are applied. Outside every window the
+	// worker defers templates whose spec changed until the next window opens.
+	// If empty, changes are applied at any time.
+	// +optional
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+
+	// MaxApplyRate is the number of objects per second the worker applies from a single
+	// KubeTemplate of the source namespace, so that large templates don't create resources
+	// faster than downstream controllers can handle. The kubetemplater.io/apply-rate annotation
+	// of a KubeTemplate can lower it. If unset, only that annotation throttles applies.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxApplyRate int32 `json:"maxApplyRate,omitempty"`
+
+	// MaxTemplates is the number of templates a single KubeTemplate of the source namespace may
+	// contain. It can only lower the operator-wide limit of 50. If unset, that limit applies.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=50
+	// +optional
+	MaxTemplates int32 `json:"maxTemplates,omitempty"`
+
+	// ProtectUnmanagedResources prevents templates from silently taking over resources that
+	// already exist but were not created by KubeTemplater (they lack its tracking labels).
+	// Such a template is rejected unless it sets adopt: true.
+	// +optional
+	ProtectUnmanagedResources bool `json:"protectUnmanagedResources,omitempty"`
+
+	// PropagateLabels lists labels of the KubeTemplate that are copied onto every resource it
+	// applies, e.g. team or cost-center. Labels the KubeTemplate doesn't carry are skipped, and a
+	// label a template object sets itself keeps its value unless OverrideTemplateLabels is set.
+	// +optional
+	PropagateLabels []string `json:"propagateLabels,omitempty"`
+
+	// OverrideTemplateLabels makes propagated labels replace the values template objects set
+	// for the same keys.
+	// +optional
+	OverrideTemplateLabels bool `json:"overrideTemplateLabels,omitempty"`
+
+	// RejectTemplateStatus rejects templates whose objects set status without applyStatus: true.
+	// By default such a status is dropped with an admission warning.
+	// +optional
+	RejectTemplateStatus bool `json:"rejectTemplateStatus,omitempty"`
+
+	// RejectServerManagedMetadata rejects templates whose objects set metadata the API server
+	// manages, such as resourceVersion, uid, creationTimestamp or managedFields.
+	// By default such metadata is dropped with an admission warning.
+	// +optional
+	RejectServerManagedMetadata bool `json:"rejectServerManagedMetadata,omitempty"`
+
+	// DryRunOnAdmission makes the webhook dry-run a server-side apply of every template object
+	// at admission and warn when it conflicts with another field manager, which would make the
+	// worker's apply fail. It costs one API call per template object.
+	// +optional
+	DryRunOnAdmission bool `json:"dryRunOnAdmission,omitempty"`
+
 	ValidationRules []ValidationRule `json:"validationRules"`
 }
 
+// MaintenanceWindow is a recurring daily time range during which applies are permitted.
+type MaintenanceWindow struct {
+	// Days limits the window to these days of the week. If empty, the window opens every day.
+	// For windows that span midnight, the day is the one on which the window opens.
+	// +optional
+	Days []Weekday `json:"days,omitempty"`
+
+	// Start is the time of day the window opens, as "HH:MM".
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	Start string `json:"start"`
+
+	// End is the time of day the window closes, as "HH:MM". An End at or before Start
+	// makes the window span midnight.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	End string `json:"end"`
+
+	// TimeZone is the IANA time zone Start and End are expressed in (e.g. "Europe/Rome").
+	// Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// Weekday is an abbreviated day of the week.
+// +kubebuilder:validation:Enum=Mon;Tue;Wed;Thu;Fri;Sat;Sun
+type Weekday string
+
 // ValidationRule defines the policy for creating a specific kind of resource.
 type ValidationRule struct {
-	Kind    string `json:"kind"`
-	Group   string `json:"group"`
-	Version string `json:"version"`
+	// Kind is the kind of resources the rule applies to, or "*" for every kind of the group
+	// and version. An exact kind takes precedence over "*" when several rules match.
+	// +optional
+	Kind string `json:"kind,omitempty"`
+	// Kinds lists further kinds the rule applies to, as an alternative to one rule per kind
+	// +optional
+	Kinds   []string `json:"kinds,omitempty"`
+	Group   string   `json:"group"`
+	Version string   `json:"version"`
 
 	// Rule is a CEL expression that validates the entire object.
 	// DEPRECATED: Use FieldValidations for more granular control.
@@ -43,9 +143,19 @@ type ValidationRule struct {
 	// Each validation is evaluated independently and all must pass.
 	FieldValidations []FieldValidation `json:"fieldValidations,omitempty"`
 
+	// ImagePolicy restricts the container images used by workload resources.
+	// Images are collected from containers and initContainers of Pods and pod templates.
+	ImagePolicy *ImagePolicy `json:"imagePolicy,omitempty"`
+
 	// TargetNamespaces is a list of namespaces where resources of this kind are allowed to be created.
-	// If empty, resources of this kind cannot be created in any namespace.
-	TargetNamespaces []string `json:"targetNamespaces"`
+	// If empty and TargetNamespaceSelector is not set, resources of this kind cannot be created in any namespace.
+	// +optional
+	TargetNamespaces []string `json:"targetNamespaces,omitempty"`
+
+	// TargetNamespaceSelector additionally allows every existing namespace whose labels match,
+	// e.g. matchLabels team: payments. An empty selector matches every namespace.
+	// +optional
+	TargetNamespaceSelector *metav1.LabelSelector `json:"targetNamespaceSelector,omitempty"`
 }
 
 // FieldValidation defines validation rules for a specific field in a resource.
@@ -55,17 +165,28 @@ type FieldValidation struct {
 
 	// FieldPath is the JSON path to the field to validate (e.g., "metadata.name", "spec.replicas").
 	// Use dot notation for nested fields. For object-level validation, use empty string or "object".
+	// List elements are selected by index, "spec.containers[0].image", or all with [*],
+	// "spec.containers[*].image", which validates the field of every element.
 	FieldPath string `json:"fieldPath,omitempty"`
 
 	// Type defines the type of validation to perform.
-	// Valid values: "cel", "regex", "range", "required", "forbidden"
+	// Valid values: "cel", "regex", "range", "required", "forbidden", "securityHardening", "conditionalRequired",
+	// "allowedDomains", "enum", "length", "containerProbes"
+	// "securityHardening" ignores FieldPath and checks every pod spec of the resource for
+	// privileged settings: privileged or allowPrivilegeEscalation containers, runAsUser 0,
+	// added capabilities and hostPath volumes.
+	// "allowedDomains" ignores FieldPath and checks the hosts of an Ingress, spec.rules[*].host
+	// and spec.tls[*].hosts, against AllowedDomains.
+	// "containerProbes" ignores FieldPath and checks that every container of every pod spec
+	// of the resource defines the RequiredProbes.
 	Type FieldValidationType `json:"type"`
 
 	// CEL is a CEL expression evaluated against the field value.
 	// The variable name depends on FieldPath:
 	// - For specific fields: 'value' contains the field value
 	// - For empty FieldPath: 'object' contains the entire resource
-	// Example: "value.startsWith('prod-')" or "object.spec.replicas <= 10"
+	// 'object' is available for specific fields too, to compare the value to other fields.
+	// Example: "value.startsWith('prod-')" or "value <= object.spec.maxReplicas"
 	CEL string `json:"cel,omitempty"`
 
 	// Regex is a regular expression pattern that the field value must match.
@@ -77,16 +198,72 @@ type FieldValidation struct {
 	Min *int64 `json:"min,omitempty"`
 	Max *int64 `json:"max,omitempty"`
 
+	// MinLength and MaxLength define the allowed length of a string field, in characters,
+	// or of an array field, in items. A missing field passes unless Required is set.
+	// Only valid when Type is "length".
+	MinLength *int64 `json:"minLength,omitempty"`
+	MaxLength *int64 `json:"maxLength,omitempty"`
+
 	// Required specifies that the field must exist and be non-empty.
-	// Only valid when Type is "required".
+	// Only valid when Type is "required", or "enum" and "length" to reject a missing field too.
 	Required bool `json:"required,omitempty"`
 
+	// AllowedValues lists the values a string field may have. A missing field passes unless
+	// Required is set.
+	// Only valid when Type is "enum".
+	AllowedValues []string `json:"allowedValues,omitempty"`
+
+	// WhenFieldPath and WhenEquals are the condition of a "conditionalRequired" validation:
+	// RequiredFieldPath must be set when the field at WhenFieldPath equals WhenEquals.
+	// Values are compared in their string form, so "true" or "3" match booleans and numbers.
+	WhenFieldPath string `json:"whenFieldPath,omitempty"`
+	WhenEquals    string `json:"whenEquals,omitempty"`
+
+	// RequiredFieldPath is the field that must exist and be non-empty when the condition holds.
+	// Only valid when Type is "conditionalRequired".
+	RequiredFieldPath string `json:"requiredFieldPath,omitempty"`
+
+	// AllowedDomains lists the domains Ingress hosts must be in: "example.com" allows the domain
+	// and its subdomains, "*.example.com" only its subdomains.
+	// Only valid when Type is "allowedDomains".
+	AllowedDomains []string `json:"allowedDomains,omitempty"`
+
+	// RequiredProbes lists the probes every container must define. Init containers are not
+	// checked, since they don't support readiness or liveness probes.
+	// Only valid when Type is "containerProbes".
+	// +kubebuilder:validation:items:Enum=readinessProbe;livenessProbe;startupProbe
+	RequiredProbes []string `json:"requiredProbes,omitempty"`
+
 	// Message is a custom error message to display when validation fails.
 	Message string `json:"message,omitempty"`
 }
 
+// ImagePolicy defines supply-chain rules for container image references.
+type ImagePolicy struct {
+	// ForbidLatest rejects images tagged ":latest" and images without any tag.
+	ForbidLatest bool `json:"forbidLatest,omitempty"`
+
+	// RequireDigest rejects images that are not pinned by digest (e.g. "nginx@sha256:...").
+	RequireDigest bool `json:"requireDigest,omitempty"`
+
+	// AllowedRegistries, if set, rejects images outside these registry prefixes, e.g.
+	// "registry.example.com" or "ghcr.io/my-org". A prefix matches whole path components.
+	// Images without a registry are matched as Docker Hub images: "nginx" is
+	// "docker.io/library/nginx".
+	// +optional
+	AllowedRegistries []string `json:"allowedRegistries,omitempty"`
+
+	// DeniedRegistries rejects images in these registry prefixes, matched like
+	// AllowedRegistries. It takes precedence over AllowedRegistries.
+	// +optional
+	DeniedRegistries []string `json:"deniedRegistries,omitempty"`
+
+	// Message is a custom error message to display when an image is rejected.
+	Message string `json:"message,omitempty"`
+}
+
 // FieldValidationType defines the type of field validation.
-// +kubebuilder:validation:Enum=cel;regex;range;required;forbidden
+// +kubebuilder:validation:Enum=cel;regex;range;required;forbidden;securityHardening;conditionalRequired;allowedDomains;enum;length;containerProbes
 type FieldValidationType string
 
 const (
@@ -95,6 +272,13 @@ const (
 	FieldValidationTypeRange     FieldValidationType = "range"
 	FieldValidationTypeRequired  FieldValidationType = "required"
 	FieldValidationTypeForbidden FieldValidationType = "forbidden"
+
+	FieldValidationTypeSecurityHardening   FieldValidationType = "securityHardening"
+	FieldValidationTypeConditionalRequired FieldValidationType = "conditionalRequired"
+	FieldValidationTypeAllowedDomains      FieldValidationType = "allowedDomains"
+	FieldValidationTypeEnum                FieldValidationType = "enum"
+	FieldValidationTypeLength              FieldValidationType = "length"
+	FieldValidationTypeContainerProbes     FieldValidationType = "containerProbes"
 )
 
 // KubeTemplatePolicyStatus defines the observed state of KubeTemplatePolicy.
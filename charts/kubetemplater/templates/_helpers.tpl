@@ -60,3 +60,23 @@ Create the name of the service account to use
     {{ default "default" .Values.service_account }}
 {{- end -}}
 {{- end -}}
+
+{{/*
+The names of the webhook configurations of a type ("validating" or "mutating") whose CA bundle
+the operator patches, as a YAML list: the chart's own and those in webhook.extraConfigurations.
+The ClusterRole grants access to exactly these, so entries it couldn't cover fail the render.
+*/}}
+{{- define "kubetemplater.webhookConfigurationNames" -}}
+- {{ include "kubetemplater.fullname" .root }}-{{ .type }}-webhook-configuration
+{{- range .root.Values.webhook.extraConfigurations }}
+{{- if not .name }}
+{{- fail "webhook.extraConfigurations: every entry needs a name" }}
+{{- end }}
+{{- if not (has .type (list "validating" "mutating")) }}
+{{- fail (printf "webhook.extraConfigurations: %s has the type %q, which must be validating or mutating" .name .type) }}
+{{- end }}
+{{- if eq .type $.type }}
+- {{ .name }}
+{{- end }}
+{{- end }}
+{{- end -}}
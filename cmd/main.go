@@ -33,6 +33,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/utils/ptr"
@@ -41,15 +42,21 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	"github.com/lpeano/KubeTemplater/internal/audit"
 	"github.com/lpeano/KubeTemplater/internal/cache"
+	"github.com/lpeano/KubeTemplater/internal/celquery"
 	"github.com/lpeano/KubeTemplater/internal/cert"
 	"github.com/lpeano/KubeTemplater/internal/controller"
 	kubetemplateriocontroller "github.com/lpeano/KubeTemplater/internal/controller/kubetemplater.io"
+	"github.com/lpeano/KubeTemplater/internal/impersonation"
+	"github.com/lpeano/KubeTemplater/internal/manifest"
+	"github.com/lpeano/KubeTemplater/internal/metrics"
 	"github.com/lpeano/KubeTemplater/internal/queue"
 	kubetemplaterwebhook "github.com/lpeano/KubeTemplater/internal/webhook"
 	"github.com/lpeano/KubeTemplater/internal/worker"
@@ -99,6 +106,23 @@ func main() {
 	var webhookCertSecretName string
 	var webhookServiceName string
 	var webhookConfigurationName string
+	var mutatingWebhookConfigurationName string
+	var extraWebhookConfigurations string
+	var webhookFailOpen bool
+	var useExternalCA bool
+	var webhookCAKeySize int
+	var webhookServerKeySize int
+	var webhookCertMinDaysRemaining int
+	var allowKubeTemplaterResources bool
+	var webhookSchemaValidation bool
+	var webhookSSAAdvisories bool
+	var webhookCapacityCheck bool
+	var auditLogSink string
+	var requiredNamespaceLabel string
+	var rejectUnlabeledNamespaces bool
+	var unknownValidationTypes string
+	var pauseOnRBACDenied bool
+	var webhookMaxRequestBytes int64
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -114,6 +138,52 @@ func main() {
 	flag.StringVar(&webhookCertSecretName, "webhook-cert-secret-name", "", "The name of the secret containing webhook certificates (for automatic cert management).")
 	flag.StringVar(&webhookServiceName, "webhook-service-name", "kubetemplater-webhook-service", "The name of the webhook service.")
 	flag.StringVar(&webhookConfigurationName, "webhook-configuration-name", "kubetemplater-validating-webhook-configuration", "The name of the validating webhook configuration to patch with the CA bundle.")
+	flag.StringVar(&mutatingWebhookConfigurationName, "mutating-webhook-configuration-name", "kubetemplater-mutating-webhook-configuration", "The name of the mutating webhook configuration to patch with the CA bundle (empty = none).")
+	flag.StringVar(&extraWebhookConfigurations, "extra-webhook-configurations", "",
+		"Comma-separated type:name list of additional webhook configurations to patch with the CA bundle, "+
+			"where type is validating or mutating (e.g. validating:my-validation,mutating:my-defaults).")
+	flag.BoolVar(&webhookFailOpen, "webhook-fail-open", false,
+		"If set, the validating webhook failurePolicy is switched to Ignore (maintenance mode). "+
+			"Restarting without this flag restores the original failurePolicy.")
+	flag.BoolVar(&allowKubeTemplaterResources, "allow-kubetemplater-resources", false,
+		"If set, templates may create kubetemplater.io resources (KubeTemplates, KubeTemplatePolicies). "+
+			"Disabled by default to prevent recursive templates and privilege escalation.")
+	flag.BoolVar(&pauseOnRBACDenied, "pause-on-rbac-denied", true,
+		"If set, templates whose apply is forbidden by the operator's RBAC are paused with the missing permission "+
+			"instead of being retried. Resume them with the kubetemplater.io/resume annotation once it is granted.")
+	flag.BoolVar(&useExternalCA, "use-external-ca", false,
+		"If set, webhook server certificates are issued from the CA provided in the <webhook-cert-secret-name>-ca secret. "+
+			"The operator never generates or renews the CA, and fails to start when the secret is missing.")
+	flag.IntVar(&webhookCAKeySize, "webhook-ca-key-size", cert.DefaultKeySize,
+		"RSA key size in bits of the generated webhook CA (minimum 2048). Applies when a CA is generated or renewed.")
+	flag.IntVar(&webhookServerKeySize, "webhook-server-key-size", cert.DefaultKeySize,
+		"RSA key size in bits of the generated webhook server certificate (minimum 2048).")
+	flag.IntVar(&webhookCertMinDaysRemaining, "webhook-cert-min-days-remaining", 0,
+		"If > 0, the readiness check fails while the served webhook certificate expires within this many days (0 = disabled).")
+	flag.BoolVar(&webhookSchemaValidation, "webhook-schema-validation", false,
+		"If set, the webhook validates template objects against the cluster's OpenAPI schema "+
+			"(cached for CACHE_TTL) and rejects type errors with their field paths.")
+	flag.BoolVar(&webhookSSAAdvisories, "webhook-ssa-advisories", false,
+		"If set, the webhook warns about list fields of template objects that server-side apply replaces "+
+			"as a whole or can't merge by key. Requires --webhook-schema-validation.")
+	flag.BoolVar(&webhookCapacityCheck, "webhook-capacity-check", false,
+		"If set, the webhook warns when the workloads of a KubeTemplate request more CPU or memory "+
+			"than the schedulable nodes can allocate. The check is advisory.")
+	flag.StringVar(&auditLogSink, "audit-log", "",
+		"Where to write the audit log of every admission and apply decision, as JSON lines: \"stdout\", \"stderr\" "+
+			"or the path of a file to append to. Empty disables audit logging.")
+	flag.StringVar(&requiredNamespaceLabel, "required-namespace-label", "",
+		"A label (\"key\" or \"key=value\") that target namespaces of templates must carry, e.g. the one the "+
+			"operator's namespace watch selects on. The webhook warns about templates targeting other namespaces.")
+	flag.BoolVar(&rejectUnlabeledNamespaces, "reject-unlabeled-namespaces", false,
+		"If set, the webhook rejects templates targeting namespaces without --required-namespace-label instead of warning.")
+	flag.StringVar(&unknownValidationTypes, "unknown-validation-types", string(kubetemplaterwebhook.UnknownValidationTypeWarn),
+		"How the webhook handles field validations of a type this operator version doesn't know, e.g. in policies "+
+			"written for a newer version: \"ignore\" skips them, \"warn\" skips them with an admission warning, "+
+			"\"fail\" rejects the template.")
+	flag.Int64Var(&webhookMaxRequestBytes, "webhook-max-request-bytes", kubetemplaterwebhook.DefaultMaxRequestBytes,
+		"The size limit of admission requests in bytes. Larger requests are rejected before they are decoded. "+
+			"0 disables the limit, leaving the webhook framework's limit of 7MiB.")
 	flag.StringVar(&metricsCertPath, "metrics-cert-path", "",
 		"The directory that contains the metrics server certificate.")
 	flag.StringVar(&metricsCertName, "metrics-cert-name", "tls.crt", "The name of the metrics server certificate file.")
@@ -174,6 +244,7 @@ func main() {
 			k8sClientset,
 			webhookCertSecretName,
 			operatorNamespace,
+			webhookServiceName,
 		)
 
 		// Configure webhook to use SecretCertWatcher
@@ -182,18 +253,27 @@ func main() {
 		})
 	}
 
-	webhookServer := webhook.NewServer(webhook.Options{
+	webhookServer := kubetemplaterwebhook.LimitRequestSize(webhook.NewServer(webhook.Options{
 		TLSOpts: webhookTLSOpts,
-	})
+	}), webhookMaxRequestBytes)
 
 	// Metrics endpoint is enabled in 'config/default/kustomization.yaml'. The Metrics options configure the server.
 	// More info:
 	// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.21.0/pkg/metrics/server
 	// - https://book.kubebuilder.io/reference/metrics.html
+	// The templates the workers are processing are listed on the metrics server's /debug/processing,
+	// and the objects all templates apply are exported as YAML on /debug/export.
+	// The export handler's reader is set once the manager (and its cache) exists.
+	inFlight := worker.NewInFlightTracker()
+	exportHandler := &manifest.ExportHandler{}
 	metricsServerOptions := metricsserver.Options{
 		BindAddress:   metricsAddr,
 		SecureServing: secureMetrics,
 		TLSOpts:       tlsOpts,
+		ExtraHandlers: map[string]http.Handler{
+			worker.InFlightPath: inFlight,
+			manifest.ExportPath: exportHandler,
+		},
 	}
 
 	if secureMetrics {
@@ -231,6 +311,15 @@ func main() {
 		})
 	}
 
+	// QUEUE_DRAIN_TIMEOUT: Seconds a shutdown waits for in-flight applies to finish (default: 20)
+	// The manager's graceful shutdown timeout leaves 5s on top for the other runnables
+	queueDrainSeconds := getEnvInt("QUEUE_DRAIN_TIMEOUT", 20)
+	if queueDrainSeconds < 0 {
+		queueDrainSeconds = 0
+		setupLog.Info("QUEUE_DRAIN_TIMEOUT cannot be negative, not waiting for in-flight items", "value", 0)
+	}
+	queueDrainTimeout := time.Duration(queueDrainSeconds) * time.Second
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 scheme,
 		Metrics:                metricsServerOptions,
@@ -254,11 +343,13 @@ func main() {
 		RenewDeadline: ptr.To(7 * time.Second),  // Leader must renew within 7s
 		RetryPeriod:   ptr.To(2 * time.Second),  // Retry every 2s
 		// Result: If leader dies, new election happens in ~10s maximum
+		GracefulShutdownTimeout: ptr.To(queueDrainTimeout + 5*time.Second),
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
+	exportHandler.Reader = mgr.GetClient()
 
 	// Add SecretCertWatcher as a Runnable (all pods)
 	if secretCertWatcher != nil {
@@ -281,10 +372,36 @@ func main() {
 	// Initialize certificate manager if secret name is provided
 	var certManager *cert.Manager
 	if webhookCertSecretName != "" {
+		// WEBHOOK_KEY_ALGORITHM: Algorithm of generated webhook CA and server keys, rsa or ecdsa (P-256) (default: rsa)
+		webhookKeyAlgorithm, err := cert.ParseKeyAlgorithm(os.Getenv("WEBHOOK_KEY_ALGORITHM"))
+		if err != nil {
+			setupLog.Error(err, "invalid WEBHOOK_KEY_ALGORITHM")
+			os.Exit(1)
+		}
 		setupLog.Info("Certificate auto-management enabled",
 			"secretName", webhookCertSecretName,
 			"namespace", operatorNamespace,
-			"serviceName", webhookServiceName)
+			"serviceName", webhookServiceName,
+			"failOpen", webhookFailOpen,
+			"externalCA", useExternalCA,
+			"keyAlgorithm", webhookKeyAlgorithm,
+			"caKeySize", webhookCAKeySize,
+			"serverKeySize", webhookServerKeySize)
+		if webhookKeyAlgorithm == cert.KeyAlgorithmRSA {
+			if err := cert.ValidateKeySize(webhookCAKeySize); err != nil {
+				setupLog.Error(err, "invalid --webhook-ca-key-size")
+				os.Exit(1)
+			}
+			if err := cert.ValidateKeySize(webhookServerKeySize); err != nil {
+				setupLog.Error(err, "invalid --webhook-server-key-size")
+				os.Exit(1)
+			}
+		}
+		extraWebhookConfigs, err := cert.ParseWebhookConfigurations(extraWebhookConfigurations)
+		if err != nil {
+			setupLog.Error(err, "invalid --extra-webhook-configurations")
+			os.Exit(1)
+		}
 		
 		config := ctrl.GetConfigOrDie()
 		k8sClientset, err := kubernetes.NewForConfig(config)
@@ -300,6 +417,13 @@ func main() {
 			operatorNamespace,
 			webhookServiceName,
 			webhookConfigurationName,
+			cert.WithFailOpen(webhookFailOpen),
+			cert.WithMutatingWebhookConfiguration(mutatingWebhookConfigurationName),
+			cert.WithWebhookConfigurations(extraWebhookConfigs...),
+			cert.WithExternalCA(useExternalCA),
+			cert.WithKeyAlgorithm(webhookKeyAlgorithm),
+			cert.WithCAKeySize(webhookCAKeySize),
+			cert.WithServerKeySize(webhookServerKeySize),
 		)
 
 		// Add certificate manager as a Runnable that respects leader election
@@ -354,6 +478,30 @@ func main() {
 	}
 	policyCacheTTL := time.Duration(policyCacheTTLSeconds) * time.Second
 
+	// POLICY_CACHE_SWEEP_INTERVAL: How often expired policy cache entries are evicted, in seconds
+	// (default: POLICY_CACHE_TTL). Without the sweep, entries of namespaces that are never looked
+	// up again stay in memory until the process exits.
+	// Configured via tuning.policyCacheSweepInterval in Helm values
+	policyCacheSweepIntervalSeconds := getEnvInt("POLICY_CACHE_SWEEP_INTERVAL", policyCacheTTLSeconds)
+	if policyCacheSweepIntervalSeconds < 10 {
+		policyCacheSweepIntervalSeconds = 10
+		setupLog.Info("POLICY_CACHE_SWEEP_INTERVAL must be >= 10 seconds, using minimum", "value", 10)
+	}
+	policyCacheSweepInterval := time.Duration(policyCacheSweepIntervalSeconds) * time.Second
+
+	// DUPLICATE_POLICY_RESOLUTION: What to do when several policies cover a source namespace,
+	// error, mostRecent, alphabetical or merge (default: error = reject the namespace's templates)
+	duplicatePolicyResolution := cache.DuplicateResolution(os.Getenv("DUPLICATE_POLICY_RESOLUTION"))
+	switch duplicatePolicyResolution {
+	case cache.DuplicateResolutionError, cache.DuplicateResolutionMostRecent, cache.DuplicateResolutionAlphabetical,
+		cache.DuplicateResolutionMerge:
+	case "":
+		duplicatePolicyResolution = cache.DuplicateResolutionError
+	default:
+		setupLog.Info("Invalid DUPLICATE_POLICY_RESOLUTION, using default", "value", duplicatePolicyResolution, "default", cache.DuplicateResolutionError)
+		duplicatePolicyResolution = cache.DuplicateResolutionError
+	}
+
 	// PERIODIC_RECONCILE_INTERVAL: Interval for drift detection reconciliation in seconds (default: 60)
 	periodicReconcileSeconds := getEnvInt("PERIODIC_RECONCILE_INTERVAL", 60)
 	if periodicReconcileSeconds < 30 {
@@ -392,59 +540,272 @@ func main() {
 		setupLog.Info("QUEUE_MAX_RETRY_CYCLES cannot be negative, using unlimited", "value", 0)
 	}
 
+	// PAUSE_AUTO_RESUME_AFTER: Seconds before a template paused after its last retry cycle is queued again (default: 0 = disabled)
+	pauseAutoResumeSeconds := getEnvInt("PAUSE_AUTO_RESUME_AFTER", 0)
+	if pauseAutoResumeSeconds < 0 {
+		pauseAutoResumeSeconds = 0
+		setupLog.Info("PAUSE_AUTO_RESUME_AFTER cannot be negative, disabling auto-resume", "value", 0)
+	}
+	if pauseAutoResumeSeconds > 0 && pauseAutoResumeSeconds < 60 {
+		pauseAutoResumeSeconds = 60
+		setupLog.Info("PAUSE_AUTO_RESUME_AFTER must be >= 60 seconds, using minimum", "value", 60)
+	}
+	if pauseAutoResumeSeconds > 0 && queueMaxRetryCycles == 0 {
+		setupLog.Info("PAUSE_AUTO_RESUME_AFTER has no effect with QUEUE_MAX_RETRY_CYCLES=0, templates are never paused for repeated failures")
+	}
+	pauseAutoResumeAfter := time.Duration(pauseAutoResumeSeconds) * time.Second
+
+	// QUEUE_MODE: Dequeue order, Priority or FIFO (default: Priority)
+	queueMode := queue.Mode(os.Getenv("QUEUE_MODE"))
+	switch queueMode {
+	case queue.ModePriority, queue.ModeFIFO:
+	case "":
+		queueMode = queue.ModePriority
+	default:
+		setupLog.Info("Invalid QUEUE_MODE, using default", "value", queueMode, "default", queue.ModePriority)
+		queueMode = queue.ModePriority
+	}
+
+	// MAX_INFLIGHT_PER_NAMESPACE: Maximum templates of one namespace processed at once (default: 0 = unlimited)
+	maxInFlightPerNamespace := getEnvInt("MAX_INFLIGHT_PER_NAMESPACE", 0)
+	if maxInFlightPerNamespace < 0 {
+		maxInFlightPerNamespace = 0
+		setupLog.Info("MAX_INFLIGHT_PER_NAMESPACE cannot be negative, using unlimited", "value", 0)
+	}
+
+	// QUEUE_SATURATION_DEPTH: Templates due for processing at which the controller delays enqueueing (default: 0 = no back-pressure)
+	queueSaturationDepth := getEnvInt("QUEUE_SATURATION_DEPTH", 0)
+	if queueSaturationDepth < 0 {
+		queueSaturationDepth = 0
+		setupLog.Info("QUEUE_SATURATION_DEPTH cannot be negative, disabling back-pressure", "value", 0)
+	}
+
+	// QUEUE_SATURATION_REQUEUE_DELAY: Seconds a template waits to be enqueued while the queue is saturated (default: 10)
+	queueSaturationRequeueSeconds := getEnvInt("QUEUE_SATURATION_REQUEUE_DELAY", 10)
+	if queueSaturationRequeueSeconds < 1 {
+		queueSaturationRequeueSeconds = 1
+		setupLog.Info("QUEUE_SATURATION_REQUEUE_DELAY must be >= 1 second, using minimum", "value", 1)
+	}
+	queueSaturationRequeueDelay := time.Duration(queueSaturationRequeueSeconds) * time.Second
+
+	// FIELD_MANAGER: Server-side apply field manager of templates whose policy and template don't set one (default: kubetemplater)
+	fieldManager := os.Getenv("FIELD_MANAGER")
+	if fieldManager == "" {
+		fieldManager = manifest.DefaultFieldManager
+	} else if err := manifest.ValidateFieldManagerName(fieldManager); err != nil {
+		setupLog.Info("Invalid FIELD_MANAGER, using default", "value", fieldManager, "default", manifest.DefaultFieldManager, "error", err)
+		fieldManager = manifest.DefaultFieldManager
+	}
+
+	// POST_APPLY_VERIFY_DELAY: Delay in seconds before re-checking that applied resources exist (default: 0 = disabled)
+	postApplyVerifySeconds := getEnvInt("POST_APPLY_VERIFY_DELAY", 0)
+	if postApplyVerifySeconds < 0 {
+		postApplyVerifySeconds = 0
+		setupLog.Info("POST_APPLY_VERIFY_DELAY cannot be negative, disabling verification", "value", 0)
+	}
+	if postApplyVerifySeconds > 300 {
+		postApplyVerifySeconds = 300
+		setupLog.Info("POST_APPLY_VERIFY_DELAY must be <= 300 seconds, using maximum", "value", 300)
+	}
+	postApplyVerifyDelay := time.Duration(postApplyVerifySeconds) * time.Second
+
+	// DRIFT_APPLY_CONFLICT_RETRIES: Retries of a drift-correcting apply that conflicts with a concurrent change (default: 4)
+	driftApplyConflictRetries := getEnvInt("DRIFT_APPLY_CONFLICT_RETRIES", 4)
+	if driftApplyConflictRetries < 0 {
+		driftApplyConflictRetries = 0
+		setupLog.Info("DRIFT_APPLY_CONFLICT_RETRIES cannot be negative, disabling retries", "value", 0)
+	}
+	if driftApplyConflictRetries > 10 {
+		driftApplyConflictRetries = 10
+		setupLog.Info("DRIFT_APPLY_CONFLICT_RETRIES must be <= 10, using maximum", "value", 10)
+	}
+
+	// STARTUP_DRIFT_CHECK_RATE: Completed templates drift-checked per second after startup (default: 5, 0 = disabled)
+	startupDriftCheckRate := getEnvInt("STARTUP_DRIFT_CHECK_RATE", 5)
+	if startupDriftCheckRate < 0 {
+		startupDriftCheckRate = 0
+		setupLog.Info("STARTUP_DRIFT_CHECK_RATE cannot be negative, disabling the startup drift check", "value", 0)
+	}
+	if startupDriftCheckRate > 100 {
+		startupDriftCheckRate = 100
+		setupLog.Info("STARTUP_DRIFT_CHECK_RATE must be <= 100, using maximum", "value", 100)
+	}
+
+	// NAMESPACE_DELETION_GRACE_PERIOD: Seconds to wait before deleting the KubeTemplates of a terminating namespace (default: 10)
+	namespaceGraceSeconds := getEnvInt("NAMESPACE_DELETION_GRACE_PERIOD", 10)
+	if namespaceGraceSeconds < 0 {
+		namespaceGraceSeconds = 0
+		setupLog.Info("NAMESPACE_DELETION_GRACE_PERIOD cannot be negative, disabling grace period", "value", 0)
+	}
+	if namespaceGraceSeconds > 300 {
+		namespaceGraceSeconds = 300
+		setupLog.Info("NAMESPACE_DELETION_GRACE_PERIOD must be <= 300 seconds, using maximum", "value", 300)
+	}
+	namespaceDeletionGracePeriod := time.Duration(namespaceGraceSeconds) * time.Second
+
+	// MAX_YAML_EXPANSION_RATIO: Maximum size of a decoded template object relative to its source (default: 10, 0 = unlimited)
+	maxYAMLExpansionRatio := getEnvInt("MAX_YAML_EXPANSION_RATIO", manifest.DefaultMaxExpansionRatio)
+	if maxYAMLExpansionRatio < 0 {
+		maxYAMLExpansionRatio = 0
+		setupLog.Info("MAX_YAML_EXPANSION_RATIO cannot be negative, disabling the check", "value", 0)
+	}
+	if maxYAMLExpansionRatio > 0 && maxYAMLExpansionRatio < 2 {
+		maxYAMLExpansionRatio = 2
+		setupLog.Info("MAX_YAML_EXPANSION_RATIO must be >= 2, using minimum", "value", 2)
+	}
+	manifest.MaxExpansionRatio = maxYAMLExpansionRatio
+
+	// APPLY_KIND_ORDER: Comma-separated kinds in the order templates with the same order field are applied
+	// (default: Helm's install order, none = list order)
+	manifest.KindOrder = manifest.ParseKindOrder(os.Getenv("APPLY_KIND_ORDER"))
+
 	setupLog.Info("Tuning parameters configured",
 		"numWorkers", numWorkers,
 		"cacheTTL", cacheTTL,
 		"policyCacheTTL", policyCacheTTL,
+		"policyCacheSweepInterval", policyCacheSweepInterval,
+		"duplicatePolicyResolution", duplicatePolicyResolution,
 		"periodicReconcileInterval", periodicReconcileInterval,
+		"startupDriftCheckRate", startupDriftCheckRate,
 		"queueMaxRetries", queueMaxRetries,
 		"queueInitialRetryDelay", queueInitialRetryDelay,
 		"queueMaxRetryDelay", queueMaxRetryDelay,
-		"queueMaxRetryCycles", queueMaxRetryCycles)
+		"queueMaxRetryCycles", queueMaxRetryCycles,
+		"pauseAutoResumeAfter", pauseAutoResumeAfter,
+		"queueMode", queueMode,
+		"queueSaturationDepth", queueSaturationDepth,
+		"queueSaturationRequeueDelay", queueSaturationRequeueDelay,
+		"postApplyVerifyDelay", postApplyVerifyDelay,
+		"namespaceDeletionGracePeriod", namespaceDeletionGracePeriod,
+		"maxYAMLExpansionRatio", maxYAMLExpansionRatio,
+		"applyKindOrder", manifest.KindOrder,
+		"fieldManager", fieldManager)
 
 	// Initialize policy cache with security-focused TTL (used by webhook & workers)
 	policyCache := cache.NewPolicyCache(mgr.GetClient(), policyCacheTTL)
+	// Until the manager's cache has synced, the field index may miss policies that exist
+	policyCache.SetFallbackReader(mgr.GetAPIReader())
+	policyCache.SetDuplicateResolution(duplicatePolicyResolution)
+	if err := mgr.Add(policyCache.SyncGate(mgr.GetCache())); err != nil {
+		setupLog.Error(err, "unable to add policy cache sync gate")
+		os.Exit(1)
+	}
+	if err := mgr.Add(policyCache.Janitor(policyCacheSweepInterval)); err != nil {
+		setupLog.Error(err, "unable to add policy cache janitor")
+		os.Exit(1)
+	}
 	setupLog.Info("Policy cache initialized", "ttl", policyCacheTTL)
+	exportHandler.Policies = policyCache
+	exportHandler.OperatorNamespace = operatorNamespace
 
 	// Initialize work queue for async processing with configurable retry parameters
 	workQueue := queue.NewWorkQueueWithConfig(queueMaxRetries, queueInitialRetryDelay, queueMaxRetryDelay, queueMaxRetryCycles)
+	workQueue.Mode = queueMode
+	workQueue.MaxInFlightPerNamespace = maxInFlightPerNamespace
+	workQueue.SaturationDepth = queueSaturationDepth
+	if maxInFlightPerNamespace > 0 && maxInFlightPerNamespace >= numWorkers {
+		setupLog.Info("MAX_INFLIGHT_PER_NAMESPACE is not below NUM_WORKERS, so it has no effect",
+			"maxInFlightPerNamespace", maxInFlightPerNamespace, "numWorkers", numWorkers)
+	}
+	// Let in-flight applies finish on SIGTERM instead of leaving templates in Processing
+	if err := mgr.Add(workQueue.DrainOnShutdown(queueDrainTimeout)); err != nil {
+		setupLog.Error(err, "unable to add work queue drain")
+		os.Exit(1)
+	}
 	setupLog.Info("Work queue initialized",
+		"mode", queueMode,
+		"drainTimeout", queueDrainTimeout,
+		"maxInFlightPerNamespace", maxInFlightPerNamespace,
+		"saturationDepth", queueSaturationDepth,
 		"maxRetries", queueMaxRetries,
 		"initialRetryDelay", queueInitialRetryDelay,
 		"maxRetryDelay", queueMaxRetryDelay,
 		"maxRetryCycles", queueMaxRetryCycles)
+	if err := metrics.RegisterQueue(workQueue.Stats); err != nil {
+		setupLog.Error(err, "unable to register work queue metrics")
+		os.Exit(1)
+	}
 
 	// Create event recorder for worker events
 	eventRecorder := mgr.GetEventRecorderFor("kubetemplater-worker")
 	
+	// Clients impersonating policy ServiceAccounts (spec.serviceAccountName)
+	impersonatingClients := impersonation.NewClientFactory(mgr.GetConfig(), client.Options{
+		Scheme: mgr.GetScheme(),
+		Mapper: mgr.GetRESTMapper(),
+	})
+
+	// Audit log of every admission and apply decision, shared by the webhook and the workers
+	auditLogger, err := audit.Open(auditLogSink)
+	if err != nil {
+		setupLog.Error(err, "unable to open audit log", "sink", auditLogSink)
+		os.Exit(1)
+	}
+	if auditLogger != nil {
+		setupLog.Info("Audit logging enabled", "sink", auditLogSink)
+	}
+
 	// Start worker pool for processing templates
 	ctx := context.Background()
-	worker.StartWorkers(ctx, mgr.GetClient(), policyCache, workQueue, eventRecorder, operatorNamespace, numWorkers)
+	worker.StartWorkers(ctx, mgr.GetClient(), policyCache, workQueue, eventRecorder, operatorNamespace, numWorkers,
+		worker.WithAllowKubeTemplaterResources(allowKubeTemplaterResources),
+		worker.WithPauseOnRBACDenied(pauseOnRBACDenied),
+		worker.WithPostApplyVerification(postApplyVerifyDelay),
+		worker.WithImpersonation(impersonatingClients),
+		worker.WithInFlightTracker(inFlight),
+		worker.WithErrorLogLimiter(worker.NewErrorLogLimiter()),
+		worker.WithAuditLogger(auditLogger),
+		worker.WithFieldManager(fieldManager),
+		worker.WithResourceCounter(celquery.NewCounter(mgr.GetClient(), celquery.DefaultQPS, celquery.DefaultBurst, celquery.DefaultTimeout)))
 	setupLog.Info("Started template processor workers", "numWorkers", numWorkers)
 
-	// Setup policy cache controller to keep cache in sync
-	if err := (&kubetemplateriocontroller.PolicyCacheReconciler{
-		Client: mgr.GetClient(),
-		Cache:  policyCache,
-	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "PolicyCache")
+	// Enqueue pending templates by applyPriority once the cache is synced (leader only)
+	if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		if _, err := worker.ResyncKubeTemplates(ctx, mgr.GetClient(), workQueue); err != nil {
+			setupLog.Error(err, "Startup resync failed, templates will be enqueued by the controller")
+		}
+		return nil
+	})); err != nil {
+		setupLog.Error(err, "unable to add startup resync")
 		os.Exit(1)
 	}
 
-	if err := (&kubetemplateriocontroller.KubeTemplateReconciler{
+	kubeTemplateReconciler := &kubetemplateriocontroller.KubeTemplateReconciler{
 		Client:                    mgr.GetClient(),
 		Scheme:                    mgr.GetScheme(),
 		OperatorNamespace:         operatorNamespace,
 		WorkQueue:                 workQueue,
 		PeriodicReconcileInterval: periodicReconcileInterval,
-	}).SetupWithManager(mgr); err != nil {
+		PolicyCache:               policyCache,
+		ImpersonatingClients:      impersonatingClients,
+		DriftApplyConflictRetries: driftApplyConflictRetries,
+		FieldManager:              fieldManager,
+		Recorder:                  mgr.GetEventRecorderFor("kubetemplater-controller"),
+		PauseAutoResumeAfter:      pauseAutoResumeAfter,
+		SaturationRequeueDelay:    queueSaturationRequeueDelay,
+	}
+	if err := kubeTemplateReconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "KubeTemplate")
 		os.Exit(1)
 	}
+	// Check Completed templates for drift that happened while the operator was down (leader only)
+	if startupDriftCheckRate > 0 {
+		if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+			if _, err := kubeTemplateReconciler.StartupDriftCheck(ctx, startupDriftCheckRate); err != nil {
+				setupLog.Error(err, "Startup drift check failed, drift will be detected by the periodic reconciliation")
+			}
+			return nil
+		})); err != nil {
+			setupLog.Error(err, "unable to add startup drift check")
+			os.Exit(1)
+		}
+	}
 	if err := (&kubetemplateriocontroller.KubeTemplatePolicyReconciler{
-		Client:      mgr.GetClient(),
-		Scheme:      mgr.GetScheme(),
-		PolicyCache: policyCache,
+		Client:            mgr.GetClient(),
+		Scheme:            mgr.GetScheme(),
+		PolicyCache:       policyCache,
+		WorkQueue:         workQueue,
+		OperatorNamespace: operatorNamespace,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "KubeTemplatePolicy")
 		os.Exit(1)
@@ -463,22 +824,70 @@ func main() {
 	}
 	*/
 	if err := (&controller.NamespaceReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:              mgr.GetClient(),
+		Scheme:              mgr.GetScheme(),
+		DeletionGracePeriod: namespaceDeletionGracePeriod,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Namespace")
 		os.Exit(1)
 	}
 
+	// Setup optional OpenAPI schema validation for the webhook
+	var schemaValidator *kubetemplaterwebhook.SchemaValidator
+	if webhookSchemaValidation {
+		discoveryClient, err := discovery.NewDiscoveryClientForConfig(mgr.GetConfig())
+		if err != nil {
+			setupLog.Error(err, "unable to create discovery client for schema validation")
+			os.Exit(1)
+		}
+		schemaValidator = kubetemplaterwebhook.NewSchemaValidator(discoveryClient.OpenAPISchema, cacheTTL)
+		setupLog.Info("OpenAPI schema validation enabled", "schemaCacheTTL", cacheTTL, "ssaAdvisories", webhookSSAAdvisories)
+	} else if webhookSSAAdvisories {
+		setupLog.Info("--webhook-ssa-advisories has no effect without --webhook-schema-validation")
+	}
+
+	unknownValidationTypePolicy, err := kubetemplaterwebhook.ParseUnknownValidationTypePolicy(unknownValidationTypes)
+	if err != nil {
+		setupLog.Error(err, "invalid --unknown-validation-types")
+		os.Exit(1)
+	}
+
 	// Setup webhook for KubeTemplate validation
 	if err := (&kubetemplaterwebhook.KubeTemplateValidator{
 		Client:            mgr.GetClient(),
 		OperatorNamespace: operatorNamespace,
 		Cache:             policyCache,
+
+		AllowKubeTemplaterResources: allowKubeTemplaterResources,
+		SchemaValidator:             schemaValidator,
+		SSAAdvisories:               webhookSSAAdvisories,
+		RequiredNamespaceLabel:      requiredNamespaceLabel,
+		RejectUnlabeledNamespaces:   rejectUnlabeledNamespaces,
+		UnknownValidationTypes:      unknownValidationTypePolicy,
+		Audit:                       auditLogger,
+		FieldManager:                fieldManager,
+		CheckClusterCapacity:        webhookCapacityCheck,
+		// Separate from the workers' counter, so apply retries can't use up the admission rate
+		ResourceCounter: celquery.NewCounter(mgr.GetClient(), celquery.DefaultQPS, celquery.DefaultBurst, celquery.DefaultTimeout),
 	}).SetupWebhookWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "KubeTemplate")
 		os.Exit(1)
 	}
+
+	// Setup webhook recording the policy version each KubeTemplate is admitted against
+	if err := (&kubetemplaterwebhook.KubeTemplateDefaulter{
+		OperatorNamespace: operatorNamespace,
+		Cache:             policyCache,
+	}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "KubeTemplateDefaulter")
+		os.Exit(1)
+	}
+
+	// Setup webhook for KubeTemplatePolicy validation
+	if err := (&kubetemplaterwebhook.KubeTemplatePolicyValidator{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "KubeTemplatePolicy")
+		os.Exit(1)
+	}
 	// +kubebuilder:scaffold:builder
 
 	if metricsCertWatcher != nil {
@@ -509,6 +918,25 @@ func main() {
 			setupLog.Error(err, "unable to set up certificate readiness check")
 			os.Exit(1)
 		}
+
+		// Fail readiness while the served certificate doesn't chain to the CA in the CA secret
+		if err := mgr.AddReadyzCheck("certificate-chain", func(req *http.Request) error {
+			return secretCertWatcher.ChainError()
+		}); err != nil {
+			setupLog.Error(err, "unable to set up certificate chain readiness check")
+			os.Exit(1)
+		}
+
+		// Fail readiness while the served certificate is about to expire, e.g. renewal is broken
+		if webhookCertMinDaysRemaining > 0 {
+			minRemaining := time.Duration(webhookCertMinDaysRemaining) * 24 * time.Hour
+			if err := mgr.AddReadyzCheck("certificate-expiry", func(req *http.Request) error {
+				return secretCertWatcher.CheckExpiry(minRemaining)
+			}); err != nil {
+				setupLog.Error(err, "unable to set up certificate expiry readiness check")
+				os.Exit(1)
+			}
+		}
 	}
 
 	setupLog.Info("starting manager")
@@ -0,0 +1,173 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command validate checks KubeTemplates against a KubeTemplatePolicy offline, e.g. in CI before
+// they are pushed to a cluster. It runs the admission webhook's checks without a cluster, see
+// KubeTemplateValidator.ValidateAgainstPolicy for the ones it can't make.
+//
+//	validate -policy policy.yaml -template templates.yaml
+//
+// The template file may hold several KubeTemplates as YAML documents. The exit code is 0 when
+// all of them are valid, 1 when one is invalid, and 2 when the input can't be read.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/go-logr/logr"
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	kubetemplaterwebhook "github.com/lpeano/KubeTemplater/internal/webhook"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	exitInvalid = 1
+	exitUsage   = 2
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run validates the files named in args and returns the exit code
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("validate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	policyPath := flags.String("policy", "", "The file holding the KubeTemplatePolicy to validate against.")
+	templatePath := flags.String("template", "", "The file holding the KubeTemplates to validate, as one or more YAML documents.")
+	allowKubeTemplaterResources := flags.Bool("allow-kubetemplater-resources", false,
+		"If set, templates may create kubetemplater.io resources, as with the operator flag of the same name.")
+	unknownValidationTypes := flags.String("unknown-validation-types", string(kubetemplaterwebhook.UnknownValidationTypeWarn),
+		"How field validations of an unknown type are handled: \"ignore\", \"warn\" or \"fail\", as with the operator flag of the same name.")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if *policyPath == "" || *templatePath == "" {
+		fmt.Fprintln(stderr, "both -policy and -template are required")
+		flags.Usage()
+		return exitUsage
+	}
+	unknownValidationTypePolicy, err := kubetemplaterwebhook.ParseUnknownValidationTypePolicy(*unknownValidationTypes)
+	if err != nil {
+		fmt.Fprintf(stderr, "invalid -unknown-validation-types: %v\n", err)
+		return exitUsage
+	}
+
+	// The validators log every decision, which is noise here
+	ctx := logf.IntoContext(context.Background(), logr.Discard())
+
+	policies, err := decodeFile[kubetemplateriov1alpha1.KubeTemplatePolicy](*policyPath, "KubeTemplatePolicy")
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+	if len(policies) != 1 {
+		fmt.Fprintf(stderr, "%s must hold exactly one KubeTemplatePolicy, it holds %d\n", *policyPath, len(policies))
+		return exitUsage
+	}
+	policy := &policies[0]
+	if _, err := (&kubetemplaterwebhook.KubeTemplatePolicyValidator{}).ValidateCreate(ctx, policy); err != nil {
+		fmt.Fprintf(stderr, "policy %s is invalid: %v\n", policy.Name, err)
+		return exitUsage
+	}
+
+	templates, err := decodeFile[kubetemplateriov1alpha1.KubeTemplate](*templatePath, "KubeTemplate")
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+	if len(templates) == 0 {
+		fmt.Fprintf(stderr, "%s holds no KubeTemplate\n", *templatePath)
+		return exitUsage
+	}
+
+	validator := &kubetemplaterwebhook.KubeTemplateValidator{
+		AllowKubeTemplaterResources: *allowKubeTemplaterResources,
+		UnknownValidationTypes:      unknownValidationTypePolicy,
+	}
+	code := 0
+	for i := range templates {
+		kubeTemplate := &templates[i]
+		// Templates are governed by the policy of their namespace, which a manifest often leaves to kubectl
+		if kubeTemplate.Namespace == "" {
+			kubeTemplate.Namespace = policy.Spec.SourceNamespace
+		}
+		if kubeTemplate.Namespace != policy.Spec.SourceNamespace {
+			fmt.Fprintf(stdout, "%s/%s: invalid: policy %s governs namespace %s, not %s\n",
+				kubeTemplate.Namespace, kubeTemplate.Name, policy.Name, policy.Spec.SourceNamespace, kubeTemplate.Namespace)
+			code = exitInvalid
+			continue
+		}
+
+		warnings, err := validator.ValidateAgainstPolicy(ctx, kubeTemplate, policy)
+		for _, warning := range warnings {
+			fmt.Fprintf(stderr, "Warning: %s/%s: %s\n", kubeTemplate.Namespace, kubeTemplate.Name, warning)
+		}
+		if err != nil {
+			fmt.Fprintf(stdout, "%s/%s: invalid: %v\n", kubeTemplate.Namespace, kubeTemplate.Name, err)
+			code = exitInvalid
+			continue
+		}
+		fmt.Fprintf(stdout, "%s/%s: valid\n", kubeTemplate.Namespace, kubeTemplate.Name)
+	}
+	return code
+}
+
+// decodeFile decodes the YAML or JSON documents of the file at path, which must all be of kind,
+// skipping empty ones
+func decodeFile[T any](path, kind string) ([]T, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close() //nolint:errcheck
+
+	var objs []T
+	decoder := utilyaml.NewYAMLOrJSONDecoder(file, 4096)
+	for {
+		var doc map[string]any
+		if err := decoder.Decode(&doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				return objs, nil
+			}
+			return nil, fmt.Errorf("failed to decode %s: %w", path, err)
+		}
+		if len(doc) == 0 {
+			continue
+		}
+		if doc["kind"] != kind {
+			return nil, fmt.Errorf("%s: document %d is a %v, not a %s", path, len(objs)+1, doc["kind"], kind)
+		}
+
+		// Round trip through JSON, so template objects decode into their RawExtensions
+		data, err := json.Marshal(doc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", path, err)
+		}
+		var obj T
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", path, err)
+		}
+		objs = append(objs, obj)
+	}
+}
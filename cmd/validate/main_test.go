@@ -0,0 +1,133 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const testPolicy = `apiVersion: kubetemplater.io/v1alpha1
+kind: KubeTemplatePolicy
+metadata:
+  name: team-policy
+  namespace: kubetemplater-system
+spec:
+  sourceNamespace: team-a
+  validationRules:
+    - kind: ConfigMap
+      version: v1
+      targetNamespaces: [team-a]
+      fieldValidations:
+        - name: team-label
+          fieldPath: metadata.labels.team
+          type: required
+`
+
+// configMapTemplate is a KubeTemplate named name in namespace (none if empty) holding a
+// ConfigMap with the given labels
+func configMapTemplate(name, namespace, labels string) string {
+	meta := "  name: " + name + "\n"
+	if namespace != "" {
+		meta += "  namespace: " + namespace + "\n"
+	}
+	return `apiVersion: kubetemplater.io/v1alpha1
+kind: KubeTemplate
+metadata:
+` + meta + `spec:
+  templates:
+    - object:
+        apiVersion: v1
+        kind: ConfigMap
+        metadata:
+          name: app-config
+          namespace: team-a
+          labels: ` + labels + `
+`
+}
+
+var _ = Describe("run", func() {
+	var dir string
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+	})
+
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		Expect(os.WriteFile(path, []byte(content), 0o600)).To(Succeed())
+		return path
+	}
+
+	DescribeTable("validating templates against a policy",
+		func(policy, templates string, expectedCode int, expectedStdout, expectedStderr []string) {
+			var stdout, stderr bytes.Buffer
+			code := run([]string{"-policy", write("policy.yaml", policy), "-template", write("templates.yaml", templates)}, &stdout, &stderr)
+
+			Expect(code).To(Equal(expectedCode), stderr.String())
+			for _, line := range expectedStdout {
+				Expect(stdout.String()).To(ContainSubstring(line))
+			}
+			for _, line := range expectedStderr {
+				Expect(stderr.String()).To(ContainSubstring(line))
+			}
+		},
+		Entry("a valid template", testPolicy, configMapTemplate("app", "team-a", "{team: a}"),
+			0, []string{"team-a/app: valid"}, nil),
+		Entry("an invalid template", testPolicy, configMapTemplate("app", "team-a", "{}"),
+			exitInvalid, []string{"team-a/app: invalid: template[0]: fieldValidation (team-label)"}, nil),
+		Entry("several documents, one of them invalid", testPolicy,
+			configMapTemplate("app", "team-a", "{team: a}")+"---\n"+configMapTemplate("other", "team-a", "{}")+"---\n",
+			exitInvalid, []string{"team-a/app: valid", "team-a/other: invalid"}, nil),
+		Entry("a template without a namespace", testPolicy, configMapTemplate("app", "", "{team: a}"),
+			0, []string{"team-a/app: valid"}, nil),
+		Entry("a template in a namespace the policy doesn't govern", testPolicy, configMapTemplate("app", "team-b", "{team: a}"),
+			exitInvalid, []string{"team-b/app: invalid: policy team-policy governs namespace team-a, not team-b"}, nil),
+		Entry("a policy file holding a template", configMapTemplate("app", "team-a", "{}"), configMapTemplate("app", "team-a", "{}"),
+			exitUsage, nil, []string{"document 1 is a KubeTemplate, not a KubeTemplatePolicy"}),
+		Entry("a template file holding a policy", testPolicy, testPolicy,
+			exitUsage, nil, []string{"document 1 is a KubeTemplatePolicy, not a KubeTemplate"}),
+		Entry("an invalid policy", testPolicy+"      rule: \"object.spec ==\"\n", configMapTemplate("app", "team-a", "{team: a}"),
+			exitUsage, nil, []string{"policy team-policy is invalid"}),
+		Entry("a template file without templates", testPolicy, "---\n",
+			exitUsage, nil, []string{"holds no KubeTemplate"}),
+	)
+
+	It("should require both files", func() {
+		var stdout, stderr bytes.Buffer
+		Expect(run([]string{"-policy", write("policy.yaml", testPolicy)}, &stdout, &stderr)).To(Equal(exitUsage))
+		Expect(stderr.String()).To(ContainSubstring("both -policy and -template are required"))
+	})
+
+	It("should report a file that can't be read", func() {
+		var stdout, stderr bytes.Buffer
+		code := run([]string{"-policy", filepath.Join(dir, "missing.yaml"), "-template", write("templates.yaml", configMapTemplate("app", "team-a", "{}"))}, &stdout, &stderr)
+		Expect(code).To(Equal(exitUsage))
+		Expect(stderr.String()).To(ContainSubstring("missing.yaml"))
+	})
+
+	It("should reject an unknown -unknown-validation-types", func() {
+		var stdout, stderr bytes.Buffer
+		code := run([]string{"-policy", write("policy.yaml", testPolicy), "-template", write("templates.yaml", testPolicy), "-unknown-validation-types", "maybe"}, &stdout, &stderr)
+		Expect(code).To(Equal(exitUsage))
+		Expect(stderr.String()).To(ContainSubstring("invalid -unknown-validation-types"))
+	})
+})
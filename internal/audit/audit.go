@@ -0,0 +1,146 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package audit writes a durable, structured record of every admission and apply decision,
+// separate from the operational logs.
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Decision is the outcome recorded for a resource
+type Decision string
+
+const (
+	// DecisionAdmitted means the webhook accepted the KubeTemplate containing the resource
+	DecisionAdmitted Decision = "Admitted"
+	// DecisionDenied means the webhook or the worker refused the resource
+	DecisionDenied Decision = "Denied"
+	// DecisionApplied means the worker applied the resource
+	DecisionApplied Decision = "Applied"
+	// DecisionFailed means the worker tried to apply the resource and the API server refused it
+	DecisionFailed Decision = "Failed"
+	// DecisionPruned means the worker deleted a resource that was removed from its template
+	DecisionPruned Decision = "Pruned"
+)
+
+// Sources of audit records
+const (
+	SourceWebhook = "webhook"
+	SourceWorker  = "worker"
+)
+
+// Record is one audit log entry, written as a single JSON line
+type Record struct {
+	Time   time.Time `json:"time"`
+	Source string    `json:"source"`
+	// User is the user who submitted the KubeTemplate (webhook records only)
+	User string `json:"user,omitempty"`
+	// Template is the namespace/name of the KubeTemplate the resource belongs to
+	Template   string   `json:"template"`
+	APIVersion string   `json:"apiVersion,omitempty"`
+	Kind       string   `json:"kind,omitempty"`
+	Namespace  string   `json:"namespace,omitempty"`
+	Name       string   `json:"name,omitempty"`
+	Decision   Decision `json:"decision"`
+	// Action is what the apply did to the resource: Created, Updated or Unchanged
+	Action string `json:"action,omitempty"`
+	Reason string `json:"reason,omitempty"`
+	// DryRun marks admission decisions on dry-run requests, whose KubeTemplate was not stored
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// NewRecord returns a record of a decision on obj, a resource of the KubeTemplate kubeTemplate.
+// obj may be nil when the decision is not about a single resource.
+func NewRecord(source string, kubeTemplate client.Object, obj *unstructured.Unstructured, decision Decision) Record {
+	record := Record{
+		Source:   source,
+		Template: kubeTemplate.GetNamespace() + "/" + kubeTemplate.GetName(),
+		Decision: decision,
+	}
+	if obj != nil {
+		record.APIVersion = obj.GetAPIVersion()
+		record.Kind = obj.GetKind()
+		record.Namespace = obj.GetNamespace()
+		record.Name = obj.GetName()
+	}
+	return record
+}
+
+// Logger writes audit records to a sink. A nil Logger discards every record.
+type Logger struct {
+	mu  sync.Mutex
+	w   io.Writer
+	now func() time.Time
+}
+
+// NewLogger returns a Logger writing to w
+func NewLogger(w io.Writer) *Logger {
+	return &Logger{w: w, now: time.Now}
+}
+
+// Open returns a Logger for the configured sink: "stdout", "stderr" or the path of a file
+// records are appended to. An empty sink disables audit logging and returns a nil Logger.
+func Open(sink string) (*Logger, error) {
+	switch sink {
+	case "":
+		return nil, nil
+	case "stdout":
+		return NewLogger(os.Stdout), nil
+	case "stderr":
+		return NewLogger(os.Stderr), nil
+	}
+
+	file, err := os.OpenFile(sink, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log %s: %w", sink, err)
+	}
+	return NewLogger(file), nil
+}
+
+// Record writes record, stamping it with the current time if it has none. Write errors are
+// reported in the operational log, since a decision must not fail because it can't be audited.
+func (l *Logger) Record(record Record) {
+	if l == nil {
+		return
+	}
+	if record.Time.IsZero() {
+		record.Time = l.now()
+	}
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		logf.Log.WithName("audit").Error(err, "Failed to encode audit record", "template", record.Template)
+		return
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, err := l.w.Write(line); err != nil {
+		logf.Log.WithName("audit").Error(err, "Failed to write audit record", "template", record.Template)
+	}
+}
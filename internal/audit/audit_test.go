@@ -0,0 +1,93 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+)
+
+var _ = Describe("Logger", func() {
+	kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "team-a"},
+	}
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("ConfigMap")
+	obj.SetNamespace("team-a")
+	obj.SetName("app-config")
+
+	It("should write each record as a JSON line", func() {
+		var buf bytes.Buffer
+		logger := NewLogger(&buf)
+		logger.now = func() time.Time { return time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC) }
+
+		record := NewRecord(SourceWorker, kubeTemplate, obj, DecisionApplied)
+		record.Action = "Created"
+		logger.Record(record)
+		logger.Record(NewRecord(SourceWebhook, kubeTemplate, nil, DecisionDenied))
+
+		lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+		Expect(lines).To(HaveLen(2))
+		Expect(string(lines[0])).To(MatchJSON(`{
+			"time": "2025-06-01T12:00:00Z",
+			"source": "worker",
+			"template": "team-a/app",
+			"apiVersion": "v1",
+			"kind": "ConfigMap",
+			"namespace": "team-a",
+			"name": "app-config",
+			"decision": "Applied",
+			"action": "Created"
+		}`))
+
+		var denied Record
+		Expect(json.Unmarshal(lines[1], &denied)).To(Succeed())
+		Expect(denied.Decision).To(Equal(DecisionDenied))
+		Expect(denied.Kind).To(BeEmpty())
+	})
+
+	It("should discard records when disabled", func() {
+		logger, err := Open("")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(logger).To(BeNil())
+		logger.Record(NewRecord(SourceWorker, kubeTemplate, obj, DecisionApplied))
+	})
+
+	It("should append records to a file sink", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "audit.log")
+		for i := 0; i < 2; i++ {
+			logger, err := Open(path)
+			Expect(err).NotTo(HaveOccurred())
+			logger.Record(NewRecord(SourceWorker, kubeTemplate, obj, DecisionApplied))
+		}
+
+		data, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bytes.Count(data, []byte("\n"))).To(Equal(2))
+	})
+})
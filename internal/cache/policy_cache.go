@@ -18,13 +18,20 @@ package cache
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	policyutil "github.com/lpeano/KubeTemplater/internal/policy"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
 
 const (
@@ -32,12 +39,36 @@ const (
 	DefaultTTL = 5 * time.Minute
 )
 
+// ErrPolicyNotFound is returned (wrapped) when no KubeTemplatePolicy covers a source namespace
+var ErrPolicyNotFound = errors.New("no KubeTemplatePolicy found")
+
+// DuplicateResolution decides which policy applies when several cover the same source namespace
+type DuplicateResolution string
+
+const (
+	// DuplicateResolutionError fails every lookup for the namespace until the duplicates are removed
+	DuplicateResolutionError DuplicateResolution = "error"
+	// DuplicateResolutionMostRecent picks the policy created last, the earlier name on a tie
+	DuplicateResolutionMostRecent DuplicateResolution = "mostRecent"
+	// DuplicateResolutionAlphabetical picks the policy whose name sorts first
+	DuplicateResolutionAlphabetical DuplicateResolution = "alphabetical"
+	// DuplicateResolutionMerge combines the policies into one effective policy, see policy.Merge
+	DuplicateResolutionMerge DuplicateResolution = "merge"
+)
+
 // PolicyCache provides a thread-safe cache for KubeTemplatePolicies indexed by source namespace
 type PolicyCache struct {
 	mu      sync.RWMutex
 	entries map[string]*cacheEntry
 	ttl     time.Duration
 	client  client.Client
+
+	// fallback lists policies without the spec.sourceNamespace field index until synced is set
+	fallback client.Reader
+	synced   atomic.Bool
+
+	// resolution picks among duplicate policies (empty = DuplicateResolutionError)
+	resolution DuplicateResolution
 }
 
 type cacheEntry struct {
@@ -71,7 +102,7 @@ func (c *PolicyCache) Get(ctx context.Context, sourceNamespace string, operatorN
 		log.V(1).Info("Policy cache hit", "sourceNamespace", sourceNamespace)
 		// If policy is nil in cache, it means "not found" was cached
 		if entry.policy == nil {
-			return nil, fmt.Errorf("no KubeTemplatePolicy found for source namespace %s", sourceNamespace)
+			return nil, fmt.Errorf("%w for source namespace %s", ErrPolicyNotFound, sourceNamespace)
 		}
 		return entry.policy, nil
 	}
@@ -81,17 +112,145 @@ func (c *PolicyCache) Get(ctx context.Context, sourceNamespace string, operatorN
 	return c.refresh(ctx, sourceNamespace, operatorNamespace)
 }
 
+// SetFallbackReader makes the cache look policies up with reader, which should be the
+// manager's uncached API reader, until MarkSynced is called. Before the manager's cache has
+// synced, a list through the spec.sourceNamespace field index can come back empty and reject
+// templates whose policy exists; the fallback lists every policy of the operator namespace
+// and filters them instead.
+func (c *PolicyCache) SetFallbackReader(reader client.Reader) {
+	c.fallback = reader
+}
+
+// SetDuplicateResolution configures how a source namespace covered by several policies is
+// resolved. With DuplicateResolutionMostRecent or DuplicateResolutionAlphabetical the cache
+// picks one policy and logs an error on every lookup that finds the duplicates, instead of
+// failing every admission in the namespace. With DuplicateResolutionMerge it caches their merge,
+// and only fails lookups while the policies conflict.
+func (c *PolicyCache) SetDuplicateResolution(resolution DuplicateResolution) {
+	c.resolution = resolution
+}
+
+// MarkSynced switches lookups to the field index of the cached client
+func (c *PolicyCache) MarkSynced() {
+	c.synced.Store(true)
+}
+
+// CacheSyncWaiter is implemented by the manager's cache
+type CacheSyncWaiter interface {
+	WaitForCacheSync(ctx context.Context) bool
+}
+
+// SyncGate returns a runnable calling MarkSynced once informers have synced. It runs on every
+// replica, since webhooks look policies up whether or not their replica is the leader.
+func (c *PolicyCache) SyncGate(informers CacheSyncWaiter) manager.Runnable {
+	return &syncGate{cache: c, informers: informers}
+}
+
+type syncGate struct {
+	cache     *PolicyCache
+	informers CacheSyncWaiter
+}
+
+// Start implements manager.Runnable
+func (g *syncGate) Start(ctx context.Context) error {
+	if g.informers.WaitForCacheSync(ctx) {
+		logf.FromContext(ctx).Info("Policy cache switched to the field index, the manager's cache has synced")
+		g.cache.MarkSynced()
+	}
+	return nil
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable
+func (g *syncGate) NeedLeaderElection() bool {
+	return false
+}
+
+// Janitor returns a runnable evicting expired entries every interval, so namespaces that are
+// never looked up again don't keep their entries for the life of the process. A non-positive
+// interval sweeps once per TTL. It runs on every replica, like SyncGate.
+func (c *PolicyCache) Janitor(interval time.Duration) manager.Runnable {
+	if interval <= 0 {
+		interval = c.ttl
+	}
+	return &janitor{cache: c, interval: interval}
+}
+
+type janitor struct {
+	cache    *PolicyCache
+	interval time.Duration
+}
+
+// Start implements manager.Runnable
+func (j *janitor) Start(ctx context.Context) error {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			if evicted := j.cache.evictExpired(now); evicted > 0 {
+				logf.FromContext(ctx).V(1).Info("Evicted expired policy cache entries", "count", evicted, "remaining", j.cache.Size())
+			}
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable
+func (j *janitor) NeedLeaderElection() bool {
+	return false
+}
+
+// EvictExpired removes every entry whose TTL has passed and returns how many were removed
+func (c *PolicyCache) EvictExpired() int {
+	return c.evictExpired(time.Now())
+}
+
+func (c *PolicyCache) evictExpired(now time.Time) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	evicted := 0
+	for key, entry := range c.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(c.entries, key)
+			evicted++
+		}
+	}
+	return evicted
+}
+
 // refresh fetches the policy from the API server and updates the cache
 func (c *PolicyCache) refresh(ctx context.Context, sourceNamespace string, operatorNamespace string) (*kubetemplateriov1alpha1.KubeTemplatePolicy, error) {
-	var policies kubetemplateriov1alpha1.KubeTemplatePolicyList
-	if err := c.client.List(ctx, &policies,
-		client.InNamespace(operatorNamespace),
-		client.MatchingFields{"spec.sourceNamespace": sourceNamespace}); err != nil {
-		return nil, fmt.Errorf("failed to list KubeTemplatePolicies: %w", err)
+	policies, err := c.list(ctx, sourceNamespace, operatorNamespace)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(policies.Items) > 1 && c.resolution == DuplicateResolutionMerge {
+		merged, err := policyutil.Merge(policies.Items)
+		if err != nil {
+			return nil, fmt.Errorf("failed to merge the KubeTemplatePolicies of source namespace %s: %w", sourceNamespace, err)
+		}
+		logf.FromContext(ctx).V(1).Info("Merged KubeTemplatePolicies", "sourceNamespace", sourceNamespace, "policy", merged.Name)
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return c.store(sourceNamespace, merged), nil
 	}
 
 	if len(policies.Items) > 1 {
-		return nil, fmt.Errorf("multiple KubeTemplatePolicies found for source namespace %s", sourceNamespace)
+		if !c.resolvesDuplicates() {
+			return nil, fmt.Errorf("multiple KubeTemplatePolicies found for source namespace %s", sourceNamespace)
+		}
+		names := make([]string, len(policies.Items))
+		for i := range policies.Items {
+			names[i] = policies.Items[i].Name
+		}
+		slices.SortFunc(policies.Items, c.preferred)
+		logf.FromContext(ctx).Error(fmt.Errorf("multiple KubeTemplatePolicies found for source namespace %s", sourceNamespace),
+			"Ambiguous policy configuration, picked one of the policies; remove the others",
+			"policies", names, "picked", policies.Items[0].Name, "resolution", c.resolution)
 	}
 
 	if len(policies.Items) == 0 {
@@ -102,39 +261,69 @@ func (c *PolicyCache) refresh(ctx context.Context, sourceNamespace string, opera
 			expiresAt: time.Now().Add(c.ttl),
 		}
 		c.mu.Unlock()
-		return nil, fmt.Errorf("no KubeTemplatePolicy found for source namespace %s", sourceNamespace)
+		return nil, fmt.Errorf("%w for source namespace %s", ErrPolicyNotFound, sourceNamespace)
 	}
 
-	policy := &policies.Items[0]
-
-	// Update cache
+	// The listed policy may be older than one the controller stored meanwhile
 	c.mu.Lock()
-	c.entries[sourceNamespace] = &cacheEntry{
-		policy:    policy,
-		expiresAt: time.Now().Add(c.ttl),
-	}
-	c.mu.Unlock()
-
-	return policy, nil
+	defer c.mu.Unlock()
+	return c.store(sourceNamespace, &policies.Items[0]), nil
 }
 
-// Set explicitly sets a policy in the cache (used by the cache controller on watch events)
-func (c *PolicyCache) Set(sourceNamespace string, policy *kubetemplateriov1alpha1.KubeTemplatePolicy) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+// resolvesDuplicates reports whether duplicate policies are resolved rather than an error
+func (c *PolicyCache) resolvesDuplicates() bool {
+	return c.resolution == DuplicateResolutionMostRecent || c.resolution == DuplicateResolutionAlphabetical ||
+		c.resolution == DuplicateResolutionMerge
+}
 
-	c.entries[sourceNamespace] = &cacheEntry{
-		policy:    policy,
-		expiresAt: time.Now().Add(c.ttl),
+// preferred orders policies by the configured resolution, the policy to pick first
+func (c *PolicyCache) preferred(a, b kubetemplateriov1alpha1.KubeTemplatePolicy) int {
+	if c.resolution == DuplicateResolutionMostRecent {
+		if order := b.CreationTimestamp.Compare(a.CreationTimestamp.Time); order != 0 {
+			return order
+		}
 	}
+	return strings.Compare(a.Name, b.Name)
 }
 
-// Delete removes a policy from the cache
-func (c *PolicyCache) Delete(sourceNamespace string) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+// list returns the policies of operatorNamespace covering sourceNamespace. Unless duplicates
+// are resolved it returns at most two, which are enough to detect an ambiguous configuration.
+func (c *PolicyCache) list(ctx context.Context, sourceNamespace string, operatorNamespace string) (*kubetemplateriov1alpha1.KubeTemplatePolicyList, error) {
+	limit := 2
+	if c.resolvesDuplicates() {
+		limit = 0
+	}
 
-	delete(c.entries, sourceNamespace)
+	if c.fallback == nil || c.synced.Load() {
+		var policies kubetemplateriov1alpha1.KubeTemplatePolicyList
+		err := c.client.List(ctx, &policies,
+			client.InNamespace(operatorNamespace),
+			client.MatchingFields{"spec.sourceNamespace": sourceNamespace},
+			client.Limit(int64(limit)))
+		if err == nil {
+			return &policies, nil
+		}
+		if c.fallback == nil {
+			return nil, fmt.Errorf("failed to list KubeTemplatePolicies: %w", err)
+		}
+		logf.FromContext(ctx).Info("Failed to list KubeTemplatePolicies by source namespace, listing all of them instead",
+			"sourceNamespace", sourceNamespace, "reason", err.Error())
+	}
+
+	var all kubetemplateriov1alpha1.KubeTemplatePolicyList
+	if err := c.fallback.List(ctx, &all, client.InNamespace(operatorNamespace)); err != nil {
+		return nil, fmt.Errorf("failed to list KubeTemplatePolicies: %w", err)
+	}
+	policies := &kubetemplateriov1alpha1.KubeTemplatePolicyList{}
+	for i := range all.Items {
+		if all.Items[i].Spec.SourceNamespace == sourceNamespace {
+			policies.Items = append(policies.Items, all.Items[i])
+			if len(policies.Items) == limit {
+				break
+			}
+		}
+	}
+	return policies, nil
 }
 
 // Clear removes all entries from the cache
@@ -161,13 +350,48 @@ func (c *PolicyCache) Invalidate(sourceNamespace string) {
 	delete(c.entries, sourceNamespace)
 }
 
-// Update immediately updates the cache with a new or modified policy
-func (c *PolicyCache) Update(policy *kubetemplateriov1alpha1.KubeTemplatePolicy) {
+// Update immediately updates the cache with a new or modified policy. An update older than
+// the cached version of the same policy is ignored; it reports whether the update was stored.
+// When duplicates are resolved, the update drops the entry instead, so the next lookup
+// resolves the duplicates again: the updated policy alone may not be the one picked.
+func (c *PolicyCache) Update(policy *kubetemplateriov1alpha1.KubeTemplatePolicy) bool {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	c.entries[policy.Spec.SourceNamespace] = &cacheEntry{
+	if c.resolvesDuplicates() {
+		delete(c.entries, policy.Spec.SourceNamespace)
+		return true
+	}
+	return c.store(policy.Spec.SourceNamespace, policy) == policy
+}
+
+// store caches policy for sourceNamespace unless the entry already holds a newer version of
+// the same policy, and returns the policy that ends up cached. Callers must hold c.mu.
+func (c *PolicyCache) store(sourceNamespace string, policy *kubetemplateriov1alpha1.KubeTemplatePolicy) *kubetemplateriov1alpha1.KubeTemplatePolicy {
+	if entry, found := c.entries[sourceNamespace]; found && entry.policy != nil &&
+		entry.policy.Namespace == policy.Namespace && entry.policy.Name == policy.Name &&
+		isOlder(policy.ResourceVersion, entry.policy.ResourceVersion) {
+		return entry.policy
+	}
+
+	c.entries[sourceNamespace] = &cacheEntry{
 		policy:    policy,
 		expiresAt: time.Now().Add(c.ttl),
 	}
+	return policy
+}
+
+// isOlder reports whether resourceVersion candidate precedes current. Resource versions are
+// opaque, but the API server derives them from a monotonic revision, so they are compared as
+// integers when both parse; otherwise the candidate is not considered older.
+func isOlder(candidate, current string) bool {
+	candidateRV, err := strconv.ParseUint(candidate, 10, 64)
+	if err != nil {
+		return false
+	}
+	currentRV, err := strconv.ParseUint(current, 10, 64)
+	if err != nil {
+		return false
+	}
+	return candidateRV < currentRV
 }
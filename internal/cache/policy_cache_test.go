@@ -0,0 +1,451 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const operatorNamespace = "kubetemplater-system"
+
+var _ = Describe("PolicyCache", func() {
+	var (
+		ctx        context.Context
+		fakeClient client.Client
+		listLimits []int64
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		listLimits = nil
+
+		scheme := runtime.NewScheme()
+		Expect(kubetemplateriov1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithInterceptorFuncs(interceptor.Funcs{
+				List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+					listOpts := &client.ListOptions{}
+					listOpts.ApplyOptions(opts)
+					listLimits = append(listLimits, listOpts.Limit)
+					return c.List(ctx, list, opts...)
+				},
+			}).
+			WithIndex(&kubetemplateriov1alpha1.KubeTemplatePolicy{}, "spec.sourceNamespace", func(obj client.Object) []string {
+				return []string{obj.(*kubetemplateriov1alpha1.KubeTemplatePolicy).Spec.SourceNamespace}
+			}).
+			Build()
+	})
+
+	createPolicies := func(count int) {
+		for i := 0; i < count; i++ {
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("policy-%d", i), Namespace: operatorNamespace},
+				Spec:       kubetemplateriov1alpha1.KubeTemplatePolicySpec{SourceNamespace: "default"},
+			}
+			Expect(fakeClient.Create(ctx, policy)).To(Succeed())
+		}
+	}
+
+	It("should limit the policy List to two items", func() {
+		createPolicies(1)
+
+		policy, err := NewPolicyCache(fakeClient, 0).Get(ctx, "default", operatorNamespace)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(policy.Name).To(Equal("policy-0"))
+		Expect(listLimits).To(Equal([]int64{2}))
+	})
+
+	It("should still detect multiple policies for the same source namespace", func() {
+		createPolicies(5)
+
+		_, err := NewPolicyCache(fakeClient, 0).Get(ctx, "default", operatorNamespace)
+		Expect(err).To(MatchError(ContainSubstring("multiple KubeTemplatePolicies found for source namespace default")))
+		Expect(listLimits).To(Equal([]int64{2}))
+	})
+
+	It("should report a missing policy as ErrPolicyNotFound", func() {
+		_, err := NewPolicyCache(fakeClient, 0).Get(ctx, "default", operatorNamespace)
+		Expect(err).To(MatchError(ErrPolicyNotFound))
+	})
+
+	Context("When several policies cover a source namespace", func() {
+		base := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+		// createPolicyAt creates a policy for the default namespace created at base plus offset
+		createPolicyAt := func(name string, offset time.Duration) {
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              name,
+					Namespace:         operatorNamespace,
+					CreationTimestamp: metav1.NewTime(base.Add(offset)),
+				},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{SourceNamespace: "default"},
+			}
+			Expect(fakeClient.Create(ctx, policy)).To(Succeed())
+		}
+
+		BeforeEach(func() {
+			createPolicyAt("team-b", time.Hour)
+			createPolicyAt("team-c", 2*time.Hour)
+			createPolicyAt("team-a", 0)
+		})
+
+		It("should fail the lookup with the error resolution", func() {
+			policyCache := NewPolicyCache(fakeClient, 0)
+			policyCache.SetDuplicateResolution(DuplicateResolutionError)
+
+			_, err := policyCache.Get(ctx, "default", operatorNamespace)
+			Expect(err).To(MatchError(ContainSubstring("multiple KubeTemplatePolicies found for source namespace default")))
+		})
+
+		It("should pick the policy created last with the mostRecent resolution", func() {
+			policyCache := NewPolicyCache(fakeClient, 0)
+			policyCache.SetDuplicateResolution(DuplicateResolutionMostRecent)
+
+			policy, err := policyCache.Get(ctx, "default", operatorNamespace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(policy.Name).To(Equal("team-c"))
+			Expect(listLimits).To(Equal([]int64{0}))
+		})
+
+		It("should break a creation time tie by name with the mostRecent resolution", func() {
+			createPolicyAt("team-0", 2*time.Hour)
+			policyCache := NewPolicyCache(fakeClient, 0)
+			policyCache.SetDuplicateResolution(DuplicateResolutionMostRecent)
+
+			policy, err := policyCache.Get(ctx, "default", operatorNamespace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(policy.Name).To(Equal("team-0"))
+		})
+
+		It("should pick the policy whose name sorts first with the alphabetical resolution", func() {
+			policyCache := NewPolicyCache(fakeClient, 0)
+			policyCache.SetDuplicateResolution(DuplicateResolutionAlphabetical)
+
+			policy, err := policyCache.Get(ctx, "default", operatorNamespace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(policy.Name).To(Equal("team-a"))
+		})
+
+		It("should resolve the duplicates again after another of them is updated", func() {
+			policyCache := NewPolicyCache(fakeClient, 0)
+			policyCache.SetDuplicateResolution(DuplicateResolutionAlphabetical)
+			policy, err := policyCache.Get(ctx, "default", operatorNamespace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(policy.Name).To(Equal("team-a"))
+
+			var other kubetemplateriov1alpha1.KubeTemplatePolicy
+			Expect(fakeClient.Get(ctx, client.ObjectKey{Namespace: operatorNamespace, Name: "team-b"}, &other)).To(Succeed())
+			Expect(policyCache.Update(&other)).To(BeTrue())
+
+			policy, err = policyCache.Get(ctx, "default", operatorNamespace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(policy.Name).To(Equal("team-a"))
+		})
+
+		It("should not cache the losing policy when it is updated without an entry", func() {
+			for _, resolution := range []DuplicateResolution{DuplicateResolutionMostRecent, DuplicateResolutionAlphabetical} {
+				policyCache := NewPolicyCache(fakeClient, time.Minute)
+				policyCache.SetDuplicateResolution(resolution)
+
+				// team-b loses under both resolutions; a reconcile of it finds no entry
+				var loser kubetemplateriov1alpha1.KubeTemplatePolicy
+				Expect(fakeClient.Get(ctx, client.ObjectKey{Namespace: operatorNamespace, Name: "team-b"}, &loser)).To(Succeed())
+				Expect(policyCache.Update(&loser)).To(BeTrue())
+				Expect(policyCache.Size()).To(BeZero())
+
+				policy, err := policyCache.Get(ctx, "default", operatorNamespace)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(policy.Name).NotTo(Equal("team-b"), string(resolution))
+			}
+		})
+
+		It("should merge the policies with the merge resolution", func() {
+			for name, namespaces := range map[string][]string{"team-a": {"web"}, "team-b": {"batch"}} {
+				var existing kubetemplateriov1alpha1.KubeTemplatePolicy
+				Expect(fakeClient.Get(ctx, client.ObjectKey{Namespace: operatorNamespace, Name: name}, &existing)).To(Succeed())
+				existing.Spec.ValidationRules = []kubetemplateriov1alpha1.ValidationRule{
+					{Kind: "Deployment", Group: "apps", Version: "v1", TargetNamespaces: namespaces},
+				}
+				Expect(fakeClient.Update(ctx, &existing)).To(Succeed())
+			}
+			policyCache := NewPolicyCache(fakeClient, time.Minute)
+			policyCache.SetDuplicateResolution(DuplicateResolutionMerge)
+
+			policy, err := policyCache.Get(ctx, "default", operatorNamespace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(policy.Name).To(Equal("team-a+team-b+team-c"))
+			Expect(policy.Spec.ValidationRules).To(HaveLen(1))
+			Expect(policy.Spec.ValidationRules[0].TargetNamespaces).To(Equal([]string{"web", "batch"}))
+			Expect(listLimits).To(Equal([]int64{0}))
+
+			// An update of one of the merged policies drops the merge
+			var updated kubetemplateriov1alpha1.KubeTemplatePolicy
+			Expect(fakeClient.Get(ctx, client.ObjectKey{Namespace: operatorNamespace, Name: "team-c"}, &updated)).To(Succeed())
+			updated.Spec.ServiceAccountName = "deployer"
+			Expect(fakeClient.Update(ctx, &updated)).To(Succeed())
+			Expect(policyCache.Update(&updated)).To(BeTrue())
+
+			policy, err = policyCache.Get(ctx, "default", operatorNamespace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(policy.Spec.ServiceAccountName).To(Equal("deployer"))
+		})
+
+		It("should keep merging after an update of one policy finds the entry gone", func() {
+			for name, namespaces := range map[string][]string{"team-a": {"web"}, "team-b": {"batch"}} {
+				var existing kubetemplateriov1alpha1.KubeTemplatePolicy
+				Expect(fakeClient.Get(ctx, client.ObjectKey{Namespace: operatorNamespace, Name: name}, &existing)).To(Succeed())
+				existing.Spec.ValidationRules = []kubetemplateriov1alpha1.ValidationRule{
+					{Kind: "Deployment", Group: "apps", Version: "v1", TargetNamespaces: namespaces},
+				}
+				Expect(fakeClient.Update(ctx, &existing)).To(Succeed())
+			}
+
+			dropEntry := map[string]func(*PolicyCache){
+				"evicted": func(policyCache *PolicyCache) {
+					Expect(policyCache.evictExpired(time.Now().Add(2 * time.Minute))).To(Equal(1))
+				},
+				"cleared": func(policyCache *PolicyCache) { policyCache.Clear() },
+			}
+			for how, drop := range dropEntry {
+				policyCache := NewPolicyCache(fakeClient, time.Minute)
+				policyCache.SetDuplicateResolution(DuplicateResolutionMerge)
+				_, err := policyCache.Get(ctx, "default", operatorNamespace)
+				Expect(err).NotTo(HaveOccurred())
+				drop(policyCache)
+
+				var edited kubetemplateriov1alpha1.KubeTemplatePolicy
+				Expect(fakeClient.Get(ctx, client.ObjectKey{Namespace: operatorNamespace, Name: "team-a"}, &edited)).To(Succeed())
+				edited.Spec.ValidationRules[0].TargetNamespaces = []string{"web", "api"}
+				Expect(fakeClient.Update(ctx, &edited)).To(Succeed())
+				Expect(policyCache.Update(&edited)).To(BeTrue())
+
+				// The rules of team-b are still enforced
+				policy, err := policyCache.Get(ctx, "default", operatorNamespace)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(policy.Name).To(Equal("team-a+team-b+team-c"), how)
+				Expect(policy.Spec.ValidationRules[0].TargetNamespaces).To(ConsistOf("web", "api", "batch"), how)
+			}
+		})
+
+		It("should fail the lookup while merged policies conflict", func() {
+			for name, account := range map[string]string{"team-a": "deployer", "team-b": "admin"} {
+				var existing kubetemplateriov1alpha1.KubeTemplatePolicy
+				Expect(fakeClient.Get(ctx, client.ObjectKey{Namespace: operatorNamespace, Name: name}, &existing)).To(Succeed())
+				existing.Spec.ServiceAccountName = account
+				Expect(fakeClient.Update(ctx, &existing)).To(Succeed())
+			}
+			policyCache := NewPolicyCache(fakeClient, time.Minute)
+			policyCache.SetDuplicateResolution(DuplicateResolutionMerge)
+
+			_, err := policyCache.Get(ctx, "default", operatorNamespace)
+			Expect(err).To(MatchError(ContainSubstring("failed to merge the KubeTemplatePolicies of source namespace default: policies set conflicting serviceAccountName")))
+		})
+	})
+
+	Context("When entries expire", func() {
+		lookupMissing := func(policyCache *PolicyCache, prefix string, count int) {
+			for i := 0; i < count; i++ {
+				_, err := policyCache.Get(ctx, fmt.Sprintf("%s-%d", prefix, i), operatorNamespace)
+				Expect(err).To(MatchError(ErrPolicyNotFound))
+			}
+		}
+
+		It("should evict only the entries whose TTL has passed", func() {
+			policyCache := NewPolicyCache(fakeClient, time.Minute)
+			lookupMissing(policyCache, "early", 20)
+			cutoff := time.Now()
+			time.Sleep(time.Millisecond)
+			lookupMissing(policyCache, "late", 30)
+			Expect(policyCache.Size()).To(Equal(50))
+
+			Expect(policyCache.evictExpired(time.Now())).To(Equal(0))
+			Expect(policyCache.Size()).To(Equal(50))
+
+			Expect(policyCache.evictExpired(cutoff.Add(time.Minute))).To(Equal(20))
+			Expect(policyCache.Size()).To(Equal(30))
+
+			Expect(policyCache.evictExpired(time.Now().Add(time.Minute))).To(Equal(30))
+			Expect(policyCache.Size()).To(BeZero())
+		})
+
+		It("should sweep periodically from the janitor until stopped", func() {
+			policyCache := NewPolicyCache(fakeClient, 10*time.Millisecond)
+			lookupMissing(policyCache, "ns", 25)
+			Expect(policyCache.Size()).To(Equal(25))
+
+			janitor := policyCache.Janitor(5 * time.Millisecond)
+			Expect(janitor.(manager.LeaderElectionRunnable).NeedLeaderElection()).To(BeFalse())
+
+			janitorCtx, cancel := context.WithCancel(ctx)
+			done := make(chan error)
+			go func() { done <- janitor.Start(janitorCtx) }()
+
+			Eventually(policyCache.Size).WithTimeout(time.Second).Should(BeZero())
+			cancel()
+			Eventually(done).Should(Receive(BeNil()))
+		})
+	})
+
+	Context("When updates arrive out of order", func() {
+		policyVersion := func(resourceVersion string, templates int32) *kubetemplateriov1alpha1.KubeTemplatePolicy {
+			return &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "policy-0", Namespace: operatorNamespace, ResourceVersion: resourceVersion},
+				Spec:       kubetemplateriov1alpha1.KubeTemplatePolicySpec{SourceNamespace: "default", MaxTemplates: templates},
+			}
+		}
+
+		It("should not let an older update clobber a newer cached policy", func() {
+			policyCache := NewPolicyCache(fakeClient, 0)
+			Expect(policyCache.Update(policyVersion("20", 5))).To(BeTrue())
+			Expect(policyCache.Update(policyVersion("9", 10))).To(BeFalse())
+
+			policy, err := policyCache.Get(ctx, "default", operatorNamespace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(policy.ResourceVersion).To(Equal("20"))
+			Expect(policy.Spec.MaxTemplates).To(Equal(int32(5)))
+		})
+
+		It("should store newer updates", func() {
+			policyCache := NewPolicyCache(fakeClient, 0)
+			Expect(policyCache.Update(policyVersion("9", 10))).To(BeTrue())
+			Expect(policyCache.Update(policyVersion("20", 5))).To(BeTrue())
+
+			policy, err := policyCache.Get(ctx, "default", operatorNamespace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(policy.ResourceVersion).To(Equal("20"))
+		})
+
+		It("should keep the newer cached policy when a refresh lists an older one", func() {
+			createPolicies(1)
+			policyCache := NewPolicyCache(fakeClient, time.Nanosecond)
+
+			var listed kubetemplateriov1alpha1.KubeTemplatePolicy
+			Expect(fakeClient.Get(ctx, client.ObjectKey{Namespace: operatorNamespace, Name: "policy-0"}, &listed)).To(Succeed())
+			newer := listed.DeepCopy()
+			newer.ResourceVersion = "999999"
+			Expect(policyCache.Update(newer)).To(BeTrue())
+
+			// The entry has expired, so Get lists the policy again and gets the older version
+			time.Sleep(time.Millisecond)
+			policy, err := policyCache.Get(ctx, "default", operatorNamespace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(policy.ResourceVersion).To(Equal("999999"))
+		})
+	})
+
+	Context("When the field index has not synced yet", func() {
+		var (
+			unsyncedClient client.Client
+			indexErr       error
+		)
+
+		BeforeEach(func() {
+			indexErr = nil
+			// An informer cache that hasn't synced answers indexed lists with nothing
+			unsyncedClient = interceptor.NewClient(fakeClient.(client.WithWatch), interceptor.Funcs{
+				List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+					if indexErr != nil {
+						return indexErr
+					}
+					return nil
+				},
+			})
+		})
+
+		It("should list every policy and filter them until the cache is marked synced", func() {
+			createPolicies(1)
+			listLimits = nil
+			policyCache := NewPolicyCache(unsyncedClient, time.Nanosecond)
+			policyCache.SetFallbackReader(fakeClient)
+
+			policy, err := policyCache.Get(ctx, "default", operatorNamespace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(policy.Name).To(Equal("policy-0"))
+			Expect(listLimits).To(Equal([]int64{0}))
+
+			_, err = policyCache.Get(ctx, "other", operatorNamespace)
+			Expect(err).To(MatchError(ErrPolicyNotFound))
+
+			policyCache.MarkSynced()
+			time.Sleep(time.Millisecond)
+			_, err = policyCache.Get(ctx, "default", operatorNamespace)
+			Expect(err).To(MatchError(ErrPolicyNotFound))
+		})
+
+		It("should detect multiple policies while falling back", func() {
+			createPolicies(3)
+			policyCache := NewPolicyCache(unsyncedClient, 0)
+			policyCache.SetFallbackReader(fakeClient)
+
+			_, err := policyCache.Get(ctx, "default", operatorNamespace)
+			Expect(err).To(MatchError(ContainSubstring("multiple KubeTemplatePolicies found for source namespace default")))
+		})
+
+		It("should fall back when the indexed list fails", func() {
+			createPolicies(1)
+			indexErr = fmt.Errorf("index with name field:spec.sourceNamespace does not exist")
+			policyCache := NewPolicyCache(unsyncedClient, 0)
+			policyCache.SetFallbackReader(fakeClient)
+			policyCache.MarkSynced()
+
+			policy, err := policyCache.Get(ctx, "default", operatorNamespace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(policy.Name).To(Equal("policy-0"))
+		})
+
+		It("should report the error of a failed indexed list without a fallback", func() {
+			indexErr = fmt.Errorf("index with name field:spec.sourceNamespace does not exist")
+
+			_, err := NewPolicyCache(unsyncedClient, 0).Get(ctx, "default", operatorNamespace)
+			Expect(err).To(MatchError(ContainSubstring("failed to list KubeTemplatePolicies: index with name")))
+		})
+
+		It("should mark the cache synced once the informers have synced", func() {
+			policyCache := NewPolicyCache(unsyncedClient, 0)
+			gate := policyCache.SyncGate(syncWaiter(true))
+			Expect(gate.(manager.LeaderElectionRunnable).NeedLeaderElection()).To(BeFalse())
+			Expect(gate.Start(ctx)).To(Succeed())
+			Expect(policyCache.synced.Load()).To(BeTrue())
+
+			policyCache = NewPolicyCache(unsyncedClient, 0)
+			Expect(policyCache.SyncGate(syncWaiter(false)).Start(ctx)).To(Succeed())
+			Expect(policyCache.synced.Load()).To(BeFalse())
+		})
+	})
+})
+
+// syncWaiter reports a fixed cache sync result
+type syncWaiter bool
+
+func (w syncWaiter) WaitForCacheSync(context.Context) bool {
+	return bool(w)
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package celcache caches compiled CEL programs, so that rules evaluated again and again are
+// compiled once instead of on every evaluation.
+package celcache
+
+import (
+	"sync"
+
+	"github.com/google/cel-go/cel"
+)
+
+// MaxEntries bounds the number of cached programs. Rules of edited policies stay cached until
+// the cache is full, at which point it is emptied and refilled with the rules still in use.
+const MaxEntries = 1000
+
+type key struct {
+	rule, varName string
+}
+
+// Cache holds the programs compiled for CEL rules, keyed by the rule and the name of the
+// variable it is evaluated with. It is safe for concurrent use. The zero value is an empty
+// cache, and a nil Cache compiles on every call.
+type Cache struct {
+	mu       sync.RWMutex
+	programs map[key]cel.Program
+}
+
+// New creates an empty Cache
+func New() *Cache {
+	return &Cache{}
+}
+
+// Program returns the program of rule evaluated with varName, calling compile on the first
+// request only. Compile errors are returned and not cached, so a failing rule is compiled again
+// the next time. The environment compile uses must only depend on rule and varName.
+func (c *Cache) Program(rule, varName string, compile func() (cel.Program, error)) (cel.Program, error) {
+	if c == nil {
+		return compile()
+	}
+	k := key{rule: rule, varName: varName}
+
+	c.mu.RLock()
+	prg, found := c.programs[k]
+	c.mu.RUnlock()
+	if found {
+		return prg, nil
+	}
+
+	// Compile outside the lock; concurrent first requests for a rule may both compile it
+	prg, err := compile()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.programs == nil || len(c.programs) >= MaxEntries {
+		c.programs = make(map[key]cel.Program)
+	}
+	c.programs[k] = prg
+	return prg, nil
+}
+
+// Len returns the number of cached programs
+func (c *Cache) Len() int {
+	if c == nil {
+		return 0
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.programs)
+}
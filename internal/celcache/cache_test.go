@@ -0,0 +1,130 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package celcache
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Cache", func() {
+	// compiler returns a compile function for rule that counts its calls
+	compiler := func(rule string, calls *int) func() (cel.Program, error) {
+		return func() (cel.Program, error) {
+			*calls++
+			env, err := cel.NewEnv(cel.Variable("value", cel.DynType))
+			if err != nil {
+				return nil, err
+			}
+			ast, issues := env.Compile(rule)
+			if issues != nil && issues.Err() != nil {
+				return nil, issues.Err()
+			}
+			return env.Program(ast)
+		}
+	}
+
+	It("should compile a rule once per variable", func() {
+		cache := New()
+		calls := 0
+
+		first, err := cache.Program("value > 1", "value", compiler("value > 1", &calls))
+		Expect(err).NotTo(HaveOccurred())
+		second, err := cache.Program("value > 1", "value", compiler("value > 1", &calls))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(second).To(BeIdenticalTo(first))
+		Expect(calls).To(Equal(1))
+
+		_, err = cache.Program("value > 1", "object", compiler("value > 1", &calls))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(calls).To(Equal(2))
+		Expect(cache.Len()).To(Equal(2))
+	})
+
+	It("should not cache compile errors", func() {
+		cache := New()
+		calls := 0
+
+		for i := 0; i < 2; i++ {
+			_, err := cache.Program("value >", "value", compiler("value >", &calls))
+			Expect(err).To(HaveOccurred())
+		}
+		Expect(calls).To(Equal(2))
+		Expect(cache.Len()).To(BeZero())
+	})
+
+	It("should start over when it is full", func() {
+		cache := New()
+		calls := 0
+		for i := 0; i < MaxEntries; i++ {
+			rule := fmt.Sprintf("value == %d", i)
+			_, err := cache.Program(rule, "value", compiler(rule, &calls))
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(cache.Len()).To(Equal(MaxEntries))
+
+		_, err := cache.Program("value == -1", "value", compiler("value == -1", &calls))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cache.Len()).To(Equal(1))
+	})
+
+	It("should compile on every call when nil", func() {
+		var cache *Cache
+		calls := 0
+		for i := 0; i < 2; i++ {
+			_, err := cache.Program("value > 1", "value", compiler("value > 1", &calls))
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(calls).To(Equal(2))
+	})
+
+	It("should be safe for concurrent use", func() {
+		var cache Cache
+		var wg sync.WaitGroup
+		errs := make(chan error, 20)
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				defer GinkgoRecover()
+				rule := fmt.Sprintf("value == %d", i%5)
+				calls := 0
+				prg, err := cache.Program(rule, "value", compiler(rule, &calls))
+				if err != nil {
+					errs <- err
+					return
+				}
+				out, _, err := prg.Eval(map[string]interface{}{"value": i % 5})
+				if err == nil && out.Value() != true {
+					err = errors.New(rule + " did not match")
+				}
+				if err != nil {
+					errs <- err
+				}
+			}(i)
+		}
+		wg.Wait()
+		close(errs)
+		Expect(errs).To(BeEmpty())
+		Expect(cache.Len()).To(Equal(5))
+	})
+})
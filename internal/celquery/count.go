@@ -0,0 +1,146 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package celquery provides the CEL functions object-level rules use to query cluster state.
+package celquery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"golang.org/x/time/rate"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CountResourcesFunction is the name of the CEL function counting resources of a kind
+const CountResourcesFunction = "countResources"
+
+const (
+	// DefaultQPS is the rate at which count queries are answered
+	DefaultQPS = 20
+	// DefaultBurst is the number of count queries answered without waiting
+	DefaultBurst = 40
+	// DefaultTimeout bounds a single count query, including the wait for the rate limiter
+	DefaultTimeout = 100 * time.Millisecond
+	// MaxCallsPerEvaluation limits how often a single rule evaluation may count resources
+	MaxCallsPerEvaluation = 10
+)
+
+// Counter answers countResources calls from a cached client. Queries are rate-limited
+// and time-limited so rules can't turn into an unbounded load on the cache.
+type Counter struct {
+	reader  client.Reader
+	limiter *rate.Limiter
+	timeout time.Duration
+}
+
+// NewCounter creates a Counter listing from reader, which should be the manager's cached client
+func NewCounter(reader client.Reader, qps float64, burst int, timeout time.Duration) *Counter {
+	return &Counter{
+		reader:  reader,
+		limiter: rate.NewLimiter(rate.Limit(qps), burst),
+		timeout: timeout,
+	}
+}
+
+// Count returns the number of resources of apiVersion and kind in namespace, not counting
+// self, so that a rule sees the same count when self is created and when it is updated.
+func (c *Counter) Count(ctx context.Context, apiVersion, kind, namespace string, self *unstructured.Unstructured) (int64, error) {
+	if namespace == "" {
+		return 0, fmt.Errorf("%s: namespace is required", CountResourcesFunction)
+	}
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return 0, fmt.Errorf("%s: invalid apiVersion %q: %w", CountResourcesFunction, apiVersion, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	if err := c.limiter.Wait(ctx); err != nil {
+		return 0, fmt.Errorf("%s: rate limit exceeded: %w", CountResourcesFunction, err)
+	}
+
+	// Only metadata is needed to count, which keeps the cached informers small
+	list := &metav1.PartialObjectMetadataList{}
+	list.SetGroupVersionKind(gv.WithKind(kind + "List"))
+	if err := c.reader.List(ctx, list, client.InNamespace(namespace)); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return 0, fmt.Errorf("%s: listing %s %s in %s timed out after %s", CountResourcesFunction, apiVersion, kind, namespace, c.timeout)
+		}
+		return 0, fmt.Errorf("%s: failed to list %s %s in %s: %w", CountResourcesFunction, apiVersion, kind, namespace, err)
+	}
+
+	count := int64(len(list.Items))
+	if self != nil && self.GetAPIVersion() == apiVersion && self.GetKind() == kind && self.GetNamespace() == namespace {
+		for _, item := range list.Items {
+			if item.Name == self.GetName() {
+				count--
+				break
+			}
+		}
+	}
+	return count, nil
+}
+
+// Function declares countResources(apiVersion, kind, namespace) for one rule evaluation of
+// self, answering calls with ctx as parent context. A nil Counter still declares the function,
+// so rules using it compile, but every call fails.
+func (c *Counter) Function(ctx context.Context, self *unstructured.Unstructured) cel.EnvOption {
+	calls := 0
+	return cel.Function(CountResourcesFunction,
+		cel.Overload("countResources_string_string_string",
+			[]*cel.Type{cel.StringType, cel.StringType, cel.StringType}, cel.IntType,
+			cel.FunctionBinding(func(args ...ref.Val) ref.Val {
+				if c == nil {
+					return types.NewErr("%s is not available", CountResourcesFunction)
+				}
+				calls++
+				if calls > MaxCallsPerEvaluation {
+					return types.NewErr("%s may be called at most %d times per rule", CountResourcesFunction, MaxCallsPerEvaluation)
+				}
+
+				var params [3]string
+				for i, arg := range args {
+					s, ok := arg.(types.String)
+					if !ok {
+						return types.MaybeNoSuchOverloadErr(arg)
+					}
+					params[i] = string(s)
+				}
+
+				count, err := c.Count(ctx, params[0], params[1], params[2], self)
+				if err != nil {
+					return types.WrapErr(err)
+				}
+				return types.Int(count)
+			}),
+		),
+	)
+}
+
+// Declarations declares countResources for type-checking rules that are not evaluated
+func Declarations() cel.EnvOption {
+	return (*Counter)(nil).Function(context.Background(), nil)
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package celquery
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newWidget(namespace, name string) *unstructured.Unstructured {
+	widget := &unstructured.Unstructured{}
+	widget.SetAPIVersion("example.com/v1")
+	widget.SetKind("Widget")
+	widget.SetNamespace(namespace)
+	widget.SetName(name)
+	return widget
+}
+
+var _ = Describe("Counter", func() {
+	var (
+		ctx     context.Context
+		counter *Counter
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		var objects []client.Object
+		for i := 1; i <= 5; i++ {
+			objects = append(objects, newWidget("team-a", fmt.Sprintf("widget-%d", i)))
+		}
+		objects = append(objects, newWidget("team-b", "widget-1"))
+		// Register Widget like a CRD the operator has no Go types for
+		scheme := runtime.NewScheme()
+		gv := schema.GroupVersion{Group: "example.com", Version: "v1"}
+		scheme.AddKnownTypeWithName(gv.WithKind("Widget"), &unstructured.Unstructured{})
+		scheme.AddKnownTypeWithName(gv.WithKind("WidgetList"), &unstructured.UnstructuredList{})
+		reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+
+		counter = NewCounter(reader, DefaultQPS, DefaultBurst, DefaultTimeout)
+	})
+
+	// evaluate runs rule against self with countResources answered by c
+	evaluate := func(c *Counter, rule string, self *unstructured.Unstructured) (bool, error) {
+		env, err := cel.NewEnv(cel.Variable("object", cel.MapType(cel.StringType, cel.DynType)), c.Function(ctx, self))
+		Expect(err).NotTo(HaveOccurred())
+		checked, issues := env.Compile(rule)
+		Expect(issues.Err()).NotTo(HaveOccurred())
+		prg, err := env.Program(checked)
+		Expect(err).NotTo(HaveOccurred())
+
+		out, _, err := prg.Eval(map[string]interface{}{"object": self.Object})
+		if err != nil {
+			return false, err
+		}
+		return out.Value() == true, nil
+	}
+
+	limitRule := "countResources(object.apiVersion, object.kind, object.metadata.namespace) < 5"
+
+	It("should count the resources of a kind in a namespace", func() {
+		count, err := counter.Count(ctx, "example.com/v1", "Widget", "team-a", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(count).To(Equal(int64(5)))
+
+		count, err = counter.Count(ctx, "example.com/v1", "Widget", "team-c", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(count).To(BeZero())
+	})
+
+	It("should reject a 6th instance when 5 already exist", func() {
+		valid, err := evaluate(counter, limitRule, newWidget("team-a", "widget-6"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(valid).To(BeFalse())
+
+		valid, err = evaluate(counter, limitRule, newWidget("team-b", "widget-2"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(valid).To(BeTrue())
+	})
+
+	It("should not count the resource being validated", func() {
+		valid, err := evaluate(counter, "countResources('example.com/v1', 'Widget', 'team-a') == 4", newWidget("team-a", "widget-5"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(valid).To(BeTrue())
+	})
+
+	It("should require a namespace", func() {
+		_, err := counter.Count(ctx, "example.com/v1", "Widget", "", nil)
+		Expect(err).To(MatchError(ContainSubstring("namespace is required")))
+	})
+
+	It("should limit the number of calls per evaluation", func() {
+		rule := fmt.Sprintf("[%s].all(i, countResources('example.com/v1', 'Widget', 'team-a') >= 0)", "0,1,2,3,4,5,6,7,8,9,10")
+		_, err := evaluate(counter, rule, newWidget("team-a", "widget-6"))
+		Expect(err).To(MatchError(ContainSubstring(fmt.Sprintf("at most %d times per rule", MaxCallsPerEvaluation))))
+	})
+
+	It("should fail queries over the rate limit", func() {
+		limited := NewCounter(counter.reader, 0.001, 1, 10*time.Millisecond)
+		_, err := limited.Count(ctx, "example.com/v1", "Widget", "team-a", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = limited.Count(ctx, "example.com/v1", "Widget", "team-a", nil)
+		Expect(err).To(MatchError(ContainSubstring("rate limit exceeded")))
+	})
+
+	It("should fail every call without a counter", func() {
+		_, err := evaluate(nil, limitRule, newWidget("team-a", "widget-6"))
+		Expect(err).To(MatchError(ContainSubstring("countResources is not available")))
+	})
+})
@@ -17,16 +17,23 @@ limitations under the License.
 package cert
 
 import (
+	"bytes"
 	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
 	"crypto/x509/pkix"
+	"encoding/json"
 	"encoding/pem"
 	"fmt"
 	"math/big"
+	"strings"
 	"time"
 
+	"github.com/lpeano/KubeTemplater/internal/metrics"
 	admissionv1 "k8s.io/api/admissionregistration/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -49,8 +56,39 @@ const (
 	CARenewThreshold = 365 * 24 * time.Hour // 1 year before CA expiration
 	// Check interval for certificate renewal
 	CheckInterval = 24 * time.Hour // Daily check
+
+	// KeyAlgorithmRSA generates RSA CA and server keys of the configured key sizes
+	KeyAlgorithmRSA KeyAlgorithm = "rsa"
+	// KeyAlgorithmECDSA generates ECDSA P-256 CA and server keys; key sizes don't apply
+	KeyAlgorithmECDSA KeyAlgorithm = "ecdsa"
+
+	// DefaultKeySize is the RSA key size of generated CA and server keys
+	DefaultKeySize = 2048
+	// MinKeySize is the smallest RSA key size accepted for generated keys
+	MinKeySize = 2048
+
+	// OriginalFailurePolicyAnnotation records the failurePolicy of each webhook, as a JSON object
+	// by webhook name, that was in place before fail-open mode switched them to Ignore, so they
+	// can be restored afterwards
+	OriginalFailurePolicyAnnotation = "kubetemplater.io/original-failure-policy"
 )
 
+// KeyAlgorithm is the algorithm of generated CA and server keys
+type KeyAlgorithm string
+
+// ParseKeyAlgorithm returns the key algorithm named by value, rsa or ecdsa. An empty value
+// selects RSA.
+func ParseKeyAlgorithm(value string) (KeyAlgorithm, error) {
+	switch algorithm := KeyAlgorithm(strings.ToLower(value)); algorithm {
+	case "":
+		return KeyAlgorithmRSA, nil
+	case KeyAlgorithmRSA, KeyAlgorithmECDSA:
+		return algorithm, nil
+	default:
+		return "", fmt.Errorf("unknown key algorithm %q, must be %s or %s", value, KeyAlgorithmRSA, KeyAlgorithmECDSA)
+	}
+}
+
 // Manager manages webhook certificates with persistent CA
 type Manager struct {
 	client                  client.Client
@@ -59,13 +97,129 @@ type Manager struct {
 	secretNamespace         string
 	serviceName             string
 	webhookConfigName       string
+	extraWebhookConfigs     []WebhookConfiguration
 	stopCh                  chan struct{}
 	started                 bool
+	failOpen                bool
+	externalCA              bool
+	keyAlgorithm            KeyAlgorithm
+	caKeySize               int
+	serverKeySize           int
+}
+
+// ManagerOption configures optional Manager behavior
+type ManagerOption func(*Manager)
+
+// WithFailOpen makes the manager switch the webhook failurePolicy to Ignore, so that
+// KubeTemplate admission is not blocked while the operator is under maintenance.
+// When the manager later starts without fail-open, the original policy is restored.
+func WithFailOpen(failOpen bool) ManagerOption {
+	return func(m *Manager) {
+		m.failOpen = failOpen
+	}
+}
+
+// WithExternalCA makes the manager issue server certificates from a CA provided in the CA
+// secret instead of generating and renewing its own. A missing CA secret is an error.
+func WithExternalCA(externalCA bool) ManagerOption {
+	return func(m *Manager) {
+		m.externalCA = externalCA
+	}
+}
+
+// WithCAKeySize sets the RSA key size of a generated CA. It only applies when a CA is
+// generated, so an existing CA keeps its key until it is renewed.
+func WithCAKeySize(bits int) ManagerOption {
+	return func(m *Manager) {
+		m.caKeySize = bits
+	}
+}
+
+// WithServerKeySize sets the RSA key size of generated server certificates
+func WithServerKeySize(bits int) ManagerOption {
+	return func(m *Manager) {
+		m.serverKeySize = bits
+	}
+}
+
+// WithKeyAlgorithm sets the algorithm of generated CA and server keys. Like the key sizes it
+// applies to a CA when it is generated, so an existing RSA CA keeps signing ECDSA server keys
+// until it is renewed.
+func WithKeyAlgorithm(algorithm KeyAlgorithm) ManagerOption {
+	return func(m *Manager) {
+		m.keyAlgorithm = algorithm
+	}
+}
+
+// ValidateKeySize returns an error if bits is too small for a generated RSA key
+func ValidateKeySize(bits int) error {
+	if bits < MinKeySize {
+		return fmt.Errorf("RSA key size %d is below the minimum of %d bits", bits, MinKeySize)
+	}
+	return nil
+}
+
+// WebhookConfigurationType is the kind of an admission webhook configuration
+type WebhookConfigurationType string
+
+const (
+	// ValidatingWebhookConfigurationType selects a ValidatingWebhookConfiguration
+	ValidatingWebhookConfigurationType WebhookConfigurationType = "validating"
+	// MutatingWebhookConfigurationType selects a MutatingWebhookConfiguration
+	MutatingWebhookConfigurationType WebhookConfigurationType = "mutating"
+)
+
+// WebhookConfiguration names an admission webhook configuration that receives the CA bundle
+type WebhookConfiguration struct {
+	Name string
+	Type WebhookConfigurationType
+}
+
+// ParseWebhookConfigurations parses a comma-separated list of type:name pairs, e.g.
+// "validating:extra-validation,mutating:defaults", as accepted by the
+// --extra-webhook-configurations flag. An empty string yields no configurations.
+func ParseWebhookConfigurations(value string) ([]WebhookConfiguration, error) {
+	var configs []WebhookConfiguration
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		kind, name, ok := strings.Cut(entry, ":")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid webhook configuration %q: expected type:name", entry)
+		}
+		configType := WebhookConfigurationType(kind)
+		if configType != ValidatingWebhookConfigurationType && configType != MutatingWebhookConfigurationType {
+			return nil, fmt.Errorf("invalid webhook configuration %q: type must be %s or %s",
+				entry, ValidatingWebhookConfigurationType, MutatingWebhookConfigurationType)
+		}
+		configs = append(configs, WebhookConfiguration{Name: name, Type: configType})
+	}
+	return configs, nil
+}
+
+// WithWebhookConfigurations makes the manager also patch the CA bundle of the given webhook
+// configurations, in addition to the validating configuration passed to NewManager
+func WithWebhookConfigurations(configs ...WebhookConfiguration) ManagerOption {
+	return func(m *Manager) {
+		for _, config := range configs {
+			if config.Name != "" {
+				m.extraWebhookConfigs = append(m.extraWebhookConfigs, config)
+			}
+		}
+	}
+}
+
+// WithMutatingWebhookConfiguration makes the manager also patch the CA bundle of the named
+// MutatingWebhookConfiguration
+func WithMutatingWebhookConfiguration(name string) ManagerOption {
+	return WithWebhookConfigurations(WebhookConfiguration{Name: name, Type: MutatingWebhookConfigurationType})
 }
 
 // NewManager creates a new certificate manager
-func NewManager(client client.Client, clientset *kubernetes.Clientset, secretName, secretNamespace, serviceName, webhookConfigName string) *Manager {
-	return &Manager{
+func NewManager(client client.Client, clientset *kubernetes.Clientset, secretName, secretNamespace, serviceName, webhookConfigName string, opts ...ManagerOption) *Manager {
+	m := &Manager{
 		client:            client,
 		clientset:         clientset,
 		secretName:        secretName,
@@ -74,7 +228,14 @@ func NewManager(client client.Client, clientset *kubernetes.Clientset, secretNam
 		webhookConfigName: webhookConfigName,
 		stopCh:            make(chan struct{}),
 		started:           false,
+		keyAlgorithm:      KeyAlgorithmRSA,
+		caKeySize:         DefaultKeySize,
+		serverKeySize:     DefaultKeySize,
+	}
+	for _, opt := range opts {
+		opt(m)
 	}
+	return m
 }
 
 // NeedLeaderElection implements manager.LeaderElectionRunnable
@@ -89,6 +250,17 @@ func (m *Manager) Start(ctx context.Context) error {
 		log.Info("Certificate manager already started, skipping")
 		return nil
 	}
+	if _, err := ParseKeyAlgorithm(string(m.keyAlgorithm)); err != nil {
+		return err
+	}
+	if m.keyAlgorithm == KeyAlgorithmRSA {
+		if err := ValidateKeySize(m.caKeySize); err != nil {
+			return fmt.Errorf("invalid CA key size: %w", err)
+		}
+		if err := ValidateKeySize(m.serverKeySize); err != nil {
+			return fmt.Errorf("invalid server key size: %w", err)
+		}
+	}
 	m.started = true
 
 	log.Info("Starting certificate manager (leader instance)", "secretName", m.secretName, "namespace", m.secretNamespace)
@@ -98,6 +270,12 @@ func (m *Manager) Start(ctx context.Context) error {
 		return fmt.Errorf("failed to ensure certificate: %w", err)
 	}
 
+	// Apply (or revert) fail-open mode on the webhook configuration
+	if err := m.reconcileFailurePolicy(ctx); err != nil {
+		log.Error(err, "Failed to reconcile webhook failurePolicy", "failOpen", m.failOpen)
+		// Don't fail - certificate management is still functional
+	}
+
 	// Start renewal loop
 	go m.renewalLoop(ctx)
 
@@ -137,6 +315,7 @@ func (m *Manager) ensureCertificate(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("failed to ensure CA: %w", err)
 	}
+	metrics.ObserveCertificateExpiry(metrics.CertificateCA, caCert.NotAfter)
 
 	// Check if server certificate needs generation
 	needsGeneration, err := m.needsServerCertGeneration(ctx, caCert)
@@ -149,7 +328,7 @@ func (m *Manager) ensureCertificate(ctx context.Context) error {
 			return fmt.Errorf("failed to generate server certificate: %w", err)
 		}
 
-		// Patch ValidatingWebhookConfiguration with CA bundle
+		// Patch the webhook configurations with the CA bundle
 		if err := m.patchWebhookConfiguration(ctx, caCert); err != nil {
 			log.Error(err, "Failed to patch webhook configuration", "note", "Webhook may not work correctly")
 			// Don't fail - certificate is still valid
@@ -160,9 +339,18 @@ func (m *Manager) ensureCertificate(ctx context.Context) error {
 }
 
 // ensureCA ensures the CA certificate exists, creates if needed, and handles CA renewal with coexistence period
-func (m *Manager) ensureCA(ctx context.Context) (*x509.Certificate, *rsa.PrivateKey, error) {
+func (m *Manager) ensureCA(ctx context.Context) (*x509.Certificate, crypto.Signer, error) {
 	caSecretName := m.secretName + "-ca"
 	caSecretNameNew := caSecretName + "-new"
+
+	if m.externalCA {
+		return m.loadExternalCA(ctx, caSecretName)
+	}
+
+	// Finish a promotion an earlier pass left half done before looking at the secrets
+	if err := m.cleanupCAPromotion(ctx, caSecretName, caSecretNameNew); err != nil {
+		log.Error(err, "Failed to clean up after an incomplete CA promotion, will retry on the next pass")
+	}
 	
 	// Check if new CA exists (in transition period)
 	newSecret := &corev1.Secret{}
@@ -275,8 +463,75 @@ func (m *Manager) ensureCA(ctx context.Context) (*x509.Certificate, *rsa.Private
 	return m.generateCA(ctx, caSecretName)
 }
 
+// loadExternalCA loads the externally managed CA from its secret. The CA is never generated or
+// renewed here: rotating it is up to whoever provisions the secret.
+func (m *Manager) loadExternalCA(ctx context.Context, caSecretName string) (*x509.Certificate, crypto.Signer, error) {
+	secret := &corev1.Secret{}
+	if err := m.client.Get(ctx, types.NamespacedName{Name: caSecretName, Namespace: m.secretNamespace}, secret); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil, fmt.Errorf("external CA secret %s/%s not found: create it with ca.crt and ca.key, or run without --use-external-ca", m.secretNamespace, caSecretName)
+		}
+		return nil, nil, fmt.Errorf("failed to get external CA secret: %w", err)
+	}
+
+	caCert, caKey, err := m.parseCAFromSecret(secret)
+	if err != nil {
+		return nil, nil, err
+	}
+	if time.Until(caCert.NotAfter) < CARenewThreshold {
+		log.Info("External CA certificate is approaching expiration, it must be renewed by its provider",
+			"secretName", caSecretName,
+			"expiresAt", caCert.NotAfter)
+	}
+	return caCert, caKey, nil
+}
+
+// cleanupCAPromotion repairs the secrets of a CA promotion that failed midway. Promotion deletes
+// the old CA secret, copies the -new secret to the primary name and deletes the -new secret;
+// when a step fails, either the primary secret is missing, or the -new secret is left behind
+// with the same CA as the primary. Both leave the manager in the transition period for good.
+func (m *Manager) cleanupCAPromotion(ctx context.Context, caSecretName, caSecretNameNew string) error {
+	newSecret := &corev1.Secret{}
+	if err := m.client.Get(ctx, types.NamespacedName{Name: caSecretNameNew, Namespace: m.secretNamespace}, newSecret); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get new CA secret: %w", err)
+	}
+
+	secret := &corev1.Secret{}
+	err := m.client.Get(ctx, types.NamespacedName{Name: caSecretName, Namespace: m.secretNamespace}, secret)
+	switch {
+	case errors.IsNotFound(err):
+		// The old CA was deleted but the new one was never copied to the primary name
+		log.Info("Primary CA secret missing after promotion, completing promotion", "secretName", caSecretName)
+		if err := m.client.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      caSecretName,
+				Namespace: m.secretNamespace,
+			},
+			Type: newSecret.Type,
+			Data: newSecret.Data,
+		}); err != nil {
+			return fmt.Errorf("failed to create promoted CA secret: %w", err)
+		}
+	case err != nil:
+		return fmt.Errorf("failed to get CA secret: %w", err)
+	case !bytes.Equal(secret.Data["ca.crt"], newSecret.Data["ca.crt"]):
+		// Two different CAs: a regular coexistence period
+		return nil
+	default:
+		log.Info("Deleting new CA secret left behind by a completed promotion", "secretName", caSecretNameNew)
+	}
+
+	if err := m.client.Delete(ctx, newSecret); err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete new CA secret: %w", err)
+	}
+	return nil
+}
+
 // parseCAFromSecret parses CA certificate and key from secret
-func (m *Manager) parseCAFromSecret(secret *corev1.Secret) (*x509.Certificate, *rsa.PrivateKey, error) {
+func (m *Manager) parseCAFromSecret(secret *corev1.Secret) (*x509.Certificate, crypto.Signer, error) {
 	certPEM, ok := secret.Data["ca.crt"]
 	if !ok {
 		return nil, nil, fmt.Errorf("CA secret missing ca.crt")
@@ -299,7 +554,7 @@ func (m *Manager) parseCAFromSecret(secret *corev1.Secret) (*x509.Certificate, *
 	if keyBlock == nil {
 		return nil, nil, fmt.Errorf("failed to decode CA key PEM")
 	}
-	caKey, err := x509.ParsePKCS1PrivateKey(keyBlock.Bytes)
+	caKey, err := parseCAKey(keyBlock)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to parse CA key: %w", err)
 	}
@@ -308,10 +563,60 @@ func (m *Manager) parseCAFromSecret(secret *corev1.Secret) (*x509.Certificate, *
 	return caCert, caKey, nil
 }
 
+// parseCAKey parses a PKCS1 ("RSA PRIVATE KEY"), SEC 1 ("EC PRIVATE KEY") or PKCS8
+// ("PRIVATE KEY") encoded CA key, the latter being what cert-manager and most external PKIs
+// issue. Only RSA and ECDSA keys can sign server certs.
+func parseCAKey(block *pem.Block) (crypto.Signer, error) {
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		return key, nil
+	case *ecdsa.PrivateKey:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("CA key is a %T, only RSA and ECDSA keys are supported", key)
+	}
+}
+
+// generateKey generates a private key with the manager's key algorithm, of rsaBits bits for RSA
+func (m *Manager) generateKey(rsaBits int) (crypto.Signer, error) {
+	if m.keyAlgorithm == KeyAlgorithmECDSA {
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	}
+	return rsa.GenerateKey(rand.Reader, rsaBits)
+}
+
+// encodePrivateKey PEM-encodes an RSA key as PKCS1 and an ECDSA key as SEC 1, which
+// tls.X509KeyPair and parseCAKey both read
+func encodePrivateKey(key crypto.Signer) ([]byte, error) {
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}), nil
+	case *ecdsa.PrivateKey:
+		der, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			return nil, err
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), nil
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+}
+
 // generateCA generates a new CA certificate
-func (m *Manager) generateCA(ctx context.Context, caSecretName string) (*x509.Certificate, *rsa.PrivateKey, error) {
+func (m *Manager) generateCA(ctx context.Context, caSecretName string) (*x509.Certificate, crypto.Signer, error) {
 	// Generate CA private key
-	caKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	caKey, err := m.generateKey(m.caKeySize)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to generate CA key: %w", err)
 	}
@@ -336,7 +641,7 @@ func (m *Manager) generateCA(ctx context.Context, caSecretName string) (*x509.Ce
 	}
 
 	// Self-sign the CA certificate
-	caCertBytes, err := x509.CreateCertificate(rand.Reader, &caTemplate, &caTemplate, &caKey.PublicKey, caKey)
+	caCertBytes, err := x509.CreateCertificate(rand.Reader, &caTemplate, &caTemplate, caKey.Public(), caKey)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to create CA certificate: %w", err)
 	}
@@ -348,7 +653,10 @@ func (m *Manager) generateCA(ctx context.Context, caSecretName string) (*x509.Ce
 
 	// Store CA certificate in secret
 	caCertPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caCertBytes})
-	caKeyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(caKey)})
+	caKeyPEM, err := encodePrivateKey(caKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode CA key: %w", err)
+	}
 
 	caSecret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
@@ -366,7 +674,7 @@ func (m *Manager) generateCA(ctx context.Context, caSecretName string) (*x509.Ce
 		return nil, nil, fmt.Errorf("failed to create CA secret: %w", err)
 	}
 
-	log.Info("CA certificate generated and stored", "validUntil", caTemplate.NotAfter)
+	log.Info("CA certificate generated and stored", "validUntil", caTemplate.NotAfter, "keyAlgorithm", m.keyAlgorithm, "keySize", m.caKeySize)
 	return caCert, caKey, nil
 }
 
@@ -405,6 +713,7 @@ func (m *Manager) needsServerCertGeneration(ctx context.Context, caCert *x509.Ce
 		log.Info("Failed to parse certificate, regenerating", "error", err)
 		return true, nil
 	}
+	metrics.ObserveCertificateExpiry(metrics.CertificateServer, cert.NotAfter)
 
 	// Check if certificate expires soon
 	renewTime := time.Now().Add(RenewThreshold)
@@ -452,11 +761,11 @@ func (m *Manager) needsServerCertGeneration(ctx context.Context, caCert *x509.Ce
 }
 
 // generateServerCert generates a new server certificate signed by CA
-func (m *Manager) generateServerCert(ctx context.Context, caCert *x509.Certificate, caKey *rsa.PrivateKey) error {
+func (m *Manager) generateServerCert(ctx context.Context, caCert *x509.Certificate, caKey crypto.Signer) error {
 	log.Info("Generating new server certificate", "service", m.serviceName, "namespace", m.secretNamespace)
 
 	// Generate server private key
-	serverKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	serverKey, err := m.generateKey(m.serverKeySize)
 	if err != nil {
 		return fmt.Errorf("failed to generate server key: %w", err)
 	}
@@ -481,20 +790,27 @@ func (m *Manager) generateServerCert(ctx context.Context, caCert *x509.Certifica
 		},
 		NotBefore:             time.Now(),
 		NotAfter:              time.Now().Add(CertValidityDuration),
-		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		KeyUsage:              x509.KeyUsageDigitalSignature,
 		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
 		BasicConstraintsValid: true,
 	}
+	// Key encipherment only applies to RSA key exchange
+	if _, ok := serverKey.(*rsa.PrivateKey); ok {
+		template.KeyUsage |= x509.KeyUsageKeyEncipherment
+	}
 
 	// Sign certificate with CA
-	certBytes, err := x509.CreateCertificate(rand.Reader, &template, caCert, &serverKey.PublicKey, caKey)
+	certBytes, err := x509.CreateCertificate(rand.Reader, &template, caCert, serverKey.Public(), caKey)
 	if err != nil {
 		return fmt.Errorf("failed to create certificate: %w", err)
 	}
 
 	// Encode to PEM
 	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certBytes})
-	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(serverKey)})
+	keyPEM, err := encodePrivateKey(serverKey)
+	if err != nil {
+		return fmt.Errorf("failed to encode server key: %w", err)
+	}
 
 	// Update or create secret
 	secret := &corev1.Secret{}
@@ -538,31 +854,166 @@ func (m *Manager) generateServerCert(ctx context.Context, caCert *x509.Certifica
 	log.Info("Certificate generated and stored successfully",
 		"secretName", m.secretName,
 		"validUntil", template.NotAfter.Format(time.RFC3339))
+	metrics.ObserveCertificateExpiry(metrics.CertificateServer, template.NotAfter)
 
 	return nil
 }
 
-// patchWebhookConfiguration updates the ValidatingWebhookConfiguration with CA bundle
-func (m *Manager) patchWebhookConfiguration(ctx context.Context, caCert *x509.Certificate) error {
-	log.Info("Patching validating webhook configuration with new CA bundle", "name", m.webhookConfigName)
+// webhookConfigurations returns the validating configuration passed to NewManager followed
+// by the extra configurations, without duplicates
+func (m *Manager) webhookConfigurations() []WebhookConfiguration {
+	configs := []WebhookConfiguration{{Name: m.webhookConfigName, Type: ValidatingWebhookConfigurationType}}
+	seen := map[WebhookConfiguration]bool{configs[0]: true}
+	for _, config := range m.extraWebhookConfigs {
+		if !seen[config] {
+			seen[config] = true
+			configs = append(configs, config)
+		}
+	}
+	return configs
+}
 
+// patchWebhookConfiguration updates every configured webhook configuration with the CA
+// bundle. Configurations that do not exist yet are skipped, and a failure on one
+// configuration does not prevent patching the others.
+func (m *Manager) patchWebhookConfiguration(ctx context.Context, caCert *x509.Certificate) error {
 	caCertPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caCert.Raw})
 
+	var failed []string
+	var firstErr error
+	for _, config := range m.webhookConfigurations() {
+		log.Info("Patching webhook configuration with new CA bundle", "name", config.Name, "type", config.Type)
+
+		err := m.patchCABundle(ctx, config, caCertPEM)
+		switch {
+		case err == nil:
+			log.Info("Successfully patched webhook configuration with new CA bundle", "name", config.Name, "type", config.Type)
+		case errors.IsNotFound(err):
+			log.Info("Webhook configuration not found, skipping CA bundle patch", "name", config.Name, "type", config.Type)
+		case errors.IsForbidden(err):
+			// The operator's ClusterRole only grants access to the configurations it names
+			log.Error(err, "Not allowed to patch webhook configuration, add it to the resourceNames of the operator's ClusterRole",
+				"name", config.Name, "type", config.Type)
+			failed = append(failed, config.Name)
+			if firstErr == nil {
+				firstErr = err
+			}
+		default:
+			failed = append(failed, config.Name)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	if firstErr != nil {
+		return fmt.Errorf("failed to patch webhook configurations %s: %w", strings.Join(failed, ", "), firstErr)
+	}
+	return nil
+}
+
+// patchCABundle sets the CA bundle of all webhooks of one webhook configuration
+func (m *Manager) patchCABundle(ctx context.Context, config WebhookConfiguration, caCertPEM []byte) error {
+	key := types.NamespacedName{Name: config.Name}
+
+	switch config.Type {
+	case MutatingWebhookConfigurationType:
+		mutatingConfig := &admissionv1.MutatingWebhookConfiguration{}
+		if err := m.client.Get(ctx, key, mutatingConfig); err != nil {
+			return err
+		}
+		for i := range mutatingConfig.Webhooks {
+			mutatingConfig.Webhooks[i].ClientConfig.CABundle = caCertPEM
+		}
+		return m.client.Update(ctx, mutatingConfig)
+	default:
+		webhookConfig := &admissionv1.ValidatingWebhookConfiguration{}
+		if err := m.client.Get(ctx, key, webhookConfig); err != nil {
+			return err
+		}
+		for i := range webhookConfig.Webhooks {
+			webhookConfig.Webhooks[i].ClientConfig.CABundle = caCertPEM
+		}
+		return m.client.Update(ctx, webhookConfig)
+	}
+}
+
+// reconcileFailurePolicy switches the webhook failurePolicy to Ignore when fail-open mode
+// is enabled, and restores the recorded original policy once it is disabled again
+func (m *Manager) reconcileFailurePolicy(ctx context.Context) error {
 	webhookConfig := &admissionv1.ValidatingWebhookConfiguration{}
-	err := m.client.Get(ctx, types.NamespacedName{Name: m.webhookConfigName}, webhookConfig)
-	if err != nil {
+	if err := m.client.Get(ctx, types.NamespacedName{Name: m.webhookConfigName}, webhookConfig); err != nil {
 		return fmt.Errorf("failed to get webhook configuration: %w", err)
 	}
 
-	// Update CA bundle for all webhooks
-	for i := range webhookConfig.Webhooks {
-		webhookConfig.Webhooks[i].ClientConfig.CABundle = caCertPEM
+	original, failedOpen := webhookConfig.Annotations[OriginalFailurePolicyAnnotation]
+
+	switch {
+	case m.failOpen && !failedOpen:
+		// Remember the configured policies before overriding them
+		originalPolicies := make(map[string]admissionv1.FailurePolicyType, len(webhookConfig.Webhooks))
+		for _, webhook := range webhookConfig.Webhooks {
+			originalPolicies[webhook.Name] = admissionv1.Fail
+			if webhook.FailurePolicy != nil {
+				originalPolicies[webhook.Name] = *webhook.FailurePolicy
+			}
+		}
+		recorded, err := json.Marshal(originalPolicies)
+		if err != nil {
+			return fmt.Errorf("failed to record the original failurePolicies: %w", err)
+		}
+		if webhookConfig.Annotations == nil {
+			webhookConfig.Annotations = make(map[string]string)
+		}
+		webhookConfig.Annotations[OriginalFailurePolicyAnnotation] = string(recorded)
+		for i := range webhookConfig.Webhooks {
+			setFailurePolicy(&webhookConfig.Webhooks[i], admissionv1.Ignore)
+		}
+		log.Info("Fail-open mode enabled, setting webhook failurePolicy to Ignore",
+			"name", m.webhookConfigName,
+			"originalPolicies", originalPolicies)
+	case !m.failOpen && failedOpen:
+		delete(webhookConfig.Annotations, OriginalFailurePolicyAnnotation)
+		originalPolicies := originalFailurePolicies(webhookConfig, original)
+		for i := range webhookConfig.Webhooks {
+			webhook := &webhookConfig.Webhooks[i]
+			// Webhooks added while failed open get the API server's default
+			policy, ok := originalPolicies[webhook.Name]
+			if !ok {
+				policy = admissionv1.Fail
+			}
+			setFailurePolicy(webhook, policy)
+		}
+		log.Info("Fail-open mode disabled, restoring webhook failurePolicy",
+			"name", m.webhookConfigName,
+			"failurePolicies", original)
+	default:
+		// Already in the desired state
+		return nil
 	}
 
 	if err := m.client.Update(ctx, webhookConfig); err != nil {
 		return fmt.Errorf("failed to update webhook configuration: %w", err)
 	}
-
-	log.Info("Successfully patched ValidatingWebhookConfiguration with new CA bundle", "name", m.webhookConfigName)
 	return nil
-}
\ No newline at end of file
+}
+
+// originalFailurePolicies parses OriginalFailurePolicyAnnotation of webhookConfig. Older
+// versions recorded a single policy, which then applies to every webhook.
+func originalFailurePolicies(webhookConfig *admissionv1.ValidatingWebhookConfiguration, recorded string) map[string]admissionv1.FailurePolicyType {
+	var policies map[string]admissionv1.FailurePolicyType
+	if err := json.Unmarshal([]byte(recorded), &policies); err == nil {
+		return policies
+	}
+	policies = make(map[string]admissionv1.FailurePolicyType, len(webhookConfig.Webhooks))
+	for _, webhook := range webhookConfig.Webhooks {
+		policies[webhook.Name] = admissionv1.FailurePolicyType(recorded)
+	}
+	return policies
+}
+
+// setFailurePolicy sets the failurePolicy of webhook
+func setFailurePolicy(webhook *admissionv1.ValidatingWebhook, policy admissionv1.FailurePolicyType) {
+	failurePolicy := policy
+	webhook.FailurePolicy = &failurePolicy
+}
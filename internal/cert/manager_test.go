@@ -0,0 +1,601 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cert
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/lpeano/KubeTemplater/internal/metrics"
+)
+
+const testWebhookConfigName = "kubetemplater-validating-webhook-configuration"
+
+var _ = Describe("Manager failurePolicy", func() {
+	var (
+		ctx        context.Context
+		fakeClient client.Client
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+
+		failurePolicy := admissionv1.Fail
+		ignore := admissionv1.Ignore
+		webhookConfig := &admissionv1.ValidatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: testWebhookConfigName},
+			Webhooks: []admissionv1.ValidatingWebhook{
+				{
+					Name:          "vkubetemplate.kb.io",
+					FailurePolicy: &failurePolicy,
+				},
+				{
+					Name:          "vkubetemplatepolicy.kb.io",
+					FailurePolicy: &ignore,
+				},
+			},
+		}
+
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(webhookConfig).
+			Build()
+	})
+
+	getWebhookConfig := func() *admissionv1.ValidatingWebhookConfiguration {
+		webhookConfig := &admissionv1.ValidatingWebhookConfiguration{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Name: testWebhookConfigName}, webhookConfig)).To(Succeed())
+		return webhookConfig
+	}
+
+	newManager := func(opts ...ManagerOption) *Manager {
+		return NewManager(fakeClient, nil, "webhook-certs", "kubetemplater-system", "webhook-service", testWebhookConfigName, opts...)
+	}
+
+	It("should leave the failurePolicy untouched when fail-open is disabled", func() {
+		Expect(newManager().reconcileFailurePolicy(ctx)).To(Succeed())
+
+		webhookConfig := getWebhookConfig()
+		Expect(*webhookConfig.Webhooks[0].FailurePolicy).To(Equal(admissionv1.Fail))
+		Expect(webhookConfig.Annotations).NotTo(HaveKey(OriginalFailurePolicyAnnotation))
+	})
+
+	It("should switch to Ignore when fail-open is enabled and restore each webhook afterwards", func() {
+		const recorded = `{"vkubetemplate.kb.io":"Fail","vkubetemplatepolicy.kb.io":"Ignore"}`
+		Expect(newManager(WithFailOpen(true)).reconcileFailurePolicy(ctx)).To(Succeed())
+
+		webhookConfig := getWebhookConfig()
+		Expect(*webhookConfig.Webhooks[0].FailurePolicy).To(Equal(admissionv1.Ignore))
+		Expect(*webhookConfig.Webhooks[1].FailurePolicy).To(Equal(admissionv1.Ignore))
+		Expect(webhookConfig.Annotations).To(HaveKeyWithValue(OriginalFailurePolicyAnnotation, recorded))
+
+		// Reconciling again must not overwrite the recorded original policies
+		Expect(newManager(WithFailOpen(true)).reconcileFailurePolicy(ctx)).To(Succeed())
+		webhookConfig = getWebhookConfig()
+		Expect(webhookConfig.Annotations).To(HaveKeyWithValue(OriginalFailurePolicyAnnotation, recorded))
+
+		Expect(newManager(WithFailOpen(false)).reconcileFailurePolicy(ctx)).To(Succeed())
+
+		webhookConfig = getWebhookConfig()
+		Expect(*webhookConfig.Webhooks[0].FailurePolicy).To(Equal(admissionv1.Fail))
+		Expect(*webhookConfig.Webhooks[1].FailurePolicy).To(Equal(admissionv1.Ignore))
+		Expect(webhookConfig.Annotations).NotTo(HaveKey(OriginalFailurePolicyAnnotation))
+	})
+
+	It("should restore a single policy recorded by an older version on every webhook", func() {
+		webhookConfig := getWebhookConfig()
+		for i := range webhookConfig.Webhooks {
+			setFailurePolicy(&webhookConfig.Webhooks[i], admissionv1.Ignore)
+		}
+		webhookConfig.Annotations = map[string]string{OriginalFailurePolicyAnnotation: string(admissionv1.Fail)}
+		Expect(fakeClient.Update(ctx, webhookConfig)).To(Succeed())
+
+		Expect(newManager(WithFailOpen(false)).reconcileFailurePolicy(ctx)).To(Succeed())
+
+		webhookConfig = getWebhookConfig()
+		Expect(*webhookConfig.Webhooks[0].FailurePolicy).To(Equal(admissionv1.Fail))
+		Expect(*webhookConfig.Webhooks[1].FailurePolicy).To(Equal(admissionv1.Fail))
+	})
+
+	It("should return an error when the webhook configuration does not exist", func() {
+		m := NewManager(fakeClient, nil, "webhook-certs", "kubetemplater-system", "webhook-service", "missing", WithFailOpen(true))
+		Expect(m.reconcileFailurePolicy(ctx)).NotTo(Succeed())
+	})
+})
+
+var _ = Describe("Manager CA promotion", func() {
+	const (
+		caSecretName    = "webhook-certs-ca"
+		caSecretNameNew = "webhook-certs-ca-new"
+		secretNamespace = "kubetemplater-system"
+	)
+
+	var (
+		ctx        context.Context
+		fakeClient client.Client
+		m          *Manager
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+
+		fakeClient = fake.NewClientBuilder().WithScheme(scheme).Build()
+		m = NewManager(fakeClient, nil, "webhook-certs", secretNamespace, "webhook-service", testWebhookConfigName)
+	})
+
+	getSecret := func(name string) (*corev1.Secret, error) {
+		secret := &corev1.Secret{}
+		return secret, fakeClient.Get(ctx, types.NamespacedName{Name: name, Namespace: secretNamespace}, secret)
+	}
+
+	// copySecret stores the data of the from secret under the name to, as a promotion does
+	copySecret := func(from, to string) {
+		secret, err := getSecret(from)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fakeClient.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: to, Namespace: secretNamespace},
+			Data:       secret.Data,
+		})).To(Succeed())
+	}
+
+	It("should delete a new CA secret left behind by a completed promotion", func() {
+		promotedCA, _, err := m.generateCA(ctx, caSecretNameNew)
+		Expect(err).NotTo(HaveOccurred())
+		copySecret(caSecretNameNew, caSecretName)
+
+		caCert, _, err := m.ensureCA(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(caCert.Equal(promotedCA)).To(BeTrue())
+
+		_, err = getSecret(caSecretNameNew)
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+		_, err = getSecret(caSecretName)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should complete a promotion that deleted the old CA but never copied the new one", func() {
+		promotedCA, _, err := m.generateCA(ctx, caSecretNameNew)
+		Expect(err).NotTo(HaveOccurred())
+
+		caCert, _, err := m.ensureCA(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(caCert.Equal(promotedCA)).To(BeTrue())
+
+		secret, err := getSecret(caSecretName)
+		Expect(err).NotTo(HaveOccurred())
+		loaded, _, err := m.parseCAFromSecret(secret)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(loaded.Equal(promotedCA)).To(BeTrue())
+
+		_, err = getSecret(caSecretNameNew)
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should keep the new CA secret during a regular coexistence period", func() {
+		_, _, err := m.generateCA(ctx, caSecretName)
+		Expect(err).NotTo(HaveOccurred())
+		newCA, _, err := m.generateCA(ctx, caSecretNameNew)
+		Expect(err).NotTo(HaveOccurred())
+
+		caCert, _, err := m.ensureCA(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(caCert.Equal(newCA)).To(BeTrue())
+
+		_, err = getSecret(caSecretNameNew)
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
+
+// newExternalCA creates a CA the way an external PKI would, returning its certificate as PEM
+func newExternalCA() (*x509.Certificate, *rsa.PrivateKey, []byte) {
+	caKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	Expect(err).NotTo(HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "External CA"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(CAValidityDuration),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &caKey.PublicKey, caKey)
+	Expect(err).NotTo(HaveOccurred())
+	caCert, err := x509.ParseCertificate(der)
+	Expect(err).NotTo(HaveOccurred())
+	return caCert, caKey, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+var _ = Describe("Manager CA key parsing", func() {
+	var (
+		m      *Manager
+		caCert []byte
+		caKey  *rsa.PrivateKey
+	)
+
+	BeforeEach(func() {
+		m = NewManager(nil, nil, "webhook-certs", "kubetemplater-system", "webhook-service", testWebhookConfigName)
+
+		_, caKey, caCert = newExternalCA()
+	})
+
+	caSecret := func(keyPEM []byte) *corev1.Secret {
+		return &corev1.Secret{Data: map[string][]byte{"ca.crt": caCert, "ca.key": keyPEM}}
+	}
+
+	It("should load a PKCS1 encoded CA key", func() {
+		keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(caKey)})
+
+		_, key, err := m.parseCAFromSecret(caSecret(keyPEM))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(caKey.Equal(key)).To(BeTrue())
+	})
+
+	It("should load a PKCS8 encoded CA key", func() {
+		der, err := x509.MarshalPKCS8PrivateKey(caKey)
+		Expect(err).NotTo(HaveOccurred())
+		keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+		_, key, err := m.parseCAFromSecret(caSecret(keyPEM))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(caKey.Equal(key)).To(BeTrue())
+	})
+
+	It("should load SEC 1 and PKCS8 encoded ECDSA CA keys", func() {
+		ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+
+		sec1, err := x509.MarshalECPrivateKey(ecKey)
+		Expect(err).NotTo(HaveOccurred())
+		_, key, err := m.parseCAFromSecret(caSecret(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: sec1})))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ecKey.Equal(key)).To(BeTrue())
+
+		pkcs8, err := x509.MarshalPKCS8PrivateKey(ecKey)
+		Expect(err).NotTo(HaveOccurred())
+		_, key, err = m.parseCAFromSecret(caSecret(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8})))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ecKey.Equal(key)).To(BeTrue())
+	})
+
+	It("should reject a PKCS8 key that is neither RSA nor ECDSA", func() {
+		_, edKey, err := ed25519.GenerateKey(rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+		der, err := x509.MarshalPKCS8PrivateKey(edKey)
+		Expect(err).NotTo(HaveOccurred())
+		keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+		_, _, err = m.parseCAFromSecret(caSecret(keyPEM))
+		Expect(err).To(MatchError(ContainSubstring("only RSA and ECDSA keys are supported")))
+	})
+})
+
+var _ = Describe("Manager external CA", func() {
+	const secretNamespace = "kubetemplater-system"
+
+	var (
+		ctx        context.Context
+		fakeClient client.Client
+		m          *Manager
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+
+		fakeClient = fake.NewClientBuilder().WithScheme(scheme).Build()
+		m = NewManager(fakeClient, nil, "webhook-certs", secretNamespace, "webhook-service", testWebhookConfigName, WithExternalCA(true))
+	})
+
+	It("should issue the server certificate from the provided CA", func() {
+		caCert, caKey, caCertPEM := newExternalCA()
+		keyDER, err := x509.MarshalPKCS8PrivateKey(caKey)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fakeClient.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "webhook-certs-ca", Namespace: secretNamespace},
+			Data: map[string][]byte{
+				"ca.crt": caCertPEM,
+				"ca.key": pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}),
+			},
+		})).To(Succeed())
+
+		Expect(m.ensureCertificate(ctx)).To(Succeed())
+
+		secret := &corev1.Secret{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Name: "webhook-certs", Namespace: secretNamespace}, secret)).To(Succeed())
+		block, _ := pem.Decode(secret.Data["tls.crt"])
+		Expect(block).NotTo(BeNil())
+		serverCert, err := x509.ParseCertificate(block.Bytes)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(serverCert.CheckSignatureFrom(caCert)).To(Succeed())
+	})
+
+	It("should fail instead of generating a CA when the CA secret is missing", func() {
+		Expect(m.ensureCertificate(ctx)).To(MatchError(ContainSubstring("external CA secret kubetemplater-system/webhook-certs-ca not found")))
+
+		secrets := &corev1.SecretList{}
+		Expect(fakeClient.List(ctx, secrets)).To(Succeed())
+		Expect(secrets.Items).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Manager key sizes", func() {
+	const secretNamespace = "kubetemplater-system"
+
+	var (
+		ctx        context.Context
+		fakeClient client.Client
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+
+		fakeClient = fake.NewClientBuilder().WithScheme(scheme).Build()
+	})
+
+	decodeSecret := func(name, key string) []byte {
+		secret := &corev1.Secret{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Name: name, Namespace: secretNamespace}, secret)).To(Succeed())
+		block, _ := pem.Decode(secret.Data[key])
+		Expect(block).NotTo(BeNil())
+		return block.Bytes
+	}
+
+	It("should generate a 4096-bit CA signing a 2048-bit server certificate", func() {
+		m := NewManager(fakeClient, nil, "webhook-certs", secretNamespace, "webhook-service", testWebhookConfigName,
+			WithCAKeySize(4096), WithServerKeySize(2048))
+		Expect(m.ensureCertificate(ctx)).To(Succeed())
+
+		caKey, err := x509.ParsePKCS1PrivateKey(decodeSecret("webhook-certs-ca", "ca.key"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(caKey.N.BitLen()).To(Equal(4096))
+		caCert, err := x509.ParseCertificate(decodeSecret("webhook-certs-ca", "ca.crt"))
+		Expect(err).NotTo(HaveOccurred())
+
+		serverKey, err := x509.ParsePKCS1PrivateKey(decodeSecret("webhook-certs", "tls.key"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(serverKey.N.BitLen()).To(Equal(2048))
+		serverCert, err := x509.ParseCertificate(decodeSecret("webhook-certs", "tls.crt"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(serverCert.CheckSignatureFrom(caCert)).To(Succeed())
+	})
+
+	It("should report the expiry of the generated CA and server certificate", func() {
+		m := NewManager(fakeClient, nil, "webhook-certs", secretNamespace, "webhook-service", testWebhookConfigName)
+		Expect(m.ensureCertificate(ctx)).To(Succeed())
+
+		caCert, err := x509.ParseCertificate(decodeSecret("webhook-certs-ca", "ca.crt"))
+		Expect(err).NotTo(HaveOccurred())
+		serverCert, err := x509.ParseCertificate(decodeSecret("webhook-certs", "tls.crt"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(testutil.ToFloat64(metrics.WebhookCertificateExpiry.WithLabelValues(metrics.CertificateCA))).
+			To(Equal(float64(caCert.NotAfter.Unix())))
+		Expect(testutil.ToFloat64(metrics.WebhookCertificateExpiry.WithLabelValues(metrics.CertificateServer))).
+			To(Equal(float64(serverCert.NotAfter.Unix())))
+	})
+
+	It("should refuse to start with a key size below the minimum", func() {
+		m := NewManager(fakeClient, nil, "webhook-certs", secretNamespace, "webhook-service", testWebhookConfigName,
+			WithCAKeySize(1024))
+		Expect(m.Start(ctx)).To(MatchError(ContainSubstring("invalid CA key size: RSA key size 1024 is below the minimum of 2048 bits")))
+
+		m = NewManager(fakeClient, nil, "webhook-certs", secretNamespace, "webhook-service", testWebhookConfigName,
+			WithServerKeySize(1024))
+		Expect(m.Start(ctx)).To(MatchError(ContainSubstring("invalid server key size")))
+
+		secrets := &corev1.SecretList{}
+		Expect(fakeClient.List(ctx, secrets)).To(Succeed())
+		Expect(secrets.Items).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Manager webhook configurations", func() {
+	var (
+		ctx        context.Context
+		fakeClient client.Client
+		caCert     *x509.Certificate
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(
+				&admissionv1.ValidatingWebhookConfiguration{
+					ObjectMeta: metav1.ObjectMeta{Name: testWebhookConfigName},
+					Webhooks:   []admissionv1.ValidatingWebhook{{Name: "vkubetemplate.kb.io"}},
+				},
+				&admissionv1.ValidatingWebhookConfiguration{
+					ObjectMeta: metav1.ObjectMeta{Name: "extra-validation"},
+					Webhooks:   []admissionv1.ValidatingWebhook{{Name: "a.kb.io"}, {Name: "b.kb.io"}},
+				},
+				&admissionv1.MutatingWebhookConfiguration{
+					ObjectMeta: metav1.ObjectMeta{Name: "defaults"},
+					Webhooks:   []admissionv1.MutatingWebhook{{Name: "mkubetemplate.kb.io"}},
+				},
+			).
+			Build()
+
+		caCert, _, _ = newExternalCA()
+	})
+
+	caBundle := func() []byte {
+		return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caCert.Raw})
+	}
+
+	It("should patch the CA bundle of every configured webhook configuration", func() {
+		m := NewManager(fakeClient, nil, "webhook-certs", "kubetemplater-system", "webhook-service", testWebhookConfigName,
+			WithWebhookConfigurations(
+				WebhookConfiguration{Name: "extra-validation", Type: ValidatingWebhookConfigurationType},
+				WebhookConfiguration{Name: "defaults", Type: MutatingWebhookConfigurationType},
+			))
+		Expect(m.patchWebhookConfiguration(ctx, caCert)).To(Succeed())
+
+		for _, name := range []string{testWebhookConfigName, "extra-validation"} {
+			validating := &admissionv1.ValidatingWebhookConfiguration{}
+			Expect(fakeClient.Get(ctx, types.NamespacedName{Name: name}, validating)).To(Succeed())
+			for _, webhook := range validating.Webhooks {
+				Expect(webhook.ClientConfig.CABundle).To(Equal(caBundle()), name)
+			}
+		}
+
+		mutating := &admissionv1.MutatingWebhookConfiguration{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Name: "defaults"}, mutating)).To(Succeed())
+		Expect(mutating.Webhooks[0].ClientConfig.CABundle).To(Equal(caBundle()))
+	})
+
+	It("should skip configurations that do not exist yet", func() {
+		m := NewManager(fakeClient, nil, "webhook-certs", "kubetemplater-system", "webhook-service", testWebhookConfigName,
+			WithMutatingWebhookConfiguration("missing"),
+			WithWebhookConfigurations(WebhookConfiguration{Name: "defaults", Type: MutatingWebhookConfigurationType}))
+		Expect(m.patchWebhookConfiguration(ctx, caCert)).To(Succeed())
+
+		mutating := &admissionv1.MutatingWebhookConfiguration{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Name: "defaults"}, mutating)).To(Succeed())
+		Expect(mutating.Webhooks[0].ClientConfig.CABundle).To(Equal(caBundle()))
+	})
+
+	It("should parse type:name lists", func() {
+		configs, err := ParseWebhookConfigurations(" validating:extra-validation, mutating:defaults,")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(configs).To(Equal([]WebhookConfiguration{
+			{Name: "extra-validation", Type: ValidatingWebhookConfigurationType},
+			{Name: "defaults", Type: MutatingWebhookConfigurationType},
+		}))
+
+		configs, err = ParseWebhookConfigurations("")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(configs).To(BeEmpty())
+
+		_, err = ParseWebhookConfigurations("extra-validation")
+		Expect(err).To(MatchError(ContainSubstring("expected type:name")))
+		_, err = ParseWebhookConfigurations("conversion:crd")
+		Expect(err).To(MatchError(ContainSubstring("type must be validating or mutating")))
+	})
+})
+
+var _ = Describe("Manager ECDSA keys", func() {
+	const secretNamespace = "kubetemplater-system"
+
+	var (
+		ctx        context.Context
+		fakeClient client.Client
+		m          *Manager
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+
+		fakeClient = fake.NewClientBuilder().WithScheme(scheme).Build()
+		// Key sizes don't apply to ECDSA keys
+		m = NewManager(fakeClient, nil, "webhook-certs", secretNamespace, "webhook-service", testWebhookConfigName,
+			WithKeyAlgorithm(KeyAlgorithmECDSA), WithCAKeySize(1024))
+	})
+
+	getSecret := func(name string) *corev1.Secret {
+		secret := &corev1.Secret{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Name: name, Namespace: secretNamespace}, secret)).To(Succeed())
+		return secret
+	}
+
+	It("should generate and round-trip a P-256 CA and server certificate", func() {
+		Expect(m.ensureCertificate(ctx)).To(Succeed())
+
+		caSecret := getSecret("webhook-certs-ca")
+		block, _ := pem.Decode(caSecret.Data["ca.key"])
+		Expect(block).NotTo(BeNil())
+		Expect(block.Type).To(Equal("EC PRIVATE KEY"))
+
+		caCert, caKey, err := m.parseCAFromSecret(caSecret)
+		Expect(err).NotTo(HaveOccurred())
+		ecCAKey, ok := caKey.(*ecdsa.PrivateKey)
+		Expect(ok).To(BeTrue())
+		Expect(ecCAKey.Curve).To(Equal(elliptic.P256()))
+
+		serverSecret := getSecret("webhook-certs")
+		block, _ = pem.Decode(serverSecret.Data["tls.key"])
+		Expect(block).NotTo(BeNil())
+		Expect(block.Type).To(Equal("EC PRIVATE KEY"))
+
+		keyPair, err := tls.X509KeyPair(serverSecret.Data["tls.crt"], serverSecret.Data["tls.key"])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(keyPair.PrivateKey).To(BeAssignableToTypeOf(&ecdsa.PrivateKey{}))
+		serverCert, err := x509.ParseCertificate(keyPair.Certificate[0])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(serverCert.CheckSignatureFrom(caCert)).To(Succeed())
+		Expect(serverCert.KeyUsage & x509.KeyUsageKeyEncipherment).To(BeZero())
+
+		// The stored certificates are reused rather than regenerated
+		needsGeneration, err := m.needsServerCertGeneration(ctx, caCert)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(needsGeneration).To(BeFalse())
+	})
+
+	It("should parse key algorithm names", func() {
+		algorithm, err := ParseKeyAlgorithm("")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(algorithm).To(Equal(KeyAlgorithmRSA))
+
+		algorithm, err = ParseKeyAlgorithm("ECDSA")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(algorithm).To(Equal(KeyAlgorithmECDSA))
+
+		_, err = ParseKeyAlgorithm("ed25519")
+		Expect(err).To(MatchError(ContainSubstring("must be rsa or ecdsa")))
+	})
+})
@@ -19,16 +19,21 @@ package cert
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/lpeano/KubeTemplater/internal/metrics"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
+	toolscache "k8s.io/client-go/tools/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -37,26 +42,35 @@ var secretLog = logf.Log.WithName("secret-cert-watcher")
 
 // SecretCertWatcher watches a Kubernetes Secret and serves the certificate it contains.
 type SecretCertWatcher struct {
-	Client          client.Client         // For Get operations (public for external assignment)
-	clientset       *kubernetes.Clientset // For Watch operations
+	Client          client.Client        // For Get operations (public for external assignment)
+	clientset       kubernetes.Interface // For Watch operations
 	secretName      string
 	secretNamespace string
+	serviceName     string       // The certificate must be valid for <serviceName>.<secretNamespace>.svc
 	cert            atomic.Value // Holds the current *tls.Certificate
 	isReady         chan struct{}
 	readyOnce       sync.Once
 	lastValidCert   *tls.Certificate // Keep last valid cert for graceful rotation
 	lastCertMu      sync.RWMutex     // Protect lastValidCert
+	// loadCAs returns the CAs the served certificate must chain to (nil = don't verify)
+	loadCAs  func(ctx context.Context) ([]*x509.Certificate, error)
+	chainErr error        // Why the served certificate doesn't chain to the CA, nil if it does
+	chainMu  sync.RWMutex // Protect chainErr
 }
 
 // NewSecretCertWatcher creates a new SecretCertWatcher.
-func NewSecretCertWatcher(client client.Client, clientset *kubernetes.Clientset, secretName, secretNamespace string) *SecretCertWatcher {
-	return &SecretCertWatcher{
+// The webhook service is expected in secretNamespace; an empty serviceName skips the DNS name check.
+func NewSecretCertWatcher(client client.Client, clientset kubernetes.Interface, secretName, secretNamespace, serviceName string) *SecretCertWatcher {
+	s := &SecretCertWatcher{
 		Client:          client,
 		clientset:       clientset,
 		secretName:      secretName,
 		secretNamespace: secretNamespace,
+		serviceName:     serviceName,
 		isReady:         make(chan struct{}),
 	}
+	s.loadCAs = s.loadCAsFromSecrets
+	return s
 }
 
 // NeedLeaderElection implements manager.LeaderElectionRunnable
@@ -94,23 +108,11 @@ func (s *SecretCertWatcher) Start(ctx context.Context) error {
 	// Initial load
 	s.performInitialLoad(ctx)
 
-	// Watch loop with backoff on failures
-	for {
-		select {
-		case <-ctx.Done():
-			secretLog.Info("Context cancelled, stopping secret watcher.")
-			return nil
-		default:
-			s.runWatch(ctx)
-			// Add small delay between watch restarts to avoid busy loop on persistent errors
-			select {
-			case <-ctx.Done():
-				return nil
-			case <-time.After(2 * time.Second):
-				// Continue to next watch attempt
-			}
-		}
-	}
+	// The informer relists and re-watches with backoff when the watch closes or its
+	// resourceVersion expires (410 Gone)
+	s.runInformer(ctx)
+	secretLog.Info("Context cancelled, stopping secret watcher.")
+	return nil
 }
 
 // performInitialLoad tries to load the secret once at the beginning.
@@ -159,47 +161,57 @@ func (s *SecretCertWatcher) performInitialLoad(ctx context.Context) {
 	}
 }
 
-// runWatch sets up and manages the watch on the secret.
-func (s *SecretCertWatcher) runWatch(ctx context.Context) {
-	watcher, err := s.clientset.CoreV1().Secrets(s.secretNamespace).Watch(ctx, metav1.ListOptions{
-		FieldSelector: fmt.Sprintf("metadata.name=%s", s.secretName),
-	})
-	if err != nil {
-		secretLog.Error(err, "Failed to create secret watch, retrying in 5 seconds")
-		time.Sleep(5 * time.Second)
-		return
-	}
-	defer watcher.Stop()
+// runInformer watches the secret through an informer filtered to it until ctx is done.
+func (s *SecretCertWatcher) runInformer(ctx context.Context) {
+	factory := informers.NewSharedInformerFactoryWithOptions(s.clientset, 0,
+		informers.WithNamespace(s.secretNamespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("metadata.name", s.secretName).String()
+		}))
+	informer := factory.Core().V1().Secrets().Informer()
 
-	secretLog.Info("Secret watch established")
-	for {
-		select {
-		case <-ctx.Done():
+	if err := informer.SetWatchErrorHandler(func(_ *toolscache.Reflector, err error) {
+		if errors.IsResourceExpired(err) || errors.IsGone(err) {
+			secretLog.V(1).Info("Secret watch expired, relisting", "reason", err.Error())
 			return
-		case event, ok := <-watcher.ResultChan():
-			if !ok {
-				secretLog.Info("Secret watch channel closed, restarting watch in 2 seconds.")
-				time.Sleep(2 * time.Second) // Prevent busy loop
-				return
-			}
-			
-			if event.Type == watch.Deleted {
-				secretLog.Info("Secret was deleted. Keeping last certificate for graceful rotation.")
-				// Don't clear cert - keep serving last valid cert until new one arrives
-				// This prevents downtime during rotation
-				continue
-			}
+		}
+		secretLog.Error(err, "Secret watch failed, retrying with backoff")
+	}); err != nil {
+		secretLog.Error(err, "Failed to set secret watch error handler")
+	}
 
-			secret, ok := event.Object.(*corev1.Secret)
-			if !ok {
-				secretLog.Info("Received non-secret object in watch stream")
-				continue
-			}
+	if _, err := informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		AddFunc: s.onSecret,
+		UpdateFunc: func(_, newObj interface{}) {
+			s.onSecret(newObj)
+		},
+		DeleteFunc: func(interface{}) {
+			secretLog.Info("Secret was deleted. Keeping last certificate for graceful rotation.")
+			// Don't clear cert - keep serving last valid cert until new one arrives
+			// This prevents downtime during rotation
+		},
+	}); err != nil {
+		secretLog.Error(err, "Failed to register secret event handler")
+		return
+	}
 
-			if err := s.loadCertificate(secret); err != nil {
-				secretLog.Error(err, "Failed to process secret from watch event")
-			}
-		}
+	factory.Start(ctx.Done())
+	if toolscache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
+		secretLog.Info("Secret watch established")
+	}
+	<-ctx.Done()
+	factory.Shutdown()
+}
+
+// onSecret loads the certificate of a secret added or updated in the informer
+func (s *SecretCertWatcher) onSecret(obj interface{}) {
+	secret, ok := obj.(*corev1.Secret)
+	if !ok || secret.Name != s.secretName {
+		secretLog.Info("Received unexpected object from secret informer")
+		return
+	}
+	if err := s.loadCertificate(secret); err != nil {
+		secretLog.Error(err, "Failed to process secret from watch event")
 	}
 }
 
@@ -239,6 +251,31 @@ func (s *SecretCertWatcher) loadCertificate(secret *corev1.Secret) error {
 		return fmt.Errorf("failed to parse certificate and key from secret %s: %w", s.secretName, err)
 	}
 
+	if cert.Leaf == nil {
+		if cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0]); err != nil {
+			return fmt.Errorf("failed to parse certificate from secret %s: %w", s.secretName, err)
+		}
+	}
+
+	// A certificate for another service would make every TLS handshake fail, so keep serving
+	// the last valid one instead
+	if err := s.verifyDNSName(cert.Leaf); err != nil {
+		secretLog.Error(err, "Rejecting webhook certificate, keeping the last valid certificate", "secret", s.secretName)
+		return err
+	}
+	metrics.ObserveCertificateExpiry(metrics.CertificateServer, cert.Leaf.NotAfter)
+
+	// A certificate that doesn't chain to the CA is still served, since the API server may
+	// trust it through a caBundle set by hand, but readiness fails until it is fixed
+	chainErr := s.verifyChain(&cert)
+	if chainErr != nil {
+		secretLog.Error(chainErr, "Webhook certificate does not chain to the configured CA, the API server will reject the TLS handshake",
+			"secret", s.secretName)
+	}
+	s.chainMu.Lock()
+	s.chainErr = chainErr
+	s.chainMu.Unlock()
+
 	s.cert.Store(&cert)
 	
 	// Save as last valid certificate
@@ -264,4 +301,127 @@ func (s *SecretCertWatcher) IsReady() bool {
 	default:
 		return false
 	}
-}
\ No newline at end of file
+}
+// ExpiresAt returns the expiry time of the served certificate, or false while none is loaded
+func (s *SecretCertWatcher) ExpiresAt() (time.Time, bool) {
+	cert, ok := s.cert.Load().(*tls.Certificate)
+	if !ok || cert == nil {
+		s.lastCertMu.RLock()
+		cert = s.lastValidCert
+		s.lastCertMu.RUnlock()
+	}
+	if cert == nil || cert.Leaf == nil {
+		return time.Time{}, false
+	}
+	return cert.Leaf.NotAfter, true
+}
+
+// CheckExpiry returns an error when the served certificate expires within minRemaining. While
+// no certificate is loaded it returns nil, leaving that to the certificate-ready check.
+func (s *SecretCertWatcher) CheckExpiry(minRemaining time.Duration) error {
+	notAfter, ok := s.ExpiresAt()
+	if !ok {
+		return nil
+	}
+	if remaining := time.Until(notAfter); remaining < minRemaining {
+		return fmt.Errorf("served webhook certificate expires at %s, in %.1f days, less than the required %.0f days",
+			notAfter.Format(time.RFC3339), remaining.Hours()/24, minRemaining.Hours()/24)
+	}
+	return nil
+}
+
+// ChainError returns why the served certificate does not chain to the CA in the CA secrets,
+// or nil if it does or no CA secret exists
+func (s *SecretCertWatcher) ChainError() error {
+	s.chainMu.RLock()
+	defer s.chainMu.RUnlock()
+	return s.chainErr
+}
+
+// verifyDNSName checks that leaf is valid for the DNS name the API server calls the webhook service by
+func (s *SecretCertWatcher) verifyDNSName(leaf *x509.Certificate) error {
+	if s.serviceName == "" {
+		return nil
+	}
+	expected := fmt.Sprintf("%s.%s.svc", s.serviceName, s.secretNamespace)
+	if err := leaf.VerifyHostname(expected); err != nil {
+		return fmt.Errorf("certificate in secret %s is not valid for %s (DNS names %v): %w",
+			s.secretName, expected, leaf.DNSNames, err)
+	}
+	return nil
+}
+
+// verifyChain verifies that the leaf of cert, with the intermediates that follow it in
+// tls.crt, chains to one of the CAs returned by loadCAs
+func (s *SecretCertWatcher) verifyChain(cert *tls.Certificate) error {
+	if s.loadCAs == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cas, err := s.loadCAs(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load the CA to verify the certificate of secret %s against: %w", s.secretName, err)
+	}
+	if len(cas) == 0 {
+		secretLog.V(1).Info("No CA secret found, not verifying the certificate chain", "secret", s.secretName)
+		return nil
+	}
+
+	roots := x509.NewCertPool()
+	for _, ca := range cas {
+		roots.AddCert(ca)
+	}
+	intermediates := x509.NewCertPool()
+	for _, der := range cert.Certificate[1:] {
+		intermediate, err := x509.ParseCertificate(der)
+		if err != nil {
+			return fmt.Errorf("failed to parse intermediate certificate in secret %s: %w", s.secretName, err)
+		}
+		intermediates.AddCert(intermediate)
+	}
+
+	if _, err := cert.Leaf.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}); err != nil {
+		return fmt.Errorf("certificate in secret %s (issuer %q) does not chain to the CA in secret %s: %w",
+			s.secretName, cert.Leaf.Issuer.CommonName, s.secretName+"-ca", err)
+	}
+	return nil
+}
+
+// loadCAsFromSecrets returns the CAs of the <secret>-ca secret and, during a CA transition,
+// of the <secret>-ca-new secret. Missing secrets are skipped.
+func (s *SecretCertWatcher) loadCAsFromSecrets(ctx context.Context) ([]*x509.Certificate, error) {
+	if s.clientset == nil {
+		return nil, nil
+	}
+
+	var cas []*x509.Certificate
+	for _, name := range []string{s.secretName + "-ca", s.secretName + "-ca-new"} {
+		secret, err := s.clientset.CoreV1().Secrets(s.secretNamespace).Get(ctx, name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		rest := secret.Data["ca.crt"]
+		for {
+			var block *pem.Block
+			if block, rest = pem.Decode(rest); block == nil {
+				break
+			}
+			ca, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse ca.crt of secret %s: %w", name, err)
+			}
+			cas = append(cas, ca)
+		}
+	}
+	return cas, nil
+}
@@ -0,0 +1,295 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cert
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/lpeano/KubeTemplater/internal/metrics"
+)
+
+// newServerSecret returns a TLS secret with a server certificate expiring at notAfter, issued
+// by caCert, for dnsNames (default webhook-service.kubetemplater-system.svc)
+func newServerSecret(notAfter time.Time, caCert *x509.Certificate, caKey *rsa.PrivateKey, dnsNames ...string) *corev1.Secret {
+	if len(dnsNames) == 0 {
+		dnsNames = []string{"webhook-service.kubetemplater-system.svc"}
+	}
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	Expect(err).NotTo(HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "webhook-service.kubetemplater-system.svc"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+		DNSNames:     dnsNames,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+	Expect(err).NotTo(HaveOccurred())
+	return &corev1.Secret{Data: map[string][]byte{
+		"tls.crt": pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		"tls.key": pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+	}}
+}
+
+var _ = Describe("SecretCertWatcher certificate expiry", func() {
+	var watcher *SecretCertWatcher
+
+	BeforeEach(func() {
+		watcher = NewSecretCertWatcher(nil, nil, "webhook-certs", "kubetemplater-system", "webhook-service")
+	})
+
+	// serverSecret returns a TLS secret with a server certificate expiring at notAfter
+	serverSecret := func(notAfter time.Time) *corev1.Secret {
+		caCert, caKey, _ := newExternalCA()
+		return newServerSecret(notAfter, caCert, caKey)
+	}
+
+	It("should report the expiry of the served certificate", func() {
+		_, ok := watcher.ExpiresAt()
+		Expect(ok).To(BeFalse())
+		Expect(watcher.CheckExpiry(30 * 24 * time.Hour)).To(Succeed())
+
+		notAfter := time.Now().Add(90 * 24 * time.Hour).Truncate(time.Second)
+		Expect(watcher.loadCertificate(serverSecret(notAfter))).To(Succeed())
+
+		expiresAt, ok := watcher.ExpiresAt()
+		Expect(ok).To(BeTrue())
+		Expect(expiresAt.Equal(notAfter)).To(BeTrue())
+		Expect(testutil.ToFloat64(metrics.WebhookCertificateExpiry.WithLabelValues(metrics.CertificateServer))).
+			To(Equal(float64(notAfter.Unix())))
+	})
+
+	It("should fail the expiry check when the served certificate expires within the threshold", func() {
+		Expect(watcher.loadCertificate(serverSecret(time.Now().Add(10 * 24 * time.Hour)))).To(Succeed())
+
+		Expect(watcher.CheckExpiry(7 * 24 * time.Hour)).To(Succeed())
+		Expect(watcher.CheckExpiry(14 * 24 * time.Hour)).To(MatchError(ContainSubstring("less than the required 14 days")))
+	})
+})
+
+var _ = Describe("SecretCertWatcher certificate chain", func() {
+	var (
+		watcher *SecretCertWatcher
+		caCert  *x509.Certificate
+		caKey   *rsa.PrivateKey
+		cas     []*x509.Certificate
+	)
+
+	BeforeEach(func() {
+		caCert, caKey, _ = newExternalCA()
+		cas = []*x509.Certificate{caCert}
+
+		watcher = NewSecretCertWatcher(nil, nil, "webhook-certs", "kubetemplater-system", "webhook-service")
+		watcher.loadCAs = func(context.Context) ([]*x509.Certificate, error) {
+			return cas, nil
+		}
+	})
+
+	It("should accept a certificate issued by the configured CA", func() {
+		Expect(watcher.loadCertificate(newServerSecret(time.Now().Add(time.Hour), caCert, caKey))).To(Succeed())
+		Expect(watcher.ChainError()).NotTo(HaveOccurred())
+	})
+
+	It("should detect a certificate that does not chain to the configured CA", func() {
+		otherCA, otherKey, _ := newExternalCA()
+		Expect(watcher.loadCertificate(newServerSecret(time.Now().Add(time.Hour), otherCA, otherKey))).To(Succeed())
+
+		Expect(watcher.ChainError()).To(MatchError(ContainSubstring(
+			"certificate in secret webhook-certs (issuer \"External CA\") does not chain to the CA in secret webhook-certs-ca")))
+		// The certificate is still served
+		Expect(watcher.IsReady()).To(BeTrue())
+
+		// Re-issuing the certificate from the configured CA clears the error
+		Expect(watcher.loadCertificate(newServerSecret(time.Now().Add(time.Hour), caCert, caKey))).To(Succeed())
+		Expect(watcher.ChainError()).NotTo(HaveOccurred())
+	})
+
+	It("should accept certificates of either CA during a CA transition", func() {
+		newCA, newKey, _ := newExternalCA()
+		cas = append(cas, newCA)
+
+		Expect(watcher.loadCertificate(newServerSecret(time.Now().Add(time.Hour), newCA, newKey))).To(Succeed())
+		Expect(watcher.ChainError()).NotTo(HaveOccurred())
+	})
+
+	It("should not verify the chain when no CA secret exists", func() {
+		cas = nil
+		otherCA, otherKey, _ := newExternalCA()
+		Expect(watcher.loadCertificate(newServerSecret(time.Now().Add(time.Hour), otherCA, otherKey))).To(Succeed())
+		Expect(watcher.ChainError()).NotTo(HaveOccurred())
+	})
+})
+
+var _ = Describe("SecretCertWatcher certificate DNS names", func() {
+	var (
+		watcher *SecretCertWatcher
+		caCert  *x509.Certificate
+		caKey   *rsa.PrivateKey
+	)
+
+	BeforeEach(func() {
+		caCert, caKey, _ = newExternalCA()
+		watcher = NewSecretCertWatcher(nil, nil, "webhook-certs", "kubetemplater-system", "webhook-service")
+	})
+
+	It("should reject a certificate for another service and keep the last valid certificate", func() {
+		valid := newServerSecret(time.Now().Add(time.Hour), caCert, caKey)
+		Expect(watcher.loadCertificate(valid)).To(Succeed())
+		served, err := watcher.GetCertificate(nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		wrong := newServerSecret(time.Now().Add(time.Hour), caCert, caKey,
+			"other-service.kubetemplater-system.svc", "other-service")
+		Expect(watcher.loadCertificate(wrong)).To(MatchError(ContainSubstring(
+			"certificate in secret webhook-certs is not valid for webhook-service.kubetemplater-system.svc")))
+
+		current, err := watcher.GetCertificate(nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(current).To(BeIdenticalTo(served))
+	})
+
+	It("should not become ready with a certificate for another service", func() {
+		wrong := newServerSecret(time.Now().Add(time.Hour), caCert, caKey, "webhook-service.other-namespace.svc")
+		Expect(watcher.loadCertificate(wrong)).NotTo(Succeed())
+		Expect(watcher.IsReady()).To(BeFalse())
+	})
+
+	It("should not check DNS names without a service name", func() {
+		watcher = NewSecretCertWatcher(nil, nil, "webhook-certs", "kubetemplater-system", "")
+		wrong := newServerSecret(time.Now().Add(time.Hour), caCert, caKey, "other-service.kubetemplater-system.svc")
+		Expect(watcher.loadCertificate(wrong)).To(Succeed())
+	})
+})
+
+var _ = Describe("SecretCertWatcher watch", func() {
+	var (
+		ctx       context.Context
+		cancel    context.CancelFunc
+		clientset *fake.Clientset
+		watchers  chan *watch.FakeWatcher
+		watcher   *SecretCertWatcher
+		caCert    *x509.Certificate
+		caKey     *rsa.PrivateKey
+		done      chan struct{}
+	)
+
+	// secretExpiringAt returns the webhook-certs secret with a certificate expiring at notAfter
+	secretExpiringAt := func(notAfter time.Time) *corev1.Secret {
+		secret := newServerSecret(notAfter, caCert, caKey)
+		secret.ObjectMeta = metav1.ObjectMeta{Name: "webhook-certs", Namespace: "kubetemplater-system"}
+		return secret
+	}
+
+	// servedExpiry returns the expiry of the served certificate
+	servedExpiry := func() time.Time {
+		expiresAt, _ := watcher.ExpiresAt()
+		return expiresAt
+	}
+
+	BeforeEach(func() {
+		caCert, caKey, _ = newExternalCA()
+		first := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+		clientset = fake.NewClientset(secretExpiringAt(first))
+
+		// Hand every watch the informer opens to the test
+		watchers = make(chan *watch.FakeWatcher, 10)
+		clientset.PrependWatchReactor("secrets", func(k8stesting.Action) (bool, watch.Interface, error) {
+			w := watch.NewFake()
+			watchers <- w
+			return true, w, nil
+		})
+
+		watcher = NewSecretCertWatcher(nil, clientset, "webhook-certs", "kubetemplater-system", "webhook-service")
+		ctx, cancel = context.WithCancel(context.Background())
+		done = make(chan struct{})
+		go func() {
+			defer close(done)
+			Expect(watcher.Start(ctx)).To(Succeed())
+		}()
+
+		Eventually(watcher.IsReady).Should(BeTrue())
+		Expect(servedExpiry().Equal(first)).To(BeTrue())
+	})
+
+	AfterEach(func() {
+		cancel()
+		Eventually(done).Should(BeClosed())
+	})
+
+	It("should recover after the watch channel closes", func() {
+		var w *watch.FakeWatcher
+		Eventually(watchers).Should(Receive(&w))
+		w.Stop()
+
+		// The informer watches again and keeps the last certificate meanwhile
+		Eventually(watchers, 10*time.Second).Should(Receive(&w))
+		Expect(servedExpiry()).NotTo(BeZero())
+
+		second := time.Now().Add(48 * time.Hour).Truncate(time.Second)
+		w.Modify(secretExpiringAt(second))
+		Eventually(servedExpiry).Should(BeTemporally("==", second))
+	})
+
+	It("should relist when the watch resourceVersion expires", func() {
+		var w *watch.FakeWatcher
+		Eventually(watchers).Should(Receive(&w))
+
+		// The update is missed by the watch and only seen by the relist
+		second := time.Now().Add(48 * time.Hour).Truncate(time.Second)
+		_, err := clientset.CoreV1().Secrets("kubetemplater-system").Update(ctx, secretExpiringAt(second), metav1.UpdateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		w.Error(&metav1.Status{
+			Status:  metav1.StatusFailure,
+			Code:    http.StatusGone,
+			Reason:  metav1.StatusReasonExpired,
+			Message: "too old resource version",
+		})
+		Eventually(servedExpiry, 10*time.Second).Should(BeTemporally("==", second))
+	})
+
+	It("should keep serving the last certificate when the secret is deleted", func() {
+		var w *watch.FakeWatcher
+		Eventually(watchers).Should(Receive(&w))
+		served, err := watcher.GetCertificate(nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		w.Delete(secretExpiringAt(time.Now()))
+		Consistently(func() (interface{}, error) {
+			return watcher.GetCertificate(nil)
+		}, 200*time.Millisecond).Should(BeIdenticalTo(served))
+	})
+})
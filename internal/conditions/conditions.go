@@ -0,0 +1,55 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package conditions sets the status conditions of a KubeTemplate.
+package conditions
+
+import (
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Set sets the condition conditionType of status, observed at generation. The transition
+// time only changes when the condition status does.
+func Set(status *kubetemplateriov1alpha1.KubeTemplateStatus, generation int64, conditionType string, conditionStatus metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             conditionStatus,
+		ObservedGeneration: generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// MarkFailed sets conditionType and Ready to False for reason
+func MarkFailed(status *kubetemplateriov1alpha1.KubeTemplateStatus, generation int64, conditionType, reason, message string) {
+	Set(status, generation, conditionType, metav1.ConditionFalse, reason, message)
+	Set(status, generation, kubetemplateriov1alpha1.ConditionReady, metav1.ConditionFalse, reason, message)
+}
+
+// MarkPending sets Ready to Unknown while generation waits for, or is in, processing
+func MarkPending(status *kubetemplateriov1alpha1.KubeTemplateStatus, generation int64, reason, message string) {
+	Set(status, generation, kubetemplateriov1alpha1.ConditionReady, metav1.ConditionUnknown, reason, message)
+}
+
+// SyncStatus sets the legacy Status field to the message of the Ready condition. Templates
+// last processed before conditions were introduced keep their Status.
+func SyncStatus(status *kubetemplateriov1alpha1.KubeTemplateStatus) {
+	if ready := meta.FindStatusCondition(status.Conditions, kubetemplateriov1alpha1.ConditionReady); ready != nil {
+		status.Status = ready.Message
+	}
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conditions
+
+import (
+	"time"
+
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Conditions", func() {
+	It("should fail the condition and Ready together", func() {
+		status := &kubetemplateriov1alpha1.KubeTemplateStatus{}
+		MarkFailed(status, 2, kubetemplateriov1alpha1.ConditionApplied, kubetemplateriov1alpha1.ReasonApplyFailed, "Error: denied")
+
+		for _, conditionType := range []string{kubetemplateriov1alpha1.ConditionApplied, kubetemplateriov1alpha1.ConditionReady} {
+			c := meta.FindStatusCondition(status.Conditions, conditionType)
+			Expect(c).NotTo(BeNil())
+			Expect(c.Status).To(Equal(metav1.ConditionFalse))
+			Expect(c.Reason).To(Equal(kubetemplateriov1alpha1.ReasonApplyFailed))
+			Expect(c.ObservedGeneration).To(Equal(int64(2)))
+		}
+		Expect(meta.FindStatusCondition(status.Conditions, kubetemplateriov1alpha1.ConditionPolicyValidated)).To(BeNil())
+	})
+
+	It("should keep the transition time while the condition status is unchanged", func() {
+		status := &kubetemplateriov1alpha1.KubeTemplateStatus{}
+		MarkPending(status, 1, kubetemplateriov1alpha1.ReasonQueued, "Queued for processing")
+		queued := meta.FindStatusCondition(status.Conditions, kubetemplateriov1alpha1.ConditionReady).LastTransitionTime
+		status.Conditions[0].LastTransitionTime = metav1.NewTime(queued.Add(-time.Minute))
+
+		MarkPending(status, 2, kubetemplateriov1alpha1.ReasonProcessing, "Processing")
+		ready := meta.FindStatusCondition(status.Conditions, kubetemplateriov1alpha1.ConditionReady)
+		Expect(ready.LastTransitionTime.Time).To(Equal(queued.Add(-time.Minute)))
+		Expect(ready.Reason).To(Equal(kubetemplateriov1alpha1.ReasonProcessing))
+		Expect(ready.ObservedGeneration).To(Equal(int64(2)))
+	})
+
+	It("should mirror the Ready message in the legacy status", func() {
+		status := &kubetemplateriov1alpha1.KubeTemplateStatus{Status: "Completed"}
+		SyncStatus(status)
+		Expect(status.Status).To(Equal("Completed"))
+
+		Set(status, 1, kubetemplateriov1alpha1.ConditionReady, metav1.ConditionFalse, kubetemplateriov1alpha1.ReasonPaused, "Paused due to repeated failures")
+		SyncStatus(status)
+		Expect(status.Status).To(Equal("Paused due to repeated failures"))
+	})
+})
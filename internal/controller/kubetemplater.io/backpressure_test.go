@@ -0,0 +1,114 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubetemplaterio
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	"github.com/lpeano/KubeTemplater/internal/queue"
+)
+
+var _ = Describe("Queue saturation back-pressure", func() {
+	var (
+		ctx        context.Context
+		fakeClient client.Client
+		workQueue  *queue.WorkQueue
+		reconciler *KubeTemplateReconciler
+	)
+
+	key := types.NamespacedName{Namespace: "default", Name: "test-template"}
+	busy := types.NamespacedName{Namespace: "default", Name: "busy"}
+
+	reconcile := func() ctrl.Result {
+		result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		return result
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		scheme := runtime.NewScheme()
+		Expect(kubetemplateriov1alpha1.AddToScheme(scheme)).To(Succeed())
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithStatusSubresource(&kubetemplateriov1alpha1.KubeTemplate{}).
+			Build()
+
+		workQueue = queue.NewWorkQueue()
+		workQueue.SaturationDepth = 1
+		reconciler = &KubeTemplateReconciler{
+			Client:                    fakeClient,
+			Scheme:                    scheme,
+			WorkQueue:                 workQueue,
+			PeriodicReconcileInterval: time.Minute,
+			SaturationRequeueDelay:    30 * time.Second,
+		}
+
+		Expect(fakeClient.Create(ctx, &kubetemplateriov1alpha1.KubeTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+		})).To(Succeed())
+	})
+
+	AfterEach(func() {
+		workQueue.Shutdown()
+	})
+
+	It("should requeue a new template with a delay while the queue is saturated", func() {
+		workQueue.Enqueue(busy, 0)
+		Expect(workQueue.IsSaturated()).To(BeTrue())
+
+		Expect(reconcile()).To(Equal(ctrl.Result{RequeueAfter: 30 * time.Second}))
+		Expect(workQueue.Contains(key)).To(BeFalse())
+
+		var kubeTemplate kubetemplateriov1alpha1.KubeTemplate
+		Expect(fakeClient.Get(ctx, key, &kubeTemplate)).To(Succeed())
+		Expect(kubeTemplate.Status.ProcessingPhase).To(Equal("Queued"))
+
+		// Once the workers catch up the template is enqueued
+		_, ok := workQueue.Dequeue()
+		Expect(ok).To(BeTrue())
+		Expect(reconcile()).To(Equal(ctrl.Result{}))
+		Expect(workQueue.Contains(key)).To(BeTrue())
+	})
+
+	It("should use the default delay when none is set", func() {
+		reconciler.SaturationRequeueDelay = 0
+		workQueue.Enqueue(busy, 0)
+
+		Expect(reconcile()).To(Equal(ctrl.Result{RequeueAfter: DefaultSaturationRequeueDelay}))
+	})
+
+	It("should enqueue right away while the queue is not saturated", func() {
+		workQueue.SaturationDepth = 2
+		workQueue.Enqueue(busy, 0)
+
+		Expect(reconcile()).To(Equal(ctrl.Result{}))
+		Expect(workQueue.Contains(key)).To(BeTrue())
+	})
+})
@@ -0,0 +1,156 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubetemplaterio
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	"github.com/lpeano/KubeTemplater/internal/manifest"
+)
+
+var _ = Describe("Drift correction apply", func() {
+	var (
+		ctx          context.Context
+		fakeClient   client.Client
+		reconciler   *KubeTemplateReconciler
+		kubeTemplate *kubetemplateriov1alpha1.KubeTemplate
+		realApplies  int
+		// conflicting makes the server answer the next real apply with a conflict, as it may
+		// while a concurrent write settles
+		conflicting bool
+	)
+
+	configMapKey := types.NamespacedName{Namespace: "default", Name: "app-config"}
+
+	liveConfigMap := func(c client.Client) *corev1.ConfigMap {
+		cm := &corev1.ConfigMap{}
+		Expect(c.Get(ctx, configMapKey, cm)).To(Succeed())
+		return cm
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		realApplies = 0
+		conflicting = false
+
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(kubetemplateriov1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		// The fake client does not support Server-Side Apply: a dry run leaves the desired object
+		// as is, and a real apply is emulated with Update, conflicting once when the resource was
+		// edited after the dry run
+		patch := func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			if patch.Type() != types.ApplyPatchType {
+				return c.Patch(ctx, obj, patch, opts...)
+			}
+			patchOpts := &client.PatchOptions{}
+			patchOpts.ApplyOptions(opts)
+
+			live := &corev1.ConfigMap{}
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), live)).To(Succeed())
+
+			if len(patchOpts.DryRun) > 0 {
+				// Someone edits the resource between the dry run and the real apply
+				live.Labels = map[string]string{"edited": "true"}
+				if err := c.Update(ctx, live); err != nil {
+					return err
+				}
+				conflicting = true
+				return nil
+			}
+
+			realApplies++
+			if conflicting {
+				conflicting = false
+				return errors.NewConflict(schema.GroupResource{Resource: "configmaps"}, obj.GetName(),
+					fmt.Errorf("the object has been modified"))
+			}
+			obj.SetResourceVersion(live.GetResourceVersion())
+			return c.Update(ctx, obj)
+		}
+
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithInterceptorFuncs(interceptor.Funcs{Patch: patch}).
+			WithStatusSubresource(&kubetemplateriov1alpha1.KubeTemplate{}).
+			Build()
+
+		Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: configMapKey.Name, Namespace: configMapKey.Namespace},
+			Data:       map[string]string{"key": "drifted"},
+		})).To(Succeed())
+
+		object := `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"app-config","namespace":"default"},"data":{"key":"value"}}`
+		kubeTemplate = &kubetemplateriov1alpha1.KubeTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default"},
+			Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+				Templates: []kubetemplateriov1alpha1.Template{
+					{Object: runtime.RawExtension{Raw: []byte(object)}},
+				},
+			},
+		}
+		Expect(fakeClient.Create(ctx, kubeTemplate)).To(Succeed())
+
+		reconciler = &KubeTemplateReconciler{
+			Client:                    fakeClient,
+			Scheme:                    scheme,
+			DriftApplyConflictRetries: 4,
+		}
+	})
+
+	It("should re-apply when the apply conflicts with a change after the dry run", func() {
+		Expect(reconciler.applyTemplateResources(ctx, kubeTemplate)).To(Succeed())
+
+		Expect(realApplies).To(Equal(2))
+		Expect(liveConfigMap(fakeClient).Data).To(HaveKeyWithValue("key", "value"))
+		Expect(kubeTemplate.Status.ResourcesSynced).To(Equal(1))
+		Expect(kubeTemplate.Status.DriftDetectionCount).To(Equal(1))
+	})
+
+	It("should keep the tracking labels the worker sets", func() {
+		Expect(reconciler.applyTemplateResources(ctx, kubeTemplate)).To(Succeed())
+
+		labels := liveConfigMap(fakeClient).Labels
+		Expect(labels).To(HaveKeyWithValue(manifest.TemplateNameLabel, "test-template"))
+		Expect(labels).To(HaveKeyWithValue(manifest.TemplateNamespaceLabel, "default"))
+	})
+
+	It("should give up on the resource when retries are disabled", func() {
+		reconciler.DriftApplyConflictRetries = 0
+		Expect(reconciler.applyTemplateResources(ctx, kubeTemplate)).To(Succeed())
+
+		Expect(realApplies).To(Equal(1))
+		Expect(liveConfigMap(fakeClient).Data).To(HaveKeyWithValue("key", "drifted"))
+		Expect(kubeTemplate.Status.ResourcesSynced).To(BeZero())
+	})
+})
@@ -21,16 +21,25 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"time"
 
 	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	"github.com/lpeano/KubeTemplater/internal/cache"
+	"github.com/lpeano/KubeTemplater/internal/conditions"
+	"github.com/lpeano/KubeTemplater/internal/impersonation"
+	"github.com/lpeano/KubeTemplater/internal/manifest"
+	"github.com/lpeano/KubeTemplater/internal/pause"
 	"github.com/lpeano/KubeTemplater/internal/queue"
+	"github.com/lpeano/KubeTemplater/internal/summary"
+	corev1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
@@ -38,7 +47,6 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
-	"sigs.k8s.io/yaml"
 )
 
 // KubeTemplateReconciler reconciles a KubeTemplate object
@@ -48,8 +56,31 @@ type KubeTemplateReconciler struct {
 	OperatorNamespace         string
 	WorkQueue                 *queue.WorkQueue
 	PeriodicReconcileInterval time.Duration
+	// PolicyCache and ImpersonatingClients make drift correction apply as the policy's
+	// ServiceAccount, like the worker does. Optional: without a cache the operator identity is used.
+	PolicyCache          *cache.PolicyCache
+	ImpersonatingClients impersonation.ClientFactory
+	// DriftApplyConflictRetries is how many times a drift-correcting apply is retried when it
+	// conflicts with a concurrent write to the resource (0 = no retry)
+	DriftApplyConflictRetries int
+	// FieldManager is the server-side apply field manager of templates whose policy and template
+	// don't set one; it must match the worker's so drift correction keeps the same field ownership
+	// (empty = manifest.DefaultFieldManager)
+	FieldManager string
+	// Recorder emits an event on the KubeTemplate for every resource drift correction applies
+	// (nil = no events)
+	Recorder record.EventRecorder
+	// PauseAutoResumeAfter is how long a template paused after its last retry cycle stays
+	// paused before it is queued again with its retry cycles reset (0 = until resumed manually)
+	PauseAutoResumeAfter time.Duration
+	// SaturationRequeueDelay is how long a template waits to be enqueued while the work queue
+	// reports saturation (0 = DefaultSaturationRequeueDelay)
+	SaturationRequeueDelay time.Duration
 }
 
+// DefaultSaturationRequeueDelay is the SaturationRequeueDelay used when none is set
+const DefaultSaturationRequeueDelay = 10 * time.Second
+
 // +kubebuilder:rbac:groups=kubetemplater.io,resources=kubetemplates,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=kubetemplater.io,resources=kubetemplates/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=kubetemplater.io,resources=kubetemplates/finalizers,verbs=update
@@ -77,22 +108,30 @@ func (r *KubeTemplateReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	//   TODO: Re-enable when DynamicInformer is implemented for drift detection
 	// Note: Failed templates now automatically retry with reset counter after 5 min cooldown
 
-	// Handle Paused templates with resume annotation
+	// A template frozen with the pause annotation is neither queued nor drift-corrected
+	if pause.Requested(&kubeTemplate) {
+		return r.pauseManually(ctx, &kubeTemplate)
+	}
+
+	// Handle Paused templates with resume annotation, or manually paused ones whose pause
+	// annotation was removed
 	if kubeTemplate.Status.ProcessingPhase == "Paused" {
-		if resumeValue, hasResume := kubeTemplate.Annotations["kubetemplater.io/resume"]; hasResume && resumeValue == "true" {
-			log.Info("Resume annotation detected, resetting template to Queued",
+		if pause.ResumeRequested(&kubeTemplate) || kubeTemplate.Status.PausedReason == pause.ReasonManual {
+			log.Info("Resuming paused template, resetting template to Queued",
 				"name", kubeTemplate.Name,
-				"namespace", kubeTemplate.Namespace)
+				"namespace", kubeTemplate.Namespace,
+				"pausedReason", kubeTemplate.Status.PausedReason)
 			
 			// Reset to Queued and clear pause info
 			kubeTemplate.Status.ProcessingPhase = "Queued"
+			conditions.MarkPending(&kubeTemplate.Status, kubeTemplate.Generation, kubetemplateriov1alpha1.ReasonQueued, "Queued for processing")
 			kubeTemplate.Status.PausedReason = ""
 			kubeTemplate.Status.PausedAt = nil
 			kubeTemplate.Status.RetryCount = 0
 			now := metav1.Now()
 			kubeTemplate.Status.QueuedAt = &now
 			
-			if err := r.Status().Update(ctx, &kubeTemplate); err != nil {
+			if err := r.updateStatus(ctx, &kubeTemplate); err != nil {
 				if !errors.IsConflict(err) {
 					log.Error(err, "Failed to update status after resume")
 					return ctrl.Result{}, err
@@ -100,14 +139,18 @@ func (r *KubeTemplateReconciler) Reconcile(ctx context.Context, req ctrl.Request
 			}
 			
 			// Enqueue for processing
-			r.WorkQueue.Enqueue(types.NamespacedName{
-				Namespace: kubeTemplate.Namespace,
-				Name:      kubeTemplate.Name,
-			}, 0)
+			if !r.enqueue(ctx, &kubeTemplate) {
+				return r.backOff(), nil
+			}
 			
 			return ctrl.Result{}, nil
 		}
 		
+		// Templates paused after their last retry cycle resume on their own after the cooldown
+		if r.PauseAutoResumeAfter > 0 && pause.RetriesExhausted(kubeTemplate.Status.PausedReason) {
+			return r.autoResume(ctx, &kubeTemplate)
+		}
+
 		// Paused template without resume annotation - do nothing
 		return ctrl.Result{}, nil
 	}
@@ -131,13 +174,14 @@ func (r *KubeTemplateReconciler) Reconcile(ctx context.Context, req ctrl.Request
 			
 			// Reset to Queued for fresh processing
 			kubeTemplate.Status.ProcessingPhase = "Queued"
+			conditions.MarkPending(&kubeTemplate.Status, kubeTemplate.Generation, kubetemplateriov1alpha1.ReasonQueued, "Queued for processing")
 			kubeTemplate.Status.RetryCount = 0
 			kubeTemplate.Status.RetryCycle = 0
 			now := metav1.Now()
 			kubeTemplate.Status.QueuedAt = &now
 			kubeTemplate.Status.AppliedSpecHash = currentHash
 			
-			if err := r.Status().Update(ctx, &kubeTemplate); err != nil {
+			if err := r.updateStatus(ctx, &kubeTemplate); err != nil {
 				if !errors.IsConflict(err) {
 					log.Error(err, "Failed to update status after spec change on failed template")
 					return ctrl.Result{}, err
@@ -145,10 +189,9 @@ func (r *KubeTemplateReconciler) Reconcile(ctx context.Context, req ctrl.Request
 			}
 			
 			// Enqueue immediately for processing
-			r.WorkQueue.Enqueue(types.NamespacedName{
-				Namespace: kubeTemplate.Namespace,
-				Name:      kubeTemplate.Name,
-			}, 0)
+			if !r.enqueue(ctx, &kubeTemplate) {
+				return r.backOff(), nil
+			}
 			
 			log.Info("Failed template re-queued after spec change",
 				"name", kubeTemplate.Name,
@@ -179,6 +222,35 @@ func (r *KubeTemplateReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return ctrl.Result{}, nil
 	}
 
+	// Dry-run templates only run again when their spec changes and are never drift-corrected,
+	// which would apply them for real
+	if kubeTemplate.Status.ProcessingPhase == "DryRunCompleted" {
+		if calculateSpecHash(kubeTemplate.Spec) == kubeTemplate.Status.AppliedSpecHash {
+			return ctrl.Result{}, nil
+		}
+		log.Info("Spec change detected on dry-run template, re-queueing template",
+			"name", kubeTemplate.Name,
+			"namespace", kubeTemplate.Namespace)
+
+		kubeTemplate.Status.ProcessingPhase = "Queued"
+		conditions.MarkPending(&kubeTemplate.Status, kubeTemplate.Generation, kubetemplateriov1alpha1.ReasonQueued, "Queued for processing")
+		kubeTemplate.Status.RetryCount = 0
+		now := metav1.Now()
+		kubeTemplate.Status.QueuedAt = &now
+
+		if err := r.updateStatus(ctx, &kubeTemplate); err != nil {
+			if !errors.IsConflict(err) {
+				log.Error(err, "Failed to update status after spec change on dry-run template")
+				return ctrl.Result{}, err
+			}
+		}
+
+		if !r.enqueue(ctx, &kubeTemplate) {
+			return r.backOff(), nil
+		}
+		return ctrl.Result{}, nil
+	}
+
 	// For completed templates, check if spec has changed via hash comparison
 	if kubeTemplate.Status.ProcessingPhase == "Completed" {
 		// Calculate current spec hash
@@ -190,7 +262,7 @@ func (r *KubeTemplateReconciler) Reconcile(ctx context.Context, req ctrl.Request
 				"name", kubeTemplate.Name,
 				"namespace", kubeTemplate.Namespace)
 			kubeTemplate.Status.AppliedSpecHash = currentHash
-			if err := r.Status().Update(ctx, &kubeTemplate); err != nil {
+			if err := r.updateStatus(ctx, &kubeTemplate); err != nil {
 				if !errors.IsConflict(err) {
 					log.Error(err, "Failed to update AppliedSpecHash")
 				}
@@ -208,12 +280,13 @@ func (r *KubeTemplateReconciler) Reconcile(ctx context.Context, req ctrl.Request
 			
 			// Reset to Queued for full reprocessing
 			kubeTemplate.Status.ProcessingPhase = "Queued"
+			conditions.MarkPending(&kubeTemplate.Status, kubeTemplate.Generation, kubetemplateriov1alpha1.ReasonQueued, "Queued for processing")
 			kubeTemplate.Status.RetryCount = 0
 			now := metav1.Now()
 			kubeTemplate.Status.QueuedAt = &now
 			kubeTemplate.Status.AppliedSpecHash = currentHash
 			
-			if err := r.Status().Update(ctx, &kubeTemplate); err != nil {
+			if err := r.updateStatus(ctx, &kubeTemplate); err != nil {
 				if !errors.IsConflict(err) {
 					log.Error(err, "Failed to update status after spec change")
 					return ctrl.Result{}, err
@@ -221,10 +294,9 @@ func (r *KubeTemplateReconciler) Reconcile(ctx context.Context, req ctrl.Request
 			}
 			
 			// Enqueue for processing
-			r.WorkQueue.Enqueue(types.NamespacedName{
-				Namespace: kubeTemplate.Namespace,
-				Name:      kubeTemplate.Name,
-			}, 0)
+			if !r.enqueue(ctx, &kubeTemplate) {
+				return r.backOff(), nil
+			}
 			
 			return ctrl.Result{}, nil
 		}
@@ -280,13 +352,14 @@ func (r *KubeTemplateReconciler) Reconcile(ctx context.Context, req ctrl.Request
 
 			// Update status fields
 			latestTemplate.Status.ProcessingPhase = "Queued"
+			conditions.MarkPending(&latestTemplate.Status, latestTemplate.Generation, kubetemplateriov1alpha1.ReasonQueued, "Queued for processing")
 			now := metav1.Now()
 			latestTemplate.Status.QueuedAt = &now
 			latestTemplate.Status.ProcessedAt = nil
 			latestTemplate.Status.RetryCount = 0
 
 			// Attempt status update
-			return r.Status().Update(ctx, &latestTemplate)
+			return r.updateStatus(ctx, &latestTemplate)
 		})
 
 		if err != nil {
@@ -303,10 +376,9 @@ func (r *KubeTemplateReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	// Only enqueue for async processing if not already Completed
 	// Completed templates are handled by periodic reconciliation (RequeueAfter)
 	if kubeTemplate.Status.ProcessingPhase != "Completed" {
-		r.WorkQueue.Enqueue(types.NamespacedName{
-			Namespace: kubeTemplate.Namespace,
-			Name:      kubeTemplate.Name,
-		}, 0) // Priority 0 (normal)
+		if !r.enqueue(ctx, &kubeTemplate) {
+			return r.backOff(), nil
+		}
 
 		log.Info("Enqueued KubeTemplate for processing", "name", kubeTemplate.Name, "namespace", kubeTemplate.Namespace)
 	}
@@ -314,6 +386,123 @@ func (r *KubeTemplateReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	return ctrl.Result{}, nil
 }
 
+// autoResume queues a template paused after its last retry cycle again once it has been paused
+// for PauseAutoResumeAfter, and otherwise reconciles it again when the cooldown ends
+func (r *KubeTemplateReconciler) autoResume(ctx context.Context, kubeTemplate *kubetemplateriov1alpha1.KubeTemplate) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	// Templates paused before PausedAt was recorded have waited long enough
+	if kubeTemplate.Status.PausedAt != nil {
+		if remaining := time.Until(kubeTemplate.Status.PausedAt.Add(r.PauseAutoResumeAfter)); remaining > 0 {
+			return ctrl.Result{RequeueAfter: remaining}, nil
+		}
+	}
+
+	log.Info("Pause cooldown elapsed, resetting template to Queued",
+		"name", kubeTemplate.Name,
+		"namespace", kubeTemplate.Namespace,
+		"cooldown", r.PauseAutoResumeAfter,
+		"pausedReason", kubeTemplate.Status.PausedReason)
+
+	kubeTemplate.Status.ProcessingPhase = "Queued"
+	conditions.MarkPending(&kubeTemplate.Status, kubeTemplate.Generation, kubetemplateriov1alpha1.ReasonQueued,
+		fmt.Sprintf("Queued again after a pause cooldown of %s", r.PauseAutoResumeAfter))
+	kubeTemplate.Status.PausedReason = ""
+	kubeTemplate.Status.PausedAt = nil
+	kubeTemplate.Status.RetryCount = 0
+	kubeTemplate.Status.RetryCycle = 0
+	now := metav1.Now()
+	kubeTemplate.Status.QueuedAt = &now
+
+	if err := r.updateStatus(ctx, kubeTemplate); err != nil {
+		if errors.IsConflict(err) {
+			// Try again with the latest version
+			return ctrl.Result{RequeueAfter: 1 * time.Second}, nil
+		}
+		log.Error(err, "Failed to update status after pause cooldown")
+		return ctrl.Result{}, err
+	}
+
+	if r.Recorder != nil {
+		r.Recorder.Event(kubeTemplate, corev1.EventTypeNormal, "TemplateResumed",
+			fmt.Sprintf("Template resumed after being paused for %s", r.PauseAutoResumeAfter))
+	}
+	if !r.enqueue(ctx, kubeTemplate) {
+		return r.backOff(), nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// pauseManually sets the template to Paused for the pause annotation. A template being
+// processed is left to the worker and paused once the worker is done with it.
+func (r *KubeTemplateReconciler) pauseManually(ctx context.Context, kubeTemplate *kubetemplateriov1alpha1.KubeTemplate) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	switch {
+	case kubeTemplate.Status.ProcessingPhase == "Paused" && kubeTemplate.Status.PausedReason == pause.ReasonManual:
+		return ctrl.Result{}, nil
+	case kubeTemplate.Status.ProcessingPhase == "Processing":
+		log.V(1).Info("Pause annotation detected on template being processed, waiting for the worker",
+			"name", kubeTemplate.Name,
+			"namespace", kubeTemplate.Namespace)
+		return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+	}
+
+	log.Info("Pause annotation detected, pausing template",
+		"name", kubeTemplate.Name,
+		"namespace", kubeTemplate.Namespace,
+		"phase", kubeTemplate.Status.ProcessingPhase)
+
+	now := metav1.Now()
+	kubeTemplate.Status.ProcessingPhase = "Paused"
+	kubeTemplate.Status.PausedReason = pause.ReasonManual
+	kubeTemplate.Status.PausedAt = &now
+	conditions.Set(&kubeTemplate.Status, kubeTemplate.Generation, kubetemplateriov1alpha1.ConditionReady, metav1.ConditionFalse,
+		kubetemplateriov1alpha1.ReasonPaused, "Paused by the "+pause.Annotation+" annotation")
+
+	if err := r.updateStatus(ctx, kubeTemplate); err != nil {
+		if !errors.IsConflict(err) {
+			log.Error(err, "Failed to update status to Paused")
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{}, nil
+}
+
+// enqueue adds the template to the work queue with a snapshot of its current policy, so the
+// worker can still process it if the policy is deleted before the item is dequeued.
+// It returns false without enqueueing while the queue is saturated and the template isn't
+// queued yet; the template stays Queued and the caller reconciles it again after backOff.
+func (r *KubeTemplateReconciler) enqueue(ctx context.Context, kubeTemplate *kubetemplateriov1alpha1.KubeTemplate) bool {
+	key := types.NamespacedName{Namespace: kubeTemplate.Namespace, Name: kubeTemplate.Name}
+
+	if r.WorkQueue.IsSaturated() && !r.WorkQueue.Contains(key) {
+		logf.FromContext(ctx).V(1).Info("Work queue is saturated, delaying enqueue", "item", key, "delay", r.backOff().RequeueAfter)
+		return false
+	}
+
+	var policy *kubetemplateriov1alpha1.KubeTemplatePolicy
+	if r.PolicyCache != nil {
+		if cached, err := r.PolicyCache.Get(ctx, kubeTemplate.Namespace, r.OperatorNamespace); err == nil {
+			policy = cached.DeepCopy()
+		} else {
+			logf.FromContext(ctx).V(1).Info("No policy to snapshot for queued template", "item", key, "reason", err.Error())
+		}
+	}
+
+	r.WorkQueue.EnqueueWithPolicy(key, kubeTemplate.Spec.ApplyPriority, policy)
+	return true
+}
+
+// backOff reconciles a template that wasn't enqueued because the work queue is saturated
+// again after SaturationRequeueDelay
+func (r *KubeTemplateReconciler) backOff() ctrl.Result {
+	if r.SaturationRequeueDelay > 0 {
+		return ctrl.Result{RequeueAfter: r.SaturationRequeueDelay}
+	}
+	return ctrl.Result{RequeueAfter: DefaultSaturationRequeueDelay}
+}
+
 // applyTemplateResources applies the resources defined in the template using Server-Side Apply with dry-run drift detection
 // This is used during periodic reconciliation to detect and correct drift accurately
 func (r *KubeTemplateReconciler) applyTemplateResources(ctx context.Context, kubeTemplate *kubetemplateriov1alpha1.KubeTemplate) error {
@@ -323,13 +512,38 @@ func (r *KubeTemplateReconciler) applyTemplateResources(ctx context.Context, kub
 	syncedResources := 0
 	driftDetected := false
 
-	for _, template := range kubeTemplate.Spec.Templates {
+	// Apply with the same identity as the worker
+	var applyClient client.Client = r.Client
+	var policy *kubetemplateriov1alpha1.KubeTemplatePolicy
+	if r.PolicyCache != nil {
+		var err error
+		if policy, err = r.PolicyCache.Get(ctx, kubeTemplate.Namespace, r.OperatorNamespace); err != nil {
+			return err
+		}
+		if applyClient, err = impersonation.ClientForPolicy(r.Client, r.ImpersonatingClients, policy); err != nil {
+			return err
+		}
+	}
+
+	for _, idx := range manifest.ApplyOrder(kubeTemplate.Spec.Templates) {
+		template := kubeTemplate.Spec.Templates[idx]
 		// Parse the raw template object to unstructured
-		var obj unstructured.Unstructured
-		if err := yaml.Unmarshal(template.Object.Raw, &obj); err != nil {
+		obj, err := manifest.DecodeWithParameters(template.Object.Raw, kubeTemplate.Spec.Parameters)
+		if err != nil {
 			log.Error(err, "Failed to unmarshal template object")
 			continue
 		}
+		// Applied as the worker applies it; a status subresource is not drift-corrected
+		if !template.ApplyStatus {
+			manifest.StripStatus(&obj)
+		}
+		manifest.StripServerManagedMetadata(&obj)
+		// Propagated and tracking labels aren't compared for drift, but a correcting apply must
+		// keep them: the field manager owns them, so leaving them out would remove them
+		if policy != nil {
+			manifest.PropagateLabels(&obj, kubeTemplate, policy.Spec.PropagateLabels, policy.Spec.OverrideTemplateLabels)
+		}
+		manifest.SetTrackingLabels(&obj, kubeTemplate)
 
 		// Step 1: Get current resource state
 		currentObj := &unstructured.Unstructured{}
@@ -341,8 +555,8 @@ func (r *KubeTemplateReconciler) applyTemplateResources(ctx context.Context, kub
 
 		// Step 2: Dry-run SSA to see what WOULD change
 		dryRunObj := obj.DeepCopy()
-		fieldManager := "kubetemplater"
-		dryRunErr := r.Client.Patch(ctx, dryRunObj, client.Apply,
+		fieldManager := manifest.FieldManager(template, policy, r.FieldManager)
+		dryRunErr := applyClient.Patch(ctx, dryRunObj, client.Apply,
 			client.FieldOwner(fieldManager),
 			client.ForceOwnership,
 			client.DryRunAll)
@@ -387,7 +601,7 @@ func (r *KubeTemplateReconciler) applyTemplateResources(ctx context.Context, kub
 
 		// Step 4: Apply for real ONLY if drift detected or resource missing
 		if resourceDrifted {
-			if err := r.Client.Patch(ctx, &obj, client.Apply,
+			if err := r.applyWithConflictRetry(ctx, applyClient, &obj,
 				client.FieldOwner(fieldManager),
 				client.ForceOwnership); err != nil {
 				log.Error(err, "Failed to apply object",
@@ -400,6 +614,13 @@ func (r *KubeTemplateReconciler) applyTemplateResources(ctx context.Context, kub
 				"kind", obj.GetKind(),
 				"name", obj.GetName(),
 				"namespace", obj.GetNamespace())
+			if r.Recorder != nil {
+				message := fmt.Sprintf("Corrected drift of %s %s", obj.GetAPIVersion(), describeObject(&obj))
+				if getErr != nil {
+					message = fmt.Sprintf("Re-created missing %s %s", obj.GetAPIVersion(), describeObject(&obj))
+				}
+				r.Recorder.Event(kubeTemplate, corev1.EventTypeNormal, "DriftCorrected", message)
+			}
 		}
 
 		syncedResources++
@@ -427,7 +648,7 @@ func (r *KubeTemplateReconciler) applyTemplateResources(ctx context.Context, kub
 		}
 
 		// Update status
-		if err := r.Status().Update(ctx, kubeTemplate); err != nil {
+		if err := r.updateStatus(ctx, kubeTemplate); err != nil {
 			log.Error(err, "Failed to update template status after reconciliation")
 			return err
 		}
@@ -440,6 +661,36 @@ func (r *KubeTemplateReconciler) applyTemplateResources(ctx context.Context, kub
 	return nil
 }
 
+// updateStatus writes the status of kubeTemplate, refreshing the legacy Status from the
+// conditions and the summary first
+func (r *KubeTemplateReconciler) updateStatus(ctx context.Context, kubeTemplate *kubetemplateriov1alpha1.KubeTemplate) error {
+	conditions.SyncStatus(&kubeTemplate.Status)
+	kubeTemplate.Status.Summary = summary.Compute(&kubeTemplate.Status)
+	return r.Status().Update(ctx, kubeTemplate)
+}
+
+// applyWithConflictRetry applies obj with Server-Side Apply, retrying on conflict up to
+// DriftApplyConflictRetries times. Template objects carry no resourceVersion and the apply forces
+// ownership, so the apply itself doesn't conflict; the retry covers conflicts the server returns
+// while concurrent writes settle, e.g. from admission webhooks or aggregated API servers.
+func (r *KubeTemplateReconciler) applyWithConflictRetry(ctx context.Context, applyClient client.Client, obj *unstructured.Unstructured, opts ...client.PatchOption) error {
+	backoff := retry.DefaultRetry
+	backoff.Steps = max(r.DriftApplyConflictRetries, 0) + 1
+
+	return retry.RetryOnConflict(backoff, func() error {
+		err := applyClient.Patch(ctx, obj, client.Apply, opts...)
+		if !errors.IsConflict(err) {
+			return err
+		}
+
+		logf.FromContext(ctx).V(1).Info("Apply conflicted with a concurrent change, retrying",
+			"kind", obj.GetKind(),
+			"name", obj.GetName(),
+			"namespace", obj.GetNamespace())
+		return err
+	})
+}
+
 // calculateSpecHash computes SHA256 hash of the template spec for versioning
 func calculateSpecHash(spec kubetemplateriov1alpha1.KubeTemplateSpec) string {
 	specJSON, err := json.Marshal(spec)
@@ -451,6 +702,14 @@ func calculateSpecHash(spec kubetemplateriov1alpha1.KubeTemplateSpec) string {
 	return hex.EncodeToString(hash[:])
 }
 
+// describeObject returns "Kind namespace/name", or "Kind name" for cluster-scoped objects
+func describeObject(obj *unstructured.Unstructured) string {
+	if obj.GetNamespace() == "" {
+		return obj.GetKind() + " " + obj.GetName()
+	}
+	return fmt.Sprintf("%s %s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName())
+}
+
 // hasDrift compares two objects ignoring server-managed fields to detect real drift
 func hasDrift(current, desired *unstructured.Unstructured) bool {
 	// Extract specs for comparison
@@ -506,8 +765,10 @@ func (r *KubeTemplateReconciler) SetupWithManager(mgr ctrl.Manager) error {
 				specChanged := !apiequality.Semantic.DeepEqual(oldTemplate.Spec, newTemplate.Spec)
 				annotationsChanged := !apiequality.Semantic.DeepEqual(oldTemplate.Annotations, newTemplate.Annotations)
 				labelsChanged := !apiequality.Semantic.DeepEqual(oldTemplate.Labels, newTemplate.Labels)
+				// The worker pausing a template starts the cooldown of PauseAutoResumeAfter
+				paused := newTemplate.Status.ProcessingPhase == "Paused" && oldTemplate.Status.ProcessingPhase != "Paused"
 				
-				return specChanged || annotationsChanged || labelsChanged
+				return specChanged || annotationsChanged || labelsChanged || paused
 			},
 		})).
 		Named("kubetemplater.io-kubetemplate").
@@ -18,15 +18,28 @@ package kubetemplaterio
 
 import (
 	"context"
+	"fmt"
 
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
 	"github.com/lpeano/KubeTemplater/internal/cache"
+	"github.com/lpeano/KubeTemplater/internal/conditions"
+	"github.com/lpeano/KubeTemplater/internal/metrics"
+	"github.com/lpeano/KubeTemplater/internal/queue"
+	"github.com/lpeano/KubeTemplater/internal/summary"
 )
 
 // KubeTemplatePolicyReconciler reconciles a KubeTemplatePolicy object
@@ -34,17 +47,25 @@ type KubeTemplatePolicyReconciler struct {
 	client.Client
 	Scheme      *runtime.Scheme
 	PolicyCache *cache.PolicyCache
+	// WorkQueue receives the templates of the source namespace that failed because no policy
+	// covered it (nil = they wait for their retry backoff)
+	WorkQueue *queue.WorkQueue
+	// OperatorNamespace is where policies take effect; policies elsewhere don't requeue templates
+	OperatorNamespace string
 }
 
 //+kubebuilder:rbac:groups=kubetemplater.io,resources=kubetemplatepolicies,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=kubetemplater.io,resources=kubetemplatepolicies/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=kubetemplater.io,resources=kubetemplatepolicies/finalizers,verbs=update
 //+kubebuilder:rbac:groups=kubetemplater.io,resources=kubetemplates,verbs=get;list;watch
+//+kubebuilder:rbac:groups=kubetemplater.io,resources=kubetemplates/status,verbs=get;update;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
 // This controller watches KubeTemplatePolicy changes and immediately updates the PolicyCache
 // to ensure webhook validation uses the most current policies without waiting for TTL expiration.
+// It is the only controller writing to the cache, and the cache ignores updates older than the
+// version it holds, so a late reconcile cannot bring back a superseded policy.
 //
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.16.3/pkg/reconcile
@@ -62,6 +83,9 @@ func (r *KubeTemplatePolicyReconciler) Reconcile(ctx context.Context, req ctrl.R
 				r.PolicyCache.Clear()
 				log.Info("Policy deleted, cleared entire cache for immediate effect", "policy", req.Name)
 			}
+			if r.takesEffect(req.Namespace) {
+				metrics.ForgetPolicy(req.Name)
+			}
 			return ctrl.Result{}, nil
 		}
 		log.Error(err, "Failed to get KubeTemplatePolicy")
@@ -70,18 +94,138 @@ func (r *KubeTemplatePolicyReconciler) Reconcile(ctx context.Context, req ctrl.R
 
 	// Policy exists (created or updated) - update cache immediately
 	if r.PolicyCache != nil {
-		r.PolicyCache.Update(&policy)
+		if !r.PolicyCache.Update(&policy) {
+			log.V(1).Info("Ignored stale policy, PolicyCache holds a newer version",
+				"policy", policy.Name,
+				"resourceVersion", policy.ResourceVersion)
+			return ctrl.Result{}, nil
+		}
 		log.V(1).Info("Updated PolicyCache",
 			"policy", policy.Name,
 			"sourceNamespace", policy.Spec.SourceNamespace)
 	}
 
+	if err := r.requeueTemplatesWithoutPolicy(ctx, &policy); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.observeGovernedTemplates(ctx, &policy); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	return ctrl.Result{}, nil
 }
 
+// takesEffect reports whether policies of namespace govern templates
+func (r *KubeTemplatePolicyReconciler) takesEffect(namespace string) bool {
+	return r.OperatorNamespace == "" || namespace == r.OperatorNamespace
+}
+
+// observeGovernedTemplates records the number of KubeTemplates in the policy's source namespace
+func (r *KubeTemplatePolicyReconciler) observeGovernedTemplates(ctx context.Context, policy *kubetemplateriov1alpha1.KubeTemplatePolicy) error {
+	if !r.takesEffect(policy.Namespace) {
+		return nil
+	}
+
+	var templates kubetemplateriov1alpha1.KubeTemplateList
+	if err := r.List(ctx, &templates, client.InNamespace(policy.Spec.SourceNamespace)); err != nil {
+		return fmt.Errorf("failed to list KubeTemplates of source namespace %s: %w", policy.Spec.SourceNamespace, err)
+	}
+	metrics.ObservePolicyTemplates(policy.Name, len(templates.Items))
+	return nil
+}
+
+// policiesForTemplate maps a KubeTemplate to the policies whose source namespace holds it, so
+// their governed template count follows templates being created and deleted
+func (r *KubeTemplatePolicyReconciler) policiesForTemplate(ctx context.Context, obj client.Object) []reconcile.Request {
+	var policies kubetemplateriov1alpha1.KubeTemplatePolicyList
+	var opts []client.ListOption
+	if r.OperatorNamespace != "" {
+		opts = append(opts, client.InNamespace(r.OperatorNamespace))
+	}
+	if err := r.List(ctx, &policies, opts...); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list KubeTemplatePolicies", "namespace", obj.GetNamespace())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, policy := range policies.Items {
+		if policy.Spec.SourceNamespace == obj.GetNamespace() {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&policy)})
+		}
+	}
+	return requests
+}
+
+// requeueTemplatesWithoutPolicy queues the Failed templates of the policy's source namespace
+// whose processing failed because no policy covered the namespace, e.g. templates created
+// while the webhook was unavailable, so they are processed now instead of after their backoff
+func (r *KubeTemplatePolicyReconciler) requeueTemplatesWithoutPolicy(ctx context.Context, policy *kubetemplateriov1alpha1.KubeTemplatePolicy) error {
+	if r.WorkQueue == nil || !r.takesEffect(policy.Namespace) {
+		return nil
+	}
+	log := log.FromContext(ctx)
+
+	var templates kubetemplateriov1alpha1.KubeTemplateList
+	if err := r.List(ctx, &templates, client.InNamespace(policy.Spec.SourceNamespace)); err != nil {
+		return fmt.Errorf("failed to list KubeTemplates of source namespace %s: %w", policy.Spec.SourceNamespace, err)
+	}
+
+	requeued := 0
+	for i := range templates.Items {
+		kubeTemplate := &templates.Items[i]
+		if !failedWithoutPolicy(kubeTemplate) {
+			continue
+		}
+
+		kubeTemplate.Status.ProcessingPhase = "Queued"
+		conditions.MarkPending(&kubeTemplate.Status, kubeTemplate.Generation, kubetemplateriov1alpha1.ReasonQueued,
+			fmt.Sprintf("Queued for processing, policy %s now covers the namespace", policy.Name))
+		kubeTemplate.Status.RetryCount = 0
+		kubeTemplate.Status.RetryCycle = 0
+		now := metav1.Now()
+		kubeTemplate.Status.QueuedAt = &now
+		conditions.SyncStatus(&kubeTemplate.Status)
+		kubeTemplate.Status.Summary = summary.Compute(&kubeTemplate.Status)
+		if err := r.Status().Update(ctx, kubeTemplate); err != nil && !errors.IsConflict(err) {
+			return fmt.Errorf("failed to queue KubeTemplate %s/%s: %w", kubeTemplate.Namespace, kubeTemplate.Name, err)
+		}
+
+		// A template still backing off from the failure only needs to be due now
+		key := types.NamespacedName{Namespace: kubeTemplate.Namespace, Name: kubeTemplate.Name}
+		if !r.WorkQueue.Expedite(key) {
+			r.WorkQueue.EnqueueWithPolicy(key, kubeTemplate.Spec.ApplyPriority, policy.DeepCopy())
+		}
+		requeued++
+	}
+	if requeued > 0 {
+		log.Info("Queued templates that failed for lack of a policy",
+			"policy", policy.Name,
+			"sourceNamespace", policy.Spec.SourceNamespace,
+			"templates", requeued)
+	}
+	return nil
+}
+
+// failedWithoutPolicy reports whether the last processing of kubeTemplate failed because no
+// policy covered its namespace
+func failedWithoutPolicy(kubeTemplate *kubetemplateriov1alpha1.KubeTemplate) bool {
+	if kubeTemplate.Status.ProcessingPhase != "Failed" {
+		return false
+	}
+	validated := meta.FindStatusCondition(kubeTemplate.Status.Conditions, kubetemplateriov1alpha1.ConditionPolicyValidated)
+	return validated != nil && validated.Status == metav1.ConditionFalse &&
+		validated.Reason == kubetemplateriov1alpha1.ReasonPolicyNotFound
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *KubeTemplatePolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&kubetemplateriov1alpha1.KubeTemplatePolicy{}).
+		Watches(&kubetemplateriov1alpha1.KubeTemplate{},
+			handler.EnqueueRequestsFromMapFunc(r.policiesForTemplate),
+			builder.WithPredicates(predicate.Funcs{
+				UpdateFunc: func(event.UpdateEvent) bool { return false },
+			})).
 		Complete(r)
 }
@@ -0,0 +1,200 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubetemplaterio
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	"github.com/lpeano/KubeTemplater/internal/cache"
+	"github.com/lpeano/KubeTemplater/internal/conditions"
+	"github.com/lpeano/KubeTemplater/internal/metrics"
+	"github.com/lpeano/KubeTemplater/internal/queue"
+)
+
+var _ = Describe("KubeTemplatePolicy Controller", func() {
+	const operatorNamespace = "kubetemplater-system"
+
+	var (
+		ctx        context.Context
+		fakeClient client.Client
+		workQueue  *queue.WorkQueue
+		reconciler *KubeTemplatePolicyReconciler
+	)
+
+	// failedTemplate returns a template whose processing failed with reason and message
+	failedTemplate := func(name, reason, message string) *kubetemplateriov1alpha1.KubeTemplate {
+		kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "team-a"},
+			Spec:       kubetemplateriov1alpha1.KubeTemplateSpec{ApplyPriority: 5},
+		}
+		kubeTemplate.Status.ProcessingPhase = "Failed"
+		conditions.MarkFailed(&kubeTemplate.Status, 0, kubetemplateriov1alpha1.ConditionPolicyValidated, reason, message)
+		kubeTemplate.Status.RetryCount = 3
+		return kubeTemplate
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		scheme := runtime.NewScheme()
+		Expect(kubetemplateriov1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(
+				failedTemplate("no-policy", kubetemplateriov1alpha1.ReasonPolicyNotFound,
+					"Error: no KubeTemplatePolicy found for source namespace team-a"),
+				failedTemplate("ambiguous", kubetemplateriov1alpha1.ReasonPolicyUnavailable,
+					"Error: multiple KubeTemplatePolicies found for source namespace team-a"),
+				failedTemplate("apply-failed", kubetemplateriov1alpha1.ReasonApplyFailed, "Error: no KubeTemplatePolicy found"),
+				failedTemplate("unavailable", kubetemplateriov1alpha1.ReasonPolicyUnavailable,
+					"Error: no KubeTemplatePolicy found for source namespace team-a"),
+			).
+			WithStatusSubresource(&kubetemplateriov1alpha1.KubeTemplate{}).
+			Build()
+
+		workQueue = queue.NewWorkQueue()
+		reconciler = &KubeTemplatePolicyReconciler{
+			Client:            fakeClient,
+			Scheme:            scheme,
+			PolicyCache:       cache.NewPolicyCache(fakeClient, 0),
+			WorkQueue:         workQueue,
+			OperatorNamespace: operatorNamespace,
+		}
+	})
+
+	AfterEach(func() {
+		workQueue.Shutdown()
+	})
+
+	createPolicy := func(namespace string) ctrl.Request {
+		policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "team-a-policy", Namespace: namespace},
+			Spec:       kubetemplateriov1alpha1.KubeTemplatePolicySpec{SourceNamespace: "team-a"},
+		}
+		Expect(fakeClient.Create(ctx, policy)).To(Succeed())
+		return ctrl.Request{NamespacedName: client.ObjectKeyFromObject(policy)}
+	}
+
+	key := func(name string) types.NamespacedName {
+		return types.NamespacedName{Namespace: "team-a", Name: name}
+	}
+
+	It("should queue templates that failed because no policy existed", func() {
+		_, err := reconciler.Reconcile(ctx, createPolicy(operatorNamespace))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(workQueue.Len()).To(Equal(1))
+		item, ok := workQueue.Dequeue()
+		Expect(ok).To(BeTrue())
+		Expect(item.NamespacedName).To(Equal(key("no-policy")))
+		Expect(item.Priority).To(Equal(5))
+		Expect(item.Policy).NotTo(BeNil())
+		Expect(item.Policy.Name).To(Equal("team-a-policy"))
+
+		var kubeTemplate kubetemplateriov1alpha1.KubeTemplate
+		Expect(fakeClient.Get(ctx, key("no-policy"), &kubeTemplate)).To(Succeed())
+		Expect(kubeTemplate.Status.ProcessingPhase).To(Equal("Queued"))
+		Expect(kubeTemplate.Status.RetryCount).To(BeZero())
+		ready := meta.FindStatusCondition(kubeTemplate.Status.Conditions, kubetemplateriov1alpha1.ConditionReady)
+		Expect(ready.Reason).To(Equal(kubetemplateriov1alpha1.ReasonQueued))
+		Expect(kubeTemplate.Status.Status).To(Equal("Queued for processing, policy team-a-policy now covers the namespace"))
+	})
+
+	It("should leave templates that failed for other reasons alone", func() {
+		_, err := reconciler.Reconcile(ctx, createPolicy(operatorNamespace))
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, name := range []string{"ambiguous", "apply-failed", "unavailable"} {
+			var kubeTemplate kubetemplateriov1alpha1.KubeTemplate
+			Expect(fakeClient.Get(ctx, key(name), &kubeTemplate)).To(Succeed())
+			Expect(kubeTemplate.Status.ProcessingPhase).To(Equal("Failed"))
+			Expect(workQueue.Contains(key(name))).To(BeFalse())
+		}
+	})
+
+	It("should make a template backing off from the failure due now", func() {
+		workQueue.InitialRetryDelay = time.Hour
+		workQueue.Enqueue(key("no-policy"), 0)
+		item, ok := workQueue.Dequeue()
+		Expect(ok).To(BeTrue())
+		workQueue.Requeue(item, cache.ErrPolicyNotFound)
+
+		_, err := reconciler.Reconcile(ctx, createPolicy(operatorNamespace))
+		Expect(err).NotTo(HaveOccurred())
+
+		dequeued := make(chan *queue.WorkItem)
+		go func() {
+			defer GinkgoRecover()
+			item, ok := workQueue.Dequeue()
+			Expect(ok).To(BeTrue())
+			dequeued <- item
+		}()
+		Eventually(dequeued).Should(Receive(HaveField("NamespacedName", key("no-policy"))))
+	})
+
+	It("should ignore policies outside the operator namespace", func() {
+		_, err := reconciler.Reconcile(ctx, createPolicy("elsewhere"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(workQueue.Len()).To(BeZero())
+	})
+
+	It("should report the templates the policy governs", func() {
+		req := createPolicy(operatorNamespace)
+		_, err := reconciler.Reconcile(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(testutil.ToFloat64(metrics.PolicyTemplates.WithLabelValues("team-a-policy"))).To(Equal(4.0))
+
+		Expect(fakeClient.Delete(ctx, &kubetemplateriov1alpha1.KubeTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "apply-failed", Namespace: "team-a"},
+		})).To(Succeed())
+		deleted := &kubetemplateriov1alpha1.KubeTemplate{ObjectMeta: metav1.ObjectMeta{Name: "apply-failed", Namespace: "team-a"}}
+		Expect(reconciler.policiesForTemplate(ctx, deleted)).To(ConsistOf(req))
+		_, err = reconciler.Reconcile(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(testutil.ToFloat64(metrics.PolicyTemplates.WithLabelValues("team-a-policy"))).To(Equal(3.0))
+	})
+
+	It("should remove the series of a deleted policy", func() {
+		req := createPolicy(operatorNamespace)
+		_, err := reconciler.Reconcile(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+		metrics.ObservePolicyValidation("team-a-policy", metrics.PolicySourceWebhook, true)
+
+		Expect(fakeClient.Delete(ctx, &kubetemplateriov1alpha1.KubeTemplatePolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: req.Name, Namespace: req.Namespace},
+		})).To(Succeed())
+		_, err = reconciler.Reconcile(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(testutil.CollectAndCount(metrics.PolicyTemplates, "kubetemplater_policy_templates")).To(BeZero())
+		Expect(testutil.ToFloat64(metrics.PolicyValidationsTotal.WithLabelValues(
+			"team-a-policy", metrics.PolicySourceWebhook, metrics.PolicyResultAllowed))).To(BeZero())
+	})
+})
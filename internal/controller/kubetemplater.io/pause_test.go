@@ -0,0 +1,186 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubetemplaterio
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	"github.com/lpeano/KubeTemplater/internal/pause"
+	"github.com/lpeano/KubeTemplater/internal/queue"
+)
+
+var _ = Describe("Manual pause", func() {
+	var (
+		ctx        context.Context
+		fakeClient client.Client
+		workQueue  *queue.WorkQueue
+		reconciler *KubeTemplateReconciler
+	)
+
+	key := types.NamespacedName{Namespace: "default", Name: "test-template"}
+
+	// createTemplate stores a template in phase with the annotations
+	createTemplate := func(phase, pausedReason string, annotations map[string]string) {
+		kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace, Annotations: annotations},
+		}
+		Expect(fakeClient.Create(ctx, kubeTemplate)).To(Succeed())
+		kubeTemplate.Status.ProcessingPhase = phase
+		kubeTemplate.Status.PausedReason = pausedReason
+		Expect(fakeClient.Status().Update(ctx, kubeTemplate)).To(Succeed())
+	}
+
+	reconcile := func() ctrl.Result {
+		result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		return result
+	}
+
+	status := func() kubetemplateriov1alpha1.KubeTemplateStatus {
+		var kubeTemplate kubetemplateriov1alpha1.KubeTemplate
+		Expect(fakeClient.Get(ctx, key, &kubeTemplate)).To(Succeed())
+		return kubeTemplate.Status
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		scheme := runtime.NewScheme()
+		Expect(kubetemplateriov1alpha1.AddToScheme(scheme)).To(Succeed())
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithStatusSubresource(&kubetemplateriov1alpha1.KubeTemplate{}).
+			Build()
+
+		workQueue = queue.NewWorkQueue()
+		reconciler = &KubeTemplateReconciler{
+			Client:                    fakeClient,
+			Scheme:                    scheme,
+			WorkQueue:                 workQueue,
+			PeriodicReconcileInterval: time.Minute,
+		}
+	})
+
+	AfterEach(func() {
+		workQueue.Shutdown()
+	})
+
+	It("should pause a template with the pause annotation and stop its periodic reconciliation", func() {
+		createTemplate("Completed", "", map[string]string{pause.Annotation: "true"})
+
+		Expect(reconcile()).To(Equal(ctrl.Result{}))
+		Expect(status().ProcessingPhase).To(Equal("Paused"))
+		Expect(status().PausedReason).To(Equal(pause.ReasonManual))
+		Expect(status().PausedAt).NotTo(BeNil())
+		Expect(workQueue.Len()).To(BeZero())
+	})
+
+	It("should not enqueue a new template with the pause annotation", func() {
+		createTemplate("", "", map[string]string{pause.Annotation: "true"})
+
+		reconcile()
+		Expect(status().ProcessingPhase).To(Equal("Paused"))
+		Expect(workQueue.Contains(key)).To(BeFalse())
+	})
+
+	It("should wait for the worker before pausing a template being processed", func() {
+		createTemplate("Processing", "", map[string]string{pause.Annotation: "true"})
+
+		Expect(reconcile().RequeueAfter).To(BeNumerically(">", 0))
+		Expect(status().ProcessingPhase).To(Equal("Processing"))
+	})
+
+	It("should resume a manually paused template once the annotation is removed", func() {
+		createTemplate("Paused", pause.ReasonManual, nil)
+
+		reconcile()
+		Expect(status().ProcessingPhase).To(Equal("Queued"))
+		Expect(status().PausedReason).To(BeEmpty())
+		Expect(workQueue.Contains(key)).To(BeTrue())
+	})
+
+	It("should let the resume annotation take precedence over the pause annotation", func() {
+		createTemplate("Paused", pause.ReasonManual, map[string]string{pause.Annotation: "true", pause.ResumeAnnotation: "true"})
+
+		reconcile()
+		Expect(status().ProcessingPhase).To(Equal("Queued"))
+		Expect(workQueue.Contains(key)).To(BeTrue())
+	})
+
+	It("should keep a template paused after repeated failures until it is resumed", func() {
+		createTemplate("Paused", "Max retry cycles (5) exceeded", nil)
+
+		reconcile()
+		Expect(status().ProcessingPhase).To(Equal("Paused"))
+		Expect(workQueue.Len()).To(BeZero())
+	})
+
+	Context("When paused templates resume after a cooldown", func() {
+		// pausedFor stores a template paused after its last retry cycle for the duration
+		pausedFor := func(pausedReason string, paused time.Duration) {
+			createTemplate("Paused", pausedReason, nil)
+			var kubeTemplate kubetemplateriov1alpha1.KubeTemplate
+			Expect(fakeClient.Get(ctx, key, &kubeTemplate)).To(Succeed())
+			kubeTemplate.Status.PausedAt = &metav1.Time{Time: time.Now().Add(-paused)}
+			kubeTemplate.Status.RetryCycle = 3
+			Expect(fakeClient.Status().Update(ctx, &kubeTemplate)).To(Succeed())
+		}
+
+		BeforeEach(func() {
+			reconciler.PauseAutoResumeAfter = 10 * time.Minute
+		})
+
+		It("should reconcile again when the cooldown ends", func() {
+			pausedFor("Max retry cycles (3) exceeded. Last error: connection refused", 4*time.Minute)
+
+			result := reconcile()
+			Expect(result.RequeueAfter).To(BeNumerically("~", 6*time.Minute, time.Second))
+			Expect(status().ProcessingPhase).To(Equal("Paused"))
+			Expect(workQueue.Len()).To(BeZero())
+		})
+
+		It("should queue the template with its retry cycles reset once the cooldown has elapsed", func() {
+			pausedFor("Max retry cycles (3) exceeded. Last error: connection refused", 11*time.Minute)
+
+			Expect(reconcile()).To(Equal(ctrl.Result{}))
+			Expect(status().ProcessingPhase).To(Equal("Queued"))
+			Expect(status().RetryCycle).To(BeZero())
+			Expect(status().PausedAt).To(BeNil())
+			Expect(status().Status).To(Equal("Queued again after a pause cooldown of 10m0s"))
+			Expect(workQueue.Contains(key)).To(BeTrue())
+		})
+
+		It("should leave templates paused for other reasons alone", func() {
+			pausedFor("operator lacks RBAC for target namespace default: forbidden", time.Hour)
+
+			Expect(reconcile()).To(Equal(ctrl.Result{}))
+			Expect(status().ProcessingPhase).To(Equal("Paused"))
+			Expect(workQueue.Len()).To(BeZero())
+		})
+	})
+})
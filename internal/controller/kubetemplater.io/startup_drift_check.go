@@ -0,0 +1,75 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubetemplaterio
+
+import (
+	"context"
+	"fmt"
+
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	"github.com/lpeano/KubeTemplater/internal/pause"
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// StartupDriftCheck runs one dry-run drift check on every Completed template, at most
+// templatesPerSecond templates per second, so that drift that happened while the operator
+// was down is corrected and recorded in LastDriftDetected and DriftDetectionCount right
+// away instead of after the periodic reconciliation skips recently reconciled templates.
+// Paused templates, templates whose spec changed and templates already queued are left to
+// the controller. It returns the number of templates checked.
+func (r *KubeTemplateReconciler) StartupDriftCheck(ctx context.Context, templatesPerSecond int) (int, error) {
+	log := logf.FromContext(ctx).WithName("startup-drift-check")
+
+	var templates kubetemplateriov1alpha1.KubeTemplateList
+	if err := r.List(ctx, &templates); err != nil {
+		return 0, fmt.Errorf("failed to list KubeTemplates: %w", err)
+	}
+
+	limiter := rate.NewLimiter(rate.Limit(max(templatesPerSecond, 1)), 1)
+	checked, failed := 0, 0
+	for i := range templates.Items {
+		kt := &templates.Items[i]
+		if !needsStartupDriftCheck(kt) || r.WorkQueue.Contains(types.NamespacedName{Namespace: kt.Namespace, Name: kt.Name}) {
+			continue
+		}
+		if err := limiter.Wait(ctx); err != nil {
+			return checked, err
+		}
+
+		checked++
+		if err := r.applyTemplateResources(ctx, kt); err != nil {
+			failed++
+			log.Info("Startup drift check failed, the periodic reconciliation will check again",
+				"kubetemplate", client.ObjectKeyFromObject(kt), "reason", err.Error())
+		}
+	}
+
+	log.Info("Startup drift check completed", "checked", checked, "failed", failed, "total", len(templates.Items))
+	return checked, nil
+}
+
+// needsStartupDriftCheck reports whether kt is a Completed template applied with its
+// current spec, which the startup drift check can check without reprocessing it
+func needsStartupDriftCheck(kt *kubetemplateriov1alpha1.KubeTemplate) bool {
+	return kt.Status.ProcessingPhase == "Completed" &&
+		!pause.Requested(kt) &&
+		kt.Status.AppliedSpecHash != "" &&
+		kt.Status.AppliedSpecHash == calculateSpecHash(kt.Spec)
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubetemplaterio
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	"github.com/lpeano/KubeTemplater/internal/pause"
+	"github.com/lpeano/KubeTemplater/internal/queue"
+)
+
+var _ = Describe("Startup drift check", func() {
+	var (
+		ctx        context.Context
+		fakeClient client.Client
+		reconciler *KubeTemplateReconciler
+		workQueue  *queue.WorkQueue
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(kubetemplateriov1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		// The fake client does not support Server-Side Apply: a dry run leaves the desired object
+		// as is, and a real apply is emulated with Update
+		patch := func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			if patch.Type() != types.ApplyPatchType {
+				return c.Patch(ctx, obj, patch, opts...)
+			}
+			patchOpts := &client.PatchOptions{}
+			patchOpts.ApplyOptions(opts)
+			if len(patchOpts.DryRun) > 0 {
+				return nil
+			}
+			live := &corev1.ConfigMap{}
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), live)).To(Succeed())
+			obj.SetResourceVersion(live.GetResourceVersion())
+			return c.Update(ctx, obj)
+		}
+
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithInterceptorFuncs(interceptor.Funcs{Patch: patch}).
+			WithStatusSubresource(&kubetemplateriov1alpha1.KubeTemplate{}).
+			Build()
+
+		workQueue = queue.NewWorkQueue()
+		reconciler = &KubeTemplateReconciler{
+			Client:    fakeClient,
+			Scheme:    scheme,
+			WorkQueue: workQueue,
+		}
+	})
+
+	// createCompleted creates a ConfigMap with the given live value and a Completed template
+	// that wants it to be "value", reconciled just before the operator restarted
+	createCompleted := func(name, liveValue string) *kubetemplateriov1alpha1.KubeTemplate {
+		Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Data:       map[string]string{"key": liveValue},
+		})).To(Succeed())
+
+		// Keys are sorted so the spec hash stays the same when the fake client lists templates
+		object := `{"apiVersion":"v1","data":{"key":"value"},"kind":"ConfigMap","metadata":{"name":"` + name + `","namespace":"default"}}`
+		kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+				Templates: []kubetemplateriov1alpha1.Template{
+					{Object: runtime.RawExtension{Raw: []byte(object)}},
+				},
+			},
+		}
+		Expect(fakeClient.Create(ctx, kubeTemplate)).To(Succeed())
+
+		now := metav1.Now()
+		kubeTemplate.Status.ProcessingPhase = "Completed"
+		kubeTemplate.Status.AppliedSpecHash = calculateSpecHash(kubeTemplate.Spec)
+		kubeTemplate.Status.LastReconcileTime = &now
+		kubeTemplate.Status.DriftDetectionCount = 2
+		Expect(fakeClient.Status().Update(ctx, kubeTemplate)).To(Succeed())
+		return kubeTemplate
+	}
+
+	getTemplate := func(name string) *kubetemplateriov1alpha1.KubeTemplate {
+		kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: name}, kubeTemplate)).To(Succeed())
+		return kubeTemplate
+	}
+
+	liveValue := func(name string) string {
+		cm := &corev1.ConfigMap{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: name}, cm)).To(Succeed())
+		return cm.Data["key"]
+	}
+
+	It("should record and correct drift that happened while the operator was down", func() {
+		createCompleted("drifted", "edited-during-downtime")
+		createCompleted("in-sync", "value")
+
+		checked, err := reconciler.StartupDriftCheck(ctx, 100)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checked).To(Equal(2))
+
+		drifted := getTemplate("drifted")
+		Expect(drifted.Status.DriftDetectionCount).To(Equal(3))
+		Expect(drifted.Status.LastDriftDetected).NotTo(BeNil())
+		Expect(liveValue("drifted")).To(Equal("value"))
+
+		inSync := getTemplate("in-sync")
+		Expect(inSync.Status.DriftDetectionCount).To(Equal(2))
+		Expect(inSync.Status.LastDriftDetected).To(BeNil())
+	})
+
+	It("should skip templates that are not Completed with their current spec", func() {
+		paused := createCompleted("paused", "edited")
+		paused.Annotations = map[string]string{pause.Annotation: "true"}
+		Expect(fakeClient.Update(ctx, paused)).To(Succeed())
+
+		changed := createCompleted("changed", "edited")
+		changed.Spec.ApplyPriority = 10
+		Expect(fakeClient.Update(ctx, changed)).To(Succeed())
+
+		createCompleted("queued", "edited")
+		workQueue.Enqueue(types.NamespacedName{Namespace: "default", Name: "queued"}, 0)
+
+		checked, err := reconciler.StartupDriftCheck(ctx, 100)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checked).To(BeZero())
+		for _, name := range []string{"paused", "changed", "queued"} {
+			Expect(getTemplate(name).Status.DriftDetectionCount).To(Equal(2), name)
+			Expect(liveValue(name)).To(Equal("edited"), name)
+		}
+	})
+})
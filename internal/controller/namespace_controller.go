@@ -2,6 +2,7 @@ package controller
 
 import (
 	"context"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -22,6 +23,20 @@ const (
 type NamespaceReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+
+	// DeletionGracePeriod delays the cleanup of a terminating namespace's KubeTemplates,
+	// counted from its deletion timestamp. The namespace is fetched again once it has
+	// elapsed, so templates survive a deletion that is reverted in the meantime. 0 disables it.
+	DeletionGracePeriod time.Duration
+	// Now returns the current time; defaults to time.Now
+	Now func() time.Time
+}
+
+func (r *NamespaceReconciler) now() time.Time {
+	if r.Now != nil {
+		return r.Now()
+	}
+	return time.Now()
 }
 
 // +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch;update
@@ -57,6 +72,14 @@ func (r *NamespaceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 
 	// Namespace is being deleted
 	if controllerutil.ContainsFinalizer(&namespace, namespaceFinalizer) {
+		// Wait out the grace period; the requeued reconcile re-checks the namespace is still terminating
+		if remaining := namespace.DeletionTimestamp.Add(r.DeletionGracePeriod).Sub(r.now()); remaining > 0 {
+			log.Info("Namespace is being deleted, waiting for grace period before cleaning up KubeTemplates",
+				"namespace", namespace.Name,
+				"remaining", remaining)
+			return ctrl.Result{RequeueAfter: remaining}, nil
+		}
+
 		// Delete all KubeTemplates in this namespace
 		log.Info("Namespace is being deleted, cleaning up KubeTemplates", "namespace", namespace.Name)
 
@@ -0,0 +1,115 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("NamespaceReconciler", func() {
+	var (
+		ctx        context.Context
+		scheme     *runtime.Scheme
+		k8sClient  client.Client
+		reconciler *NamespaceReconciler
+		deletedAt  time.Time
+		now        time.Time
+	)
+
+	request := ctrl.Request{NamespacedName: types.NamespacedName{Name: "team-a"}}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(kubetemplateriov1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		deletedAt = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+		namespace := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "team-a",
+				Finalizers:        []string{namespaceFinalizer},
+				DeletionTimestamp: &metav1.Time{Time: deletedAt},
+			},
+		}
+		template := &kubetemplateriov1alpha1.KubeTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "team-a"},
+		}
+		k8sClient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(namespace, template).Build()
+
+		reconciler = &NamespaceReconciler{
+			Client:              k8sClient,
+			Scheme:              scheme,
+			DeletionGracePeriod: 10 * time.Second,
+			Now:                 func() time.Time { return now },
+		}
+	})
+
+	templateExists := func() bool {
+		var kt kubetemplateriov1alpha1.KubeTemplate
+		return k8sClient.Get(ctx, types.NamespacedName{Namespace: "team-a", Name: "app"}, &kt) == nil
+	}
+
+	It("should wait for the grace period before deleting templates", func() {
+		now = deletedAt.Add(3 * time.Second)
+
+		result, err := reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(7 * time.Second))
+		Expect(templateExists()).To(BeTrue())
+
+		var namespace corev1.Namespace
+		Expect(k8sClient.Get(ctx, request.NamespacedName, &namespace)).To(Succeed())
+		Expect(namespace.Finalizers).To(ContainElement(namespaceFinalizer))
+	})
+
+	It("should clean up once the namespace is still terminating after the grace period", func() {
+		now = deletedAt.Add(3 * time.Second)
+		result, err := reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(templateExists()).To(BeTrue())
+
+		now = now.Add(result.RequeueAfter)
+		result, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(ctrl.Result{}))
+		Expect(templateExists()).To(BeFalse())
+	})
+
+	It("should clean up immediately without a grace period", func() {
+		reconciler.DeletionGracePeriod = 0
+		now = deletedAt
+
+		result, err := reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(ctrl.Result{}))
+		Expect(templateExists()).To(BeFalse())
+	})
+})
@@ -0,0 +1,76 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package impersonation builds clients that act as a policy's ServiceAccount, so that
+// RBAC bounds what the templates of each policy can apply.
+package impersonation
+
+import (
+	"fmt"
+	"sync"
+
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+)
+
+// +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=impersonate
+
+// ClientFactory returns a client that impersonates the given user
+type ClientFactory func(username string) (client.Client, error)
+
+// NewClientFactory returns a ClientFactory that builds clients from config with the
+// Impersonate user set. Clients are cached per username.
+func NewClientFactory(config *rest.Config, options client.Options) ClientFactory {
+	var mu sync.Mutex
+	clients := make(map[string]client.Client)
+
+	return func(username string) (client.Client, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if c, ok := clients[username]; ok {
+			return c, nil
+		}
+
+		impersonated := rest.CopyConfig(config)
+		impersonated.Impersonate = rest.ImpersonationConfig{UserName: username}
+		c, err := client.New(impersonated, options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create client impersonating %s: %w", username, err)
+		}
+		clients[username] = c
+		return c, nil
+	}
+}
+
+// ServiceAccountUsername returns the username the API server uses for a ServiceAccount
+func ServiceAccountUsername(namespace, name string) string {
+	return fmt.Sprintf("system:serviceaccount:%s:%s", namespace, name)
+}
+
+// ClientForPolicy returns the client to apply resources governed by the policy: the
+// operator client if the policy has no ServiceAccount, otherwise a client impersonating it
+func ClientForPolicy(operatorClient client.Client, factory ClientFactory, policy *kubetemplateriov1alpha1.KubeTemplatePolicy) (client.Client, error) {
+	if policy.Spec.ServiceAccountName == "" {
+		return operatorClient, nil
+	}
+	if factory == nil {
+		return nil, fmt.Errorf("policy %s requires ServiceAccount %s but impersonation is not configured", policy.Name, policy.Spec.ServiceAccountName)
+	}
+	return factory(ServiceAccountUsername(policy.Namespace, policy.Spec.ServiceAccountName))
+}
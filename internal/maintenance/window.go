@@ -0,0 +1,135 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package maintenance evaluates the maintenance windows of a KubeTemplatePolicy.
+package maintenance
+
+import (
+	"fmt"
+	"time"
+
+	// Embed the time zone database so TimeZone works in minimal container images
+	_ "time/tzdata"
+
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+)
+
+var weekdays = map[kubetemplateriov1alpha1.Weekday]time.Weekday{
+	"Sun": time.Sunday,
+	"Mon": time.Monday,
+	"Tue": time.Tuesday,
+	"Wed": time.Wednesday,
+	"Thu": time.Thursday,
+	"Fri": time.Friday,
+	"Sat": time.Saturday,
+}
+
+// Check reports whether now falls inside any of the windows. When it does not, next is
+// the earliest time one of the windows opens. An empty list of windows is always open.
+func Check(windows []kubetemplateriov1alpha1.MaintenanceWindow, now time.Time) (open bool, next time.Time, err error) {
+	if len(windows) == 0 {
+		return true, time.Time{}, nil
+	}
+
+	for i, window := range windows {
+		w, err := parseWindow(window)
+		if err != nil {
+			return false, time.Time{}, fmt.Errorf("maintenanceWindows[%d]: %w", i, err)
+		}
+
+		local := now.In(w.location)
+		today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, w.location)
+
+		// Yesterday's window may still be open if it spans midnight; a full week ahead
+		// always contains the next opening
+		for offset := -1; offset <= 7; offset++ {
+			day := today.AddDate(0, 0, offset)
+			if !w.opensOn(day.Weekday()) {
+				continue
+			}
+			start := w.at(day, w.start)
+			end := w.at(day, w.end)
+			if !end.After(start) {
+				end = end.AddDate(0, 0, 1)
+			}
+
+			if !now.Before(start) && now.Before(end) {
+				return true, time.Time{}, nil
+			}
+			if start.After(now) && (next.IsZero() || start.Before(next)) {
+				next = start
+			}
+		}
+	}
+
+	return false, next, nil
+}
+
+type window struct {
+	days     map[time.Weekday]bool
+	start    int // minutes after midnight
+	end      int
+	location *time.Location
+}
+
+// at returns the given time of day on day, computed by wall clock so DST changes are honored
+func (w *window) at(day time.Time, minutes int) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), minutes/60, minutes%60, 0, 0, w.location)
+}
+
+func (w *window) opensOn(day time.Weekday) bool {
+	return len(w.days) == 0 || w.days[day]
+}
+
+func parseWindow(mw kubetemplateriov1alpha1.MaintenanceWindow) (*window, error) {
+	w := &window{location: time.UTC}
+
+	var err error
+	if w.start, err = parseTimeOfDay(mw.Start); err != nil {
+		return nil, fmt.Errorf("invalid start: %w", err)
+	}
+	if w.end, err = parseTimeOfDay(mw.End); err != nil {
+		return nil, fmt.Errorf("invalid end: %w", err)
+	}
+
+	if mw.TimeZone != "" {
+		if w.location, err = time.LoadLocation(mw.TimeZone); err != nil {
+			return nil, fmt.Errorf("invalid timeZone: %w", err)
+		}
+	}
+
+	if len(mw.Days) > 0 {
+		w.days = make(map[time.Weekday]bool, len(mw.Days))
+		for _, d := range mw.Days {
+			weekday, ok := weekdays[d]
+			if !ok {
+				return nil, fmt.Errorf("invalid day %q", d)
+			}
+			w.days[weekday] = true
+		}
+	}
+
+	return w, nil
+}
+
+// parseTimeOfDay parses "HH:MM" into minutes after midnight
+func parseTimeOfDay(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a HH:MM time", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maintenance
+
+import (
+	"time"
+
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// Wednesday, 2025-01-15
+func at(hour, minute int) time.Time {
+	return time.Date(2025, time.January, 15, hour, minute, 0, 0, time.UTC)
+}
+
+var _ = Describe("Check", func() {
+	nightly := kubetemplateriov1alpha1.MaintenanceWindow{Start: "22:00", End: "02:00"}
+	weekend := kubetemplateriov1alpha1.MaintenanceWindow{Days: []kubetemplateriov1alpha1.Weekday{"Sat", "Sun"}, Start: "08:00", End: "12:00"}
+
+	It("should always be open without windows", func() {
+		open, _, err := Check(nil, at(12, 0))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(open).To(BeTrue())
+	})
+
+	DescribeTable("windows spanning midnight",
+		func(now time.Time, expectOpen bool, expectNext time.Time) {
+			open, next, err := Check([]kubetemplateriov1alpha1.MaintenanceWindow{nightly}, now)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(open).To(Equal(expectOpen))
+			Expect(next).To(BeTemporally("==", expectNext))
+		},
+		Entry("before the window", at(21, 59), false, at(22, 0)),
+		Entry("at the start", at(22, 0), true, time.Time{}),
+		Entry("after midnight", at(1, 30), true, time.Time{}),
+		Entry("at the end", at(2, 0), false, at(22, 0)),
+	)
+
+	It("should only open on the listed days", func() {
+		open, next, err := Check([]kubetemplateriov1alpha1.MaintenanceWindow{weekend}, at(9, 0))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(open).To(BeFalse())
+		Expect(next).To(BeTemporally("==", time.Date(2025, time.January, 18, 8, 0, 0, 0, time.UTC)))
+
+		open, _, err = Check([]kubetemplateriov1alpha1.MaintenanceWindow{weekend}, time.Date(2025, time.January, 19, 11, 0, 0, 0, time.UTC))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(open).To(BeTrue())
+	})
+
+	It("should report the earliest opening across windows", func() {
+		_, next, err := Check([]kubetemplateriov1alpha1.MaintenanceWindow{weekend, nightly}, at(12, 0))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(next).To(BeTemporally("==", at(22, 0)))
+	})
+
+	It("should evaluate the window in its time zone", func() {
+		rome := kubetemplateriov1alpha1.MaintenanceWindow{Start: "09:00", End: "10:00", TimeZone: "Europe/Rome"}
+
+		// 08:30 UTC is 09:30 in Rome (CET, UTC+1)
+		open, _, err := Check([]kubetemplateriov1alpha1.MaintenanceWindow{rome}, at(8, 30))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(open).To(BeTrue())
+	})
+
+	It("should reject invalid windows", func() {
+		_, _, err := Check([]kubetemplateriov1alpha1.MaintenanceWindow{{Start: "25:00", End: "02:00"}}, at(12, 0))
+		Expect(err).To(MatchError(ContainSubstring("maintenanceWindows[0]: invalid start")))
+
+		_, _, err = Check([]kubetemplateriov1alpha1.MaintenanceWindow{{Start: "01:00", End: "02:00", TimeZone: "Mars/Olympus"}}, at(12, 0))
+		Expect(err).To(MatchError(ContainSubstring("invalid timeZone")))
+	})
+})
@@ -0,0 +1,99 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package manifest decodes the objects embedded in KubeTemplate templates.
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// DefaultMaxExpansionRatio is the default value of MaxExpansionRatio
+const DefaultMaxExpansionRatio = 10
+
+// MaxExpansionRatio caps how many times larger than its source a decoded object may be.
+// YAML anchors and aliases let a small document expand into a huge object; legitimate
+// manifests stay close to their source size. 0 disables the check.
+var MaxExpansionRatio = DefaultMaxExpansionRatio
+
+// Decode parses a Template.Object payload into an unstructured object.
+//
+// The payload may be JSON (as stored by the API server), inline YAML (as used in
+// tests and by clients building RawExtensions directly), or a JSON string holding a
+// YAML document. The latter happens when the object is written as a block scalar
+// (`object: |`) in a manifest: the CRD preserves it as a string instead of a map.
+// All three forms decode to identical objects.
+func Decode(raw []byte) (unstructured.Unstructured, error) {
+	var obj unstructured.Unstructured
+
+	trimmed, err := document(raw)
+	if err != nil {
+		return obj, err
+	}
+
+	// Convert to JSON first so the expanded size is known before the object is built
+	expanded, err := yaml.YAMLToJSON(trimmed)
+	if err != nil {
+		return obj, err
+	}
+	if MaxExpansionRatio > 0 && len(expanded) > MaxExpansionRatio*len(trimmed) {
+		return obj, fmt.Errorf("decoded object is %d bytes, more than %d times its %d byte source; YAML aliases may be expanding it", len(expanded), MaxExpansionRatio, len(trimmed))
+	}
+	if err := json.Unmarshal(expanded, &obj); err != nil {
+		return obj, err
+	}
+	if len(obj.Object) == 0 {
+		return obj, errors.New("object is empty")
+	}
+	return obj, nil
+}
+
+// document returns the YAML or JSON document of a Template.Object payload, unwrapping one
+// embedded as a JSON string
+func document(raw []byte) ([]byte, error) {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null")) {
+		return nil, errors.New("object is empty")
+	}
+
+	// Unwrap a YAML document embedded as a JSON string
+	if trimmed[0] == '"' {
+		var doc string
+		if err := json.Unmarshal(trimmed, &doc); err != nil {
+			return nil, fmt.Errorf("failed to decode object string: %w", err)
+		}
+		trimmed = []byte(doc)
+	}
+	return trimmed, nil
+}
+
+// ValidateName checks that a decoded object has a metadata.name. Objects are applied with
+// server-side apply, which addresses them by name, so metadata.generateName cannot be used.
+func ValidateName(obj *unstructured.Unstructured) error {
+	if obj.GetName() != "" {
+		return nil
+	}
+	if generateName := obj.GetGenerateName(); generateName != "" {
+		return fmt.Errorf("metadata.generateName %q is not supported because resources are applied with server-side apply, which requires metadata.name; set metadata.name instead", generateName)
+	}
+	return errors.New("metadata.name is required")
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manifest
+
+import (
+	"encoding/json"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const deploymentYAML = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  labels:
+    app: web
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - name: web
+        image: nginx:1.27
+        args:
+        - --port=8080
+      terminationGracePeriodSeconds: 30
+`
+
+const deploymentJSON = `{
+  "apiVersion": "apps/v1",
+  "kind": "Deployment",
+  "metadata": {"name": "web", "labels": {"app": "web"}},
+  "spec": {
+    "replicas": 3,
+    "template": {
+      "spec": {
+        "containers": [{"name": "web", "image": "nginx:1.27", "args": ["--port=8080"]}],
+        "terminationGracePeriodSeconds": 30
+      }
+    }
+  }
+}`
+
+var _ = Describe("Decode", func() {
+	// JSON string holding the YAML document, as stored for `object: |` block scalars
+	quotedYAML, err := json.Marshal(deploymentYAML)
+	if err != nil {
+		panic(err)
+	}
+
+	It("should decode YAML, JSON and YAML-in-a-string identically", func() {
+		fromJSON, err := Decode([]byte(deploymentJSON))
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, raw := range [][]byte{[]byte(deploymentYAML), quotedYAML} {
+			obj, err := Decode(raw)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(obj.Object).To(Equal(fromJSON.Object))
+		}
+
+		Expect(fromJSON.GetKind()).To(Equal("Deployment"))
+		Expect(fromJSON.GetName()).To(Equal("web"))
+		Expect(fromJSON.Object["spec"].(map[string]interface{})["replicas"]).To(Equal(int64(3)))
+	})
+
+	DescribeTable("should reject payloads that are not objects",
+		func(raw string) {
+			_, err := Decode([]byte(raw))
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("empty", ""),
+		Entry("null", "null"),
+		Entry("empty object", "{}"),
+		Entry("list", "[1, 2]"),
+		Entry("plain string", `"just text"`),
+	)
+
+	Context("when YAML aliases amplify the object", func() {
+		// Few enough aliases to pass the YAML parser's own excessive aliasing check
+		amplified := "apiVersion: v1\nkind: Example\n" +
+			"a: &a [" + strings.TrimSuffix(strings.Repeat(`"`+strings.Repeat("x", 48)+`", `, 10), ", ") + "]\n" +
+			"b: &b [*a, *a, *a, *a, *a, *a, *a, *a, *a, *a]\n" +
+			"c: [*b, *b, *b, *b, *b, *b, *b, *b]\n"
+
+		AfterEach(func() {
+			MaxExpansionRatio = DefaultMaxExpansionRatio
+		})
+
+		It("should reject the object", func() {
+			_, err := Decode([]byte(amplified))
+			Expect(err).To(MatchError(ContainSubstring("YAML aliases may be expanding it")))
+		})
+
+		It("should accept the object when the check is disabled", func() {
+			MaxExpansionRatio = 0
+			obj, err := Decode([]byte(amplified))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(obj.Object["c"]).To(HaveLen(8))
+		})
+	})
+})
+
+var _ = Describe("ValidateName", func() {
+	It("should accept an object with a name", func() {
+		obj, err := Decode([]byte(deploymentJSON))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ValidateName(&obj)).To(Succeed())
+	})
+
+	It("should reject generateName with a clear message", func() {
+		obj, err := Decode([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"generateName":"app-"}}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ValidateName(&obj)).To(MatchError(ContainSubstring("server-side apply, which requires metadata.name")))
+	})
+
+	It("should reject an object without a name", func() {
+		obj, err := Decode([]byte(`{"apiVersion":"v1","kind":"ConfigMap"}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ValidateName(&obj)).To(MatchError("metadata.name is required"))
+	})
+})
@@ -0,0 +1,108 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manifest
+
+import (
+	"fmt"
+	"io"
+
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// Tracking labels set on every resource applied from a KubeTemplate
+const (
+	TemplateNameLabel      = "kubetemplater.io/template-name"
+	TemplateNamespaceLabel = "kubetemplater.io/template-namespace"
+)
+
+// SetTrackingLabels labels obj with the KubeTemplate it is applied from
+func SetTrackingLabels(obj *unstructured.Unstructured, kubeTemplate *kubetemplateriov1alpha1.KubeTemplate) {
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	labels[TemplateNameLabel] = kubeTemplate.Name
+	labels[TemplateNamespaceLabel] = kubeTemplate.Namespace
+	obj.SetLabels(labels)
+}
+
+// DesiredObjects reconstructs the objects a KubeTemplate applies, as the worker builds them:
+// decoded from spec.templates in apply order, defaulted to the template's namespace and
+// carrying the labels policy propagates (if policy is not nil) and the tracking labels.
+// Owner references are left out since their UIDs do not survive a restore.
+func DesiredObjects(kubeTemplate *kubetemplateriov1alpha1.KubeTemplate, policy *kubetemplateriov1alpha1.KubeTemplatePolicy) ([]unstructured.Unstructured, error) {
+	objects := make([]unstructured.Unstructured, 0, len(kubeTemplate.Spec.Templates))
+	for _, idx := range ApplyOrder(kubeTemplate.Spec.Templates) {
+		template := kubeTemplate.Spec.Templates[idx]
+		obj, err := DecodeWithParameters(template.Object.Raw, kubeTemplate.Spec.Parameters)
+		if err != nil {
+			return nil, fmt.Errorf("%s/%s: template[%d]: %w", kubeTemplate.Namespace, kubeTemplate.Name, idx, err)
+		}
+		if obj.GetNamespace() == "" {
+			obj.SetNamespace(kubeTemplate.Namespace)
+		}
+		if !template.ApplyStatus {
+			StripStatus(&obj)
+		}
+		StripServerManagedMetadata(&obj)
+		if policy != nil {
+			PropagateLabels(&obj, kubeTemplate, policy.Spec.PropagateLabels, policy.Spec.OverrideTemplateLabels)
+		}
+		SetTrackingLabels(&obj, kubeTemplate)
+		objects = append(objects, obj)
+	}
+	return objects, nil
+}
+
+// RedactSecretData empties the values of data and stringData if obj is a core Secret, keeping
+// the keys so a restore shows which values it needs
+func RedactSecretData(obj *unstructured.Unstructured) {
+	if gvk := obj.GroupVersionKind(); gvk.Group != "" || gvk.Kind != "Secret" {
+		return
+	}
+	for _, field := range []string{"data", "stringData"} {
+		values, found, err := unstructured.NestedMap(obj.Object, field)
+		if err != nil {
+			// Not a map, so not something the key listing can keep
+			unstructured.RemoveNestedField(obj.Object, field)
+			continue
+		}
+		if !found {
+			continue
+		}
+		for key := range values {
+			values[key] = ""
+		}
+		_ = unstructured.SetNestedMap(obj.Object, values, field)
+	}
+}
+
+// WriteYAML writes objects to w as a multi-document YAML stream
+func WriteYAML(w io.Writer, objects []unstructured.Unstructured) error {
+	for _, obj := range objects {
+		data, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s %s/%s: %w", obj.GetKind(), obj.GetNamespace(), obj.GetName(), err)
+		}
+		if _, err := fmt.Fprintf(w, "---\n%s", data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manifest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// ExportPath is the path of the debug endpoint exporting the desired objects as YAML
+const ExportPath = "/debug/export"
+
+// PolicyGetter returns the policy of a source namespace, as cache.PolicyCache does
+type PolicyGetter interface {
+	Get(ctx context.Context, sourceNamespace string, operatorNamespace string) (*kubetemplateriov1alpha1.KubeTemplatePolicy, error)
+}
+
+// ExportHandler serves the objects KubeTemplates apply as a YAML stream, for GitOps backups.
+// The optional "namespace" query parameter limits the export to one namespace, and "name"
+// (together with "namespace") to a single KubeTemplate. The values of Secrets are redacted.
+// Templates that can't be decoded are skipped and listed in comments at the top of the stream.
+type ExportHandler struct {
+	Reader client.Reader
+	// Policies, if set, looks up the policy whose propagated labels the objects carry
+	Policies          PolicyGetter
+	OperatorNamespace string
+}
+
+// ServeHTTP implements http.Handler
+func (h *ExportHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	namespace := r.URL.Query().Get("namespace")
+	name := r.URL.Query().Get("name")
+
+	var templates []kubetemplateriov1alpha1.KubeTemplate
+	if name != "" {
+		if namespace == "" {
+			http.Error(w, "name requires namespace", http.StatusBadRequest)
+			return
+		}
+		var kt kubetemplateriov1alpha1.KubeTemplate
+		if err := h.Reader.Get(r.Context(), types.NamespacedName{Namespace: namespace, Name: name}, &kt); err != nil {
+			status := http.StatusInternalServerError
+			if errors.IsNotFound(err) {
+				status = http.StatusNotFound
+			}
+			http.Error(w, err.Error(), status)
+			return
+		}
+		templates = append(templates, kt)
+	} else {
+		var list kubetemplateriov1alpha1.KubeTemplateList
+		if err := h.Reader.List(r.Context(), &list, client.InNamespace(namespace)); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		templates = list.Items
+	}
+
+	sort.Slice(templates, func(i, j int) bool {
+		if templates[i].Namespace != templates[j].Namespace {
+			return templates[i].Namespace < templates[j].Namespace
+		}
+		return templates[i].Name < templates[j].Name
+	})
+
+	var objects []unstructured.Unstructured
+	var buf bytes.Buffer
+	for i := range templates {
+		desired, err := DesiredObjects(&templates[i], h.policyFor(r.Context(), &templates[i]))
+		if err != nil {
+			logf.FromContext(r.Context()).WithName("export").Info("Skipping KubeTemplate that can't be exported", "reason", err.Error())
+			fmt.Fprintf(&buf, "# skipped %s\n", strings.ReplaceAll(err.Error(), "\n", " "))
+			continue
+		}
+		for j := range desired {
+			RedactSecretData(&desired[j])
+		}
+		objects = append(objects, desired...)
+	}
+
+	// Render fully before writing so that a failure can still be reported as an error status
+	if err := WriteYAML(&buf, objects); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/yaml")
+	_, _ = w.Write(buf.Bytes())
+}
+
+// policyFor returns the policy of kubeTemplate's namespace, or nil if there is none or
+// Policies is not set, in which case the objects carry no propagated labels
+func (h *ExportHandler) policyFor(ctx context.Context, kubeTemplate *kubetemplateriov1alpha1.KubeTemplate) *kubetemplateriov1alpha1.KubeTemplatePolicy {
+	if h.Policies == nil {
+		return nil
+	}
+	policy, err := h.Policies.Get(ctx, kubeTemplate.Namespace, h.OperatorNamespace)
+	if err != nil {
+		return nil
+	}
+	return policy
+}
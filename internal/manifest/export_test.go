@@ -0,0 +1,210 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manifest
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// readYAMLStream decodes every document of a multi-document YAML stream
+func readYAMLStream(data []byte) []unstructured.Unstructured {
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(data)))
+	var objects []unstructured.Unstructured
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return objects
+		}
+		Expect(err).NotTo(HaveOccurred())
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+		obj, err := Decode(doc)
+		Expect(err).NotTo(HaveOccurred())
+		objects = append(objects, obj)
+	}
+}
+
+func newKubeTemplate(namespace, name string, objects ...string) *kubetemplateriov1alpha1.KubeTemplate {
+	kt := &kubetemplateriov1alpha1.KubeTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+	}
+	for _, obj := range objects {
+		kt.Spec.Templates = append(kt.Spec.Templates, kubetemplateriov1alpha1.Template{
+			Object: runtime.RawExtension{Raw: []byte(obj)},
+		})
+	}
+	return kt
+}
+
+var _ = Describe("Export", func() {
+	kubeTemplate := newKubeTemplate("apps", "web",
+		deploymentYAML,
+		`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"web-config","namespace":"shared"},"data":{"key":"value"}}`,
+	)
+
+	It("should return the objects in apply order", func() {
+		ordered := newKubeTemplate("apps", "web",
+			deploymentYAML,
+			`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"web-config"}}`,
+		)
+		ordered.Spec.Templates[0].Order = 1
+
+		objects, err := DesiredObjects(ordered, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(objects).To(HaveLen(2))
+		Expect(objects[0].GetKind()).To(Equal("ConfigMap"))
+		Expect(objects[1].GetKind()).To(Equal("Deployment"))
+	})
+
+	It("should reconstruct the objects with namespace and tracking labels", func() {
+		objects, err := DesiredObjects(kubeTemplate, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(objects).To(HaveLen(2))
+
+		// The ConfigMap is applied before the Deployment by kind
+		Expect(objects[0].GetNamespace()).To(Equal("shared"))
+		Expect(objects[1].GetNamespace()).To(Equal("apps"))
+		Expect(objects[1].GetLabels()).To(Equal(map[string]string{
+			"app":                  "web",
+			TemplateNameLabel:      "web",
+			TemplateNamespaceLabel: "apps",
+		}))
+	})
+
+	It("should carry the labels the policy propagates", func() {
+		labeled := newKubeTemplate("apps", "web", `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"web-config"}}`)
+		labeled.Labels = map[string]string{"team": "payments", "internal": "yes"}
+		policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+			Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{PropagateLabels: []string{"team"}},
+		}
+
+		objects, err := DesiredObjects(labeled, policy)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(objects[0].GetLabels()).To(Equal(map[string]string{
+			"team":                 "payments",
+			TemplateNameLabel:      "web",
+			TemplateNamespaceLabel: "apps",
+		}))
+	})
+
+	It("should redact the values of Secrets only", func() {
+		secret, err := Decode([]byte(`{"apiVersion":"v1","kind":"Secret","metadata":{"name":"db"},"data":{"password":"aHVudGVyMg=="},"stringData":{"user":"admin"}}`))
+		Expect(err).NotTo(HaveOccurred())
+		RedactSecretData(&secret)
+		Expect(secret.Object["data"]).To(Equal(map[string]interface{}{"password": ""}))
+		Expect(secret.Object["stringData"]).To(Equal(map[string]interface{}{"user": ""}))
+
+		configMap, err := Decode([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"db"},"data":{"user":"admin"}}`))
+		Expect(err).NotTo(HaveOccurred())
+		RedactSecretData(&configMap)
+		Expect(configMap.Object["data"]).To(Equal(map[string]interface{}{"user": "admin"}))
+	})
+
+	It("should round-trip the exported YAML back to the same objects", func() {
+		objects, err := DesiredObjects(kubeTemplate, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		var buf bytes.Buffer
+		Expect(WriteYAML(&buf, objects)).To(Succeed())
+		Expect(readYAMLStream(buf.Bytes())).To(Equal(objects))
+	})
+
+	It("should report templates that cannot be decoded", func() {
+		_, err := DesiredObjects(newKubeTemplate("apps", "broken", `null`), nil)
+		Expect(err).To(MatchError(ContainSubstring("apps/broken: template[0]: object is empty")))
+	})
+
+	Context("ExportHandler", func() {
+		var handler *ExportHandler
+
+		BeforeEach(func() {
+			scheme := runtime.NewScheme()
+			Expect(kubetemplateriov1alpha1.AddToScheme(scheme)).To(Succeed())
+			// The fake client only stores JSON template objects
+			handler = &ExportHandler{
+				Reader: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+					newKubeTemplate("apps", "web",
+						`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"web"}}`,
+						`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"web-config","namespace":"shared"}}`),
+					newKubeTemplate("other", "db", `{"apiVersion":"v1","kind":"Secret","metadata":{"name":"db"},"data":{"password":"aHVudGVyMg=="}}`),
+				).Build(),
+			}
+		})
+
+		get := func(url string) *httptest.ResponseRecorder {
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, url, nil))
+			return recorder
+		}
+
+		It("should export the objects of every KubeTemplate", func() {
+			recorder := get(ExportPath)
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(recorder.Header().Get("Content-Type")).To(Equal("application/yaml"))
+
+			objects := readYAMLStream(recorder.Body.Bytes())
+			Expect(objects).To(HaveLen(3))
+			Expect(objects[0].GetName()).To(Equal("web-config"))
+			Expect(objects[1].GetName()).To(Equal("web"))
+			Expect(objects[2].GetName()).To(Equal("db"))
+		})
+
+		It("should export a single KubeTemplate", func() {
+			recorder := get(ExportPath + "?namespace=other&name=db")
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+
+			objects := readYAMLStream(recorder.Body.Bytes())
+			Expect(objects).To(HaveLen(1))
+			Expect(objects[0].GetKind()).To(Equal("Secret"))
+			Expect(objects[0].GetNamespace()).To(Equal("other"))
+			Expect(objects[0].Object["data"]).To(Equal(map[string]interface{}{"password": ""}))
+			Expect(recorder.Body.String()).NotTo(ContainSubstring("aHVudGVyMg=="))
+		})
+
+		It("should skip and report a KubeTemplate that can't be decoded", func() {
+			Expect(handler.Reader.(client.Client).Create(context.Background(), newKubeTemplate("apps", "broken", `"kind: [ConfigMap"`))).To(Succeed())
+
+			recorder := get(ExportPath)
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			comment, stream, _ := strings.Cut(recorder.Body.String(), "\n")
+			Expect(comment).To(HavePrefix("# skipped apps/broken: template[0]: "))
+			Expect(readYAMLStream([]byte(stream))).To(HaveLen(3))
+		})
+
+		It("should return 404 for an unknown KubeTemplate", func() {
+			Expect(get(ExportPath + "?namespace=other&name=missing").Code).To(Equal(http.StatusNotFound))
+		})
+	})
+})
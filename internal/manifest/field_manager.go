@@ -0,0 +1,68 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manifest
+
+import (
+	"fmt"
+	"regexp"
+
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+)
+
+// DefaultFieldManager is the server-side apply field manager used when neither the template,
+// its policy nor the operator configuration set one
+const DefaultFieldManager = "kubetemplater"
+
+// MaxFieldManagerLength is the longest field manager the API server accepts
+const MaxFieldManagerLength = 128
+
+var fieldManagerPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._:/-]*$`)
+
+// FieldManager returns the field manager the template's object is applied with: the template's
+// own override, else the policy's, else operatorDefault (DefaultFieldManager if empty).
+// policy may be nil.
+func FieldManager(template kubetemplateriov1alpha1.Template, policy *kubetemplateriov1alpha1.KubeTemplatePolicy, operatorDefault string) string {
+	if template.FieldManager != "" {
+		return template.FieldManager
+	}
+	if policy != nil && policy.Spec.FieldManager != "" {
+		return policy.Spec.FieldManager
+	}
+	if operatorDefault != "" {
+		return operatorDefault
+	}
+	return DefaultFieldManager
+}
+
+// ValidateFieldManager checks the field manager override of a template, if any
+func ValidateFieldManager(template kubetemplateriov1alpha1.Template) error {
+	if template.FieldManager == "" {
+		return nil
+	}
+	return ValidateFieldManagerName(template.FieldManager)
+}
+
+// ValidateFieldManagerName checks that the API server accepts name as a field manager
+func ValidateFieldManagerName(name string) error {
+	if len(name) > MaxFieldManagerLength {
+		return fmt.Errorf("fieldManager is %d characters long, at most %d are allowed", len(name), MaxFieldManagerLength)
+	}
+	if !fieldManagerPattern.MatchString(name) {
+		return fmt.Errorf("fieldManager %q must start with a letter or digit and contain only letters, digits and . _ : / -", name)
+	}
+	return nil
+}
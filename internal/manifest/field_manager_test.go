@@ -0,0 +1,57 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manifest
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+)
+
+var _ = Describe("FieldManager", func() {
+	It("should fall back to the default field manager", func() {
+		Expect(FieldManager(kubetemplateriov1alpha1.Template{}, nil, "")).To(Equal(DefaultFieldManager))
+	})
+
+	It("should prefer the template's, then the policy's, then the operator's field manager", func() {
+		policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{FieldManager: "team-a"}}
+		template := kubetemplateriov1alpha1.Template{FieldManager: "team-a/config"}
+
+		Expect(FieldManager(template, policy, "platform")).To(Equal("team-a/config"))
+		Expect(FieldManager(kubetemplateriov1alpha1.Template{}, policy, "platform")).To(Equal("team-a"))
+		Expect(FieldManager(kubetemplateriov1alpha1.Template{}, &kubetemplateriov1alpha1.KubeTemplatePolicy{}, "platform")).To(Equal("platform"))
+	})
+
+	It("should use the template's field manager", func() {
+		template := kubetemplateriov1alpha1.Template{FieldManager: "team-a/config"}
+		Expect(FieldManager(template, nil, "")).To(Equal("team-a/config"))
+		Expect(ValidateFieldManager(template)).To(Succeed())
+	})
+
+	It("should reject a field manager that is too long", func() {
+		template := kubetemplateriov1alpha1.Template{FieldManager: strings.Repeat("m", MaxFieldManagerLength+1)}
+		Expect(ValidateFieldManager(template)).To(MatchError("fieldManager is 129 characters long, at most 128 are allowed"))
+	})
+
+	It("should reject a field manager with invalid characters", func() {
+		template := kubetemplateriov1alpha1.Template{FieldManager: "-team a"}
+		Expect(ValidateFieldManager(template)).To(MatchError(ContainSubstring("must start with a letter or digit")))
+	})
+})
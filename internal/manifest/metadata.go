@@ -0,0 +1,89 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manifest
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// serverManagedMetadata are the metadata fields the API server sets. Copied from a live object
+// into a template they break server-side apply, e.g. a stale resourceVersion makes every apply conflict.
+var serverManagedMetadata = []string{"resourceVersion", "uid", "creationTimestamp", "generation", "managedFields", "selfLink"}
+
+// ServerManagedMetadata returns the paths of the server-managed metadata fields obj sets, which
+// it usually does only because it was pasted from a dumped live resource. Fields set to null,
+// as in the creationTimestamp: null of manifests generated by kubectl, are not reported.
+func ServerManagedMetadata(obj *unstructured.Unstructured) []string {
+	metadata, _ := obj.Object["metadata"].(map[string]interface{})
+	var fields []string
+	for _, field := range serverManagedMetadata {
+		if value, found := metadata[field]; found && value != nil {
+			fields = append(fields, "metadata."+field)
+		}
+	}
+	return fields
+}
+
+// StripServerManagedMetadata removes the server-managed metadata fields of obj
+func StripServerManagedMetadata(obj *unstructured.Unstructured) {
+	for _, field := range serverManagedMetadata {
+		unstructured.RemoveNestedField(obj.Object, "metadata", field)
+	}
+}
+
+// PropagateLabels copies the labels named by keys from kubeTemplate onto obj. Keys kubeTemplate
+// doesn't carry are skipped, and a label obj already sets keeps its value unless override is set.
+func PropagateLabels(obj *unstructured.Unstructured, kubeTemplate metav1.Object, keys []string, override bool) {
+	if len(keys) == 0 {
+		return
+	}
+	labels := obj.GetLabels()
+	changed := false
+	for _, key := range keys {
+		value, found := kubeTemplate.GetLabels()[key]
+		if !found {
+			continue
+		}
+		if _, set := labels[key]; set && !override {
+			continue
+		}
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		labels[key] = value
+		changed = true
+	}
+	if changed {
+		obj.SetLabels(labels)
+	}
+}
+
+// ValidatePropagatedLabel checks that key is a valid label key other than the tracking labels,
+// which the operator sets itself
+func ValidatePropagatedLabel(key string) error {
+	if key == TemplateNameLabel || key == TemplateNamespaceLabel {
+		return fmt.Errorf("label %s is set by the operator and cannot be propagated", key)
+	}
+	if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+		return fmt.Errorf("invalid label key %q: %s", key, errs[0])
+	}
+	return nil
+}
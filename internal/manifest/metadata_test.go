@@ -0,0 +1,91 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manifest
+
+import (
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ = Describe("ServerManagedMetadata", func() {
+	It("should report and strip metadata set by the API server", func() {
+		obj, err := Decode([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"app","namespace":"apps",` +
+			`"labels":{"a":"b"},"resourceVersion":"4711","uid":"0b1c","creationTimestamp":"2025-06-02T12:00:00Z",` +
+			`"managedFields":[{"manager":"kubectl"}]},"data":{"key":"value"}}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ServerManagedMetadata(&obj)).To(Equal([]string{
+			"metadata.resourceVersion", "metadata.uid", "metadata.creationTimestamp", "metadata.managedFields",
+		}))
+
+		StripServerManagedMetadata(&obj)
+		Expect(ServerManagedMetadata(&obj)).To(BeEmpty())
+		Expect(obj.Object["metadata"]).To(Equal(map[string]interface{}{
+			"name": "app", "namespace": "apps", "labels": map[string]interface{}{"a": "b"},
+		}))
+	})
+
+	It("should not report a null creationTimestamp", func() {
+		obj, err := Decode([]byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app
+  creationTimestamp: null`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ServerManagedMetadata(&obj)).To(BeEmpty())
+
+		StripServerManagedMetadata(&obj)
+		Expect(obj.Object["metadata"]).To(Equal(map[string]interface{}{"name": "app"}))
+	})
+})
+
+var _ = Describe("PropagateLabels", func() {
+	kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "apps", Labels: map[string]string{
+			"team": "payments", "cost-center": "cc-42", "tier": "backend",
+		}},
+	}
+
+	decode := func() *unstructured.Unstructured {
+		obj, err := Decode([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"app","labels":{"team":"search"}}}`))
+		Expect(err).NotTo(HaveOccurred())
+		return &obj
+	}
+
+	It("should copy the listed labels the KubeTemplate carries", func() {
+		obj := decode()
+		PropagateLabels(obj, kubeTemplate, []string{"cost-center", "owner"}, false)
+		Expect(obj.GetLabels()).To(Equal(map[string]string{"team": "search", "cost-center": "cc-42"}))
+	})
+
+	It("should keep a value the object sets unless overriding", func() {
+		obj := decode()
+		PropagateLabels(obj, kubeTemplate, []string{"team"}, false)
+		Expect(obj.GetLabels()).To(HaveKeyWithValue("team", "search"))
+
+		PropagateLabels(obj, kubeTemplate, []string{"team"}, true)
+		Expect(obj.GetLabels()).To(HaveKeyWithValue("team", "payments"))
+	})
+
+	It("should reject keys that aren't label keys or are tracking labels", func() {
+		Expect(ValidatePropagatedLabel("example.com/cost-center")).To(Succeed())
+		Expect(ValidatePropagatedLabel("cost center")).To(MatchError(ContainSubstring(`invalid label key "cost center"`)))
+		Expect(ValidatePropagatedLabel(TemplateNameLabel)).To(MatchError(ContainSubstring("is set by the operator")))
+	})
+})
@@ -0,0 +1,141 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manifest
+
+import (
+	"sort"
+	"strings"
+
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	"sigs.k8s.io/yaml"
+)
+
+// DefaultKindOrder is the order in which templates with the same order field are applied by
+// kind, after Helm's install order: namespaces and policies first, then service accounts,
+// secrets and config maps, storage, CRDs, RBAC, services and finally workloads and ingresses
+var DefaultKindOrder = []string{
+	"Namespace",
+	"NetworkPolicy",
+	"ResourceQuota",
+	"LimitRange",
+	"PodSecurityPolicy",
+	"PodDisruptionBudget",
+	"ServiceAccount",
+	"Secret",
+	"SecretList",
+	"ConfigMap",
+	"StorageClass",
+	"PersistentVolume",
+	"PersistentVolumeClaim",
+	"CustomResourceDefinition",
+	"ClusterRole",
+	"ClusterRoleList",
+	"ClusterRoleBinding",
+	"ClusterRoleBindingList",
+	"Role",
+	"RoleList",
+	"RoleBinding",
+	"RoleBindingList",
+	"Service",
+	"DaemonSet",
+	"Pod",
+	"ReplicationController",
+	"ReplicaSet",
+	"Deployment",
+	"HorizontalPodAutoscaler",
+	"StatefulSet",
+	"Job",
+	"CronJob",
+	"IngressClass",
+	"Ingress",
+	"APIService",
+}
+
+// KindOrder orders templates with the same order field by kind; kinds it doesn't list are
+// applied after the listed ones, in list order. Empty applies them in list order only. It is
+// set from APPLY_KIND_ORDER at startup.
+var KindOrder = DefaultKindOrder
+
+// ParseKindOrder parses a comma-separated list of kinds for KindOrder, e.g.
+// "Namespace,ConfigMap,Deployment". An empty value selects DefaultKindOrder and "none"
+// disables ordering by kind.
+func ParseKindOrder(value string) []string {
+	value = strings.TrimSpace(value)
+	switch value {
+	case "":
+		return DefaultKindOrder
+	case "none":
+		return nil
+	}
+
+	var kinds []string
+	for _, kind := range strings.Split(value, ",") {
+		if kind = strings.TrimSpace(kind); kind != "" {
+			kinds = append(kinds, kind)
+		}
+	}
+	return kinds
+}
+
+// ApplyOrder returns the indices of templates in the order they are applied: by ascending
+// order field, then by the position of their kind in KindOrder, and in list order among the
+// rest
+func ApplyOrder(templates []kubetemplateriov1alpha1.Template) []int {
+	ranks := kindRanks(templates)
+	order := make([]int, len(templates))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		ta, tb := templates[order[a]], templates[order[b]]
+		if ta.Order != tb.Order {
+			return ta.Order < tb.Order
+		}
+		return ranks[order[a]] < ranks[order[b]]
+	})
+	return order
+}
+
+// kindRanks returns the position in KindOrder of the kind of every template, or
+// len(KindOrder) for kinds it doesn't list and objects whose kind can't be read
+func kindRanks(templates []kubetemplateriov1alpha1.Template) []int {
+	ranks := make([]int, len(templates))
+	if len(KindOrder) == 0 {
+		return ranks
+	}
+
+	positions := make(map[string]int, len(KindOrder))
+	for i, kind := range KindOrder {
+		if _, ok := positions[kind]; !ok {
+			positions[kind] = i
+		}
+	}
+	for i, template := range templates {
+		var typeMeta struct {
+			Kind string `json:"kind"`
+		}
+		ranks[i] = len(KindOrder)
+		doc, err := document(template.Object.Raw)
+		if err != nil || yaml.Unmarshal(doc, &typeMeta) != nil {
+			continue
+		}
+		if position, ok := positions[typeMeta.Kind]; ok {
+			ranks[i] = position
+		}
+	}
+	return ranks
+}
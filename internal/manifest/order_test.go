@@ -0,0 +1,105 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manifest
+
+import (
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var _ = Describe("ApplyOrder", func() {
+	It("should keep list order without an order field", func() {
+		Expect(ApplyOrder(make([]kubetemplateriov1alpha1.Template, 3))).To(Equal([]int{0, 1, 2}))
+	})
+
+	It("should apply lower orders first and keep list order among equal orders", func() {
+		templates := []kubetemplateriov1alpha1.Template{
+			{Order: 10}, // Deployment
+			{Order: 0},  // ConfigMap
+			{Order: -1}, // Namespace
+			{Order: 10}, // Service
+			{Order: 0},  // Secret
+		}
+		Expect(ApplyOrder(templates)).To(Equal([]int{2, 1, 4, 0, 3}))
+	})
+
+	It("should handle an empty template list", func() {
+		Expect(ApplyOrder(nil)).To(BeEmpty())
+	})
+
+	Context("By kind", func() {
+		object := func(kind string) kubetemplateriov1alpha1.Template {
+			return kubetemplateriov1alpha1.Template{
+				Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"` + kind + `","metadata":{"name":"x"}}`)},
+			}
+		}
+
+		It("should apply templates with the same order in Helm's install order", func() {
+			templates := []kubetemplateriov1alpha1.Template{
+				object("Deployment"),
+				object("Widget"), // Unknown kinds go last
+				object("ConfigMap"),
+				object("CustomResourceDefinition"),
+				object("Namespace"),
+			}
+			Expect(ApplyOrder(templates)).To(Equal([]int{4, 2, 3, 0, 1}))
+		})
+
+		It("should read the kind of an object written as a block scalar", func() {
+			// object: | stores the object as a JSON string holding a YAML document
+			namespace := kubetemplateriov1alpha1.Template{
+				Object: runtime.RawExtension{Raw: []byte(`"apiVersion: v1\nkind: Namespace\nmetadata:\n  name: team-a\n"`)},
+			}
+			templates := []kubetemplateriov1alpha1.Template{object("ConfigMap"), namespace}
+			Expect(ApplyOrder(templates)).To(Equal([]int{1, 0}))
+		})
+
+		It("should order by kind only among templates with the same order", func() {
+			late := object("Namespace")
+			late.Order = 1
+			templates := []kubetemplateriov1alpha1.Template{late, object("Deployment"), object("ConfigMap")}
+			Expect(ApplyOrder(templates)).To(Equal([]int{2, 1, 0}))
+		})
+
+		It("should use a configured kind order", func() {
+			DeferCleanup(func(kinds []string) { KindOrder = kinds }, KindOrder)
+
+			KindOrder = ParseKindOrder("Deployment, ConfigMap")
+			templates := []kubetemplateriov1alpha1.Template{object("ConfigMap"), object("Namespace"), object("Deployment")}
+			Expect(ApplyOrder(templates)).To(Equal([]int{2, 0, 1}))
+
+			KindOrder = ParseKindOrder("none")
+			Expect(ApplyOrder(templates)).To(Equal([]int{0, 1, 2}))
+		})
+	})
+})
+
+var _ = Describe("ParseKindOrder", func() {
+	It("should default to Helm's install order", func() {
+		Expect(ParseKindOrder(" ")).To(Equal(DefaultKindOrder))
+	})
+
+	It("should parse a comma-separated list of kinds", func() {
+		Expect(ParseKindOrder("Namespace,,ConfigMap ,Deployment")).To(Equal([]string{"Namespace", "ConfigMap", "Deployment"}))
+	})
+
+	It("should disable ordering by kind with none", func() {
+		Expect(ParseKindOrder("none")).To(BeEmpty())
+	})
+})
@@ -0,0 +1,85 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// placeholderPattern matches an escaped "$${" or a "${name}" placeholder
+var placeholderPattern = regexp.MustCompile(`\$\$\{|\$\{([^{}"\\]*)\}`)
+
+// ResolveParameters replaces the ${name} placeholders of a Template.Object payload with the
+// values of params, and "$${" with a literal "${". Payloads are left untouched when params is
+// empty, so templates that don't use parameters may contain "${" freely. Values are inserted
+// escaped as JSON string content, which keeps a JSON payload, the form the API server stores,
+// valid whatever the value. Placeholders naming no parameter are reported together.
+func ResolveParameters(raw []byte, params map[string]string) ([]byte, error) {
+	if len(params) == 0 {
+		return raw, nil
+	}
+
+	var undefined []string
+	resolved := placeholderPattern.ReplaceAllFunc(raw, func(match []byte) []byte {
+		if string(match) == "$${" {
+			return []byte("${")
+		}
+		name := string(match[2 : len(match)-1])
+		value, ok := params[name]
+		if !ok {
+			if !slices.Contains(undefined, name) {
+				undefined = append(undefined, name)
+			}
+			return match
+		}
+		return escapeJSONString(value)
+	})
+	switch len(undefined) {
+	case 0:
+		return resolved, nil
+	case 1:
+		return nil, fmt.Errorf("undefined parameter %s: define it in spec.parameters, or write $${ for a literal ${", undefined[0])
+	}
+	return nil, fmt.Errorf("undefined parameters %s: define them in spec.parameters, or write $${ for a literal ${", strings.Join(undefined, ", "))
+}
+
+// escapeJSONString returns value escaped for use inside a JSON string, without the quotes
+func escapeJSONString(value string) []byte {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(value) // encoding a string can't fail
+	quoted := bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+	return quoted[1 : len(quoted)-1]
+}
+
+// DecodeWithParameters resolves the parameter placeholders of raw against params, then decodes
+// it like Decode
+func DecodeWithParameters(raw []byte, params map[string]string) (unstructured.Unstructured, error) {
+	resolved, err := ResolveParameters(raw, params)
+	if err != nil {
+		return unstructured.Unstructured{}, err
+	}
+	return Decode(resolved)
+}
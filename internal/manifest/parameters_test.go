@@ -0,0 +1,52 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manifest
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ResolveParameters", func() {
+	It("should substitute parameters and keep the payload valid JSON", func() {
+		obj, err := DecodeWithParameters([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"${app}-config"},`+
+			`"data":{"greeting":"${greeting}","script":"echo $${HOME}"}}`),
+			map[string]string{"app": "shop", "greeting": `say "hi" <now>`})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(obj.GetName()).To(Equal("shop-config"))
+		Expect(obj.Object["data"]).To(Equal(map[string]interface{}{
+			"greeting": `say "hi" <now>`,
+			"script":   "echo ${HOME}",
+		}))
+	})
+
+	It("should leave payloads untouched without parameters", func() {
+		raw := []byte(`{"data":{"script":"echo ${HOME} $${USER}"}}`)
+		resolved, err := ResolveParameters(raw, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resolved).To(Equal(raw))
+	})
+
+	It("should report every undefined parameter once", func() {
+		_, err := ResolveParameters([]byte(`{"a":"${env}","b":"${region}","c":"${env}","d":"${app}"}`),
+			map[string]string{"app": "shop"})
+		Expect(err).To(MatchError("undefined parameters env, region: define them in spec.parameters, or write $${ for a literal ${"))
+
+		_, err = ResolveParameters([]byte(`{"a":"${env}"}`), map[string]string{"app": "shop"})
+		Expect(err).To(MatchError("undefined parameter env: define it in spec.parameters, or write $${ for a literal ${"))
+	})
+})
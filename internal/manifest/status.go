@@ -0,0 +1,48 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manifest
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// HasStatus reports whether obj sets a status, which objects usually do only because they
+// were pasted from a dumped live resource
+func HasStatus(obj *unstructured.Unstructured) bool {
+	_, found := obj.Object["status"]
+	return found
+}
+
+// StripStatus removes the status of obj, which KubeTemplater leaves to the controllers owning it
+func StripStatus(obj *unstructured.Unstructured) {
+	unstructured.RemoveNestedField(obj.Object, "status")
+}
+
+// StatusOf returns an object identifying obj and carrying only its status, for applying the
+// status subresource, or nil if obj sets no status
+func StatusOf(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	status, found := obj.Object["status"]
+	if !found {
+		return nil
+	}
+	statusObj := &unstructured.Unstructured{Object: map[string]interface{}{"status": runtime.DeepCopyJSONValue(status)}}
+	statusObj.SetGroupVersionKind(obj.GroupVersionKind())
+	statusObj.SetNamespace(obj.GetNamespace())
+	statusObj.SetName(obj.GetName())
+	return statusObj
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manifest
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Status", func() {
+	It("should strip the status of an object", func() {
+		obj, err := Decode([]byte(`{"apiVersion":"v1","kind":"Service","metadata":{"name":"web"},"status":{"loadBalancer":{}}}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(HasStatus(&obj)).To(BeTrue())
+
+		StripStatus(&obj)
+		Expect(HasStatus(&obj)).To(BeFalse())
+		Expect(obj.GetName()).To(Equal("web"))
+	})
+
+	It("should build the status of an object for the status subresource", func() {
+		obj, err := Decode([]byte(`{"apiVersion":"example.com/v1","kind":"Widget","metadata":{"name":"w","namespace":"apps","labels":{"a":"b"}},` +
+			`"spec":{"size":1},"status":{"ready":true}}`))
+		Expect(err).NotTo(HaveOccurred())
+
+		statusObj := StatusOf(&obj)
+		Expect(statusObj).NotTo(BeNil())
+		Expect(statusObj.Object).To(Equal(map[string]interface{}{
+			"apiVersion": "example.com/v1",
+			"kind":       "Widget",
+			"metadata":   map[string]interface{}{"name": "w", "namespace": "apps"},
+			"status":     map[string]interface{}{"ready": true},
+		}))
+
+		StripStatus(&obj)
+		Expect(StatusOf(&obj)).To(BeNil())
+	})
+})
@@ -0,0 +1,331 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics defines the Prometheus metrics exposed by KubeTemplater.
+// All collectors are registered with the controller-runtime registry so they are
+// served on the manager's existing metrics endpoint.
+package metrics
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// CEL evaluation results used as the "result" label value
+const (
+	CELResultPass  = "pass"
+	CELResultFail  = "fail"
+	CELResultError = "error"
+)
+
+// Webhook certificates used as the "certificate" label value
+const (
+	CertificateServer = "server"
+	CertificateCA     = "ca"
+)
+
+// Item processing results used as the "result" label value
+const (
+	ProcessingResultSuccess = "success"
+	ProcessingResultError   = "error"
+)
+
+// Policy validation sources used as the "source" label value
+const (
+	PolicySourceWebhook = "webhook"
+	PolicySourceWorker  = "worker"
+)
+
+// Policy validation results used as the "result" label value
+const (
+	PolicyResultAllowed = "allowed"
+	PolicyResultDenied  = "denied"
+)
+
+// MaxPolicyLabels is the number of distinct policies the per-policy metrics report; the
+// validations of further policies are reported under PolicyLabelOverflow
+const MaxPolicyLabels = 100
+
+// PolicyLabelOverflow is the "policy" label value of policies beyond MaxPolicyLabels
+const PolicyLabelOverflow = "_other"
+
+var (
+	// CELEvaluationsTotal counts CEL rule evaluations by outcome
+	CELEvaluationsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kubetemplater_cel_evaluations_total",
+			Help: "Total number of CEL rule evaluations by result (pass, fail, error)",
+		},
+		[]string{"result"},
+	)
+
+	// CELEvaluationDuration tracks how long CEL rules take to compile and evaluate
+	CELEvaluationDuration = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "kubetemplater_cel_evaluation_duration_seconds",
+			Help:    "Duration of CEL rule compilation and evaluation in seconds",
+			Buckets: prometheus.ExponentialBuckets(0.0001, 2, 12), // 100µs .. ~200ms
+		},
+	)
+
+	// ResourcesAppliedTotal counts applied template resources by the action the apply had
+	ResourcesAppliedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kubetemplater_resources_applied_total",
+			Help: "Total number of template resources applied by action (Created, Updated, Unchanged)",
+		},
+		[]string{"action"},
+	)
+
+	// ProcessingItemsInfo is 1 for every KubeTemplate a worker is currently processing
+	ProcessingItemsInfo = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kubetemplater_processing_items_info",
+			Help: "KubeTemplates currently being processed by a worker (value is always 1)",
+		},
+		[]string{"namespace", "name"},
+	)
+
+	// ReconcileLagSeconds tracks how long a spec change takes to be applied
+	ReconcileLagSeconds = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "kubetemplater_reconcile_lag_seconds",
+			Help:    "Time from a KubeTemplate spec change being queued to its new generation being Completed, in seconds",
+			Buckets: prometheus.ExponentialBuckets(0.1, 2, 14), // 100ms .. ~14min
+		},
+	)
+
+	// ItemProcessingDuration tracks how long a worker takes to process a single queue item
+	ItemProcessingDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "kubetemplater_item_processing_duration_seconds",
+			Help:    "Duration of processing a single work queue item by worker and result (success, error), in seconds",
+			Buckets: prometheus.ExponentialBuckets(0.01, 2, 14), // 10ms .. ~80s
+		},
+		[]string{"worker_id", "result"},
+	)
+
+	// ItemsPausedTotal counts templates paused after exhausting their retry cycles
+	ItemsPausedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kubetemplater_items_paused_total",
+			Help: "Total number of KubeTemplates paused after exhausting their retry cycles, by worker",
+		},
+		[]string{"worker_id"},
+	)
+
+	// WebhookCertificateExpiry is the expiry time of the webhook server certificate and its CA
+	WebhookCertificateExpiry = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kubetemplater_webhook_certificate_expiry_timestamp_seconds",
+			Help: "Expiry time of the webhook certificates by certificate (server, ca), as a Unix timestamp",
+		},
+		[]string{"certificate"},
+	)
+
+	// PolicyValidationsTotal counts KubeTemplates validated against each policy by outcome
+	PolicyValidationsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kubetemplater_policy_validations_total",
+			Help: "Total number of KubeTemplates validated against a policy by policy, source (webhook, worker) and result (allowed, denied)",
+		},
+		[]string{"policy", "source", "result"},
+	)
+
+	// PolicyTemplates is the number of KubeTemplates in the source namespace of each policy
+	PolicyTemplates = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kubetemplater_policy_templates",
+			Help: "Number of KubeTemplates governed by a policy, by policy",
+		},
+		[]string{"policy"},
+	)
+)
+
+// policyLabels holds the policies that have their own "policy" label value, so a cluster
+// with many policies can't grow the per-policy metrics without bound
+var policyLabels = struct {
+	sync.Mutex
+	names map[string]struct{}
+}{names: map[string]struct{}{}}
+
+// policyLabel returns the "policy" label value of the named policy
+func policyLabel(name string) string {
+	policyLabels.Lock()
+	defer policyLabels.Unlock()
+	if _, ok := policyLabels.names[name]; ok {
+		return name
+	}
+	if len(policyLabels.names) >= MaxPolicyLabels {
+		return PolicyLabelOverflow
+	}
+	policyLabels.names[name] = struct{}{}
+	return name
+}
+
+// Descriptors of the work queue metrics, collected from the queue's own counters
+var (
+	queueDepthDesc = prometheus.NewDesc("kubetemplater_queue_depth",
+		"Number of items waiting in the work queue, including delayed retries", nil, nil)
+	queueProcessingDesc = prometheus.NewDesc("kubetemplater_queue_processing_items",
+		"Number of work queue items currently being processed by a worker", nil, nil)
+	queueEnqueuedDesc = prometheus.NewDesc("kubetemplater_queue_enqueued_total",
+		"Total number of items added to the work queue, including deferrals and verifications", nil, nil)
+	queueDequeuedDesc = prometheus.NewDesc("kubetemplater_queue_dequeued_total",
+		"Total number of items handed to a worker", nil, nil)
+	queueRetriesDesc = prometheus.NewDesc("kubetemplater_queue_retries_total",
+		"Total number of items requeued with backoff after failing", nil, nil)
+)
+
+// QueueStats is a snapshot of the work queue counters
+type QueueStats struct {
+	Enqueued   int64
+	Dequeued   int64
+	Retries    int64
+	Depth      int
+	Processing int
+}
+
+// queueCollector reports the work queue counters on every scrape, so the queue stays the
+// single source of truth for them
+type queueCollector struct {
+	stats func() QueueStats
+}
+
+// NewQueueCollector returns a collector for the work queue whose counters stats returns
+func NewQueueCollector(stats func() QueueStats) prometheus.Collector {
+	return &queueCollector{stats: stats}
+}
+
+// Describe implements prometheus.Collector
+func (c *queueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- queueDepthDesc
+	ch <- queueProcessingDesc
+	ch <- queueEnqueuedDesc
+	ch <- queueDequeuedDesc
+	ch <- queueRetriesDesc
+}
+
+// Collect implements prometheus.Collector
+func (c *queueCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.stats()
+	ch <- prometheus.MustNewConstMetric(queueDepthDesc, prometheus.GaugeValue, float64(stats.Depth))
+	ch <- prometheus.MustNewConstMetric(queueProcessingDesc, prometheus.GaugeValue, float64(stats.Processing))
+	ch <- prometheus.MustNewConstMetric(queueEnqueuedDesc, prometheus.CounterValue, float64(stats.Enqueued))
+	ch <- prometheus.MustNewConstMetric(queueDequeuedDesc, prometheus.CounterValue, float64(stats.Dequeued))
+	ch <- prometheus.MustNewConstMetric(queueRetriesDesc, prometheus.CounterValue, float64(stats.Retries))
+}
+
+// RegisterQueue registers the metrics of the work queue whose counters stats returns.
+// Only one queue can be registered.
+func RegisterQueue(stats func() QueueStats) error {
+	return ctrlmetrics.Registry.Register(NewQueueCollector(stats))
+}
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(
+		CELEvaluationsTotal,
+		CELEvaluationDuration,
+		ResourcesAppliedTotal,
+		ProcessingItemsInfo,
+		ReconcileLagSeconds,
+		ItemProcessingDuration,
+		ItemsPausedTotal,
+		WebhookCertificateExpiry,
+		PolicyValidationsTotal,
+		PolicyTemplates,
+	)
+}
+
+// ObserveCELEvaluation records the outcome and duration of a single CEL evaluation
+func ObserveCELEvaluation(result string, duration time.Duration) {
+	CELEvaluationsTotal.WithLabelValues(result).Inc()
+	CELEvaluationDuration.Observe(duration.Seconds())
+}
+
+// ObserveResourceApplied records the action taken when applying a single resource
+func ObserveResourceApplied(action string) {
+	ResourcesAppliedTotal.WithLabelValues(action).Inc()
+}
+
+// ObserveReconcileLag records the delay between a spec change and its completion
+func ObserveReconcileLag(lag time.Duration) {
+	ReconcileLagSeconds.Observe(lag.Seconds())
+}
+
+// ObserveProcessingStarted marks a KubeTemplate as being processed
+func ObserveProcessingStarted(namespace, name string) {
+	ProcessingItemsInfo.WithLabelValues(namespace, name).Set(1)
+}
+
+// ObserveProcessingFinished removes a KubeTemplate from the set being processed
+func ObserveProcessingFinished(namespace, name string) {
+	ProcessingItemsInfo.DeleteLabelValues(namespace, name)
+}
+
+// ObserveItemProcessed records how long workerID took to process an item, and whether it failed
+func ObserveItemProcessed(workerID int, failed bool, duration time.Duration) {
+	result := ProcessingResultSuccess
+	if failed {
+		result = ProcessingResultError
+	}
+	ItemProcessingDuration.WithLabelValues(strconv.Itoa(workerID), result).Observe(duration.Seconds())
+}
+
+// ObserveItemPaused records that workerID paused a template after its last retry cycle
+func ObserveItemPaused(workerID int) {
+	ItemsPausedTotal.WithLabelValues(strconv.Itoa(workerID)).Inc()
+}
+
+// ObserveCertificateExpiry records when the given webhook certificate (server, ca) expires
+func ObserveCertificateExpiry(certificate string, notAfter time.Time) {
+	WebhookCertificateExpiry.WithLabelValues(certificate).Set(float64(notAfter.Unix()))
+}
+
+// ObservePolicyValidation records that source (webhook, worker) allowed or denied a KubeTemplate
+// validated against the named policy
+func ObservePolicyValidation(policy, source string, allowed bool) {
+	result := PolicyResultAllowed
+	if !allowed {
+		result = PolicyResultDenied
+	}
+	PolicyValidationsTotal.WithLabelValues(policyLabel(policy), source, result).Inc()
+}
+
+// ObservePolicyTemplates records the number of KubeTemplates the named policy governs. The
+// counts of policies beyond MaxPolicyLabels can't be told apart, so they aren't reported.
+func ObservePolicyTemplates(policy string, templates int) {
+	if label := policyLabel(policy); label != PolicyLabelOverflow {
+		PolicyTemplates.WithLabelValues(label).Set(float64(templates))
+	}
+}
+
+// ForgetPolicy removes the series of a deleted policy and frees its "policy" label value
+func ForgetPolicy(policy string) {
+	policyLabels.Lock()
+	_, ok := policyLabels.names[policy]
+	delete(policyLabels.names, policy)
+	policyLabels.Unlock()
+	if !ok {
+		return
+	}
+	PolicyValidationsTotal.DeletePartialMatch(prometheus.Labels{"policy": policy})
+	PolicyTemplates.DeleteLabelValues(policy)
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pause
+
+import (
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// Annotation freezes a KubeTemplate when set to "true": it is neither processed nor
+	// drift-corrected until the annotation is removed
+	Annotation = "kubetemplater.io/pause"
+	// ResumeAnnotation resumes a paused KubeTemplate when set to "true", whatever paused it.
+	// It takes precedence over Annotation.
+	ResumeAnnotation = "kubetemplater.io/resume"
+	// ReasonManual is the PausedReason of templates paused with Annotation
+	ReasonManual = "manual"
+	// ReasonRetriesExhausted starts the PausedReason of templates paused after their last
+	// retry cycle
+	ReasonRetriesExhausted = "Max retry cycles"
+)
+
+// Requested reports whether the pause annotation asks for obj to be paused
+func Requested(obj metav1.Object) bool {
+	annotations := obj.GetAnnotations()
+	return annotations[Annotation] == "true" && annotations[ResumeAnnotation] != "true"
+}
+
+// ResumeRequested reports whether the resume annotation asks for obj to be resumed
+func ResumeRequested(obj metav1.Object) bool {
+	return obj.GetAnnotations()[ResumeAnnotation] == "true"
+}
+
+// RetriesExhausted reports whether pausedReason is that of a template paused after its last
+// retry cycle
+func RetriesExhausted(pausedReason string) bool {
+	return strings.HasPrefix(pausedReason, ReasonRetriesExhausted)
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pause
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Requested", func() {
+	withAnnotations := func(annotations map[string]string) *metav1.ObjectMeta {
+		return &metav1.ObjectMeta{Annotations: annotations}
+	}
+
+	It("should pause when the annotation is true", func() {
+		Expect(Requested(withAnnotations(map[string]string{Annotation: "true"}))).To(BeTrue())
+	})
+
+	It("should not pause without the annotation or with another value", func() {
+		Expect(Requested(withAnnotations(nil))).To(BeFalse())
+		Expect(Requested(withAnnotations(map[string]string{Annotation: "false"}))).To(BeFalse())
+	})
+
+	It("should let the resume annotation take precedence", func() {
+		obj := withAnnotations(map[string]string{Annotation: "true", ResumeAnnotation: "true"})
+		Expect(Requested(obj)).To(BeFalse())
+		Expect(ResumeRequested(obj)).To(BeTrue())
+	})
+})
+
+var _ = Describe("RetriesExhausted", func() {
+	It("should only match the reason of templates paused after their last retry cycle", func() {
+		Expect(RetriesExhausted("Max retry cycles (3) exceeded. Last error: timeout")).To(BeTrue())
+		Expect(RetriesExhausted(ReasonManual)).To(BeFalse())
+		Expect(RetriesExhausted("operator lacks RBAC for target namespace default: forbidden")).To(BeFalse())
+	})
+})
@@ -0,0 +1,32 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+)
+
+// AdmittedVersionAnnotation records the resourceVersion of the policy a KubeTemplate was
+// admitted against. It is set by the mutating webhook on every create and update.
+const AdmittedVersionAnnotation = "kubetemplater.io/admitted-policy-version"
+
+// ChangedSinceAdmission returns the policy version the template was admitted against and
+// whether policy has changed since. Templates without the annotation never report a change.
+func ChangedSinceAdmission(kubeTemplate *kubetemplateriov1alpha1.KubeTemplate, policy *kubetemplateriov1alpha1.KubeTemplatePolicy) (string, bool) {
+	admitted := kubeTemplate.Annotations[AdmittedVersionAnnotation]
+	return admitted, admitted != "" && admitted != policy.ResourceVersion
+}
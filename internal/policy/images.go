@@ -0,0 +1,157 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+)
+
+// PodSpecPaths lists where a pod spec can be found in the supported workload kinds
+var PodSpecPaths = [][]string{
+	{"spec"},                     // Pod
+	{"spec", "template", "spec"}, // Deployment, StatefulSet, DaemonSet, ReplicaSet, Job
+	{"spec", "jobTemplate", "spec", "template", "spec"}, // CronJob
+}
+
+// ContainerListFields lists the pod spec fields holding containers
+var ContainerListFields = []string{"initContainers", "containers"}
+
+// dockerHub is the registry of image names without one
+const dockerHub = "docker.io"
+
+// ContainerImage is an image reference found in a resource
+type ContainerImage struct {
+	// Path identifies the container, e.g. "spec.template.spec.containers[0]"
+	Path  string
+	Image string
+}
+
+// imageReference is a parsed container image reference
+type imageReference struct {
+	name   string
+	tag    string
+	digest string
+}
+
+// ContainerImages collects the images of all containers and initContainers of the object
+func ContainerImages(obj *unstructured.Unstructured) []ContainerImage {
+	var images []ContainerImage
+	for _, specPath := range PodSpecPaths {
+		for _, field := range ContainerListFields {
+			containers, found, err := unstructured.NestedSlice(obj.Object, append(append([]string{}, specPath...), field)...)
+			if err != nil || !found {
+				continue
+			}
+			for i, c := range containers {
+				container, ok := c.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				image, ok := container["image"].(string)
+				if !ok {
+					continue
+				}
+				images = append(images, ContainerImage{
+					Path:  fmt.Sprintf("%s.%s[%d]", strings.Join(specPath, "."), field, i),
+					Image: image,
+				})
+			}
+		}
+	}
+	return images
+}
+
+// parseImageReference splits an image reference into name, tag and digest
+func parseImageReference(image string) imageReference {
+	var ref imageReference
+	if at := strings.Index(image, "@"); at >= 0 {
+		ref.digest = image[at+1:]
+		image = image[:at]
+	}
+	// A colon after the last slash separates the tag; earlier colons belong to a registry port
+	if colon := strings.LastIndex(image, ":"); colon > strings.LastIndex(image, "/") {
+		ref.tag = image[colon+1:]
+		image = image[:colon]
+	}
+	ref.name = image
+	return ref
+}
+
+// qualifiedImageName returns name with the Docker Hub registry and library namespace that
+// image names without a registry are pulled from, e.g. "nginx" is docker.io/library/nginx
+func qualifiedImageName(name string) string {
+	first, _, found := strings.Cut(name, "/")
+	switch {
+	case !found:
+		return dockerHub + "/library/" + name
+	case !strings.ContainsAny(first, ".:") && first != "localhost":
+		return dockerHub + "/" + name
+	}
+	return name
+}
+
+// matchingRegistry returns the first of prefixes the qualified image name is in, i.e. that is
+// the name or a leading part of it ending at a "/"
+func matchingRegistry(name string, prefixes []string) (string, bool) {
+	for _, prefix := range prefixes {
+		trimmed := strings.TrimSuffix(prefix, "/")
+		if name == trimmed || strings.HasPrefix(name, trimmed+"/") {
+			return prefix, true
+		}
+	}
+	return "", false
+}
+
+// ImageViolations checks all container images of the object against the image policy and
+// returns one message per rejected image, naming the image and its container
+func ImageViolations(imagePolicy *kubetemplateriov1alpha1.ImagePolicy, obj *unstructured.Unstructured) []string {
+	var violations []string
+	for _, c := range ContainerImages(obj) {
+		ref := parseImageReference(c.Image)
+		name := qualifiedImageName(ref.name)
+		denied, isDenied := matchingRegistry(name, imagePolicy.DeniedRegistries)
+		_, isAllowed := matchingRegistry(name, imagePolicy.AllowedRegistries)
+
+		var reason string
+		switch {
+		case isDenied:
+			reason = fmt.Sprintf("is from the denied registry %s", denied)
+		case len(imagePolicy.AllowedRegistries) > 0 && !isAllowed:
+			reason = fmt.Sprintf("is not from an allowed registry (%s)", strings.Join(imagePolicy.AllowedRegistries, ", "))
+		case imagePolicy.RequireDigest && ref.digest == "":
+			reason = "is not pinned by digest"
+		case imagePolicy.ForbidLatest && ref.digest == "" && ref.tag == "":
+			reason = "has no tag (defaults to latest)"
+		case imagePolicy.ForbidLatest && ref.tag == "latest":
+			reason = "uses the mutable tag latest"
+		default:
+			continue
+		}
+
+		if imagePolicy.Message != "" {
+			violations = append(violations, fmt.Sprintf("%s (image %s in %s)", imagePolicy.Message, c.Image, c.Path))
+			continue
+		}
+		violations = append(violations, fmt.Sprintf("image %s in %s %s", c.Image, c.Path, reason))
+	}
+	return violations
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+)
+
+var _ = Describe("ImageViolations", func() {
+	// deployment returns a Deployment with an init container and a container using the images
+	deployment := func(initImage, image string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"spec": map[string]interface{}{
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"initContainers": []interface{}{map[string]interface{}{"name": "init", "image": initImage}},
+						"containers":     []interface{}{map[string]interface{}{"name": "app", "image": image}},
+					},
+				},
+			},
+		}}
+	}
+
+	It("should only allow images from the allowed registries", func() {
+		imagePolicy := &kubetemplateriov1alpha1.ImagePolicy{AllowedRegistries: []string{"registry.example.com", "ghcr.io/my-org/"}}
+
+		Expect(ImageViolations(imagePolicy, deployment("registry.example.com/tools/init:1.0", "ghcr.io/my-org/app:1.0"))).To(BeEmpty())
+		Expect(ImageViolations(imagePolicy, deployment("registry.example.com:5000/init:1.0", "ghcr.io/my-org-fork/app:1.0"))).To(Equal([]string{
+			"image registry.example.com:5000/init:1.0 in spec.template.spec.initContainers[0] is not from an allowed registry (registry.example.com, ghcr.io/my-org/)",
+			"image ghcr.io/my-org-fork/app:1.0 in spec.template.spec.containers[0] is not from an allowed registry (registry.example.com, ghcr.io/my-org/)",
+		}))
+	})
+
+	It("should match images without a registry as Docker Hub images", func() {
+		imagePolicy := &kubetemplateriov1alpha1.ImagePolicy{AllowedRegistries: []string{"docker.io/library"}}
+
+		Expect(ImageViolations(imagePolicy, deployment("busybox", "nginx:1.27"))).To(BeEmpty())
+		Expect(ImageViolations(imagePolicy, deployment("busybox", "bitnami/nginx:1.27"))).To(HaveLen(1))
+		Expect(ImageViolations(imagePolicy, deployment("busybox", "localhost/nginx:1.27"))).To(HaveLen(1))
+	})
+
+	It("should let denied registries take precedence over allowed ones", func() {
+		imagePolicy := &kubetemplateriov1alpha1.ImagePolicy{
+			AllowedRegistries: []string{"registry.example.com"},
+			DeniedRegistries:  []string{"registry.example.com/untrusted"},
+			Message:           "use images from the team registry",
+		}
+
+		Expect(ImageViolations(imagePolicy, deployment("registry.example.com/tools/init:1.0", "registry.example.com/untrusted/app:1.0"))).To(Equal([]string{
+			"use images from the team registry (image registry.example.com/untrusted/app:1.0 in spec.template.spec.containers[0])",
+		}))
+	})
+
+	It("should check registries before tags and digests", func() {
+		imagePolicy := &kubetemplateriov1alpha1.ImagePolicy{DeniedRegistries: []string{"docker.io"}, ForbidLatest: true}
+
+		Expect(ImageViolations(imagePolicy, deployment("quay.io/tools/init:1.0", "nginx"))).To(Equal([]string{
+			"image nginx in spec.template.spec.containers[0] is from the denied registry docker.io",
+		}))
+	})
+})
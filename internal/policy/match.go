@@ -0,0 +1,100 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package policy matches template resources against KubeTemplatePolicy validation rules.
+package policy
+
+import (
+	"regexp"
+	"strings"
+
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// versionLike matches API versions such as "v1" or "v2beta1". No API group is named like
+// this, so a rule with such a group was meant for the core group.
+var versionLike = regexp.MustCompile(`^v[0-9]+((alpha|beta)[0-9]+)?$`)
+
+// NormalizeGroup maps common spellings of the core API group ("core", "v1") to "",
+// the group core resources actually have
+func NormalizeGroup(group string) string {
+	if strings.EqualFold(group, "core") || versionLike.MatchString(group) {
+		return ""
+	}
+	return group
+}
+
+// KindWildcard is the kind of rules that apply to every kind of their group and version
+const KindWildcard = "*"
+
+// kindMatch describes how specifically a rule names a kind; higher values take precedence
+type kindMatch int
+
+const (
+	kindMatchNone kindMatch = iota
+	kindMatchWildcard
+	kindMatchExact
+)
+
+// matchKind returns how specifically rule, through Kind or Kinds, names kind
+func matchKind(rule *kubetemplateriov1alpha1.ValidationRule, kind string) kindMatch {
+	match := kindMatchNone
+	for _, ruleKind := range append([]string{rule.Kind}, rule.Kinds...) {
+		switch ruleKind {
+		case kind:
+			return kindMatchExact
+		case KindWildcard:
+			match = kindMatchWildcard
+		}
+	}
+	return match
+}
+
+// RuleMatches reports whether rule applies to resources of the given kind
+func RuleMatches(rule *kubetemplateriov1alpha1.ValidationRule, gvk schema.GroupVersionKind) bool {
+	return NormalizeGroup(rule.Group) == gvk.Group && rule.Version == gvk.Version && matchKind(rule, gvk.Kind) != kindMatchNone
+}
+
+// FindRule returns the most specific rule of the policy matching gvk, or nil. A rule naming
+// the kind takes precedence over a wildcard rule; among equally specific rules the first wins.
+func FindRule(policy *kubetemplateriov1alpha1.KubeTemplatePolicy, gvk schema.GroupVersionKind) *kubetemplateriov1alpha1.ValidationRule {
+	if i := FindRuleIndex(policy, gvk); i >= 0 {
+		return &policy.Spec.ValidationRules[i]
+	}
+	return nil
+}
+
+// FindRuleIndex returns the index in spec.validationRules of the rule FindRule returns, or -1
+func FindRuleIndex(policy *kubetemplateriov1alpha1.KubeTemplatePolicy, gvk schema.GroupVersionKind) int {
+	found := -1
+	best := kindMatchNone
+	for i := range policy.Spec.ValidationRules {
+		rule := &policy.Spec.ValidationRules[i]
+		if !RuleMatches(rule, gvk) {
+			continue
+		}
+		if match := matchKind(rule, gvk.Kind); match > best {
+			found, best = i, match
+		}
+	}
+	return found
+}
+
+// MatchesByWildcard reports whether rule applies to kind only through KindWildcard
+func MatchesByWildcard(rule *kubetemplateriov1alpha1.ValidationRule, kind string) bool {
+	return matchKind(rule, kind) == kindMatchWildcard
+}
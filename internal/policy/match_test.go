@@ -0,0 +1,105 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var _ = Describe("RuleMatches", func() {
+	configMap := schema.GroupVersionKind{Group: "", Version: "v1", Kind: "ConfigMap"}
+	deployment := schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+
+	DescribeTable("core group spellings",
+		func(group string, expectMatch bool) {
+			rule := &kubetemplateriov1alpha1.ValidationRule{Kind: "ConfigMap", Group: group, Version: "v1"}
+			Expect(RuleMatches(rule, configMap)).To(Equal(expectMatch))
+		},
+		Entry("empty group", "", true),
+		Entry("core", "core", true),
+		Entry("Core", "Core", true),
+		Entry("version used as group", "v1", true),
+		Entry("another group", "apps", false),
+	)
+
+	It("should not normalize named groups", func() {
+		rule := &kubetemplateriov1alpha1.ValidationRule{Kind: "Deployment", Group: "apps", Version: "v1"}
+		Expect(RuleMatches(rule, deployment)).To(BeTrue())
+
+		rule.Group = "core"
+		Expect(RuleMatches(rule, deployment)).To(BeFalse())
+	})
+
+	It("should find the first matching rule of a policy", func() {
+		policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+			Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+				ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+					{Kind: "Deployment", Group: "apps", Version: "v1"},
+					{Kind: "ConfigMap", Group: "core", Version: "v1"},
+				},
+			},
+		}
+		Expect(FindRule(policy, configMap)).To(BeIdenticalTo(&policy.Spec.ValidationRules[1]))
+		Expect(FindRule(policy, schema.GroupVersionKind{Version: "v1", Kind: "Secret"})).To(BeNil())
+	})
+
+	It("should match every kind of the group and version with a wildcard", func() {
+		rule := &kubetemplateriov1alpha1.ValidationRule{Kind: KindWildcard, Group: "apps", Version: "v1"}
+		Expect(RuleMatches(rule, deployment)).To(BeTrue())
+		Expect(RuleMatches(rule, schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "StatefulSet"})).To(BeTrue())
+		Expect(RuleMatches(rule, configMap)).To(BeFalse())
+	})
+
+	It("should match the kinds of a list", func() {
+		rule := &kubetemplateriov1alpha1.ValidationRule{Kinds: []string{"ConfigMap", "Secret"}, Version: "v1"}
+		Expect(RuleMatches(rule, configMap)).To(BeTrue())
+		Expect(RuleMatches(rule, schema.GroupVersionKind{Version: "v1", Kind: "Secret"})).To(BeTrue())
+		Expect(RuleMatches(rule, schema.GroupVersionKind{Version: "v1", Kind: "Service"})).To(BeFalse())
+	})
+
+	It("should prefer a rule naming the kind over a wildcard rule", func() {
+		policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+			Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+				ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+					{Kind: KindWildcard, Version: "v1"},
+					{Kinds: []string{"Secret", "ConfigMap"}, Version: "v1"},
+					{Kind: "ConfigMap", Version: "v1"},
+				},
+			},
+		}
+		Expect(FindRule(policy, configMap)).To(BeIdenticalTo(&policy.Spec.ValidationRules[1]))
+		Expect(FindRule(policy, schema.GroupVersionKind{Version: "v1", Kind: "Service"})).To(BeIdenticalTo(&policy.Spec.ValidationRules[0]))
+	})
+
+	It("should report the index of the matching rule and whether it matched by wildcard", func() {
+		policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+			Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+				ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+					{Kind: KindWildcard, Version: "v1"},
+					{Kind: "ConfigMap", Version: "v1"},
+				},
+			},
+		}
+		Expect(FindRuleIndex(policy, configMap)).To(Equal(1))
+		Expect(FindRuleIndex(policy, deployment)).To(Equal(-1))
+		Expect(MatchesByWildcard(&policy.Spec.ValidationRules[0], "Secret")).To(BeTrue())
+		Expect(MatchesByWildcard(&policy.Spec.ValidationRules[1], "ConfigMap")).To(BeFalse())
+	})
+})
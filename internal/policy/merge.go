@@ -0,0 +1,255 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// MergedNameSeparator joins the names of merged policies into the name of the effective policy.
+// Object names can't contain it, so the effective name can't clash with a real policy.
+const MergedNameSeparator = "+"
+
+// MergedNames returns the names of the policies policy was merged from, or its own name if it
+// wasn't merged
+func MergedNames(policy *kubetemplateriov1alpha1.KubeTemplatePolicy) []string {
+	return strings.Split(policy.Name, MergedNameSeparator)
+}
+
+// Merge combines policies covering the same source namespace into one effective policy, with
+// the most restrictive settings of all of them:
+//   - validation rules are merged per kind: the target namespaces of rules for the same kind
+//     are united, their field validations all apply and their CEL rules must all hold; images
+//     must pass every image policy, so denied registries are united and allowed ones intersected
+//   - boolean protections apply if any policy sets them, limits take the lowest value set,
+//     and propagated labels are united
+//   - serviceAccountName and fieldManager may only be set to one value, and only one policy
+//     may define maintenance windows or a targetNamespaceSelector for a given kind, and image
+//     policies for a kind must allow a common registry; anything else is a conflict Merge
+//     returns as an error
+//
+// The effective policy is named after the merged policies, sorted by name and joined with
+// MergedNameSeparator, and its resourceVersion changes whenever one of theirs does.
+func Merge(policies []kubetemplateriov1alpha1.KubeTemplatePolicy) (*kubetemplateriov1alpha1.KubeTemplatePolicy, error) {
+	if len(policies) == 0 {
+		return nil, fmt.Errorf("no policies to merge")
+	}
+	sorted := slices.Clone(policies)
+	slices.SortFunc(sorted, func(a, b kubetemplateriov1alpha1.KubeTemplatePolicy) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	if len(sorted) == 1 {
+		return &sorted[0], nil
+	}
+
+	names := make([]string, len(sorted))
+	versions := make([]string, len(sorted))
+	for i := range sorted {
+		names[i] = sorted[i].Name
+		versions[i] = sorted[i].ResourceVersion
+	}
+
+	merged := &kubetemplateriov1alpha1.KubeTemplatePolicy{}
+	merged.TypeMeta = sorted[0].TypeMeta
+	merged.Namespace = sorted[0].Namespace
+	merged.Name = strings.Join(names, MergedNameSeparator)
+	merged.ResourceVersion = strings.Join(versions, ",")
+	merged.Spec.SourceNamespace = sorted[0].Spec.SourceNamespace
+
+	rules := &ruleMerger{index: map[ruleKey]int{}, origin: map[ruleKey]string{}, registriesFrom: map[ruleKey][]string{}}
+	maintenanceFrom := ""
+	for i := range sorted {
+		p := &sorted[i]
+		spec := &p.Spec
+
+		var err error
+		if merged.Spec.ServiceAccountName, err = mergeSingleValue("serviceAccountName", merged.Spec.ServiceAccountName, spec.ServiceAccountName, p.Name); err != nil {
+			return nil, err
+		}
+		if merged.Spec.FieldManager, err = mergeSingleValue("fieldManager", merged.Spec.FieldManager, spec.FieldManager, p.Name); err != nil {
+			return nil, err
+		}
+		if len(spec.MaintenanceWindows) > 0 {
+			if maintenanceFrom != "" {
+				return nil, fmt.Errorf("policies %s and %s both define maintenanceWindows", maintenanceFrom, p.Name)
+			}
+			maintenanceFrom = p.Name
+			merged.Spec.MaintenanceWindows = spec.MaintenanceWindows
+		}
+
+		merged.Spec.MaxApplyRate = lowestLimit(merged.Spec.MaxApplyRate, spec.MaxApplyRate)
+		merged.Spec.MaxTemplates = lowestLimit(merged.Spec.MaxTemplates, spec.MaxTemplates)
+		merged.Spec.ProtectUnmanagedResources = merged.Spec.ProtectUnmanagedResources || spec.ProtectUnmanagedResources
+		merged.Spec.OverrideTemplateLabels = merged.Spec.OverrideTemplateLabels || spec.OverrideTemplateLabels
+		merged.Spec.RejectTemplateStatus = merged.Spec.RejectTemplateStatus || spec.RejectTemplateStatus
+		merged.Spec.RejectServerManagedMetadata = merged.Spec.RejectServerManagedMetadata || spec.RejectServerManagedMetadata
+		merged.Spec.DryRunOnAdmission = merged.Spec.DryRunOnAdmission || spec.DryRunOnAdmission
+		merged.Spec.PropagateLabels = appendMissing(merged.Spec.PropagateLabels, spec.PropagateLabels...)
+
+		for j := range spec.ValidationRules {
+			if err := rules.add(&spec.ValidationRules[j], p.Name); err != nil {
+				return nil, err
+			}
+		}
+	}
+	merged.Spec.ValidationRules = rules.rules
+	return merged, nil
+}
+
+// ruleKey identifies the kind a merged rule applies to
+type ruleKey struct {
+	group, version, kind string
+}
+
+// ruleMerger collects rules split per kind, merging rules for a kind seen before
+type ruleMerger struct {
+	rules []kubetemplateriov1alpha1.ValidationRule
+	index map[ruleKey]int
+	// origin names the policy whose rule set the targetNamespaceSelector of a kind
+	origin map[ruleKey]string
+	// registriesFrom names the policies whose rules restrict the image registries of a kind
+	registriesFrom map[ruleKey][]string
+}
+
+func (m *ruleMerger) add(rule *kubetemplateriov1alpha1.ValidationRule, policyName string) error {
+	var kinds []string
+	for _, kind := range append([]string{rule.Kind}, rule.Kinds...) {
+		if kind != "" {
+			kinds = appendMissing(kinds, kind)
+		}
+	}
+
+	for _, kind := range kinds {
+		key := ruleKey{group: NormalizeGroup(rule.Group), version: rule.Version, kind: kind}
+		i, found := m.index[key]
+		if !found {
+			single := *rule.DeepCopy()
+			single.Kind, single.Kinds = kind, nil
+			m.index[key] = len(m.rules)
+			m.rules = append(m.rules, single)
+			if single.TargetNamespaceSelector != nil {
+				m.origin[key] = policyName
+			}
+			if single.ImagePolicy != nil && len(single.ImagePolicy.AllowedRegistries) > 0 {
+				m.registriesFrom[key] = []string{policyName}
+			}
+			continue
+		}
+
+		merged := &m.rules[i]
+		merged.TargetNamespaces = appendMissing(merged.TargetNamespaces, rule.TargetNamespaces...)
+		if rule.TargetNamespaceSelector != nil {
+			if merged.TargetNamespaceSelector != nil && !apiequality.Semantic.DeepEqual(merged.TargetNamespaceSelector, rule.TargetNamespaceSelector) {
+				return fmt.Errorf("policies %s and %s both define a targetNamespaceSelector for %s",
+					m.origin[key], policyName, schema.GroupVersionKind{Group: key.group, Version: key.version, Kind: kind})
+			}
+			merged.TargetNamespaceSelector = rule.TargetNamespaceSelector.DeepCopy()
+			m.origin[key] = policyName
+		}
+		switch {
+		case rule.Rule == "" || rule.Rule == merged.Rule:
+		case merged.Rule == "":
+			merged.Rule = rule.Rule
+		default:
+			merged.Rule = fmt.Sprintf("(%s) && (%s)", merged.Rule, rule.Rule)
+		}
+		for _, fv := range rule.FieldValidations {
+			merged.FieldValidations = append(merged.FieldValidations, *fv.DeepCopy())
+		}
+		if rule.ImagePolicy != nil {
+			if merged.ImagePolicy == nil {
+				merged.ImagePolicy = rule.ImagePolicy.DeepCopy()
+			} else {
+				merged.ImagePolicy.ForbidLatest = merged.ImagePolicy.ForbidLatest || rule.ImagePolicy.ForbidLatest
+				merged.ImagePolicy.RequireDigest = merged.ImagePolicy.RequireDigest || rule.ImagePolicy.RequireDigest
+				merged.ImagePolicy.DeniedRegistries = appendMissing(merged.ImagePolicy.DeniedRegistries, rule.ImagePolicy.DeniedRegistries...)
+				allowed, ok := intersectRegistries(merged.ImagePolicy.AllowedRegistries, rule.ImagePolicy.AllowedRegistries)
+				if !ok {
+					return fmt.Errorf("policies %s and %s allow no common image registry for %s",
+						strings.Join(m.registriesFrom[key], ", "), policyName, schema.GroupVersionKind{Group: key.group, Version: key.version, Kind: kind})
+				}
+				merged.ImagePolicy.AllowedRegistries = allowed
+				if len(rule.ImagePolicy.AllowedRegistries) > 0 {
+					m.registriesFrom[key] = append(m.registriesFrom[key], policyName)
+				}
+				if merged.ImagePolicy.Message == "" {
+					merged.ImagePolicy.Message = rule.ImagePolicy.Message
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// mergeSingleValue returns the value a setting takes when policyName sets it to value, which
+// conflicts with any other value already set
+func mergeSingleValue(field, current, value, policyName string) (string, error) {
+	if value == "" || value == current {
+		return current, nil
+	}
+	if current != "" {
+		return "", fmt.Errorf("policies set conflicting %s values %q and %q (policy %s)", field, current, value, policyName)
+	}
+	return value, nil
+}
+
+// lowestLimit returns the lower of two limits, where zero means unlimited
+func lowestLimit(a, b int32) int32 {
+	if a == 0 || (b != 0 && b < a) {
+		return b
+	}
+	return a
+}
+
+// intersectRegistries returns the registry prefixes an image must be in to be allowed by both
+// lists, and false if no image can be. An empty list allows every registry.
+func intersectRegistries(a, b []string) ([]string, bool) {
+	switch {
+	case len(a) == 0:
+		return slices.Clone(b), true
+	case len(b) == 0:
+		return a, true
+	}
+	var both []string
+	for _, x := range a {
+		for _, y := range b {
+			// Of two nested prefixes, the narrower one is allowed by both
+			if _, ok := matchingRegistry(strings.TrimSuffix(y, "/"), []string{x}); ok {
+				both = appendMissing(both, y)
+			} else if _, ok := matchingRegistry(strings.TrimSuffix(x, "/"), []string{y}); ok {
+				both = appendMissing(both, x)
+			}
+		}
+	}
+	return both, len(both) > 0
+}
+
+// appendMissing appends the values not in list yet
+func appendMissing(list []string, values ...string) []string {
+	for _, value := range values {
+		if !slices.Contains(list, value) {
+			list = append(list, value)
+		}
+	}
+	return list
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var _ = Describe("Merge", func() {
+	deployment := schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+	statefulSet := schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "StatefulSet"}
+
+	newPolicy := func(name, resourceVersion string, rules ...kubetemplateriov1alpha1.ValidationRule) kubetemplateriov1alpha1.KubeTemplatePolicy {
+		return kubetemplateriov1alpha1.KubeTemplatePolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "kubetemplater-system", ResourceVersion: resourceVersion},
+			Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+				SourceNamespace: "team-a",
+				ValidationRules: rules,
+			},
+		}
+	}
+
+	It("should return a single policy unchanged", func() {
+		single := newPolicy("baseline", "7", kubetemplateriov1alpha1.ValidationRule{Kind: "Deployment", Group: "apps", Version: "v1"})
+		merged, err := Merge([]kubetemplateriov1alpha1.KubeTemplatePolicy{single})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(merged.Name).To(Equal("baseline"))
+		Expect(merged.Spec).To(Equal(single.Spec))
+		Expect(MergedNames(merged)).To(Equal([]string{"baseline"}))
+	})
+
+	It("should merge overlapping rules for the same kind from two policies", func() {
+		team := newPolicy("team-a", "12", kubetemplateriov1alpha1.ValidationRule{
+			Kinds: []string{"Deployment", "StatefulSet"}, Group: "apps", Version: "v1",
+			TargetNamespaces: []string{"team-a", "team-a-dev"},
+			Rule:             "object.spec.replicas <= 10",
+			FieldValidations: []kubetemplateriov1alpha1.FieldValidation{{Name: "team-label", Type: "regex", FieldPath: "metadata.labels.team", Regex: "^a$"}},
+		})
+		baseline := newPolicy("baseline", "30", kubetemplateriov1alpha1.ValidationRule{
+			Kind: "Deployment", Group: "apps", Version: "v1",
+			TargetNamespaces: []string{"team-a", "shared"},
+			Rule:             "has(object.spec.strategy)",
+			FieldValidations: []kubetemplateriov1alpha1.FieldValidation{{Name: "probes", Type: "containerProbes", RequiredProbes: []string{"readinessProbe"}}},
+			ImagePolicy:      &kubetemplateriov1alpha1.ImagePolicy{ForbidLatest: true},
+		})
+
+		merged, err := Merge([]kubetemplateriov1alpha1.KubeTemplatePolicy{team, baseline})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(merged.Name).To(Equal("baseline+team-a"))
+		Expect(MergedNames(merged)).To(Equal([]string{"baseline", "team-a"}))
+		Expect(merged.Namespace).To(Equal("kubetemplater-system"))
+		Expect(merged.ResourceVersion).To(Equal("30,12"))
+		Expect(merged.Spec.SourceNamespace).To(Equal("team-a"))
+
+		rule := FindRule(merged, deployment)
+		Expect(rule).NotTo(BeNil())
+		Expect(rule.TargetNamespaces).To(Equal([]string{"team-a", "shared", "team-a-dev"}))
+		Expect(rule.Rule).To(Equal("(has(object.spec.strategy)) && (object.spec.replicas <= 10)"))
+		Expect(rule.FieldValidations).To(HaveLen(2))
+		Expect(rule.FieldValidations[0].Name).To(Equal("probes"))
+		Expect(rule.FieldValidations[1].Name).To(Equal("team-label"))
+		Expect(rule.ImagePolicy).To(Equal(&kubetemplateriov1alpha1.ImagePolicy{ForbidLatest: true}))
+
+		// StatefulSets are only covered by the team's rule
+		rule = FindRule(merged, statefulSet)
+		Expect(rule).NotTo(BeNil())
+		Expect(rule.TargetNamespaces).To(Equal([]string{"team-a", "team-a-dev"}))
+		Expect(rule.Rule).To(Equal("object.spec.replicas <= 10"))
+		Expect(rule.FieldValidations).To(HaveLen(1))
+		Expect(rule.ImagePolicy).To(BeNil())
+
+		// The input policies are left untouched
+		Expect(baseline.Spec.ValidationRules[0].TargetNamespaces).To(Equal([]string{"team-a", "shared"}))
+		Expect(team.Spec.ValidationRules[0].Kinds).To(Equal([]string{"Deployment", "StatefulSet"}))
+	})
+
+	It("should treat core group spellings as the same kind", func() {
+		merged, err := Merge([]kubetemplateriov1alpha1.KubeTemplatePolicy{
+			newPolicy("a", "1", kubetemplateriov1alpha1.ValidationRule{Kind: "ConfigMap", Group: "core", Version: "v1", TargetNamespaces: []string{"x"}}),
+			newPolicy("b", "2", kubetemplateriov1alpha1.ValidationRule{Kind: "ConfigMap", Group: "", Version: "v1", TargetNamespaces: []string{"y"}}),
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(merged.Spec.ValidationRules).To(HaveLen(1))
+		Expect(merged.Spec.ValidationRules[0].TargetNamespaces).To(Equal([]string{"x", "y"}))
+	})
+
+	It("should keep the most restrictive policy settings", func() {
+		a := newPolicy("a", "1")
+		a.Spec.MaxApplyRate = 20
+		a.Spec.RejectTemplateStatus = true
+		a.Spec.PropagateLabels = []string{"team"}
+		a.Spec.ServiceAccountName = "deployer"
+		b := newPolicy("b", "2")
+		b.Spec.MaxApplyRate = 5
+		b.Spec.MaxTemplates = 10
+		b.Spec.ProtectUnmanagedResources = true
+		b.Spec.PropagateLabels = []string{"team", "cost-center"}
+		b.Spec.MaintenanceWindows = []kubetemplateriov1alpha1.MaintenanceWindow{{Start: "22:00", End: "23:00"}}
+
+		merged, err := Merge([]kubetemplateriov1alpha1.KubeTemplatePolicy{a, b})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(merged.Spec.MaxApplyRate).To(Equal(int32(5)))
+		Expect(merged.Spec.MaxTemplates).To(Equal(int32(10)))
+		Expect(merged.Spec.RejectTemplateStatus).To(BeTrue())
+		Expect(merged.Spec.ProtectUnmanagedResources).To(BeTrue())
+		Expect(merged.Spec.RejectServerManagedMetadata).To(BeFalse())
+		Expect(merged.Spec.PropagateLabels).To(Equal([]string{"team", "cost-center"}))
+		Expect(merged.Spec.ServiceAccountName).To(Equal("deployer"))
+		Expect(merged.Spec.MaintenanceWindows).To(HaveLen(1))
+	})
+
+	It("should reject conflicting settings", func() {
+		a := newPolicy("a", "1")
+		a.Spec.ServiceAccountName = "deployer"
+		b := newPolicy("b", "2")
+		b.Spec.ServiceAccountName = "admin"
+		_, err := Merge([]kubetemplateriov1alpha1.KubeTemplatePolicy{a, b})
+		Expect(err).To(MatchError(`policies set conflicting serviceAccountName values "deployer" and "admin" (policy b)`))
+
+		window := []kubetemplateriov1alpha1.MaintenanceWindow{{Start: "22:00", End: "23:00"}}
+		a, b = newPolicy("a", "1"), newPolicy("b", "2")
+		a.Spec.MaintenanceWindows, b.Spec.MaintenanceWindows = window, window
+		_, err = Merge([]kubetemplateriov1alpha1.KubeTemplatePolicy{a, b})
+		Expect(err).To(MatchError("policies a and b both define maintenanceWindows"))
+
+		_, err = Merge([]kubetemplateriov1alpha1.KubeTemplatePolicy{
+			newPolicy("a", "1", kubetemplateriov1alpha1.ValidationRule{Kind: "Deployment", Group: "apps", Version: "v1",
+				TargetNamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}}}),
+			newPolicy("b", "2", kubetemplateriov1alpha1.ValidationRule{Kind: "Deployment", Group: "apps", Version: "v1",
+				TargetNamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "web"}}}),
+		})
+		Expect(err).To(MatchError("policies a and b both define a targetNamespaceSelector for apps/v1, Kind=Deployment"))
+	})
+
+	It("should only allow image registries every policy allows", func() {
+		imageRule := func(imagePolicy kubetemplateriov1alpha1.ImagePolicy) kubetemplateriov1alpha1.ValidationRule {
+			return kubetemplateriov1alpha1.ValidationRule{Kind: "Deployment", Group: "apps", Version: "v1", ImagePolicy: &imagePolicy}
+		}
+		merged, err := Merge([]kubetemplateriov1alpha1.KubeTemplatePolicy{
+			newPolicy("a", "1", imageRule(kubetemplateriov1alpha1.ImagePolicy{
+				AllowedRegistries: []string{"ghcr.io", "registry.example.com/team-a"},
+				DeniedRegistries:  []string{"ghcr.io/untrusted"},
+			})),
+			newPolicy("b", "2", imageRule(kubetemplateriov1alpha1.ImagePolicy{
+				AllowedRegistries: []string{"ghcr.io/my-org", "registry.example.com"},
+				DeniedRegistries:  []string{"registry.example.com/team-a/legacy"},
+			})),
+		})
+		Expect(err).NotTo(HaveOccurred())
+		imagePolicy := FindRule(merged, deployment).ImagePolicy
+		Expect(imagePolicy.AllowedRegistries).To(Equal([]string{"ghcr.io/my-org", "registry.example.com/team-a"}))
+		Expect(imagePolicy.DeniedRegistries).To(Equal([]string{"ghcr.io/untrusted", "registry.example.com/team-a/legacy"}))
+
+		_, err = Merge([]kubetemplateriov1alpha1.KubeTemplatePolicy{
+			newPolicy("a", "1", imageRule(kubetemplateriov1alpha1.ImagePolicy{AllowedRegistries: []string{"ghcr.io"}})),
+			newPolicy("b", "2", imageRule(kubetemplateriov1alpha1.ImagePolicy{AllowedRegistries: []string{"quay.io"}})),
+		})
+		Expect(err).To(MatchError("policies a and b allow no common image registry for apps/v1, Kind=Deployment"))
+	})
+})
@@ -0,0 +1,79 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ErrNoNamespaceReader is returned by NamespaceAllowed when TargetNamespaceSelector has to decide
+// but there is no reader to get the namespace from, as when a template is validated offline
+var ErrNoNamespaceReader = errors.New("no reader to get the namespace from to match targetNamespaceSelector")
+
+// HasTargetNamespaces reports whether rule allows any namespace at all, by name or by selector
+func HasTargetNamespaces(rule *kubetemplateriov1alpha1.ValidationRule) bool {
+	return len(rule.TargetNamespaces) > 0 || rule.TargetNamespaceSelector != nil
+}
+
+// NamespaceAllowed reports whether rule allows resources in namespace: TargetNamespaces lists
+// it, or its labels match TargetNamespaceSelector. The namespace is only read, from reader, when
+// the selector has to decide, so reader should be the manager's cached client. A namespace that
+// doesn't exist yet matches no selector; the error then satisfies errors.IsNotFound. A nil
+// reader makes it ErrNoNamespaceReader instead.
+func NamespaceAllowed(ctx context.Context, reader client.Reader, rule *kubetemplateriov1alpha1.ValidationRule, namespace string) (bool, error) {
+	if slices.Contains(rule.TargetNamespaces, namespace) {
+		return true, nil
+	}
+	if rule.TargetNamespaceSelector == nil {
+		return false, nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(rule.TargetNamespaceSelector)
+	if err != nil {
+		return false, fmt.Errorf("invalid targetNamespaceSelector: %w", err)
+	}
+	if reader == nil {
+		return false, ErrNoNamespaceReader
+	}
+	var ns corev1.Namespace
+	if err := reader.Get(ctx, client.ObjectKey{Name: namespace}, &ns); err != nil {
+		return false, fmt.Errorf("failed to get namespace %s to match targetNamespaceSelector: %w", namespace, err)
+	}
+	return selector.Matches(labels.Set(ns.Labels)), nil
+}
+
+// DescribeTargetNamespaces describes the namespaces rule allows, as in "[team-a team-b]" or
+// "[team-a] or matching team=payments", for rejection messages
+func DescribeTargetNamespaces(rule *kubetemplateriov1alpha1.ValidationRule) string {
+	if rule.TargetNamespaceSelector == nil {
+		return fmt.Sprintf("%v", rule.TargetNamespaces)
+	}
+	selector := "matching " + metav1.FormatLabelSelector(rule.TargetNamespaceSelector)
+	if len(rule.TargetNamespaces) == 0 {
+		return selector
+	}
+	return fmt.Sprintf("%v or %s", rule.TargetNamespaces, selector)
+}
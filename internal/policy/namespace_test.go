@@ -0,0 +1,96 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"context"
+
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("NamespaceAllowed", func() {
+	var reader client.Reader
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		reader = fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "payments-eu", Labels: map[string]string{"team": "payments"}}},
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "search"}},
+			).
+			Build()
+	})
+
+	bySelector := &kubetemplateriov1alpha1.ValidationRule{
+		TargetNamespaces:        []string{"shared"},
+		TargetNamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "payments"}},
+	}
+
+	DescribeTable("target namespaces and selector",
+		func(rule *kubetemplateriov1alpha1.ValidationRule, namespace string, expectAllowed bool) {
+			allowed, err := NamespaceAllowed(context.Background(), reader, rule, namespace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(allowed).To(Equal(expectAllowed))
+		},
+		Entry("listed namespace", &kubetemplateriov1alpha1.ValidationRule{TargetNamespaces: []string{"search"}}, "search", true),
+		Entry("unlisted namespace without selector", &kubetemplateriov1alpha1.ValidationRule{TargetNamespaces: []string{"search"}}, "payments-eu", false),
+		Entry("namespace matching the selector", bySelector, "payments-eu", true),
+		Entry("namespace not matching the selector", bySelector, "search", false),
+		Entry("listed namespace that doesn't exist", bySelector, "shared", true),
+		Entry("empty selector", &kubetemplateriov1alpha1.ValidationRule{TargetNamespaceSelector: &metav1.LabelSelector{}}, "search", true),
+	)
+
+	It("should report a namespace that doesn't exist yet as not found", func() {
+		allowed, err := NamespaceAllowed(context.Background(), reader, bySelector, "payments-us")
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+		Expect(allowed).To(BeFalse())
+	})
+
+	It("should only match by name without a reader", func() {
+		allowed, err := NamespaceAllowed(context.Background(), nil, bySelector, "shared")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(allowed).To(BeTrue())
+
+		_, err = NamespaceAllowed(context.Background(), nil, bySelector, "payments-eu")
+		Expect(err).To(MatchError(ErrNoNamespaceReader))
+	})
+
+	It("should reject an invalid selector", func() {
+		rule := &kubetemplateriov1alpha1.ValidationRule{
+			TargetNamespaceSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "team", Operator: "Near"},
+			}},
+		}
+		_, err := NamespaceAllowed(context.Background(), reader, rule, "search")
+		Expect(err).To(MatchError(ContainSubstring("invalid targetNamespaceSelector")))
+	})
+
+	It("should describe the namespaces a rule allows", func() {
+		Expect(DescribeTargetNamespaces(&kubetemplateriov1alpha1.ValidationRule{TargetNamespaces: []string{"a", "b"}})).To(Equal("[a b]"))
+		Expect(DescribeTargetNamespaces(bySelector)).To(Equal("[shared] or matching team=payments"))
+	})
+})
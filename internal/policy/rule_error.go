@@ -0,0 +1,46 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"errors"
+	"fmt"
+)
+
+// RuleError reports a CEL rule of a policy that could not be compiled or evaluated, or didn't
+// return a bool, e.g. because it dereferences a field the resource doesn't set. Unlike a rule
+// returning false, it is a problem of the policy rather than a violation by the resource.
+type RuleError struct {
+	Rule string
+	Err  error
+}
+
+// Error implements the error interface
+func (e *RuleError) Error() string {
+	return fmt.Sprintf("policy error: CEL rule %q is broken: %v", e.Rule, e.Err)
+}
+
+// Unwrap returns the compilation or evaluation error
+func (e *RuleError) Unwrap() error {
+	return e.Err
+}
+
+// IsRuleError reports whether err is or wraps a RuleError
+func IsRuleError(err error) bool {
+	var ruleErr *RuleError
+	return errors.As(err, &ruleErr)
+}
@@ -18,11 +18,15 @@ package queue
 
 import (
 	"container/heap"
+	"context"
 	"sync"
 	"time"
 
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	"github.com/lpeano/KubeTemplater/internal/metrics"
 	"k8s.io/apimachinery/pkg/types"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
 
 // Default retry configuration values
@@ -33,15 +37,41 @@ const (
 	DefaultMaxRetryCycles    = 3 // Default: stop after 3 full retry cycles (configurable per WorkQueue)
 )
 
+// Mode selects the order in which ready items are dequeued
+type Mode string
+
+const (
+	// ModePriority dequeues higher priorities first, then earlier scheduled times
+	ModePriority Mode = "Priority"
+	// ModeFIFO dequeues in enqueue order and ignores priorities. Retried and deferred
+	// items go to the back of the queue; backoff delays still apply.
+	ModeFIFO Mode = "FIFO"
+)
+
 // WorkItem represents a unit of work to be processed
 type WorkItem struct {
 	NamespacedName types.NamespacedName
 	Priority       int
 	RetryCount     int
-	RetryCycle     int       // Number of retry cycles (resets every MaxRetries)
+	RetryCycle     int // Number of retry cycles (resets every MaxRetries)
 	EnqueuedAt     time.Time
 	ScheduledAt    time.Time // For delayed retries
+	Verify         bool      // Post-apply verification pass: check applied resources exist, don't apply
 	index          int       // Index in the priority queue
+	seq            uint64    // Position in enqueue order, advanced on every (re)enqueue
+
+	// Policy is a snapshot of the policy that accepted the template at enqueue time.
+	// The worker falls back to it if the policy is deleted while the item is queued.
+	Policy *kubetemplateriov1alpha1.KubeTemplatePolicy
+
+	// ReadyWait is the object the worker deferred the item to wait for, if any
+	ReadyWait *ReadyWait
+}
+
+// ReadyWait is an applied object the worker checks again until it is ready or Deadline passes
+type ReadyWait struct {
+	Object   string // GroupVersionKind, namespace and name of the object
+	Deadline time.Time
 }
 
 // WorkQueue is a thread-safe priority queue with retry logic
@@ -51,11 +81,28 @@ type WorkQueue struct {
 	itemsMap          map[types.NamespacedName]*WorkItem
 	cond              *sync.Cond
 	shutdown          bool
+	draining          bool // Refuses new items and stops handing out queued ones (DrainAndShutdown)
 	metrics           *QueueMetrics
 	MaxRetries        int
 	InitialRetryDelay time.Duration
 	MaxRetryDelay     time.Duration
 	MaxRetryCycles    int // Maximum retry cycles before pausing (0 = unlimited)
+
+	// Mode is the dequeue order (default: ModePriority). Set it before enqueueing items.
+	Mode    Mode
+	nextSeq uint64
+
+	// MaxInFlightPerNamespace caps how many items of one namespace are processed at once, so a
+	// namespace with many templates can't occupy every worker (0 = unlimited). Set it before
+	// starting the workers.
+	MaxInFlightPerNamespace int
+	// inFlight counts the dequeued items of each namespace not yet marked Done or requeued
+	inFlight map[string]int
+
+	// SaturationDepth is the number of queued items due for processing at which IsSaturated
+	// reports back-pressure to producers (0 = never saturated). Items waiting out a retry
+	// backoff or a post-apply verification delay don't count.
+	SaturationDepth int
 }
 
 // QueueMetrics tracks queue statistics
@@ -104,6 +151,15 @@ func (pq *priorityQueue) Pop() interface{} {
 	return item
 }
 
+// fifoQueue orders a priorityQueue by enqueue order only
+type fifoQueue struct {
+	*priorityQueue
+}
+
+func (q fifoQueue) Less(i, j int) bool {
+	return (*q.priorityQueue)[i].seq < (*q.priorityQueue)[j].seq
+}
+
 // NewWorkQueue creates a new WorkQueue with default retry configuration
 func NewWorkQueue() *WorkQueue {
 	return NewWorkQueueWithConfig(DefaultMaxRetries, DefaultInitialRetryDelay, DefaultMaxRetryDelay, DefaultMaxRetryCycles)
@@ -114,30 +170,76 @@ func NewWorkQueueWithConfig(maxRetries int, initialDelay, maxDelay time.Duration
 	wq := &WorkQueue{
 		items:             make(priorityQueue, 0),
 		itemsMap:          make(map[types.NamespacedName]*WorkItem),
+		inFlight:          make(map[string]int),
 		metrics:           &QueueMetrics{},
 		MaxRetries:        maxRetries,
 		InitialRetryDelay: initialDelay,
 		MaxRetryDelay:     maxDelay,
 		MaxRetryCycles:    maxCycles,
+		Mode:              ModePriority,
 	}
 	wq.cond = sync.NewCond(&wq.mu)
 	heap.Init(&wq.items)
 	return wq
 }
 
+// ordered returns the queued items as a heap ordered according to the queue mode.
+// Must be called with wq.mu held.
+func (wq *WorkQueue) ordered() heap.Interface {
+	if wq.Mode == ModeFIFO {
+		return fifoQueue{&wq.items}
+	}
+	return &wq.items
+}
+
+// push adds an item at the back of the enqueue order.
+// Must be called with wq.mu held.
+func (wq *WorkQueue) push(item *WorkItem) {
+	wq.nextSeq++
+	item.seq = wq.nextSeq
+	heap.Push(wq.ordered(), item)
+	wq.itemsMap[item.NamespacedName] = item
+}
+
 // Enqueue adds an item to the queue
 func (wq *WorkQueue) Enqueue(namespacedName types.NamespacedName, priority int) {
+	wq.EnqueueWithPolicy(namespacedName, priority, nil)
+}
+
+// EnqueueWithPolicy adds an item to the queue together with a snapshot of the policy that
+// accepted it. A nil policy keeps any snapshot already held by a queued item.
+func (wq *WorkQueue) EnqueueWithPolicy(namespacedName types.NamespacedName, priority int, policy *kubetemplateriov1alpha1.KubeTemplatePolicy) {
 	wq.mu.Lock()
 	defer wq.mu.Unlock()
 
 	log := logf.Log.WithName("work-queue")
 
+	if wq.draining {
+		log.V(1).Info("Queue is draining, dropping enqueue", "item", namespacedName)
+		return
+	}
+
 	// Check if item already exists (deduplication)
 	if existingItem, exists := wq.itemsMap[namespacedName]; exists {
+		if policy != nil {
+			existingItem.Policy = policy
+		}
+		// A pending verification is superseded by a full processing run
+		if existingItem.Verify {
+			existingItem.Verify = false
+			existingItem.ScheduledAt = time.Now()
+			if priority > existingItem.Priority {
+				existingItem.Priority = priority
+			}
+			heap.Fix(wq.ordered(), existingItem.index)
+			log.V(1).Info("Converted pending verification to processing", "item", namespacedName)
+			wq.cond.Signal()
+			return
+		}
 		// Update priority if higher
 		if priority > existingItem.Priority {
 			existingItem.Priority = priority
-			heap.Fix(&wq.items, existingItem.index)
+			heap.Fix(wq.ordered(), existingItem.index)
 			log.V(1).Info("Updated item priority", "item", namespacedName, "priority", priority)
 		} else {
 			log.V(1).Info("Skipping duplicate enqueue", "item", namespacedName, "existingRetryCount", existingItem.RetryCount)
@@ -152,10 +254,10 @@ func (wq *WorkQueue) Enqueue(namespacedName types.NamespacedName, priority int)
 		RetryCount:     0,
 		EnqueuedAt:     time.Now(),
 		ScheduledAt:    time.Now(),
+		Policy:         policy,
 	}
 
-	heap.Push(&wq.items, item)
-	wq.itemsMap[namespacedName] = item
+	wq.push(item)
 
 	wq.metrics.mu.Lock()
 	wq.metrics.enqueueCount++
@@ -167,14 +269,45 @@ func (wq *WorkQueue) Enqueue(namespacedName types.NamespacedName, priority int)
 	wq.cond.Signal()
 }
 
+// EnqueueVerification schedules a post-apply verification pass for an item after the given delay.
+// It is a no-op if the item is already queued, since any pending run will be processed first.
+func (wq *WorkQueue) EnqueueVerification(namespacedName types.NamespacedName, priority int, delay time.Duration) {
+	wq.mu.Lock()
+	defer wq.mu.Unlock()
+
+	if _, exists := wq.itemsMap[namespacedName]; exists || wq.draining {
+		return
+	}
+
+	now := time.Now()
+	item := &WorkItem{
+		NamespacedName: namespacedName,
+		Priority:       priority,
+		EnqueuedAt:     now,
+		ScheduledAt:    now.Add(delay),
+		Verify:         true,
+	}
+
+	wq.push(item)
+
+	wq.metrics.mu.Lock()
+	wq.metrics.enqueueCount++
+	wq.metrics.currentDepth = len(wq.items)
+	wq.metrics.mu.Unlock()
+
+	logf.Log.WithName("work-queue").V(1).Info("Scheduled post-apply verification", "item", namespacedName, "delay", delay)
+
+	wq.cond.Signal()
+}
+
 // Dequeue retrieves the next item from the queue, blocking if empty
 func (wq *WorkQueue) Dequeue() (*WorkItem, bool) {
 	wq.mu.Lock()
 	defer wq.mu.Unlock()
 
 	for {
-		// Check if shutdown
-		if wq.shutdown {
+		// Check if shutdown; a draining queue only lets in-flight items finish
+		if wq.shutdown || wq.draining {
 			return nil, false
 		}
 
@@ -184,21 +317,30 @@ func (wq *WorkQueue) Dequeue() (*WorkItem, bool) {
 			item := wq.items[0]
 
 			// Check if item is ready (for delayed retries)
-			if now.Before(item.ScheduledAt) {
-				// Calculate wait time
-				waitTime := item.ScheduledAt.Sub(now)
-				// Wait with timeout
-				timer := time.AfterFunc(waitTime, func() {
-					wq.cond.Signal()
-				})
-				wq.cond.Wait()
-				timer.Stop()
-				continue
+			if !wq.dequeueable(item, now) {
+				// A delayed higher-priority item, or one whose namespace is at its in-flight cap,
+				// must not block items that can be processed now
+				if ready := wq.nextReady(now); ready != nil {
+					item = ready
+				} else if earliest, ok := wq.earliestScheduled(now); ok {
+					// Wait with timeout until the earliest scheduled item is due
+					timer := time.AfterFunc(earliest.Sub(now), func() {
+						wq.cond.Signal()
+					})
+					wq.cond.Wait()
+					timer.Stop()
+					continue
+				} else {
+					// Every waiting item's namespace is at its cap; Done or Requeue signals
+					wq.cond.Wait()
+					continue
+				}
 			}
 
 			// Remove from heap
-			heap.Pop(&wq.items)
+			heap.Remove(wq.ordered(), item.index)
 			delete(wq.itemsMap, item.NamespacedName)
+			wq.inFlight[item.NamespacedName.Namespace]++
 
 			wq.metrics.mu.Lock()
 			wq.metrics.dequeueCount++
@@ -214,6 +356,69 @@ func (wq *WorkQueue) Dequeue() (*WorkItem, bool) {
 	}
 }
 
+// dequeueable reports whether item is due and its namespace is below its in-flight cap.
+// Must be called with wq.mu held.
+func (wq *WorkQueue) dequeueable(item *WorkItem, now time.Time) bool {
+	return !now.Before(item.ScheduledAt) && !wq.atCapacity(item.NamespacedName.Namespace)
+}
+
+// atCapacity reports whether namespace has MaxInFlightPerNamespace items in flight.
+// Must be called with wq.mu held.
+func (wq *WorkQueue) atCapacity(namespace string) bool {
+	return wq.MaxInFlightPerNamespace > 0 && wq.inFlight[namespace] >= wq.MaxInFlightPerNamespace
+}
+
+// release ends the processing of a dequeued item, freeing its namespace's in-flight slot.
+// Must be called with wq.mu held.
+func (wq *WorkQueue) release(item *WorkItem) {
+	namespace := item.NamespacedName.Namespace
+	if wq.inFlight[namespace] <= 1 {
+		delete(wq.inFlight, namespace)
+	} else {
+		wq.inFlight[namespace]--
+	}
+	switch {
+	case wq.draining:
+		// DrainAndShutdown waits for the last in-flight item
+		wq.cond.Broadcast()
+	case wq.MaxInFlightPerNamespace > 0:
+		// An item held back by the cap may be processed now
+		wq.cond.Signal()
+	}
+}
+
+// nextReady returns the first dequeueable item in queue order, or nil.
+// Must be called with wq.mu held.
+func (wq *WorkQueue) nextReady(now time.Time) *WorkItem {
+	var best *WorkItem
+	for i, item := range wq.items {
+		if !wq.dequeueable(item, now) {
+			continue
+		}
+		if best == nil || wq.ordered().Less(i, best.index) {
+			best = item
+		}
+	}
+	return best
+}
+
+// earliestScheduled returns the earliest time after now that a queued item below its
+// namespace's in-flight cap is scheduled for, and false if there is none.
+// Must be called with wq.mu held.
+func (wq *WorkQueue) earliestScheduled(now time.Time) (time.Time, bool) {
+	var earliest time.Time
+	found := false
+	for _, item := range wq.items {
+		if !item.ScheduledAt.After(now) || wq.atCapacity(item.NamespacedName.Namespace) {
+			continue
+		}
+		if !found || item.ScheduledAt.Before(earliest) {
+			earliest, found = item.ScheduledAt, true
+		}
+	}
+	return earliest, found
+}
+
 // Requeue adds an item back to the queue with exponential backoff
 func (wq *WorkQueue) Requeue(item *WorkItem, err error) {
 	wq.mu.Lock()
@@ -221,12 +426,18 @@ func (wq *WorkQueue) Requeue(item *WorkItem, err error) {
 
 	log := logf.Log.WithName("work-queue")
 
+	wq.release(item)
 	item.RetryCount++
 
 	wq.metrics.mu.Lock()
 	wq.metrics.processingItems--
 	wq.metrics.mu.Unlock()
 
+	if wq.draining {
+		log.Info("Queue is draining, dropping retry", "item", item.NamespacedName, "retryCount", item.RetryCount, "error", err)
+		return
+	}
+
 	var delay time.Duration
 	if item.RetryCount > wq.MaxRetries {
 		// Check if max retry cycles exceeded (0 = unlimited)
@@ -236,9 +447,6 @@ func (wq *WorkQueue) Requeue(item *WorkItem, err error) {
 				"cycles", item.RetryCycle,
 				"maxCycles", wq.MaxRetryCycles)
 			// Don't re-enqueue - template will be marked as Paused by worker
-			wq.metrics.mu.Lock()
-			wq.metrics.processingItems--
-			wq.metrics.mu.Unlock()
 			return
 		}
 		
@@ -266,8 +474,7 @@ func (wq *WorkQueue) Requeue(item *WorkItem, err error) {
 
 	item.ScheduledAt = time.Now().Add(delay)
 
-	heap.Push(&wq.items, item)
-	wq.itemsMap[item.NamespacedName] = item
+	wq.push(item)
 
 	wq.metrics.mu.Lock()
 	wq.metrics.retryCount++
@@ -279,8 +486,67 @@ func (wq *WorkQueue) Requeue(item *WorkItem, err error) {
 	wq.cond.Signal()
 }
 
+// Expedite makes a queued item due now, e.g. a retry backing off from a failure whose cause is
+// gone. It reports whether the item was queued.
+func (wq *WorkQueue) Expedite(namespacedName types.NamespacedName) bool {
+	wq.mu.Lock()
+	defer wq.mu.Unlock()
+
+	item, exists := wq.itemsMap[namespacedName]
+	if !exists {
+		return false
+	}
+	if now := time.Now(); item.ScheduledAt.After(now) {
+		item.ScheduledAt = now
+		heap.Fix(wq.ordered(), item.index)
+		wq.cond.Signal()
+	}
+	return true
+}
+
+// Defer puts a dequeued item back to be processed again after delay without counting a retry.
+// If the item has been enqueued again in the meantime, that entry is kept instead, and takes
+// the policy snapshot and ready wait of item if it has none. The caller still marks the
+// dequeued item with Done.
+func (wq *WorkQueue) Defer(item *WorkItem, delay time.Duration) {
+	wq.mu.Lock()
+	defer wq.mu.Unlock()
+
+	if wq.draining {
+		return
+	}
+	if existing, exists := wq.itemsMap[item.NamespacedName]; exists {
+		if existing.Policy == nil {
+			existing.Policy = item.Policy
+		}
+		if existing.ReadyWait == nil {
+			existing.ReadyWait = item.ReadyWait
+		}
+		return
+	}
+
+	deferred := *item
+	deferred.Verify = false
+	deferred.ScheduledAt = time.Now().Add(delay)
+
+	wq.push(&deferred)
+
+	wq.metrics.mu.Lock()
+	wq.metrics.enqueueCount++
+	wq.metrics.currentDepth = len(wq.items)
+	wq.metrics.mu.Unlock()
+
+	logf.Log.WithName("work-queue").V(1).Info("Deferred item", "item", item.NamespacedName, "delay", delay)
+
+	wq.cond.Signal()
+}
+
 // Done marks an item as successfully processed
 func (wq *WorkQueue) Done(item *WorkItem) {
+	wq.mu.Lock()
+	wq.release(item)
+	wq.mu.Unlock()
+
 	wq.metrics.mu.Lock()
 	wq.metrics.processingItems--
 	wq.metrics.mu.Unlock()
@@ -295,6 +561,83 @@ func (wq *WorkQueue) Shutdown() {
 	wq.cond.Broadcast()
 }
 
+// DrainAndShutdown stops accepting and handing out items, waits up to timeout for the items in
+// flight to be marked Done or requeued, then shuts the queue down. Queued items that were not
+// started are logged and dropped; they are enqueued again by the next startup resync. It
+// reports whether every in-flight item finished in time.
+func (wq *WorkQueue) DrainAndShutdown(timeout time.Duration) bool {
+	log := logf.Log.WithName("work-queue")
+
+	wq.mu.Lock()
+	defer wq.mu.Unlock()
+
+	wq.draining = true
+	// Wake idle workers so they exit instead of waiting for items
+	wq.cond.Broadcast()
+
+	inFlight := func() int {
+		total := 0
+		for _, count := range wq.inFlight {
+			total += count
+		}
+		return total
+	}
+	if n := inFlight(); n > 0 {
+		log.Info("Draining work queue, waiting for in-flight items", "inFlight", n, "timeout", timeout)
+	}
+
+	deadline := time.Now().Add(timeout)
+	timer := time.AfterFunc(timeout, func() {
+		wq.mu.Lock()
+		defer wq.mu.Unlock()
+		wq.cond.Broadcast()
+	})
+	defer timer.Stop()
+	for len(wq.inFlight) > 0 && time.Now().Before(deadline) {
+		wq.cond.Wait()
+	}
+
+	drained := len(wq.inFlight) == 0
+	if !drained {
+		log.Info("Work queue drain timed out, abandoning in-flight items", "inFlight", inFlight(), "timeout", timeout)
+	}
+	if len(wq.items) > 0 {
+		pending := make([]string, 0, len(wq.items))
+		for _, item := range wq.items {
+			pending = append(pending, item.NamespacedName.String())
+		}
+		log.Info("Shutting down with queued items that were not started", "count", len(pending), "items", pending)
+	}
+
+	wq.shutdown = true
+	wq.cond.Broadcast()
+	return drained
+}
+
+// DrainOnShutdown returns a runnable that calls DrainAndShutdown with timeout when the
+// manager stops, so in-flight applies can finish before the process exits. It runs on every
+// replica, since every replica runs workers.
+func (wq *WorkQueue) DrainOnShutdown(timeout time.Duration) manager.Runnable {
+	return &drainer{queue: wq, timeout: timeout}
+}
+
+type drainer struct {
+	queue   *WorkQueue
+	timeout time.Duration
+}
+
+// Start implements manager.Runnable
+func (d *drainer) Start(ctx context.Context) error {
+	<-ctx.Done()
+	d.queue.DrainAndShutdown(d.timeout)
+	return nil
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable
+func (d *drainer) NeedLeaderElection() bool {
+	return false
+}
+
 // GetMetrics returns a snapshot of queue metrics
 func (wq *WorkQueue) GetMetrics() QueueMetrics {
 	wq.metrics.mu.RLock()
@@ -309,6 +652,20 @@ func (wq *WorkQueue) GetMetrics() QueueMetrics {
 	}
 }
 
+// Stats returns a snapshot of the queue metrics for the Prometheus collector
+func (wq *WorkQueue) Stats() metrics.QueueStats {
+	wq.metrics.mu.RLock()
+	defer wq.metrics.mu.RUnlock()
+
+	return metrics.QueueStats{
+		Enqueued:   wq.metrics.enqueueCount,
+		Dequeued:   wq.metrics.dequeueCount,
+		Retries:    wq.metrics.retryCount,
+		Depth:      wq.metrics.currentDepth,
+		Processing: wq.metrics.processingItems,
+	}
+}
+
 // Len returns the current queue depth
 func (wq *WorkQueue) Len() int {
 	wq.mu.Lock()
@@ -317,6 +674,29 @@ func (wq *WorkQueue) Len() int {
 	return len(wq.items)
 }
 
+// IsSaturated reports whether SaturationDepth or more items are due for processing, meaning
+// the workers can't keep up and new items would only wait behind them
+func (wq *WorkQueue) IsSaturated() bool {
+	if wq.SaturationDepth <= 0 {
+		return false
+	}
+
+	wq.mu.Lock()
+	defer wq.mu.Unlock()
+
+	now := time.Now()
+	due := 0
+	for _, item := range wq.items {
+		if item.ScheduledAt.After(now) {
+			continue
+		}
+		if due++; due >= wq.SaturationDepth {
+			return true
+		}
+	}
+	return false
+}
+
 // Contains checks if an item is currently in the queue
 // This is used to prevent duplicate enqueues and status update conflicts
 func (wq *WorkQueue) Contains(namespacedName types.NamespacedName) bool {
@@ -326,3 +706,16 @@ func (wq *WorkQueue) Contains(namespacedName types.NamespacedName) bool {
 	_, exists := wq.itemsMap[namespacedName]
 	return exists
 }
+
+// NextScheduled returns when a queued item becomes due, e.g. the time a retry backs off until.
+// It reports false if the item is not queued.
+func (wq *WorkQueue) NextScheduled(namespacedName types.NamespacedName) (time.Time, bool) {
+	wq.mu.Lock()
+	defer wq.mu.Unlock()
+
+	item, exists := wq.itemsMap[namespacedName]
+	if !exists {
+		return time.Time{}, false
+	}
+	return item.ScheduledAt, true
+}
@@ -0,0 +1,439 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"strings"
+	"time"
+
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	"github.com/lpeano/KubeTemplater/internal/metrics"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("WorkQueue", func() {
+	var wq *WorkQueue
+
+	BeforeEach(func() {
+		wq = NewWorkQueue()
+	})
+
+	AfterEach(func() {
+		wq.Shutdown()
+	})
+
+	It("should not let a delayed higher-priority item block ready items", func() {
+		delayed := types.NamespacedName{Namespace: "default", Name: "delayed"}
+		ready := types.NamespacedName{Namespace: "default", Name: "ready"}
+
+		wq.EnqueueVerification(delayed, 100, time.Hour)
+		wq.Enqueue(ready, 0)
+
+		item, ok := wq.Dequeue()
+		Expect(ok).To(BeTrue())
+		Expect(item.NamespacedName).To(Equal(ready))
+		Expect(wq.Contains(delayed)).To(BeTrue())
+	})
+
+	It("should run a pending verification as a full processing run when enqueued again", func() {
+		name := types.NamespacedName{Namespace: "default", Name: "template"}
+
+		wq.EnqueueVerification(name, 0, time.Hour)
+		wq.Enqueue(name, 0)
+		Expect(wq.Len()).To(Equal(1))
+
+		item, ok := wq.Dequeue()
+		Expect(ok).To(BeTrue())
+		Expect(item.Verify).To(BeFalse())
+	})
+
+	It("should not schedule a verification for an item that is already queued", func() {
+		name := types.NamespacedName{Namespace: "default", Name: "template"}
+
+		wq.Enqueue(name, 0)
+		wq.EnqueueVerification(name, 0, time.Millisecond)
+
+		item, ok := wq.Dequeue()
+		Expect(ok).To(BeTrue())
+		Expect(item.Verify).To(BeFalse())
+		Expect(wq.Len()).To(BeZero())
+	})
+
+	It("should keep the policy snapshot when a duplicate enqueue carries none", func() {
+		name := types.NamespacedName{Namespace: "default", Name: "template"}
+		policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{ObjectMeta: metav1.ObjectMeta{Name: "test-policy"}}
+
+		wq.EnqueueWithPolicy(name, 0, policy)
+		wq.Enqueue(name, 0)
+
+		item, ok := wq.Dequeue()
+		Expect(ok).To(BeTrue())
+		Expect(item.Policy).To(Equal(policy))
+	})
+
+	Describe("ordering", func() {
+		first := types.NamespacedName{Namespace: "default", Name: "first"}
+		second := types.NamespacedName{Namespace: "default", Name: "second"}
+
+		dequeueNames := func(n int) []types.NamespacedName {
+			var names []types.NamespacedName
+			for range n {
+				item, ok := wq.Dequeue()
+				Expect(ok).To(BeTrue())
+				names = append(names, item.NamespacedName)
+				wq.Done(item)
+			}
+			return names
+		}
+
+		// retryThenEnqueue fails first once, then enqueues second while the retry is backing off
+		retryThenEnqueue := func() {
+			wq.InitialRetryDelay = 20 * time.Millisecond
+			wq.Enqueue(first, 0)
+			item, ok := wq.Dequeue()
+			Expect(ok).To(BeTrue())
+			wq.Requeue(item, nil)
+			wq.Enqueue(second, 0)
+			time.Sleep(30 * time.Millisecond)
+		}
+
+		Context("in Priority mode", func() {
+			It("should dequeue higher priorities first", func() {
+				wq.Enqueue(first, 0)
+				wq.Enqueue(second, 10)
+
+				Expect(dequeueNames(2)).To(Equal([]types.NamespacedName{second, first}))
+			})
+
+			It("should order a retry by its backoff", func() {
+				retryThenEnqueue()
+
+				Expect(dequeueNames(2)).To(Equal([]types.NamespacedName{second, first}))
+			})
+		})
+
+		Context("in FIFO mode", func() {
+			BeforeEach(func() {
+				wq.Mode = ModeFIFO
+			})
+
+			It("should dequeue in enqueue order regardless of priority", func() {
+				wq.Enqueue(first, 0)
+				wq.Enqueue(second, 10)
+
+				Expect(dequeueNames(2)).To(Equal([]types.NamespacedName{first, second}))
+			})
+
+			It("should put a retry at the back of the queue", func() {
+				retryThenEnqueue()
+
+				Expect(dequeueNames(2)).To(Equal([]types.NamespacedName{first, second}))
+			})
+
+			It("should keep the position of an item enqueued again", func() {
+				wq.Enqueue(first, 0)
+				wq.Enqueue(second, 0)
+				wq.Enqueue(first, 10)
+
+				Expect(dequeueNames(2)).To(Equal([]types.NamespacedName{first, second}))
+			})
+
+			It("should not let a backing-off retry block ready items", func() {
+				wq.InitialRetryDelay = time.Hour
+				wq.Enqueue(first, 0)
+				item, ok := wq.Dequeue()
+				Expect(ok).To(BeTrue())
+				wq.Requeue(item, nil)
+				wq.Enqueue(second, 0)
+
+				Expect(dequeueNames(1)).To(Equal([]types.NamespacedName{second}))
+				Expect(wq.Contains(first)).To(BeTrue())
+			})
+		})
+	})
+
+	Context("When limiting the items in flight per namespace", func() {
+		noisy1 := types.NamespacedName{Namespace: "noisy", Name: "first"}
+		noisy2 := types.NamespacedName{Namespace: "noisy", Name: "second"}
+		quiet := types.NamespacedName{Namespace: "quiet", Name: "template"}
+
+		BeforeEach(func() {
+			wq.MaxInFlightPerNamespace = 1
+		})
+
+		It("should skip items of a namespace at its cap", func() {
+			wq.Enqueue(noisy1, 10)
+			wq.Enqueue(noisy2, 10)
+			wq.Enqueue(quiet, 0)
+
+			first, ok := wq.Dequeue()
+			Expect(ok).To(BeTrue())
+			Expect(first.NamespacedName).To(Equal(noisy1))
+
+			next, ok := wq.Dequeue()
+			Expect(ok).To(BeTrue())
+			Expect(next.NamespacedName).To(Equal(quiet))
+			Expect(wq.Contains(noisy2)).To(BeTrue())
+		})
+
+		It("should hand out a held back item once its namespace frees a slot", func() {
+			wq.Enqueue(noisy1, 0)
+			wq.Enqueue(noisy2, 0)
+			first, ok := wq.Dequeue()
+			Expect(ok).To(BeTrue())
+
+			dequeued := make(chan *WorkItem)
+			go func() {
+				defer GinkgoRecover()
+				item, ok := wq.Dequeue()
+				Expect(ok).To(BeTrue())
+				dequeued <- item
+			}()
+			Consistently(dequeued, 50*time.Millisecond).ShouldNot(Receive())
+
+			wq.Done(first)
+			var item *WorkItem
+			Eventually(dequeued).Should(Receive(&item))
+			Expect(item.NamespacedName).To(Equal(noisy2))
+		})
+
+		It("should free the slot of a requeued item", func() {
+			wq.InitialRetryDelay = time.Hour
+			wq.Enqueue(noisy1, 0)
+			wq.Enqueue(noisy2, 0)
+			first, ok := wq.Dequeue()
+			Expect(ok).To(BeTrue())
+			wq.Requeue(first, nil)
+
+			item, ok := wq.Dequeue()
+			Expect(ok).To(BeTrue())
+			Expect(item.NamespacedName).To(Equal(noisy2))
+		})
+	})
+
+	Context("When expediting an item", func() {
+		key := types.NamespacedName{Namespace: "default", Name: "backing-off"}
+
+		It("should make an item waiting for its retry due now", func() {
+			wq.InitialRetryDelay = time.Hour
+			wq.Enqueue(key, 0)
+			item, ok := wq.Dequeue()
+			Expect(ok).To(BeTrue())
+			wq.Requeue(item, nil)
+
+			Expect(wq.Expedite(key)).To(BeTrue())
+			item, ok = wq.Dequeue()
+			Expect(ok).To(BeTrue())
+			Expect(item.NamespacedName).To(Equal(key))
+		})
+
+		It("should report items that are not queued", func() {
+			Expect(wq.Expedite(key)).To(BeFalse())
+		})
+	})
+
+	Context("When reporting saturation", func() {
+		first := types.NamespacedName{Namespace: "default", Name: "first"}
+		second := types.NamespacedName{Namespace: "default", Name: "second"}
+
+		It("should never be saturated without a saturation depth", func() {
+			wq.Enqueue(first, 0)
+			wq.Enqueue(second, 0)
+			Expect(wq.IsSaturated()).To(BeFalse())
+		})
+
+		It("should be saturated once the due items reach the saturation depth", func() {
+			wq.SaturationDepth = 2
+			wq.Enqueue(first, 0)
+			Expect(wq.IsSaturated()).To(BeFalse())
+			wq.Enqueue(second, 0)
+			Expect(wq.IsSaturated()).To(BeTrue())
+
+			_, ok := wq.Dequeue()
+			Expect(ok).To(BeTrue())
+			Expect(wq.IsSaturated()).To(BeFalse())
+		})
+
+		It("should not count items waiting for their retry", func() {
+			wq.SaturationDepth = 2
+			wq.InitialRetryDelay = time.Hour
+			wq.Enqueue(first, 0)
+			item, ok := wq.Dequeue()
+			Expect(ok).To(BeTrue())
+			wq.Requeue(item, nil)
+
+			wq.Enqueue(second, 0)
+			Expect(wq.Len()).To(Equal(2))
+			Expect(wq.IsSaturated()).To(BeFalse())
+		})
+	})
+
+	Context("When reporting the next scheduled time", func() {
+		key := types.NamespacedName{Namespace: "default", Name: "retried"}
+
+		It("should report the backoff of a retry", func() {
+			wq.InitialRetryDelay = 4 * time.Second
+			wq.Enqueue(key, 0)
+			item, ok := wq.Dequeue()
+			Expect(ok).To(BeTrue())
+
+			requeuedAt := time.Now()
+			wq.Requeue(item, nil)
+			scheduledAt, queued := wq.NextScheduled(key)
+			Expect(queued).To(BeTrue())
+			Expect(scheduledAt).To(Equal(item.ScheduledAt))
+			Expect(scheduledAt).To(BeTemporally("~", requeuedAt.Add(4*time.Second), 100*time.Millisecond))
+		})
+
+		It("should report items that are not queued", func() {
+			_, queued := wq.NextScheduled(key)
+			Expect(queued).To(BeFalse())
+		})
+	})
+
+	Context("When draining on shutdown", func() {
+		active := types.NamespacedName{Namespace: "default", Name: "active"}
+		waiting := types.NamespacedName{Namespace: "default", Name: "waiting"}
+
+		It("should wait for in-flight items and stop handing out queued ones", func() {
+			wq.Enqueue(active, 10)
+			wq.Enqueue(waiting, 0)
+			item, ok := wq.Dequeue()
+			Expect(ok).To(BeTrue())
+			Expect(item.NamespacedName).To(Equal(active))
+
+			drained := make(chan bool)
+			go func() {
+				defer GinkgoRecover()
+				drained <- wq.DrainAndShutdown(time.Minute)
+			}()
+			Consistently(drained, 50*time.Millisecond).ShouldNot(Receive())
+
+			_, ok = wq.Dequeue()
+			Expect(ok).To(BeFalse())
+
+			wq.Done(item)
+			Eventually(drained).Should(Receive(BeTrue()))
+			Expect(wq.Contains(waiting)).To(BeTrue())
+		})
+
+		It("should refuse new items while draining", func() {
+			wq.Enqueue(active, 0)
+			item, ok := wq.Dequeue()
+			Expect(ok).To(BeTrue())
+
+			drained := make(chan bool)
+			go func() {
+				defer GinkgoRecover()
+				drained <- wq.DrainAndShutdown(time.Minute)
+			}()
+			// Dequeue on the empty queue returns once the drain has started
+			_, ok = wq.Dequeue()
+			Expect(ok).To(BeFalse())
+
+			wq.Enqueue(waiting, 0)
+			Expect(wq.Contains(waiting)).To(BeFalse())
+
+			// A failing in-flight item is not retried either
+			wq.Requeue(item, nil)
+			Eventually(drained).Should(Receive(BeTrue()))
+			Expect(wq.Len()).To(BeZero())
+		})
+
+		It("should give up on in-flight items after the timeout", func() {
+			wq.Enqueue(active, 0)
+			_, ok := wq.Dequeue()
+			Expect(ok).To(BeTrue())
+
+			start := time.Now()
+			Expect(wq.DrainAndShutdown(50 * time.Millisecond)).To(BeFalse())
+			Expect(time.Since(start)).To(BeNumerically(">=", 50*time.Millisecond))
+		})
+
+		It("should release idle workers right away", func() {
+			dequeued := make(chan bool)
+			go func() {
+				defer GinkgoRecover()
+				_, ok := wq.Dequeue()
+				dequeued <- ok
+			}()
+			Consistently(dequeued, 20*time.Millisecond).ShouldNot(Receive())
+
+			Expect(wq.DrainAndShutdown(time.Minute)).To(BeTrue())
+			Eventually(dequeued).Should(Receive(BeFalse()))
+		})
+	})
+
+	Context("When reporting metrics", func() {
+		name := types.NamespacedName{Namespace: "default", Name: "template"}
+
+		It("should count enqueues, dequeues and retries", func() {
+			wq.InitialRetryDelay = time.Hour
+			wq.Enqueue(name, 0)
+			item, ok := wq.Dequeue()
+			Expect(ok).To(BeTrue())
+			Expect(wq.Stats()).To(Equal(metrics.QueueStats{Enqueued: 1, Dequeued: 1, Processing: 1}))
+
+			wq.Requeue(item, nil)
+			Expect(wq.Stats()).To(Equal(metrics.QueueStats{Enqueued: 1, Dequeued: 1, Retries: 1, Depth: 1}))
+		})
+
+		It("should not count an item given up after its last retry cycle twice", func() {
+			wq.MaxRetries = 0
+			wq.MaxRetryCycles = 1
+			wq.Enqueue(name, 0)
+			item, ok := wq.Dequeue()
+			Expect(ok).To(BeTrue())
+			item.RetryCycle = 1
+
+			wq.Requeue(item, nil)
+			Expect(wq.Stats().Processing).To(BeZero())
+			Expect(wq.Len()).To(BeZero())
+		})
+
+		It("should expose the counters to Prometheus", func() {
+			wq.Enqueue(name, 0)
+			wq.Enqueue(types.NamespacedName{Namespace: "default", Name: "other"}, 0)
+			item, ok := wq.Dequeue()
+			Expect(ok).To(BeTrue())
+			wq.Done(item)
+
+			expected := `
+# HELP kubetemplater_queue_depth Number of items waiting in the work queue, including delayed retries
+# TYPE kubetemplater_queue_depth gauge
+kubetemplater_queue_depth 1
+# HELP kubetemplater_queue_dequeued_total Total number of items handed to a worker
+# TYPE kubetemplater_queue_dequeued_total counter
+kubetemplater_queue_dequeued_total 1
+# HELP kubetemplater_queue_enqueued_total Total number of items added to the work queue, including deferrals and verifications
+# TYPE kubetemplater_queue_enqueued_total counter
+kubetemplater_queue_enqueued_total 2
+# HELP kubetemplater_queue_processing_items Number of work queue items currently being processed by a worker
+# TYPE kubetemplater_queue_processing_items gauge
+kubetemplater_queue_processing_items 0
+# HELP kubetemplater_queue_retries_total Total number of items requeued with backoff after failing
+# TYPE kubetemplater_queue_retries_total counter
+kubetemplater_queue_retries_total 0
+`
+			Expect(testutil.CollectAndCompare(metrics.NewQueueCollector(wq.Stats), strings.NewReader(expected))).To(Succeed())
+		})
+	})
+})
@@ -0,0 +1,57 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package summary computes the one-line health summary shown by kubectl get kubetemplate.
+package summary
+
+import (
+	"fmt"
+	"strings"
+
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+)
+
+// MaxLength is the length summaries are truncated to, so the column stays readable
+const MaxLength = 80
+
+// Compute returns the summary of status: resource and drift counts for completed templates,
+// the reason for failed and paused ones, and the phase otherwise
+func Compute(status *kubetemplateriov1alpha1.KubeTemplateStatus) string {
+	switch status.ProcessingPhase {
+	case "Completed":
+		return fmt.Sprintf("%d/%d synced, %d drift", status.ResourcesSynced, status.ResourcesTotal, status.DriftDetectionCount)
+	case "Failed":
+		return truncate("FAILED: " + strings.TrimPrefix(status.Status, "Error: "))
+	case "Paused":
+		reason := status.PausedReason
+		if reason == "" {
+			reason = status.Status
+		}
+		return truncate("PAUSED: " + reason)
+	case "DryRunCompleted":
+		return truncate("DRY RUN: " + strings.TrimPrefix(status.Status, "Dry run completed: "))
+	}
+	return status.ProcessingPhase
+}
+
+// truncate shortens s to MaxLength runes, marking the cut with an ellipsis
+func truncate(s string) string {
+	runes := []rune(s)
+	if len(runes) <= MaxLength {
+		return s
+	}
+	return string(runes[:MaxLength-3]) + "..."
+}
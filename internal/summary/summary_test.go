@@ -0,0 +1,78 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package summary
+
+import (
+	"strings"
+
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Compute", func() {
+	It("should count synced resources and drift of completed templates", func() {
+		status := &kubetemplateriov1alpha1.KubeTemplateStatus{
+			ProcessingPhase:     "Completed",
+			ResourcesTotal:      3,
+			ResourcesSynced:     3,
+			DriftDetectionCount: 1,
+		}
+		Expect(Compute(status)).To(Equal("3/3 synced, 1 drift"))
+	})
+
+	It("should give the reason of failed templates", func() {
+		status := &kubetemplateriov1alpha1.KubeTemplateStatus{
+			ProcessingPhase: "Failed",
+			Status:          "Error: Resource /v1, Kind=Pod is not allowed by policy",
+		}
+		Expect(Compute(status)).To(Equal("FAILED: Resource /v1, Kind=Pod is not allowed by policy"))
+	})
+
+	It("should give the reason of paused templates", func() {
+		status := &kubetemplateriov1alpha1.KubeTemplateStatus{
+			ProcessingPhase: "Paused",
+			Status:          "Paused due to repeated failures",
+			PausedReason:    "Max retry cycles (5) exceeded",
+		}
+		Expect(Compute(status)).To(Equal("PAUSED: Max retry cycles (5) exceeded"))
+	})
+
+	It("should give the counts of dry runs", func() {
+		status := &kubetemplateriov1alpha1.KubeTemplateStatus{
+			ProcessingPhase: "DryRunCompleted",
+			Status:          "Dry run completed: 1 to create, 0 to update, 2 unchanged, 0 failed",
+		}
+		Expect(Compute(status)).To(Equal("DRY RUN: 1 to create, 0 to update, 2 unchanged, 0 failed"))
+	})
+
+	It("should give the phase of templates in progress", func() {
+		Expect(Compute(&kubetemplateriov1alpha1.KubeTemplateStatus{ProcessingPhase: "Queued"})).To(Equal("Queued"))
+		Expect(Compute(&kubetemplateriov1alpha1.KubeTemplateStatus{})).To(BeEmpty())
+	})
+
+	It("should truncate long reasons", func() {
+		status := &kubetemplateriov1alpha1.KubeTemplateStatus{
+			ProcessingPhase: "Failed",
+			Status:          "Error: " + strings.Repeat("x", 200),
+		}
+		summary := Compute(status)
+		Expect([]rune(summary)).To(HaveLen(MaxLength))
+		Expect(summary).To(HavePrefix("FAILED: xxx"))
+		Expect(summary).To(HaveSuffix("..."))
+	})
+})
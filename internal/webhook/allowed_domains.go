@@ -0,0 +1,109 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+)
+
+// ingressHost is a host name found in an Ingress
+type ingressHost struct {
+	// path identifies the field, e.g. "spec.rules[0].host"
+	path string
+	host string
+}
+
+// extractIngressHosts collects the hosts of the rules and TLS sections of an Ingress. A rule
+// without a host is reported with an empty host, since it matches every host.
+func extractIngressHosts(obj *unstructured.Unstructured) []ingressHost {
+	var hosts []ingressHost
+
+	rules, _, _ := unstructured.NestedSlice(obj.Object, "spec", "rules")
+	for i, r := range rules {
+		rule, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		host, _ := rule["host"].(string)
+		hosts = append(hosts, ingressHost{path: fmt.Sprintf("spec.rules[%d].host", i), host: host})
+	}
+
+	tls, _, _ := unstructured.NestedSlice(obj.Object, "spec", "tls")
+	for i, t := range tls {
+		entry, ok := t.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		tlsHosts, _, _ := unstructured.NestedStringSlice(entry, "hosts")
+		for j, host := range tlsHosts {
+			hosts = append(hosts, ingressHost{path: fmt.Sprintf("spec.tls[%d].hosts[%d]", i, j), host: host})
+		}
+	}
+	return hosts
+}
+
+// hostInDomain reports whether host is domain or one of its subdomains. A domain written as
+// "*.example.com" only allows subdomains. Wildcard hosts such as "*.apps.example.com" are
+// allowed when every host they match is.
+func hostInDomain(host, domain string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+	if sub, ok := strings.CutPrefix(domain, "*."); ok {
+		return strings.HasSuffix(host, "."+sub)
+	}
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
+// validateFieldAllowedDomains rejects Ingresses with hosts outside the allowed domains,
+// reporting every disallowed host in a single error. Other kinds have no hosts and pass.
+func (v *KubeTemplateValidator) validateFieldAllowedDomains(validation kubetemplateriov1alpha1.FieldValidation, obj *unstructured.Unstructured, templateIdx int) error {
+	if len(validation.AllowedDomains) == 0 {
+		return fmt.Errorf("template[%d]: fieldValidation (%s): allowedDomains is required for type 'allowedDomains'", templateIdx, validation.Name)
+	}
+
+	var violations []string
+	for _, h := range extractIngressHosts(obj) {
+		if h.host == "" {
+			violations = append(violations, fmt.Sprintf("%s is not set, so the rule matches every host", h.path))
+			continue
+		}
+		allowed := false
+		for _, domain := range validation.AllowedDomains {
+			if hostInDomain(h.host, domain) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			violations = append(violations, fmt.Sprintf("%s %s", h.path, h.host))
+		}
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+
+	if validation.Message != "" {
+		return fmt.Errorf("template[%d]: fieldValidation (%s): %s (%s)", templateIdx, validation.Name, validation.Message, strings.Join(violations, "; "))
+	}
+	return fmt.Errorf("template[%d]: fieldValidation (%s): %s/%s has hosts outside the allowed domains %s: %s",
+		templateIdx, validation.Name, obj.GetKind(), obj.GetName(), strings.Join(validation.AllowedDomains, ", "), strings.Join(violations, "; "))
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/lpeano/KubeTemplater/internal/policy"
+)
+
+// capacityResources are the resources whose requests are compared with node allocatable
+var capacityResources = []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory}
+
+// workloadRequests is the CPU and memory a workload object of the template requests
+type workloadRequests struct {
+	idx  int
+	kind string
+	name string
+	// perPod is the effective request of one pod, total that of all its replicas
+	perPod corev1.ResourceList
+	total  corev1.ResourceList
+}
+
+// requestsOf returns the requests of obj, or nil when it isn't a workload with a fixed number
+// of pods or requests nothing. DaemonSets are skipped, since they run a pod on every node.
+func requestsOf(idx int, obj *unstructured.Unstructured) *workloadRequests {
+	if obj.GetKind() == "DaemonSet" {
+		return nil
+	}
+	var podSpec map[string]interface{}
+	for _, specPath := range policy.PodSpecPaths {
+		spec, found, err := unstructured.NestedMap(obj.Object, specPath...)
+		if err == nil && found {
+			if _, ok := spec["containers"]; ok {
+				podSpec = spec
+				break
+			}
+		}
+	}
+	if podSpec == nil {
+		return nil
+	}
+
+	perPod := podRequests(podSpec)
+	if len(perPod) == 0 {
+		return nil
+	}
+	replicas := podCount(obj)
+	total := corev1.ResourceList{}
+	for name, quantity := range perPod {
+		sum := quantity.DeepCopy()
+		sum.Mul(replicas)
+		total[name] = sum
+	}
+	return &workloadRequests{idx: idx, kind: obj.GetKind(), name: obj.GetName(), perPod: perPod, total: total}
+}
+
+// podCount returns how many pods of obj run at once: spec.replicas, a Job's parallelism or 1
+func podCount(obj *unstructured.Unstructured) int64 {
+	var path []string
+	switch obj.GetKind() {
+	case "Deployment", "StatefulSet", "ReplicaSet", "ReplicationController":
+		path = []string{"spec", "replicas"}
+	case "Job":
+		path = []string{"spec", "parallelism"}
+	case "CronJob":
+		path = []string{"spec", "jobTemplate", "spec", "parallelism"}
+	default:
+		return 1
+	}
+	count, found, err := unstructured.NestedInt64(obj.Object, path...)
+	if err != nil || !found || count < 0 {
+		return 1
+	}
+	return count
+}
+
+// podRequests returns the effective request of a pod like the scheduler computes it: the sum
+// over its containers, or the largest init container's request if that is higher. A container
+// without a request for a resource counts its limit, which the API server defaults it to.
+func podRequests(podSpec map[string]interface{}) corev1.ResourceList {
+	result := corev1.ResourceList{}
+	containers, _, _ := unstructured.NestedSlice(podSpec, "containers")
+	for _, c := range containers {
+		for name, quantity := range containerRequests(c) {
+			sum := result[name]
+			sum.Add(quantity)
+			result[name] = sum
+		}
+	}
+	initContainers, _, _ := unstructured.NestedSlice(podSpec, "initContainers")
+	for _, c := range initContainers {
+		for name, quantity := range containerRequests(c) {
+			if current, found := result[name]; !found || quantity.Cmp(current) > 0 {
+				result[name] = quantity
+			}
+		}
+	}
+	return result
+}
+
+// containerRequests returns the CPU and memory requests of a container, skipping unparseable
+// quantities, which the API server rejects anyway
+func containerRequests(c interface{}) corev1.ResourceList {
+	container, ok := c.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	result := corev1.ResourceList{}
+	for _, name := range capacityResources {
+		for _, field := range []string{"requests", "limits"} {
+			value, found, err := unstructured.NestedFieldNoCopy(container, "resources", field, string(name))
+			if err != nil || !found {
+				continue
+			}
+			quantity, err := resource.ParseQuantity(fmt.Sprint(value))
+			if err != nil {
+				continue
+			}
+			result[name] = quantity
+			break
+		}
+	}
+	return result
+}
+
+// checkClusterCapacity warns when the workloads of a template request more than the schedulable
+// nodes can allocate, together or per pod on the largest node. It is advisory only: requests
+// of pods already running, taints and affinities are not taken into account, and the cluster
+// may autoscale.
+func (v *KubeTemplateValidator) checkClusterCapacity(ctx context.Context, workloads []*workloadRequests) admission.Warnings {
+	if len(workloads) == 0 {
+		return nil
+	}
+
+	var nodes corev1.NodeList
+	if err := v.Client.List(ctx, &nodes); err != nil {
+		return admission.Warnings{fmt.Sprintf("cluster capacity check skipped: failed to list nodes: %v", err)}
+	}
+	allocatable, largest := corev1.ResourceList{}, corev1.ResourceList{}
+	schedulable := 0
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if node.Spec.Unschedulable {
+			continue
+		}
+		schedulable++
+		for _, name := range capacityResources {
+			quantity, found := node.Status.Allocatable[name]
+			if !found {
+				continue
+			}
+			sum := allocatable[name]
+			sum.Add(quantity)
+			allocatable[name] = sum
+			if current, found := largest[name]; !found || quantity.Cmp(current) > 0 {
+				largest[name] = quantity
+			}
+		}
+	}
+	if schedulable == 0 {
+		return admission.Warnings{"cluster capacity check skipped: no schedulable nodes found"}
+	}
+
+	var warnings admission.Warnings
+	total := corev1.ResourceList{}
+	for _, workload := range workloads {
+		for _, name := range capacityResources {
+			if quantity, found := workload.perPod[name]; found {
+				if nodeMax, known := largest[name]; known && quantity.Cmp(nodeMax) > 0 {
+					warnings = append(warnings, fmt.Sprintf("template[%d]: each pod of %s %s requests %s %s, more than any node can allocate (largest: %s), so its pods cannot be scheduled",
+						workload.idx, workload.kind, workload.name, quantity.String(), name, nodeMax.String()))
+				}
+			}
+			if quantity, found := workload.total[name]; found {
+				sum := total[name]
+				sum.Add(quantity)
+				total[name] = sum
+			}
+		}
+	}
+	for _, name := range capacityResources {
+		if quantity, found := total[name]; found {
+			if available, known := allocatable[name]; known && quantity.Cmp(available) > 0 {
+				warnings = append(warnings, fmt.Sprintf("the workloads of the KubeTemplate request %s %s in total, more than the %s allocatable on all %d schedulable nodes; this check is advisory",
+					quantity.String(), name, available.String(), schedulable))
+			}
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// BenchmarkValidateCELRule compares evaluating a rule with a fresh validator, which compiles it
+// every time, to evaluating it with a validator that has already compiled it
+func BenchmarkValidateCELRule(b *testing.B) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": "bench", "namespace": "default"},
+		"spec":       map[string]interface{}{"replicas": int64(3)},
+	}}
+	rule := "object.spec.replicas <= 5 && object.metadata.name.startsWith('bench')"
+
+	b.Run("uncached", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if err := (&KubeTemplateValidator{}).validateCELRule(rule, obj, 0, ""); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("cached", func(b *testing.B) {
+		validator := &KubeTemplateValidator{}
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if err := validator.validateCELRule(rule, obj, 0, ""); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
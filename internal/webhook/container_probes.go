@@ -0,0 +1,69 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	"github.com/lpeano/KubeTemplater/internal/policy"
+)
+
+// findMissingProbes lists the containers of all pod specs of the object that don't define
+// one of the probes, each described with the path of the container
+func findMissingProbes(obj *unstructured.Unstructured, probes []string) []string {
+	var missing []string
+	for _, specPath := range policy.PodSpecPaths {
+		containers, found, err := unstructured.NestedSlice(obj.Object, append(append([]string{}, specPath...), "containers")...)
+		if err != nil || !found {
+			continue
+		}
+		for i, c := range containers {
+			container, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			var absent []string
+			for _, probe := range probes {
+				if _, ok := container[probe].(map[string]interface{}); !ok {
+					absent = append(absent, probe)
+				}
+			}
+			if len(absent) > 0 {
+				missing = append(missing, fmt.Sprintf("%s.containers[%d] (%v) has no %s", strings.Join(specPath, "."), i, container["name"], strings.Join(absent, ", ")))
+			}
+		}
+	}
+	return missing
+}
+
+// validateFieldContainerProbes rejects resources with containers that don't define the
+// required probes, reporting every container in a single error
+func (v *KubeTemplateValidator) validateFieldContainerProbes(validation kubetemplateriov1alpha1.FieldValidation, obj *unstructured.Unstructured, templateIdx int) error {
+	missing := findMissingProbes(obj, validation.RequiredProbes)
+	if len(missing) == 0 {
+		return nil
+	}
+
+	if validation.Message != "" {
+		return fmt.Errorf("template[%d]: fieldValidation (%s): %s (%s)", templateIdx, validation.Name, validation.Message, strings.Join(missing, "; "))
+	}
+	return fmt.Errorf("template[%d]: fieldValidation (%s): %s/%s has containers missing required probes: %s", templateIdx, validation.Name, obj.GetKind(), obj.GetName(), strings.Join(missing, "; "))
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// dryRunApplyTimeout bounds all dry-run applies of one KubeTemplate, so that a slow API server
+// can't use up the admission timeout
+const dryRunApplyTimeout = 2 * time.Second
+
+// dryRunTarget is a template object to dry-run apply with the field manager the worker uses
+type dryRunTarget struct {
+	idx          int
+	obj          *unstructured.Unstructured
+	fieldManager string
+}
+
+// dryRunApplyConflicts dry-runs a server-side apply of every target and warns about those that
+// conflict with another field manager. Any other failure is only logged: the apply may still
+// succeed later, e.g. once the target namespace exists.
+func (v *KubeTemplateValidator) dryRunApplyConflicts(ctx context.Context, targets []dryRunTarget) admission.Warnings {
+	log := logf.FromContext(ctx)
+	var warnings admission.Warnings
+
+	dryRunCtx, cancel := context.WithTimeout(ctx, dryRunApplyTimeout)
+	defer cancel()
+
+	for i, target := range targets {
+		if dryRunCtx.Err() != nil {
+			warnings = append(warnings, fmt.Sprintf("template[%d]: dry-run apply skipped for %d template object(s), the %s budget ran out", target.idx, len(targets)-i, dryRunApplyTimeout))
+			break
+		}
+
+		obj := target.obj.DeepCopy()
+		err := v.Client.Patch(dryRunCtx, obj, client.Apply, client.FieldOwner(target.fieldManager), client.DryRunAll)
+		switch {
+		case err == nil:
+		case errors.IsConflict(err):
+			warnings = append(warnings, fmt.Sprintf("template[%d]: applying %s %s as field manager %s conflicts with another field manager, so the apply will fail until the conflict is resolved: %v",
+				target.idx, obj.GroupVersionKind().Kind, obj.GetName(), target.fieldManager, err))
+		default:
+			log.V(1).Info("Dry-run apply failed", "index", target.idx, "gvk", obj.GroupVersionKind().String(), "name", obj.GetName(), "reason", err.Error())
+		}
+	}
+	return warnings
+}
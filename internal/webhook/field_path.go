@@ -0,0 +1,163 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+)
+
+// fieldPathKey is one step of a field path: a map field, or a list selector written as [index] or [*]
+type fieldPathKey struct {
+	name     string
+	selector bool
+}
+
+// fieldMatch is a field selected by a field path, at its concrete path with every [*] replaced
+// by an index. A field that doesn't exist is a match that isn't found.
+type fieldMatch struct {
+	path  string
+	value interface{}
+	found bool
+}
+
+// parseFieldPath splits a field path into its keys. Fields are separated by dots, and list
+// elements are selected by [index] or, every element, by [*], as in spec.containers[*].image.
+// A numeric field such as spec.containers.0.image also selects a list element.
+func parseFieldPath(fieldPath string) ([]fieldPathKey, error) {
+	var keys []fieldPathKey
+	for _, segment := range strings.Split(fieldPath, ".") {
+		name, selectors := segment, ""
+		if i := strings.IndexByte(segment, '['); i >= 0 {
+			name, selectors = segment[:i], segment[i:]
+		}
+		if name == "" {
+			return nil, fmt.Errorf("field path %s has an empty field name", fieldPath)
+		}
+		keys = append(keys, fieldPathKey{name: name})
+
+		for selectors != "" {
+			end := strings.IndexByte(selectors, ']')
+			if selectors[0] != '[' || end < 0 {
+				return nil, fmt.Errorf("field path %s has a malformed list selector in %s", fieldPath, segment)
+			}
+			selector := selectors[1:end]
+			if selector != "*" {
+				if index, err := strconv.Atoi(selector); err != nil || index < 0 {
+					return nil, fmt.Errorf("field path %s selects list elements with [%s], use an index or *", fieldPath, selector)
+				}
+			}
+			keys = append(keys, fieldPathKey{name: selector, selector: true})
+			selectors = selectors[end+1:]
+		}
+	}
+	return keys, nil
+}
+
+// checkFieldPaths checks the syntax of the field paths of validation
+func checkFieldPaths(validation kubetemplateriov1alpha1.FieldValidation) error {
+	for _, fieldPath := range []string{validation.WhenFieldPath, validation.RequiredFieldPath} {
+		if fieldPath == "" {
+			continue
+		}
+		if _, err := parseFieldPath(fieldPath); err != nil {
+			return err
+		}
+	}
+	if celVariableFor(validation.FieldPath) == "object" {
+		return nil
+	}
+	_, err := parseFieldPath(validation.FieldPath)
+	return err
+}
+
+// resolveFieldPath returns the fields of obj that fieldPath selects. A path without [*]
+// selects one field; [*] selects the field of every element of the list, so an empty list
+// selects none. A missing field, or an index past the end of a list, is a match that isn't
+// found.
+func resolveFieldPath(obj map[string]interface{}, fieldPath string) ([]fieldMatch, error) {
+	keys, err := parseFieldPath(fieldPath)
+	if err != nil {
+		return nil, err
+	}
+	var matches []fieldMatch
+	if err := collectFieldMatches(obj, keys, "", &matches); err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+// collectFieldMatches appends the fields keys select in value, which is at path, to matches
+func collectFieldMatches(value interface{}, keys []fieldPathKey, path string, matches *[]fieldMatch) error {
+	if len(keys) == 0 {
+		*matches = append(*matches, fieldMatch{path: path, value: value, found: true})
+		return nil
+	}
+	if value == nil {
+		*matches = append(*matches, fieldMatch{path: appendFieldPathKeys(path, keys)})
+		return nil
+	}
+
+	key := keys[0]
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if key.selector {
+			return fmt.Errorf("%s is a map, not a list", path)
+		}
+		child, ok := v[key.name]
+		if !ok {
+			*matches = append(*matches, fieldMatch{path: appendFieldPathKeys(path, keys)})
+			return nil
+		}
+		return collectFieldMatches(child, keys[1:], joinFieldPath(path, key.name), matches)
+	case []interface{}:
+		if key.name == "*" {
+			for i, item := range v {
+				if err := collectFieldMatches(item, keys[1:], fmt.Sprintf("%s[%d]", path, i), matches); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		index, err := strconv.Atoi(key.name)
+		if err != nil {
+			return fmt.Errorf("%s is a list, select its elements with [index] or [*]", path)
+		}
+		if index < 0 || index >= len(v) {
+			*matches = append(*matches, fieldMatch{path: appendFieldPathKeys(path, keys)})
+			return nil
+		}
+		return collectFieldMatches(v[index], keys[1:], fmt.Sprintf("%s[%d]", path, index), matches)
+	default:
+		return fmt.Errorf("%s is of the type %T, not a map or a list", path, value)
+	}
+}
+
+// appendFieldPathKeys appends keys to path in field path notation
+func appendFieldPathKeys(path string, keys []fieldPathKey) string {
+	for _, key := range keys {
+		if key.selector {
+			path += "[" + key.name + "]"
+		} else {
+			path = joinFieldPath(path, key.name)
+		}
+	}
+	return path
+}
@@ -0,0 +1,35 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	"github.com/lpeano/KubeTemplater/internal/policy"
+)
+
+// validateImagePolicy checks all container images of the object against the image policy
+func validateImagePolicy(imagePolicy *kubetemplateriov1alpha1.ImagePolicy, obj *unstructured.Unstructured, templateIdx int) []error {
+	var errs []error
+	for _, violation := range policy.ImageViolations(imagePolicy, obj) {
+		errs = append(errs, fmt.Errorf("template[%d]: imagePolicy: %s", templateIdx, violation))
+	}
+	return errs
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	"github.com/lpeano/KubeTemplater/internal/cache"
+	"github.com/lpeano/KubeTemplater/internal/policy"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// +kubebuilder:webhook:path=/mutate-kubetemplater-io-v1alpha1-kubetemplate,mutating=true,failurePolicy=ignore,sideEffects=None,groups=kubetemplater.io,resources=kubetemplates,verbs=create;update,versions=v1alpha1,name=mkubetemplate.kb.io,admissionReviewVersions=v1
+
+// KubeTemplateDefaulter records on each KubeTemplate the version of the policy it is admitted
+// against, so that the worker can tell when the policy changed before the template was applied
+type KubeTemplateDefaulter struct {
+	OperatorNamespace string
+	Cache             *cache.PolicyCache
+}
+
+var _ webhook.CustomDefaulter = &KubeTemplateDefaulter{}
+
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the type
+func (d *KubeTemplateDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	kubeTemplate, ok := obj.(*kubetemplateriov1alpha1.KubeTemplate)
+	if !ok {
+		return fmt.Errorf("expected a KubeTemplate but got a %T", obj)
+	}
+
+	matchedPolicy, err := d.Cache.Get(ctx, kubeTemplate.Namespace, d.OperatorNamespace)
+	if err != nil {
+		// The validating webhook reports the missing policy; don't keep a stale version
+		delete(kubeTemplate.Annotations, policy.AdmittedVersionAnnotation)
+		return nil
+	}
+
+	if kubeTemplate.Annotations == nil {
+		kubeTemplate.Annotations = make(map[string]string)
+	}
+	kubeTemplate.Annotations[policy.AdmittedVersionAnnotation] = matchedPolicy.ResourceVersion
+	logf.FromContext(ctx).V(1).Info("Recorded admitted policy version",
+		"name", kubeTemplate.Name, "policy", matchedPolicy.Name, "resourceVersion", matchedPolicy.ResourceVersion)
+	return nil
+}
+
+// SetupWebhookWithManager registers the webhook with the manager
+func (d *KubeTemplateDefaulter) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&kubetemplateriov1alpha1.KubeTemplate{}).
+		WithDefaulter(d).
+		Complete()
+}
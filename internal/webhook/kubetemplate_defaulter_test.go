@@ -0,0 +1,84 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	"github.com/lpeano/KubeTemplater/internal/cache"
+	"github.com/lpeano/KubeTemplater/internal/policy"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("KubeTemplate Defaulter", func() {
+	var (
+		ctx        context.Context
+		fakeClient client.Client
+		defaulter  *KubeTemplateDefaulter
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		scheme := runtime.NewScheme()
+		Expect(kubetemplateriov1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithIndex(&kubetemplateriov1alpha1.KubeTemplatePolicy{}, "spec.sourceNamespace", func(obj client.Object) []string {
+				return []string{obj.(*kubetemplateriov1alpha1.KubeTemplatePolicy).Spec.SourceNamespace}
+			}).
+			Build()
+
+		defaulter = &KubeTemplateDefaulter{
+			OperatorNamespace: "kubetemplater-system",
+			Cache:             cache.NewPolicyCache(fakeClient, 0),
+		}
+	})
+
+	It("should record the version of the policy the template is admitted against", func() {
+		kubePolicy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: "kubetemplater-system"},
+			Spec:       kubetemplateriov1alpha1.KubeTemplatePolicySpec{SourceNamespace: "default"},
+		}
+		Expect(fakeClient.Create(ctx, kubePolicy)).To(Succeed())
+
+		kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default"},
+		}
+		Expect(defaulter.Default(ctx, kubeTemplate)).To(Succeed())
+		Expect(kubeTemplate.Annotations).To(HaveKeyWithValue(policy.AdmittedVersionAnnotation, kubePolicy.ResourceVersion))
+	})
+
+	It("should drop a stale version when no policy covers the namespace", func() {
+		kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "test-template",
+				Namespace:   "default",
+				Annotations: map[string]string{policy.AdmittedVersionAnnotation: "42"},
+			},
+		}
+		Expect(defaulter.Default(ctx, kubeTemplate)).To(Succeed())
+		Expect(kubeTemplate.Annotations).NotTo(HaveKey(policy.AdmittedVersionAnnotation))
+	})
+})
@@ -18,17 +18,31 @@ package webhook
 
 import (
 	"context"
+	goerrors "errors"
 	"fmt"
+	"maps"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	exprpb "google.golang.org/genproto/googleapis/api/expr/v1alpha1"
 
 	"github.com/google/cel-go/cel"
 	"github.com/google/cel-go/checker/decls"
 	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	"github.com/lpeano/KubeTemplater/internal/audit"
 	"github.com/lpeano/KubeTemplater/internal/cache"
+	"github.com/lpeano/KubeTemplater/internal/celcache"
+	"github.com/lpeano/KubeTemplater/internal/celquery"
+	"github.com/lpeano/KubeTemplater/internal/maintenance"
+	"github.com/lpeano/KubeTemplater/internal/manifest"
+	"github.com/lpeano/KubeTemplater/internal/metrics"
+	"github.com/lpeano/KubeTemplater/internal/pause"
+	"github.com/lpeano/KubeTemplater/internal/policy"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -36,7 +50,6 @@ import (
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
-	"sigs.k8s.io/yaml"
 )
 
 const (
@@ -46,6 +59,11 @@ const (
 	maxTemplateSizeBytes = 1 * 1024 * 1024
 	// CELEvaluationTimeout is the maximum time allowed for CEL evaluation
 	celEvaluationTimeout = 100 * time.Millisecond
+	// MinApplyPriority and MaxApplyPriority bound spec.applyPriority
+	minApplyPriority = -1000
+	maxApplyPriority = 1000
+	// MaxReadyTimeout bounds waitForReady.timeout
+	maxReadyTimeout = time.Hour
 )
 
 // +kubebuilder:webhook:path=/validate-kubetemplater-io-v1alpha1-kubetemplate,mutating=false,failurePolicy=fail,sideEffects=None,groups=kubetemplater.io,resources=kubetemplates,verbs=create;update,versions=v1alpha1,name=vkubetemplate.kb.io,admissionReviewVersions=v1
@@ -55,7 +73,38 @@ type KubeTemplateValidator struct {
 	Client            client.Client
 	OperatorNamespace string
 	Cache             *cache.PolicyCache
-	regexCache        map[string]*regexp.Regexp
+	// AllowKubeTemplaterResources lets templates create kubetemplater.io resources
+	// (KubeTemplates, KubeTemplatePolicies). Disabled by default to prevent recursion.
+	AllowKubeTemplaterResources bool
+	// SchemaValidator checks templates against the cluster's OpenAPI schema (nil = disabled)
+	SchemaValidator *SchemaValidator
+	// SSAAdvisories warns about list fields server-side apply replaces as a whole or can't merge
+	// by key. Requires SchemaValidator; advisory only, see SchemaValidator.SSAAdvisories.
+	SSAAdvisories bool
+	// RequiredNamespaceLabel is a label ("key" or "key=value") target namespaces must carry,
+	// e.g. the one the operator's namespace watch selects on (empty = no check)
+	RequiredNamespaceLabel string
+	// RejectUnlabeledNamespaces rejects templates targeting namespaces without the required
+	// label instead of only warning
+	RejectUnlabeledNamespaces bool
+	// Audit records every admission decision (nil = disabled)
+	Audit *audit.Logger
+	// UnknownValidationTypes decides how field validations of an unknown type are handled
+	// (empty = warn)
+	UnknownValidationTypes UnknownValidationTypePolicy
+	// ResourceCounter answers countResources calls of object-level rules (nil = every call fails)
+	ResourceCounter *celquery.Counter
+	// FieldManager is the operator's server-side apply field manager, which dry-run applies of
+	// policies with dryRunOnAdmission use unless the policy or template overrides it
+	// (empty = manifest.DefaultFieldManager)
+	FieldManager string
+	// CheckClusterCapacity warns about workloads requesting more CPU or memory than the
+	// schedulable nodes can allocate. Advisory only, see checkClusterCapacity.
+	CheckClusterCapacity bool
+	// Now returns the current time for maintenance window checks (nil = time.Now)
+	Now         func() time.Time
+	regexCache  map[string]*regexp.Regexp
+	celPrograms celcache.Cache
 }
 
 var _ webhook.CustomValidator = &KubeTemplateValidator{}
@@ -70,7 +119,9 @@ func (v *KubeTemplateValidator) ValidateCreate(ctx context.Context, obj runtime.
 	log := logf.FromContext(ctx)
 	log.Info("Validating KubeTemplate", "name", kubeTemplate.Name, "namespace", kubeTemplate.Namespace)
 
-	return v.validateKubeTemplate(ctx, kubeTemplate)
+	warnings, err := v.validateKubeTemplate(ctx, kubeTemplate)
+	v.auditAdmission(ctx, kubeTemplate, err)
+	return warnings, err
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
@@ -83,7 +134,43 @@ func (v *KubeTemplateValidator) ValidateUpdate(ctx context.Context, oldObj, newO
 	log := logf.FromContext(ctx)
 	log.Info("Validating KubeTemplate update", "name", kubeTemplate.Name, "namespace", kubeTemplate.Namespace)
 
-	return v.validateKubeTemplate(ctx, kubeTemplate)
+	// Pausing must work during an incident, even when the policy no longer admits the template
+	if oldTemplate, ok := oldObj.(*kubetemplateriov1alpha1.KubeTemplate); ok && onlyTogglesPause(oldTemplate, kubeTemplate) {
+		log.Info("Allowing KubeTemplate update that only pauses or resumes it", "name", kubeTemplate.Name, "namespace", kubeTemplate.Namespace)
+		v.auditAdmission(ctx, kubeTemplate, nil)
+		return nil, nil
+	}
+
+	warnings, err := v.validateKubeTemplate(ctx, kubeTemplate)
+	v.auditAdmission(ctx, kubeTemplate, err)
+	return warnings, err
+}
+
+// onlyTogglesPause reports whether an update changes nothing but the pause and resume
+// annotations. The admitted policy version is ignored too, since the defaulter sets it on
+// every update.
+func onlyTogglesPause(oldTemplate, newTemplate *kubetemplateriov1alpha1.KubeTemplate) bool {
+	if !apiequality.Semantic.DeepEqual(oldTemplate.Spec, newTemplate.Spec) ||
+		!apiequality.Semantic.DeepEqual(oldTemplate.Labels, newTemplate.Labels) {
+		return false
+	}
+
+	toggled := false
+	for _, key := range []string{pause.Annotation, pause.ResumeAnnotation} {
+		if oldTemplate.Annotations[key] != newTemplate.Annotations[key] {
+			toggled = true
+		}
+	}
+	return toggled && maps.Equal(withoutPauseAnnotations(oldTemplate.Annotations), withoutPauseAnnotations(newTemplate.Annotations))
+}
+
+// withoutPauseAnnotations copies annotations without the ones onlyTogglesPause ignores
+func withoutPauseAnnotations(annotations map[string]string) map[string]string {
+	others := maps.Clone(annotations)
+	delete(others, pause.Annotation)
+	delete(others, pause.ResumeAnnotation)
+	delete(others, policy.AdmittedVersionAnnotation)
+	return others
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
@@ -92,8 +179,58 @@ func (v *KubeTemplateValidator) ValidateDelete(ctx context.Context, obj runtime.
 	return nil, nil
 }
 
-// validateKubeTemplate contains the core validation logic
-func (v *KubeTemplateValidator) validateKubeTemplate(ctx context.Context, kubeTemplate *kubetemplateriov1alpha1.KubeTemplate) (admission.Warnings, error) {
+// auditAdmission records the admission decision for every resource of the KubeTemplate. A
+// denial applies to the whole KubeTemplate, so every resource is recorded as denied with the
+// reason; resources that can't be decoded are recorded without their identity.
+func (v *KubeTemplateValidator) auditAdmission(ctx context.Context, kubeTemplate *kubetemplateriov1alpha1.KubeTemplate, validationErr error) {
+	if v.Audit == nil {
+		return
+	}
+
+	decision, reason := audit.DecisionAdmitted, ""
+	if validationErr != nil {
+		decision, reason = audit.DecisionDenied, validationErr.Error()
+	}
+	var user string
+	if req, err := admission.RequestFromContext(ctx); err == nil {
+		user = req.UserInfo.Username
+	}
+	dryRun := isDryRun(ctx)
+
+	for _, template := range kubeTemplate.Spec.Templates {
+		var resource *unstructured.Unstructured
+		if obj, err := manifest.DecodeWithParameters(template.Object.Raw, kubeTemplate.Spec.Parameters); err == nil {
+			if obj.GetNamespace() == "" {
+				obj.SetNamespace(kubeTemplate.Namespace)
+			}
+			resource = &obj
+		}
+		record := audit.NewRecord(audit.SourceWebhook, kubeTemplate, resource, decision)
+		record.User = user
+		record.Reason = reason
+		record.DryRun = dryRun
+		v.Audit.Record(record)
+	}
+	if len(kubeTemplate.Spec.Templates) == 0 {
+		record := audit.NewRecord(audit.SourceWebhook, kubeTemplate, nil, decision)
+		record.User = user
+		record.Reason = reason
+		record.DryRun = dryRun
+		v.Audit.Record(record)
+	}
+}
+
+// isDryRun reports whether the admission request in ctx is a dry run (kubectl --dry-run=server),
+// whose object the API server won't store
+func isDryRun(ctx context.Context) bool {
+	req, err := admission.RequestFromContext(ctx)
+	return err == nil && req.DryRun != nil && *req.DryRun
+}
+
+// validateKubeTemplate contains the core validation logic. The webhook is registered with
+// sideEffects=None, so validation must never change the cluster; a check that would have to,
+// such as creating a missing namespace, may only run when isDryRun is false.
+func (v *KubeTemplateValidator) validateKubeTemplate(ctx context.Context, kubeTemplate *kubetemplateriov1alpha1.KubeTemplate) (warnings admission.Warnings, err error) {
 	log := logf.FromContext(ctx)
 
 	// Use policy cache for fast lookup (95% API call reduction!)
@@ -104,25 +241,106 @@ func (v *KubeTemplateValidator) validateKubeTemplate(ctx context.Context, kubeTe
 	}
 
 	log.Info("Found matching policy", "policy", matchedPolicy.Name, "sourceNamespace", matchedPolicy.Spec.SourceNamespace)
+	defer func() {
+		// Recorded for each policy a merged policy was built from, whose series are freed when it is deleted
+		for _, name := range policy.MergedNames(matchedPolicy) {
+			metrics.ObservePolicyValidation(name, metrics.PolicySourceWebhook, err == nil)
+		}
+	}()
+
+	return v.ValidateAgainstPolicy(ctx, kubeTemplate, matchedPolicy)
+}
+
+// ValidateAgainstPolicy checks kubeTemplate against matchedPolicy, the policy of its namespace,
+// and returns the admission warnings and the reason to reject it, if any. Without a Client it
+// checks a template offline, e.g. in CI: it then matches target namespaces by name only, warning
+// about those a targetNamespaceSelector would have to decide, skips rules calling countResources
+// with a warning unless a ResourceCounter is set, and skips the required namespace label,
+// dry-run apply and cluster capacity checks, which need the cluster.
+func (v *KubeTemplateValidator) ValidateAgainstPolicy(ctx context.Context, kubeTemplate *kubetemplateriov1alpha1.KubeTemplate, matchedPolicy *kubetemplateriov1alpha1.KubeTemplatePolicy) (admission.Warnings, error) {
+	log := logf.FromContext(ctx)
 
 	var warnings admission.Warnings
+	if isDryRun(ctx) {
+		warnings = append(warnings, fmt.Sprintf("dry run: validated against policy %s; the KubeTemplate was not stored and none of its resources will be applied", matchedPolicy.Name))
+	}
 
-	// Validate template count limit
-	if len(kubeTemplate.Spec.Templates) > maxTemplatesPerKubeTemplate {
-		return warnings, fmt.Errorf("too many templates: %d (max allowed: %d)", len(kubeTemplate.Spec.Templates), maxTemplatesPerKubeTemplate)
+	if len(matchedPolicy.Spec.MaintenanceWindows) > 0 {
+		now := time.Now()
+		if v.Now != nil {
+			now = v.Now()
+		}
+		open, next, err := maintenance.Check(matchedPolicy.Spec.MaintenanceWindows, now)
+		switch {
+		case err != nil:
+			warnings = append(warnings, fmt.Sprintf("policy %s has an invalid maintenance window, changes will not be applied: %v", matchedPolicy.Name, err))
+		case !open:
+			warnings = append(warnings, fmt.Sprintf("policy %s is outside its maintenance windows, changes will not take effect until the next window opens at %s", matchedPolicy.Name, next.UTC().Format(time.RFC3339)))
+		}
 	}
 
+	for _, key := range matchedPolicy.Spec.PropagateLabels {
+		if _, found := kubeTemplate.Labels[key]; !found {
+			warnings = append(warnings, fmt.Sprintf("policy %s propagates label %s to the resources of the KubeTemplate, which doesn't set it", matchedPolicy.Name, key))
+		}
+	}
+
+	// Field validation failures are collected across templates so that a single
+	// admission response reports every failing template, not just the first one
+	var fieldFailures templateValidationErrors
+
+	if p := kubeTemplate.Spec.ApplyPriority; p < minApplyPriority || p > maxApplyPriority {
+		return warnings, fmt.Errorf("applyPriority %d is out of range, it must be between %d and %d", p, minApplyPriority, maxApplyPriority)
+	}
+
+	// Validate template count limit, which the policy may lower
+	maxTemplates := maxTemplatesPerKubeTemplate
+	if budget := int(matchedPolicy.Spec.MaxTemplates); budget > 0 && budget < maxTemplates {
+		maxTemplates = budget
+	}
+	if len(kubeTemplate.Spec.Templates) > maxTemplates {
+		return warnings, fmt.Errorf("too many templates: attempted %d, but only %d are allowed", len(kubeTemplate.Spec.Templates), maxTemplates)
+	}
+	if matchedPolicy.Spec.MaxTemplates > 0 {
+		warnings = append(warnings, fmt.Sprintf("using %d of %d allowed templates (policy %s)", len(kubeTemplate.Spec.Templates), maxTemplates, matchedPolicy.Name))
+	}
+
+	// Target namespaces whose required label was checked already
+	checkedNamespaces := make(map[string]bool)
+	// Objects to dry-run apply and workloads to check against cluster capacity once every
+	// template has passed validation
+	var dryRunTargets []dryRunTarget
+	var workloads []*workloadRequests
+
 	// Validate each template in the KubeTemplate
 	for idx, template := range kubeTemplate.Spec.Templates {
 		// Validate template size
 		if len(template.Object.Raw) > maxTemplateSizeBytes {
 			return warnings, fmt.Errorf("template[%d]: size %d bytes exceeds maximum allowed size of %d bytes", idx, len(template.Object.Raw), maxTemplateSizeBytes)
 		}
-		// Unmarshal the template object
-		var obj unstructured.Unstructured
-		if err := yaml.Unmarshal(template.Object.Raw, &obj); err != nil {
+		// Resolve parameter placeholders, then unmarshal the template object
+		raw, err := manifest.ResolveParameters(template.Object.Raw, kubeTemplate.Spec.Parameters)
+		if err != nil {
+			return warnings, fmt.Errorf("template[%d]: %w", idx, err)
+		}
+		obj, err := manifest.Decode(raw)
+		if err != nil {
 			return warnings, fmt.Errorf("template[%d]: failed to unmarshal object: %w", idx, err)
 		}
+		if err := manifest.ValidateName(&obj); err != nil {
+			return warnings, fmt.Errorf("template[%d]: %s: %w", idx, obj.GroupVersionKind().Kind, err)
+		}
+		if err := manifest.ValidateFieldManager(template); err != nil {
+			return warnings, fmt.Errorf("template[%d]: %w", idx, err)
+		}
+		if template.WaitForReady != nil {
+			if err := checkReadyCondition(template.WaitForReady.Condition); err != nil {
+				return warnings, fmt.Errorf("template[%d]: waitForReady.condition: %w", idx, err)
+			}
+			if timeout := template.WaitForReady.Timeout; timeout != nil && (timeout.Duration <= 0 || timeout.Duration > maxReadyTimeout) {
+				return warnings, fmt.Errorf("template[%d]: waitForReady.timeout %s is out of range, it must be greater than 0s and at most %s", idx, timeout.Duration, maxReadyTimeout)
+			}
+		}
 
 		// Set default namespace if not specified
 		if obj.GetNamespace() == "" {
@@ -132,42 +350,125 @@ func (v *KubeTemplateValidator) validateKubeTemplate(ctx context.Context, kubeTe
 		gvk := obj.GroupVersionKind()
 		log.Info("Validating template", "index", idx, "gvk", gvk.String(), "name", obj.GetName(), "namespace", obj.GetNamespace())
 
-		// Find the matching validation rule for this resource type
-		var matchedRule *kubetemplateriov1alpha1.ValidationRule
-		for i := range matchedPolicy.Spec.ValidationRules {
-			rule := &matchedPolicy.Spec.ValidationRules[i]
-			if rule.Kind == gvk.Kind && rule.Group == gvk.Group && rule.Version == gvk.Version {
-				matchedRule = rule
-				break
-			}
+		// Safety backstop: templates must not create KubeTemplater resources (recursion, privilege escalation)
+		if gvk.Group == kubetemplateriov1alpha1.GroupVersion.Group && !v.AllowKubeTemplaterResources {
+			return warnings, fmt.Errorf("template[%d]: resource type %s belongs to the %s API group and cannot be created from a template", idx, gvk.String(), gvk.Group)
 		}
 
+		// Find the matching validation rule for this resource type
+		matchedRule := policy.FindRule(matchedPolicy, gvk)
+
 		// Check if the resource type is allowed
 		if matchedRule == nil {
 			return warnings, fmt.Errorf("template[%d]: resource type %s is not allowed by policy %s", idx, gvk.String(), matchedPolicy.Name)
 		}
+		if matchedRule.Group != gvk.Group {
+			warnings = append(warnings, fmt.Sprintf("template[%d]: policy %s has a rule for %s with group %q, which was treated as the core group; use group: \"\" for core resources", idx, matchedPolicy.Name, gvk.Kind, matchedRule.Group))
+		}
 
 		// Check if target namespaces are defined
-		if len(matchedRule.TargetNamespaces) == 0 {
-			return warnings, fmt.Errorf("template[%d]: resource type %s has no target namespaces defined in policy %s. At least one target namespace must be specified", idx, gvk.String(), matchedPolicy.Name)
+		if !policy.HasTargetNamespaces(matchedRule) {
+			return warnings, fmt.Errorf("template[%d]: resource type %s has no target namespaces defined in policy %s. At least one target namespace or a targetNamespaceSelector must be specified", idx, gvk.String(), matchedPolicy.Name)
 		}
 
-		// Check if the resource's namespace is in the allowed target namespaces
-		if !contains(matchedRule.TargetNamespaces, obj.GetNamespace()) {
-			return warnings, fmt.Errorf("template[%d]: resource namespace %s is not in the allowed target namespaces %v for resource type %s", idx, obj.GetNamespace(), matchedRule.TargetNamespaces, gvk.String())
+		// Check if the resource's namespace is in the allowed target namespaces or matches the selector
+		var reader client.Reader
+		if v.Client != nil {
+			reader = v.Client
+		}
+		namespaceAllowed, err := policy.NamespaceAllowed(ctx, reader, matchedRule, obj.GetNamespace())
+		switch {
+		case goerrors.Is(err, policy.ErrNoNamespaceReader):
+			warnings = append(warnings, fmt.Sprintf("template[%d]: target namespace %s could not be matched against the targetNamespaceSelector of policy %s without a cluster", idx, obj.GetNamespace(), matchedPolicy.Name))
+		case errors.IsNotFound(err):
+			// The namespace may still be created before the worker applies the template, which checks again then
+			warnings = append(warnings, fmt.Sprintf("template[%d]: target namespace %s does not exist yet, so it could not be matched against the targetNamespaceSelector of policy %s", idx, obj.GetNamespace(), matchedPolicy.Name))
+		case err != nil:
+			return warnings, fmt.Errorf("template[%d]: %w", idx, err)
+		case !namespaceAllowed:
+			return warnings, fmt.Errorf("template[%d]: resource namespace %s is not in the allowed target namespaces %s for resource type %s", idx, obj.GetNamespace(), policy.DescribeTargetNamespaces(matchedRule), gvk.String())
+		}
+
+		// Check that the target namespace is labeled for the operator
+		if v.RequiredNamespaceLabel != "" && v.Client != nil && !checkedNamespaces[obj.GetNamespace()] {
+			checkedNamespaces[obj.GetNamespace()] = true
+			problem, err := v.missingNamespaceLabel(ctx, obj.GetNamespace())
+			switch {
+			case err != nil:
+				log.Error(err, "Skipping required namespace label check")
+				warnings = append(warnings, fmt.Sprintf("template[%d]: required namespace label check skipped: %v", idx, err))
+			case problem != "" && v.RejectUnlabeledNamespaces:
+				return warnings, fmt.Errorf("template[%d]: %s", idx, problem)
+			case problem != "":
+				warnings = append(warnings, fmt.Sprintf("template[%d]: %s, the operator may not manage resources in it", idx, problem))
+			}
+		}
+
+		// KubeTemplater manages spec, not status; a status usually comes from a dumped object
+		if manifest.HasStatus(&obj) && !template.ApplyStatus {
+			if matchedPolicy.Spec.RejectTemplateStatus {
+				fieldFailures.add(idx, &obj, fmt.Errorf("template[%d]: %s %s sets status, which policy %s does not allow; remove it or set applyStatus: true", idx, gvk.Kind, obj.GetName(), matchedPolicy.Name))
+			} else {
+				warnings = append(warnings, fmt.Sprintf("template[%d]: %s %s sets status, which will be dropped; set applyStatus: true to apply it", idx, gvk.Kind, obj.GetName()))
+			}
+			// The object is validated as it will be applied
+			manifest.StripStatus(&obj)
+		}
+
+		// Server-managed metadata pasted from a live object breaks server-side apply
+		if fields := manifest.ServerManagedMetadata(&obj); len(fields) > 0 {
+			if matchedPolicy.Spec.RejectServerManagedMetadata {
+				fieldFailures.add(idx, &obj, fmt.Errorf("template[%d]: %s %s sets server-managed metadata %s, which policy %s does not allow; remove it", idx, gvk.Kind, obj.GetName(), strings.Join(fields, ", "), matchedPolicy.Name))
+			} else {
+				warnings = append(warnings, fmt.Sprintf("template[%d]: %s %s sets server-managed metadata %s, which will be dropped", idx, gvk.Kind, obj.GetName(), strings.Join(fields, ", ")))
+			}
+			manifest.StripServerManagedMetadata(&obj)
+		}
+
+		// Validate structure against the cluster's OpenAPI schema if enabled
+		if v.SchemaValidator != nil {
+			schemaErrs, err := v.SchemaValidator.Validate(&obj)
+			if err != nil {
+				log.Error(err, "Skipping OpenAPI schema validation")
+				warnings = append(warnings, fmt.Sprintf("template[%d]: OpenAPI schema validation skipped: %v", idx, err))
+			}
+			for _, schemaErr := range schemaErrs {
+				fieldFailures.add(idx, &obj, fmt.Errorf("template[%d]: schema: %w", idx, schemaErr))
+			}
+
+			if v.SSAAdvisories && err == nil {
+				advisories, _ := v.SchemaValidator.SSAAdvisories(&obj)
+				for _, advisory := range advisories {
+					warnings = append(warnings, fmt.Sprintf("template[%d]: %s %s: %s", idx, gvk.Kind, obj.GetName(), advisory))
+				}
+			}
 		}
 
 		// Validate legacy CEL rule if present (backward compatibility)
-		if matchedRule.Rule != "" {
+		if matchedRule.Rule != "" && v.countsWithoutCluster(matchedRule.Rule, "object") {
+			warnings = append(warnings, fmt.Sprintf("template[%d]: the rule of policy %s for %s calls %s, which needs a cluster, and was not checked", idx, matchedPolicy.Name, gvk.String(), celquery.CountResourcesFunction))
+		} else if matchedRule.Rule != "" {
 			if err := v.validateCELRule(matchedRule.Rule, &obj, idx, ""); err != nil {
-				return warnings, err
+				if policy.IsRuleError(err) {
+					log.Error(err, "Policy has a broken CEL rule", "policy", matchedPolicy.Name, "gvk", gvk.String())
+				}
+				fieldFailures.add(idx, &obj, err)
 			}
 		}
 
 		// Validate field validations if present
 		if len(matchedRule.FieldValidations) > 0 {
-			if err := v.validateFieldValidations(ctx, matchedRule.FieldValidations, &obj, idx); err != nil {
-				return warnings, err
+			validationWarnings, errs := v.validateFieldValidations(ctx, matchedRule.FieldValidations, &obj, idx)
+			warnings = append(warnings, validationWarnings...)
+			for _, err := range errs {
+				fieldFailures.add(idx, &obj, err)
+			}
+		}
+
+		// Validate container images if an image policy is set
+		if matchedRule.ImagePolicy != nil {
+			for _, err := range validateImagePolicy(matchedRule.ImagePolicy, &obj, idx) {
+				fieldFailures.add(idx, &obj, err)
 			}
 		}
 
@@ -175,15 +476,36 @@ func (v *KubeTemplateValidator) validateKubeTemplate(ctx context.Context, kubeTe
 		if template.Replace {
 			warnings = append(warnings, fmt.Sprintf("template[%d]: replace is enabled for %s/%s. The resource will be deleted and recreated if immutable fields are changed", idx, gvk.String(), obj.GetName()))
 		}
+
+		if matchedPolicy.Spec.DryRunOnAdmission && v.Client != nil {
+			dryRunTargets = append(dryRunTargets, dryRunTarget{idx: idx, obj: obj.DeepCopy(), fieldManager: manifest.FieldManager(template, matchedPolicy, v.FieldManager)})
+		}
+		if v.CheckClusterCapacity && v.Client != nil {
+			if requests := requestsOf(idx, &obj); requests != nil {
+				workloads = append(workloads, requests)
+			}
+		}
+	}
+
+	if err := fieldFailures.err(); err != nil {
+		return warnings, err
 	}
 
+	// SSA conflicts only warn: the other field manager may give the fields up before the apply
+	warnings = append(warnings, v.dryRunApplyConflicts(ctx, dryRunTargets)...)
+	warnings = append(warnings, v.checkClusterCapacity(ctx, workloads)...)
+
 	log.Info("KubeTemplate validation successful", "name", kubeTemplate.Name, "namespace", kubeTemplate.Namespace, "templatesCount", len(kubeTemplate.Spec.Templates))
 	return warnings, nil
 }
 
-// validateFieldValidations validates all field validations for a resource
-func (v *KubeTemplateValidator) validateFieldValidations(ctx context.Context, validations []kubetemplateriov1alpha1.FieldValidation, obj *unstructured.Unstructured, templateIdx int) error {
+// validateFieldValidations validates all field validations for a resource and returns the
+// failures of every one that fails. Validations of an unknown type are handled according to
+// UnknownValidationTypes, and warn by default.
+func (v *KubeTemplateValidator) validateFieldValidations(ctx context.Context, validations []kubetemplateriov1alpha1.FieldValidation, obj *unstructured.Unstructured, templateIdx int) (admission.Warnings, []error) {
 	log := logf.FromContext(ctx)
+	var warnings admission.Warnings
+	var errs []error
 
 	for validationIdx, validation := range validations {
 		log.Info("Validating field", "validation", validation.Name, "type", validation.Type, "fieldPath", validation.FieldPath)
@@ -191,6 +513,10 @@ func (v *KubeTemplateValidator) validateFieldValidations(ctx context.Context, va
 		var err error
 		switch validation.Type {
 		case kubetemplateriov1alpha1.FieldValidationTypeCEL:
+			if v.countsWithoutCluster(validation.CEL, celVariableFor(validation.FieldPath)) {
+				warnings = append(warnings, fmt.Sprintf("template[%d]: fieldValidation[%d] (%s): calls %s, which needs a cluster, and was not checked", templateIdx, validationIdx, validation.Name, celquery.CountResourcesFunction))
+				continue
+			}
 			err = v.validateFieldCEL(validation, obj, templateIdx)
 		case kubetemplateriov1alpha1.FieldValidationTypeRegex:
 			err = v.validateFieldRegex(validation, obj, templateIdx)
@@ -200,16 +526,44 @@ func (v *KubeTemplateValidator) validateFieldValidations(ctx context.Context, va
 			err = v.validateFieldRequired(validation, obj, templateIdx)
 		case kubetemplateriov1alpha1.FieldValidationTypeForbidden:
 			err = v.validateFieldForbidden(validation, obj, templateIdx)
+		case kubetemplateriov1alpha1.FieldValidationTypeSecurityHardening:
+			err = v.validateFieldSecurityHardening(validation, obj, templateIdx)
+		case kubetemplateriov1alpha1.FieldValidationTypeConditionalRequired:
+			err = v.validateFieldConditionalRequired(validation, obj, templateIdx)
+		case kubetemplateriov1alpha1.FieldValidationTypeEnum:
+			err = v.validateFieldEnum(validation, obj, templateIdx)
+		case kubetemplateriov1alpha1.FieldValidationTypeLength:
+			err = v.validateFieldLength(validation, obj, templateIdx)
+		case kubetemplateriov1alpha1.FieldValidationTypeAllowedDomains:
+			err = v.validateFieldAllowedDomains(validation, obj, templateIdx)
+		case kubetemplateriov1alpha1.FieldValidationTypeContainerProbes:
+			err = v.validateFieldContainerProbes(validation, obj, templateIdx)
 		default:
-			return fmt.Errorf("template[%d]: fieldValidation[%d] (%s): unknown validation type: %s", templateIdx, validationIdx, validation.Name, validation.Type)
+			switch v.UnknownValidationTypes {
+			case UnknownValidationTypeFail:
+				errs = append(errs, fmt.Errorf("template[%d]: fieldValidation[%d] (%s): unknown validation type: %s", templateIdx, validationIdx, validation.Name, validation.Type))
+			case UnknownValidationTypeIgnore:
+				log.Info("Skipping field validation of unknown type", "validation", validation.Name, "type", validation.Type)
+			default:
+				warnings = append(warnings, fmt.Sprintf("template[%d]: fieldValidation[%d] (%s): unknown validation type %s was not checked, the policy may be written for a newer operator version", templateIdx, validationIdx, validation.Name, validation.Type))
+			}
+			continue
 		}
 
 		if err != nil {
-			return err
+			errs = append(errs, err)
 		}
 	}
 
-	return nil
+	return warnings, errs
+}
+
+// countsWithoutCluster reports whether rule, evaluated with the variable varName, counts
+// resources while the validator runs offline, without a Client or ResourceCounter, so it can't
+// be evaluated
+func (v *KubeTemplateValidator) countsWithoutCluster(rule, varName string) bool {
+	return v.Client == nil && v.ResourceCounter == nil &&
+		varName == "object" && strings.Contains(rule, celquery.CountResourcesFunction)
 }
 
 // validateFieldCEL validates a field using a CEL expression
@@ -218,35 +572,34 @@ func (v *KubeTemplateValidator) validateFieldCEL(validation kubetemplateriov1alp
 		return fmt.Errorf("template[%d]: fieldValidation (%s): CEL expression is required for type 'cel'", templateIdx, validation.Name)
 	}
 
-	// Determine the variable name and value based on fieldPath
-	var varName string
-	var varValue interface{}
-
-	if validation.FieldPath == "" || validation.FieldPath == "object" {
+	// Determine the variable name and values based on fieldPath: the whole object, or every
+	// field the path selects, each evaluated on its own
+	varName := celVariableFor(validation.FieldPath)
+	var varValues []interface{}
+	if varName == "object" {
 		// Object-level validation
-		varName = "object"
-		varValue = obj.Object
+		varValues = []interface{}{obj.Object}
 	} else {
 		// Field-level validation
-		varName = "value"
-		fieldValue, found, err := unstructured.NestedFieldCopy(obj.Object, fieldPathToKeys(validation.FieldPath)...)
+		matches, err := resolveFieldPath(obj.Object, validation.FieldPath)
 		if err != nil {
 			return fmt.Errorf("template[%d]: fieldValidation (%s): failed to get field %s: %w", templateIdx, validation.Name, validation.FieldPath, err)
 		}
-		if !found {
-			// Field doesn't exist, treat as null
-			varValue = nil
-		} else {
-			varValue = fieldValue
+		for _, match := range matches {
+			// A field that doesn't exist is null
+			varValues = append(varValues, runtime.DeepCopyJSONValue(match.value))
 		}
 	}
 
 	// Validate using CEL with custom variable name
-	if err := v.validateCELRule(validation.CEL, obj, templateIdx, validation.Name, varName, varValue); err != nil {
-		if validation.Message != "" {
-			return fmt.Errorf("template[%d]: fieldValidation (%s): %s", templateIdx, validation.Name, validation.Message)
+	for _, varValue := range varValues {
+		if err := v.validateCELRule(validation.CEL, obj, templateIdx, validation.Name, varName, varValue); err != nil {
+			// The message describes a violation, which a broken rule is not
+			if validation.Message != "" && !policy.IsRuleError(err) {
+				return fmt.Errorf("template[%d]: fieldValidation (%s): %s", templateIdx, validation.Name, validation.Message)
+			}
+			return err
 		}
-		return err
 	}
 
 	return nil
@@ -261,14 +614,11 @@ func (v *KubeTemplateValidator) validateFieldRegex(validation kubetemplateriov1a
 		return fmt.Errorf("template[%d]: fieldValidation (%s): fieldPath is required for type 'regex'", templateIdx, validation.Name)
 	}
 
-	// Get field value
-	fieldValue, found, err := unstructured.NestedString(obj.Object, fieldPathToKeys(validation.FieldPath)...)
+	// Get field values
+	matches, err := resolveFieldPath(obj.Object, validation.FieldPath)
 	if err != nil {
 		return fmt.Errorf("template[%d]: fieldValidation (%s): failed to get field %s: %w", templateIdx, validation.Name, validation.FieldPath, err)
 	}
-	if !found {
-		return fmt.Errorf("template[%d]: fieldValidation (%s): field %s not found", templateIdx, validation.Name, validation.FieldPath)
-	}
 
 	// Get or compile regex pattern (with caching)
 	if v.regexCache == nil {
@@ -284,14 +634,22 @@ func (v *KubeTemplateValidator) validateFieldRegex(validation kubetemplateriov1a
 		v.regexCache[validation.Regex] = re
 	}
 
-	// Match regex
-	matched := re.MatchString(fieldValue)
+	// Every selected field must match
+	for _, match := range matches {
+		if !match.found {
+			return fmt.Errorf("template[%d]: fieldValidation (%s): field %s not found", templateIdx, validation.Name, match.path)
+		}
+		fieldValue, ok := match.value.(string)
+		if !ok {
+			return fmt.Errorf("template[%d]: fieldValidation (%s): failed to get field %s: value is of the type %T, expected string", templateIdx, validation.Name, match.path, match.value)
+		}
 
-	if !matched {
-		if validation.Message != "" {
-			return fmt.Errorf("template[%d]: fieldValidation (%s): %s", templateIdx, validation.Name, validation.Message)
+		if !re.MatchString(fieldValue) {
+			if validation.Message != "" {
+				return fmt.Errorf("template[%d]: fieldValidation (%s): %s", templateIdx, validation.Name, validation.Message)
+			}
+			return fmt.Errorf("template[%d]: fieldValidation (%s): field %s value '%s' does not match regex pattern '%s'", templateIdx, validation.Name, match.path, fieldValue, validation.Regex)
 		}
-		return fmt.Errorf("template[%d]: fieldValidation (%s): field %s value '%s' does not match regex pattern '%s'", templateIdx, validation.Name, validation.FieldPath, fieldValue, validation.Regex)
 	}
 
 	return nil
@@ -306,27 +664,124 @@ func (v *KubeTemplateValidator) validateFieldRange(validation kubetemplateriov1a
 		return fmt.Errorf("template[%d]: fieldValidation (%s): at least one of min or max must be specified for type 'range'", templateIdx, validation.Name)
 	}
 
-	// Get field value
-	fieldValue, found, err := unstructured.NestedInt64(obj.Object, fieldPathToKeys(validation.FieldPath)...)
+	// Get field values
+	matches, err := resolveFieldPath(obj.Object, validation.FieldPath)
 	if err != nil {
 		return fmt.Errorf("template[%d]: fieldValidation (%s): failed to get field %s as int64: %w", templateIdx, validation.Name, validation.FieldPath, err)
 	}
-	if !found {
-		return fmt.Errorf("template[%d]: fieldValidation (%s): field %s not found", templateIdx, validation.Name, validation.FieldPath)
+
+	for _, match := range matches {
+		if !match.found {
+			return fmt.Errorf("template[%d]: fieldValidation (%s): field %s not found", templateIdx, validation.Name, match.path)
+		}
+		fieldValue, ok := match.value.(int64)
+		if !ok {
+			return fmt.Errorf("template[%d]: fieldValidation (%s): failed to get field %s as int64: value is of the type %T", templateIdx, validation.Name, match.path, match.value)
+		}
+
+		// Check range
+		if validation.Min != nil && fieldValue < *validation.Min {
+			if validation.Message != "" {
+				return fmt.Errorf("template[%d]: fieldValidation (%s): %s", templateIdx, validation.Name, validation.Message)
+			}
+			return fmt.Errorf("template[%d]: fieldValidation (%s): field %s value %d is less than minimum %d", templateIdx, validation.Name, match.path, fieldValue, *validation.Min)
+		}
+		if validation.Max != nil && fieldValue > *validation.Max {
+			if validation.Message != "" {
+				return fmt.Errorf("template[%d]: fieldValidation (%s): %s", templateIdx, validation.Name, validation.Message)
+			}
+			return fmt.Errorf("template[%d]: fieldValidation (%s): field %s value %d is greater than maximum %d", templateIdx, validation.Name, match.path, fieldValue, *validation.Max)
+		}
+	}
+
+	return nil
+}
+
+// validateFieldEnum validates that a string field is one of the allowed values
+func (v *KubeTemplateValidator) validateFieldEnum(validation kubetemplateriov1alpha1.FieldValidation, obj *unstructured.Unstructured, templateIdx int) error {
+	if validation.FieldPath == "" {
+		return fmt.Errorf("template[%d]: fieldValidation (%s): fieldPath is required for type 'enum'", templateIdx, validation.Name)
+	}
+	if len(validation.AllowedValues) == 0 {
+		return fmt.Errorf("template[%d]: fieldValidation (%s): allowedValues is required for type 'enum'", templateIdx, validation.Name)
 	}
 
-	// Check range
-	if validation.Min != nil && fieldValue < *validation.Min {
+	// Get field values
+	matches, err := resolveFieldPath(obj.Object, validation.FieldPath)
+	if err != nil {
+		return fmt.Errorf("template[%d]: fieldValidation (%s): failed to get field %s as string: %w", templateIdx, validation.Name, validation.FieldPath, err)
+	}
+
+	for _, match := range matches {
+		if !match.found {
+			if validation.Required {
+				return fmt.Errorf("template[%d]: fieldValidation (%s): field %s not found", templateIdx, validation.Name, match.path)
+			}
+			continue
+		}
+		fieldValue, ok := match.value.(string)
+		if !ok {
+			return fmt.Errorf("template[%d]: fieldValidation (%s): failed to get field %s as string: value is of the type %T", templateIdx, validation.Name, match.path, match.value)
+		}
+		if slices.Contains(validation.AllowedValues, fieldValue) {
+			continue
+		}
+
 		if validation.Message != "" {
 			return fmt.Errorf("template[%d]: fieldValidation (%s): %s", templateIdx, validation.Name, validation.Message)
 		}
-		return fmt.Errorf("template[%d]: fieldValidation (%s): field %s value %d is less than minimum %d", templateIdx, validation.Name, validation.FieldPath, fieldValue, *validation.Min)
+		return fmt.Errorf("template[%d]: fieldValidation (%s): field %s value '%s' is not one of %s", templateIdx, validation.Name, match.path, fieldValue, strings.Join(validation.AllowedValues, ", "))
 	}
-	if validation.Max != nil && fieldValue > *validation.Max {
-		if validation.Message != "" {
-			return fmt.Errorf("template[%d]: fieldValidation (%s): %s", templateIdx, validation.Name, validation.Message)
+
+	return nil
+}
+
+// validateFieldLength validates the length of a string field, in characters, or of an array field, in items
+func (v *KubeTemplateValidator) validateFieldLength(validation kubetemplateriov1alpha1.FieldValidation, obj *unstructured.Unstructured, templateIdx int) error {
+	if validation.FieldPath == "" {
+		return fmt.Errorf("template[%d]: fieldValidation (%s): fieldPath is required for type 'length'", templateIdx, validation.Name)
+	}
+	if validation.MinLength == nil && validation.MaxLength == nil {
+		return fmt.Errorf("template[%d]: fieldValidation (%s): at least one of minLength or maxLength must be specified for type 'length'", templateIdx, validation.Name)
+	}
+
+	matches, err := resolveFieldPath(obj.Object, validation.FieldPath)
+	if err != nil {
+		return fmt.Errorf("template[%d]: fieldValidation (%s): failed to get field %s: %w", templateIdx, validation.Name, validation.FieldPath, err)
+	}
+
+	for _, match := range matches {
+		if !match.found {
+			if validation.Required {
+				return fmt.Errorf("template[%d]: fieldValidation (%s): field %s not found", templateIdx, validation.Name, match.path)
+			}
+			continue
+		}
+
+		// Get field length, of the field as a string or as an array
+		var length int64
+		switch fieldValue := match.value.(type) {
+		case string:
+			length = int64(utf8.RuneCountInString(fieldValue))
+		case []interface{}:
+			length = int64(len(fieldValue))
+		default:
+			return fmt.Errorf("template[%d]: fieldValidation (%s): field %s is neither a string nor an array", templateIdx, validation.Name, match.path)
+		}
+
+		// Check length
+		if validation.MinLength != nil && length < *validation.MinLength {
+			if validation.Message != "" {
+				return fmt.Errorf("template[%d]: fieldValidation (%s): %s", templateIdx, validation.Name, validation.Message)
+			}
+			return fmt.Errorf("template[%d]: fieldValidation (%s): field %s length %d is less than minimum length %d", templateIdx, validation.Name, match.path, length, *validation.MinLength)
+		}
+		if validation.MaxLength != nil && length > *validation.MaxLength {
+			if validation.Message != "" {
+				return fmt.Errorf("template[%d]: fieldValidation (%s): %s", templateIdx, validation.Name, validation.Message)
+			}
+			return fmt.Errorf("template[%d]: fieldValidation (%s): field %s length %d is greater than maximum length %d", templateIdx, validation.Name, match.path, length, *validation.MaxLength)
 		}
-		return fmt.Errorf("template[%d]: fieldValidation (%s): field %s value %d is greater than maximum %d", templateIdx, validation.Name, validation.FieldPath, fieldValue, *validation.Max)
 	}
 
 	return nil
@@ -338,17 +793,56 @@ func (v *KubeTemplateValidator) validateFieldRequired(validation kubetemplaterio
 		return fmt.Errorf("template[%d]: fieldValidation (%s): fieldPath is required for type 'required'", templateIdx, validation.Name)
 	}
 
-	// Check if field exists
-	fieldValue, found, err := unstructured.NestedFieldCopy(obj.Object, fieldPathToKeys(validation.FieldPath)...)
+	// Check that every selected field exists
+	matches, err := resolveFieldPath(obj.Object, validation.FieldPath)
 	if err != nil {
 		return fmt.Errorf("template[%d]: fieldValidation (%s): failed to get field %s: %w", templateIdx, validation.Name, validation.FieldPath, err)
 	}
 
-	if !found || fieldValue == nil || fieldValue == "" {
-		if validation.Message != "" {
-			return fmt.Errorf("template[%d]: fieldValidation (%s): %s", templateIdx, validation.Name, validation.Message)
+	for _, match := range matches {
+		if !match.found || match.value == nil || match.value == "" {
+			// Always attribute the failure to the template kind and the missing field,
+			// even when a custom message is set, so aggregated output stays readable
+			if validation.Message != "" {
+				return fmt.Errorf("template[%d]: fieldValidation (%s): %s (missing field %s on %s/%s)", templateIdx, validation.Name, validation.Message, match.path, obj.GetKind(), obj.GetName())
+			}
+			return fmt.Errorf("template[%d]: fieldValidation (%s): required field %s is missing or empty on %s/%s", templateIdx, validation.Name, match.path, obj.GetKind(), obj.GetName())
+		}
+	}
+
+	return nil
+}
+
+// validateFieldConditionalRequired validates that a field exists and is non-empty when
+// another field has a given value
+func (v *KubeTemplateValidator) validateFieldConditionalRequired(validation kubetemplateriov1alpha1.FieldValidation, obj *unstructured.Unstructured, templateIdx int) error {
+	if validation.WhenFieldPath == "" || validation.RequiredFieldPath == "" {
+		return fmt.Errorf("template[%d]: fieldValidation (%s): whenFieldPath and requiredFieldPath are required for type 'conditionalRequired'", templateIdx, validation.Name)
+	}
+
+	// The condition holds when any field selected by whenFieldPath has the value
+	whenMatches, err := resolveFieldPath(obj.Object, validation.WhenFieldPath)
+	if err != nil {
+		return fmt.Errorf("template[%d]: fieldValidation (%s): failed to get field %s: %w", templateIdx, validation.Name, validation.WhenFieldPath, err)
+	}
+	if !slices.ContainsFunc(whenMatches, func(match fieldMatch) bool {
+		return match.found && fmt.Sprint(match.value) == validation.WhenEquals
+	}) {
+		return nil
+	}
+
+	requiredMatches, err := resolveFieldPath(obj.Object, validation.RequiredFieldPath)
+	if err != nil {
+		return fmt.Errorf("template[%d]: fieldValidation (%s): failed to get field %s: %w", templateIdx, validation.Name, validation.RequiredFieldPath, err)
+	}
+
+	for _, match := range requiredMatches {
+		if !match.found || match.value == nil || match.value == "" {
+			if validation.Message != "" {
+				return fmt.Errorf("template[%d]: fieldValidation (%s): %s (missing field %s on %s/%s)", templateIdx, validation.Name, validation.Message, match.path, obj.GetKind(), obj.GetName())
+			}
+			return fmt.Errorf("template[%d]: fieldValidation (%s): field %s is required when %s is %q, but is missing or empty on %s/%s", templateIdx, validation.Name, match.path, validation.WhenFieldPath, validation.WhenEquals, obj.GetKind(), obj.GetName())
 		}
-		return fmt.Errorf("template[%d]: fieldValidation (%s): required field %s is missing or empty", templateIdx, validation.Name, validation.FieldPath)
 	}
 
 	return nil
@@ -360,25 +854,48 @@ func (v *KubeTemplateValidator) validateFieldForbidden(validation kubetemplateri
 		return fmt.Errorf("template[%d]: fieldValidation (%s): fieldPath is required for type 'forbidden'", templateIdx, validation.Name)
 	}
 
-	// Check if field exists
-	_, found, err := unstructured.NestedFieldCopy(obj.Object, fieldPathToKeys(validation.FieldPath)...)
+	// Check that none of the selected fields exists
+	matches, err := resolveFieldPath(obj.Object, validation.FieldPath)
 	if err != nil {
 		return fmt.Errorf("template[%d]: fieldValidation (%s): failed to get field %s: %w", templateIdx, validation.Name, validation.FieldPath, err)
 	}
 
-	if found {
+	for _, match := range matches {
+		if !match.found {
+			continue
+		}
 		if validation.Message != "" {
 			return fmt.Errorf("template[%d]: fieldValidation (%s): %s", templateIdx, validation.Name, validation.Message)
 		}
-		return fmt.Errorf("template[%d]: fieldValidation (%s): forbidden field %s is present", templateIdx, validation.Name, validation.FieldPath)
+		return fmt.Errorf("template[%d]: fieldValidation (%s): forbidden field %s is present", templateIdx, validation.Name, match.path)
 	}
 
 	return nil
 }
 
-// fieldPathToKeys converts a dot-notation field path to a slice of keys
-func fieldPathToKeys(fieldPath string) []string {
-	return strings.Split(fieldPath, ".")
+// newCELEnv creates the CEL environment rules are evaluated in: the "object" variable holds
+// the whole resource, and for field-level rules (varName "value") the "value" variable holds
+// the field value, of any type, so that it can be compared to sibling fields. Extra options
+// declare functions, such as countResources for object-level rules.
+func newCELEnv(varName string, opts ...cel.EnvOption) (*cel.Env, error) {
+	declarations := []*exprpb.Decl{
+		decls.NewVar("object", decls.NewMapType(decls.String, decls.Dyn)),
+	}
+	if varName == "value" {
+		declarations = append(declarations, decls.NewVar("value", decls.Dyn))
+	}
+	return cel.NewEnv(append([]cel.EnvOption{
+		cel.Declarations(declarations...),
+	}, opts...)...)
+}
+
+// celVariableFor returns the variable a field validation's CEL expression is evaluated with,
+// besides "object"
+func celVariableFor(fieldPath string) string {
+	if fieldPath == "" || fieldPath == "object" {
+		return "object"
+	}
+	return "value"
 }
 
 // validateCELRule validates a single CEL rule against an object or field value
@@ -386,106 +903,107 @@ func fieldPathToKeys(fieldPath string) []string {
 func (v *KubeTemplateValidator) validateCELRule(rule string, obj *unstructured.Unstructured, templateIdx int, validationName string, varNameAndValue ...interface{}) error {
 	gvkStr := obj.GroupVersionKind().String()
 
+	// Record the evaluation outcome; anything that returns before the result is
+	// known (environment, parse, check or eval failure) is counted as an error
+	start := time.Now()
+	result := metrics.CELResultError
+	defer func() {
+		metrics.ObserveCELEvaluation(result, time.Since(start))
+	}()
+
 	// Determine variable name and value
 	varName := "object"
 	var varValue interface{} = obj.Object
-	var varType *exprpb.Type = decls.NewMapType(decls.String, decls.Dyn)
 
 	if len(varNameAndValue) >= 2 {
 		if name, ok := varNameAndValue[0].(string); ok && name != "" {
 			varName = name
 		}
 		varValue = varNameAndValue[1]
-		// For 'value' variable, use dynamic type
-		if varName == "value" {
-			varType = decls.Dyn
-		}
 	}
 
-	// Create CEL environment
-	env, err := cel.NewEnv(
-		cel.Declarations(
-			decls.NewVar(varName, varType),
-		),
-	)
-	if err != nil {
-		errPrefix := fmt.Sprintf("template[%d]", templateIdx)
-		if validationName != "" {
-			errPrefix = fmt.Sprintf("template[%d]: fieldValidation (%s)", templateIdx, validationName)
-		}
-		return fmt.Errorf("%s: failed to create CEL environment: %w", errPrefix, err)
+	errPrefix := fmt.Sprintf("template[%d]", templateIdx)
+	if validationName != "" {
+		errPrefix = fmt.Sprintf("template[%d]: fieldValidation (%s)", templateIdx, validationName)
 	}
 
-	// Parse the CEL rule
-	parsed, issues := env.Parse(rule)
-	if issues != nil && issues.Err() != nil {
-		errPrefix := fmt.Sprintf("template[%d]", templateIdx)
-		if validationName != "" {
-			errPrefix = fmt.Sprintf("template[%d]: fieldValidation (%s)", templateIdx, validationName)
-		}
-		return fmt.Errorf("%s: failed to parse CEL rule: %w", errPrefix, issues.Err())
+	// Evaluate the CEL rule with timeout, which also bounds the cluster queries it makes
+	evalCtx, cancel := context.WithTimeout(context.Background(), celEvaluationTimeout)
+	defer cancel()
+
+	// Create CEL environment, letting object-level rules count resources. Those bind the
+	// function to this evaluation, so only programs of rules that don't count are cached.
+	countsResources := varName == "object" && strings.Contains(rule, celquery.CountResourcesFunction)
+	var envOpts []cel.EnvOption
+	if countsResources {
+		envOpts = append(envOpts, v.ResourceCounter.Function(evalCtx, obj))
 	}
+	compile := func() (cel.Program, error) {
+		env, err := newCELEnv(varName, envOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create CEL environment: %w", err)
+		}
 
-	// Check the CEL rule
-	checked, issues := env.Check(parsed)
-	if issues != nil && issues.Err() != nil {
-		errPrefix := fmt.Sprintf("template[%d]", templateIdx)
-		if validationName != "" {
-			errPrefix = fmt.Sprintf("template[%d]: fieldValidation (%s)", templateIdx, validationName)
+		// Parse the CEL rule
+		parsed, issues := env.Parse(rule)
+		if issues != nil && issues.Err() != nil {
+			return nil, fmt.Errorf("failed to parse CEL rule: %w", issues.Err())
 		}
-		return fmt.Errorf("%s: failed to check CEL rule: %w", errPrefix, issues.Err())
-	}
 
-	// Create CEL program with cost tracking and cost limit
-	prg, err := env.Program(checked, 
-		cel.CostTracking(nil),
-		cel.CostLimit(1000000), // Limit to 1M cost units
-	)
-	if err != nil {
-		errPrefix := fmt.Sprintf("template[%d]", templateIdx)
-		if validationName != "" {
-			errPrefix = fmt.Sprintf("template[%d]: fieldValidation (%s)", templateIdx, validationName)
+		// Check the CEL rule
+		checked, issues := env.Check(parsed)
+		if issues != nil && issues.Err() != nil {
+			return nil, fmt.Errorf("failed to check CEL rule: %w", issues.Err())
 		}
-		return fmt.Errorf("%s: failed to create CEL program: %w", errPrefix, err)
+
+		// Create CEL program with cost tracking and cost limit
+		prg, err := env.Program(checked,
+			cel.CostTracking(nil),
+			cel.CostLimit(1000000), // Limit to 1M cost units
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create CEL program: %w", err)
+		}
+		return prg, nil
 	}
 
-	// Evaluate the CEL rule with timeout
-	evalCtx, cancel := context.WithTimeout(context.Background(), celEvaluationTimeout)
-	defer cancel()
+	var prg cel.Program
+	var err error
+	if countsResources {
+		prg, err = compile()
+	} else {
+		prg, err = v.celPrograms.Program(rule, varName, compile)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", errPrefix, &policy.RuleError{Rule: rule, Err: err})
+	}
 
-	out, _, err := prg.ContextEval(evalCtx, map[string]interface{}{
+	// Field-level rules see the whole resource next to the field value
+	vars := map[string]interface{}{
 		varName: varValue,
-	})
+	}
+	if varName != "object" {
+		vars["object"] = obj.Object
+	}
+	out, _, err := prg.ContextEval(evalCtx, vars)
 	if err != nil {
-		errPrefix := fmt.Sprintf("template[%d]", templateIdx)
-		if validationName != "" {
-			errPrefix = fmt.Sprintf("template[%d]: fieldValidation (%s)", templateIdx, validationName)
-		}
-		return fmt.Errorf("%s: failed to evaluate CEL rule: %w", errPrefix, err)
+		return fmt.Errorf("%s: %w", errPrefix, &policy.RuleError{Rule: rule, Err: fmt.Errorf("failed to evaluate CEL rule: %w", err)})
 	}
 
-	// Check if the rule passed
-	if out.Value() != true {
-		errPrefix := fmt.Sprintf("template[%d]", templateIdx)
-		if validationName != "" {
-			errPrefix = fmt.Sprintf("template[%d]: fieldValidation (%s)", templateIdx, validationName)
-		}
+	// A rule that doesn't return a bool is broken, not failed
+	passed, isBool := out.Value().(bool)
+	if !isBool {
+		return fmt.Errorf("%s: %w", errPrefix, &policy.RuleError{Rule: rule, Err: fmt.Errorf("CEL rule returned %s, not a bool", out.Type().TypeName())})
+	}
+	if !passed {
+		result = metrics.CELResultFail
 		return fmt.Errorf("%s: resource %s/%s failed CEL validation rule: %s", errPrefix, gvkStr, obj.GetName(), rule)
 	}
 
+	result = metrics.CELResultPass
 	return nil
 }
 
-// contains checks if a string is in a slice
-func contains(slice []string, str string) bool {
-	for _, v := range slice {
-		if v == str {
-			return true
-		}
-	}
-	return false
-}
-
 // SetupWebhookWithManager registers the webhook with the manager
 func (v *KubeTemplateValidator) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
@@ -17,14 +17,38 @@ limitations under the License.
 package webhook
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
 
+	openapi_v2 "github.com/google/gnostic-models/openapiv2"
 	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	"github.com/lpeano/KubeTemplater/internal/audit"
+	"github.com/lpeano/KubeTemplater/internal/cache"
+	"github.com/lpeano/KubeTemplater/internal/celquery"
+	"github.com/lpeano/KubeTemplater/internal/metrics"
+	"github.com/lpeano/KubeTemplater/internal/pause"
+	"github.com/lpeano/KubeTemplater/internal/policy"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
 var _ = Describe("KubeTemplate Webhook", func() {
@@ -43,11 +67,15 @@ var _ = Describe("KubeTemplate Webhook", func() {
 
 		fakeClient := fake.NewClientBuilder().
 			WithScheme(scheme).
+			WithIndex(&kubetemplateriov1alpha1.KubeTemplatePolicy{}, "spec.sourceNamespace", func(obj client.Object) []string {
+				return []string{obj.(*kubetemplateriov1alpha1.KubeTemplatePolicy).Spec.SourceNamespace}
+			}).
 			Build()
 
 		validator = &KubeTemplateValidator{
 			Client:            fakeClient,
 			OperatorNamespace: operatorNamespace,
+			Cache:             cache.NewPolicyCache(fakeClient, 0),
 		}
 	})
 
@@ -129,6 +157,28 @@ data:
 			Expect(err).NotTo(HaveOccurred())
 			Expect(warnings).To(BeEmpty())
 		})
+
+		It("Should only accept an applyPriority between -1000 and 1000", func() {
+			kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default"},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					ApplyPriority: 1000,
+					Templates: []kubetemplateriov1alpha1.Template{
+						{Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"test-cm"}}`)}},
+					},
+				},
+			}
+			_, err := validator.ValidateCreate(ctx, kubeTemplate)
+			Expect(err).NotTo(HaveOccurred())
+
+			kubeTemplate.Spec.ApplyPriority = 1001
+			_, err = validator.ValidateCreate(ctx, kubeTemplate)
+			Expect(err).To(MatchError("applyPriority 1001 is out of range, it must be between -1000 and 1000"))
+
+			kubeTemplate.Spec.ApplyPriority = -1001
+			_, err = validator.ValidateUpdate(ctx, kubeTemplate, kubeTemplate)
+			Expect(err).To(MatchError("applyPriority -1001 is out of range, it must be between -1000 and 1000"))
+		})
 	})
 
 	Context("When validating a KubeTemplate with disallowed resource type", func() {
@@ -478,6 +528,67 @@ data:
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring("ConfigMap name must start with 'prod-'"))
 			})
+
+			It("Should compare the field value to other fields of the object", func() {
+				policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-policy",
+						Namespace: operatorNamespace,
+					},
+					Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+						SourceNamespace: "default",
+						ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+							{
+								Kind:             "ConfigMap",
+								Group:            "",
+								Version:          "v1",
+								TargetNamespaces: []string{"default"},
+								FieldValidations: []kubetemplateriov1alpha1.FieldValidation{
+									{
+										Name:      "replicas-within-max",
+										FieldPath: "data.replicas",
+										Type:      kubetemplateriov1alpha1.FieldValidationTypeCEL,
+										CEL:       "int(value) <= int(object.data.maxReplicas)",
+										Message:   "replicas must not exceed maxReplicas",
+									},
+								},
+							},
+						},
+					},
+				}
+				Expect(validator.Client.Create(ctx, policy)).To(Succeed())
+
+				newTemplate := func(replicas string) *kubetemplateriov1alpha1.KubeTemplate {
+					return &kubetemplateriov1alpha1.KubeTemplate{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "test-template",
+							Namespace: "default",
+						},
+						Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+							Templates: []kubetemplateriov1alpha1.Template{
+								{
+									Object: runtime.RawExtension{
+										Raw: []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: scaled-config
+data:
+  replicas: "` + replicas + `"
+  maxReplicas: "5"`),
+									},
+								},
+							},
+						},
+					}
+				}
+
+				_, err := validator.ValidateCreate(ctx, newTemplate("5"))
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = validator.ValidateCreate(ctx, newTemplate("6"))
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("replicas must not exceed maxReplicas"))
+			})
 		})
 
 		Context("With Regex field validation", func() {
@@ -723,6 +834,235 @@ spec:
 			})
 		})
 
+		Context("With Enum field validation", func() {
+			createPolicy := func(validation kubetemplateriov1alpha1.FieldValidation) {
+				policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-policy",
+						Namespace: operatorNamespace,
+					},
+					Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+						SourceNamespace: "default",
+						ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+							{
+								Kind:             "Service",
+								Group:            "",
+								Version:          "v1",
+								TargetNamespaces: []string{"default"},
+								FieldValidations: []kubetemplateriov1alpha1.FieldValidation{validation},
+							},
+						},
+					},
+				}
+				Expect(validator.Client.Create(ctx, policy)).To(Succeed())
+			}
+
+			newService := func(spec string) *kubetemplateriov1alpha1.KubeTemplate {
+				return &kubetemplateriov1alpha1.KubeTemplate{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-template",
+						Namespace: "default",
+					},
+					Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+						Templates: []kubetemplateriov1alpha1.Template{
+							{
+								Object: runtime.RawExtension{
+									Raw: []byte(`apiVersion: v1
+kind: Service
+metadata:
+  name: test-service
+spec:
+  ports:
+  - port: 80
+` + spec),
+								},
+							},
+						},
+					},
+				}
+			}
+
+			serviceType := kubetemplateriov1alpha1.FieldValidation{
+				Name:          "service-type-check",
+				FieldPath:     "spec.type",
+				Type:          kubetemplateriov1alpha1.FieldValidationTypeEnum,
+				AllowedValues: []string{"ClusterIP", "NodePort"},
+			}
+
+			It("Should pass when the value is allowed", func() {
+				createPolicy(serviceType)
+
+				_, err := validator.ValidateCreate(ctx, newService("  type: NodePort\n"))
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("Should fail when the value is not allowed", func() {
+				createPolicy(serviceType)
+
+				_, err := validator.ValidateCreate(ctx, newService("  type: LoadBalancer\n"))
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("field spec.type value 'LoadBalancer' is not one of ClusterIP, NodePort"))
+			})
+
+			It("Should use the custom message", func() {
+				validation := serviceType
+				validation.Message = "Only ClusterIP and NodePort Services are allowed"
+				createPolicy(validation)
+
+				_, err := validator.ValidateCreate(ctx, newService("  type: LoadBalancer\n"))
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("Only ClusterIP and NodePort Services are allowed"))
+			})
+
+			It("Should pass when the field is missing and not required", func() {
+				createPolicy(serviceType)
+
+				_, err := validator.ValidateCreate(ctx, newService(""))
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("Should fail when the field is missing and required", func() {
+				validation := serviceType
+				validation.Required = true
+				createPolicy(validation)
+
+				_, err := validator.ValidateCreate(ctx, newService(""))
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("field spec.type not found"))
+			})
+		})
+
+		Context("With Length field validation", func() {
+			createPolicy := func(validation kubetemplateriov1alpha1.FieldValidation) {
+				policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-policy",
+						Namespace: operatorNamespace,
+					},
+					Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+						SourceNamespace: "default",
+						ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+							{
+								Kind:             "Service",
+								Group:            "",
+								Version:          "v1",
+								TargetNamespaces: []string{"default"},
+								FieldValidations: []kubetemplateriov1alpha1.FieldValidation{validation},
+							},
+						},
+					},
+				}
+				Expect(validator.Client.Create(ctx, policy)).To(Succeed())
+			}
+
+			newService := func(name, ports string) *kubetemplateriov1alpha1.KubeTemplate {
+				return &kubetemplateriov1alpha1.KubeTemplate{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-template",
+						Namespace: "default",
+					},
+					Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+						Templates: []kubetemplateriov1alpha1.Template{
+							{
+								Object: runtime.RawExtension{
+									Raw: []byte(`apiVersion: v1
+kind: Service
+metadata:
+  name: ` + name + `
+spec:
+` + ports),
+								},
+							},
+						},
+					},
+				}
+			}
+
+			twoPorts := "  ports:\n  - port: 80\n  - port: 443\n"
+
+			nameLength := kubetemplateriov1alpha1.FieldValidation{
+				Name:      "name-length-check",
+				FieldPath: "metadata.name",
+				Type:      kubetemplateriov1alpha1.FieldValidationTypeLength,
+				MinLength: int64Ptr(3),
+				MaxLength: int64Ptr(15),
+			}
+
+			It("Should pass when the string length is within bounds", func() {
+				createPolicy(nameLength)
+
+				_, err := validator.ValidateCreate(ctx, newService("frontend", twoPorts))
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("Should fail when the string is too long", func() {
+				createPolicy(nameLength)
+
+				_, err := validator.ValidateCreate(ctx, newService("frontend-service-v2", twoPorts))
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("field metadata.name length 19 is greater than maximum length 15"))
+			})
+
+			It("Should fail when the string is too short", func() {
+				createPolicy(nameLength)
+
+				_, err := validator.ValidateCreate(ctx, newService("fe", twoPorts))
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("field metadata.name length 2 is less than minimum length 3"))
+			})
+
+			It("Should check the number of items of an array", func() {
+				createPolicy(kubetemplateriov1alpha1.FieldValidation{
+					Name:      "ports-length-check",
+					FieldPath: "spec.ports",
+					Type:      kubetemplateriov1alpha1.FieldValidationTypeLength,
+					MaxLength: int64Ptr(1),
+				})
+
+				_, err := validator.ValidateCreate(ctx, newService("frontend", twoPorts))
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("field spec.ports length 2 is greater than maximum length 1"))
+			})
+
+			It("Should use the custom message", func() {
+				validation := nameLength
+				validation.Message = "Service names must be 3 to 15 characters long"
+				createPolicy(validation)
+
+				_, err := validator.ValidateCreate(ctx, newService("frontend-service-v2", twoPorts))
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("Service names must be 3 to 15 characters long"))
+			})
+
+			It("Should fail for a field that is neither a string nor an array", func() {
+				createPolicy(kubetemplateriov1alpha1.FieldValidation{
+					Name:      "port-length-check",
+					FieldPath: "spec.ports",
+					Type:      kubetemplateriov1alpha1.FieldValidationTypeLength,
+					MaxLength: int64Ptr(1),
+				})
+
+				_, err := validator.ValidateCreate(ctx, newService("frontend", "  ports: 80\n"))
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("field spec.ports is neither a string nor an array"))
+			})
+
+			It("Should fail when the field is missing and required", func() {
+				validation := kubetemplateriov1alpha1.FieldValidation{
+					Name:      "ports-length-check",
+					FieldPath: "spec.ports",
+					Type:      kubetemplateriov1alpha1.FieldValidationTypeLength,
+					MinLength: int64Ptr(1),
+					Required:  true,
+				}
+				createPolicy(validation)
+
+				_, err := validator.ValidateCreate(ctx, newService("frontend", "  type: ClusterIP\n"))
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("field spec.ports not found"))
+			})
+		})
+
 		Context("With Required field validation", func() {
 			It("Should pass when required field exists", func() {
 				policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
@@ -948,6 +1288,114 @@ spec:
 			})
 		})
 
+		Context("With field paths selecting list elements", func() {
+			var deployment *unstructured.Unstructured
+
+			BeforeEach(func() {
+				deployment = &unstructured.Unstructured{Object: map[string]interface{}{
+					"apiVersion": "apps/v1",
+					"kind":       "Deployment",
+					"metadata":   map[string]interface{}{"name": "web"},
+					"spec": map[string]interface{}{"template": map[string]interface{}{"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{"name": "app", "image": "registry.corp.example.com/app:1.2"},
+							map[string]interface{}{"name": "proxy", "image": "docker.io/envoyproxy/envoy:v1.30"},
+						},
+					}}},
+				}}
+			})
+
+			imageRegex := kubetemplateriov1alpha1.FieldValidation{
+				Name:      "internal-images",
+				FieldPath: "spec.template.spec.containers[*].image",
+				Type:      kubetemplateriov1alpha1.FieldValidationTypeRegex,
+				Regex:     "^registry\\.corp\\.example\\.com/",
+			}
+
+			It("Should fail a regex when any selected element violates it", func() {
+				err := validator.validateFieldRegex(imageRegex, deployment, 0)
+				Expect(err).To(MatchError("template[0]: fieldValidation (internal-images): field spec.template.spec.containers[1].image value 'docker.io/envoyproxy/envoy:v1.30' does not match regex pattern '^registry\\.corp\\.example\\.com/'"))
+			})
+
+			It("Should pass a regex when every selected element matches it", func() {
+				containers, _, _ := unstructured.NestedSlice(deployment.Object, "spec", "template", "spec", "containers")
+				containers[1].(map[string]interface{})["image"] = "registry.corp.example.com/envoy:v1.30"
+				Expect(unstructured.SetNestedSlice(deployment.Object, containers, "spec", "template", "spec", "containers")).To(Succeed())
+
+				Expect(validator.validateFieldRegex(imageRegex, deployment, 0)).To(Succeed())
+			})
+
+			It("Should select a single element by index", func() {
+				validation := imageRegex
+				validation.FieldPath = "spec.template.spec.containers[0].image"
+				Expect(validator.validateFieldRegex(validation, deployment, 0)).To(Succeed())
+
+				validation.FieldPath = "spec.template.spec.containers.1.image"
+				Expect(validator.validateFieldRegex(validation, deployment, 0)).To(MatchError(ContainSubstring("field spec.template.spec.containers[1].image value")))
+			})
+
+			It("Should treat an index past the end of the list as a missing field", func() {
+				validation := imageRegex
+				validation.FieldPath = "spec.template.spec.containers[5].image"
+				Expect(validator.validateFieldRegex(validation, deployment, 0)).To(MatchError(ContainSubstring("field spec.template.spec.containers[5].image not found")))
+			})
+
+			It("Should require the field in every selected element", func() {
+				validation := kubetemplateriov1alpha1.FieldValidation{
+					Name:      "pull-policy",
+					FieldPath: "spec.template.spec.containers[*].imagePullPolicy",
+					Type:      kubetemplateriov1alpha1.FieldValidationTypeRequired,
+				}
+				Expect(validator.validateFieldRequired(validation, deployment, 0)).To(MatchError(
+					"template[0]: fieldValidation (pull-policy): required field spec.template.spec.containers[0].imagePullPolicy is missing or empty on Deployment/web"))
+			})
+
+			It("Should pass required and forbidden over an empty list", func() {
+				validation := kubetemplateriov1alpha1.FieldValidation{
+					Name:      "init-images",
+					FieldPath: "spec.template.spec.initContainers[*].image",
+					Type:      kubetemplateriov1alpha1.FieldValidationTypeRequired,
+				}
+				Expect(unstructured.SetNestedSlice(deployment.Object, []interface{}{}, "spec", "template", "spec", "initContainers")).To(Succeed())
+				Expect(validator.validateFieldRequired(validation, deployment, 0)).To(Succeed())
+
+				validation.Type = kubetemplateriov1alpha1.FieldValidationTypeForbidden
+				Expect(validator.validateFieldForbidden(validation, deployment, 0)).To(Succeed())
+			})
+
+			It("Should forbid the field when any selected element sets it", func() {
+				containers, _, _ := unstructured.NestedSlice(deployment.Object, "spec", "template", "spec", "containers")
+				containers[1].(map[string]interface{})["securityContext"] = map[string]interface{}{"privileged": true}
+				Expect(unstructured.SetNestedSlice(deployment.Object, containers, "spec", "template", "spec", "containers")).To(Succeed())
+
+				validation := kubetemplateriov1alpha1.FieldValidation{
+					Name:      "no-privileged",
+					FieldPath: "spec.template.spec.containers[*].securityContext.privileged",
+					Type:      kubetemplateriov1alpha1.FieldValidationTypeForbidden,
+				}
+				Expect(validator.validateFieldForbidden(validation, deployment, 0)).To(MatchError(
+					"template[0]: fieldValidation (no-privileged): forbidden field spec.template.spec.containers[1].securityContext.privileged is present"))
+			})
+
+			It("Should evaluate a CEL validation against every selected element", func() {
+				validation := kubetemplateriov1alpha1.FieldValidation{
+					Name:      "named-containers",
+					FieldPath: "spec.template.spec.containers[*].name",
+					Type:      kubetemplateriov1alpha1.FieldValidationTypeCEL,
+					CEL:       "value.size() > 3",
+					Message:   "container names must be longer than 3 characters",
+				}
+				Expect(validator.validateFieldCEL(validation, deployment, 0)).To(MatchError(
+					"template[0]: fieldValidation (named-containers): container names must be longer than 3 characters"))
+			})
+
+			It("Should fail when a path selects elements of a map", func() {
+				validation := imageRegex
+				validation.FieldPath = "spec.template[0].image"
+				Expect(validator.validateFieldRegex(validation, deployment, 0)).To(MatchError(ContainSubstring("spec.template is a map, not a list")))
+			})
+		})
+
 		Context("With multiple field validations", func() {
 			It("Should pass when all validations succeed", func() {
 				policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
@@ -1101,6 +1549,2479 @@ spec:
 			})
 		})
 	})
+	Context("When several templates miss required fields", func() {
+		It("Should report every failure attributed to its template, kind and field", func() {
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-policy",
+					Namespace: operatorNamespace,
+				},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{
+							Kind:             "ConfigMap",
+							Group:            "",
+							Version:          "v1",
+							TargetNamespaces: []string{"default"},
+							FieldValidations: []kubetemplateriov1alpha1.FieldValidation{
+								{
+									Name:      "team-label-required",
+									FieldPath: "metadata.labels.team",
+									Type:      kubetemplateriov1alpha1.FieldValidationTypeRequired,
+									Required:  true,
+								},
+							},
+						},
+						{
+							Kind:             "Service",
+							Group:            "",
+							Version:          "v1",
+							TargetNamespaces: []string{"default"},
+							FieldValidations: []kubetemplateriov1alpha1.FieldValidation{
+								{
+									Name:      "selector-required",
+									FieldPath: "spec.selector",
+									Type:      kubetemplateriov1alpha1.FieldValidationTypeRequired,
+									Required:  true,
+									Message:   "Services must select pods",
+								},
+							},
+						},
+					},
+				},
+			}
+			Expect(validator.Client.Create(ctx, policy)).To(Succeed())
+
+			kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-template",
+					Namespace: "default",
+				},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{
+							Object: runtime.RawExtension{
+								Raw: []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test-cm
+data:
+  key: value`),
+							},
+						},
+						{
+							Object: runtime.RawExtension{
+								Raw: []byte(`apiVersion: v1
+kind: Service
+metadata:
+  name: test-svc
+spec:
+  ports:
+  - port: 80`),
+							},
+						},
+					},
+				},
+			}
+
+			_, err := validator.ValidateCreate(ctx, kubeTemplate)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("2 validation failures in 2 template(s)"))
+			Expect(err.Error()).To(ContainSubstring("template[0] ConfigMap/test-cm:"))
+			Expect(err.Error()).To(ContainSubstring("required field metadata.labels.team is missing or empty on ConfigMap/test-cm"))
+			Expect(err.Error()).To(ContainSubstring("template[1] Service/test-svc:"))
+			Expect(err.Error()).To(ContainSubstring("Services must select pods (missing field spec.selector on Service/test-svc)"))
+		})
+
+		It("Should report every field validation a single template breaks", func() {
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-policy",
+					Namespace: operatorNamespace,
+				},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{
+							Kind:             "ConfigMap",
+							Group:            "",
+							Version:          "v1",
+							TargetNamespaces: []string{"default"},
+							FieldValidations: []kubetemplateriov1alpha1.FieldValidation{
+								{
+									Name:      "team-label-required",
+									FieldPath: "metadata.labels.team",
+									Type:      kubetemplateriov1alpha1.FieldValidationTypeRequired,
+									Required:  true,
+								},
+								{
+									Name:      "name-prefix-check",
+									FieldPath: "metadata.name",
+									Type:      kubetemplateriov1alpha1.FieldValidationTypeCEL,
+									CEL:       "value.startsWith('prod-')",
+									Message:   "ConfigMap name must start with 'prod-'",
+								},
+								{
+									Name:      "passes",
+									FieldPath: "data.key",
+									Type:      kubetemplateriov1alpha1.FieldValidationTypeRequired,
+									Required:  true,
+								},
+								{
+									Name:          "mode-enum",
+									FieldPath:     "data.mode",
+									Type:          kubetemplateriov1alpha1.FieldValidationTypeEnum,
+									AllowedValues: []string{"fast", "safe"},
+									Message:       "mode must be fast or safe",
+								},
+							},
+						},
+					},
+				},
+			}
+			Expect(validator.Client.Create(ctx, policy)).To(Succeed())
+
+			kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-template",
+					Namespace: "default",
+				},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{
+							Object: runtime.RawExtension{
+								Raw: []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: dev-config
+data:
+  key: value
+  mode: reckless`),
+							},
+						},
+					},
+				},
+			}
+
+			_, err := validator.ValidateCreate(ctx, kubeTemplate)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("3 validation failures in 1 template(s)"))
+			Expect(err.Error()).To(ContainSubstring("template[0] ConfigMap/dev-config:"))
+			lines := strings.Split(err.Error(), "\n")
+			Expect(lines).To(HaveLen(5))
+			Expect(lines[2]).To(ContainSubstring("fieldValidation (team-label-required)"))
+			Expect(lines[3]).To(ContainSubstring("ConfigMap name must start with 'prod-'"))
+			Expect(lines[4]).To(ContainSubstring("mode must be fast or safe"))
+		})
+	})
+	Context("When recording CEL evaluation metrics", func() {
+		var obj *unstructured.Unstructured
+
+		BeforeEach(func() {
+			obj = &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata":   map[string]interface{}{"name": "test-deployment"},
+				"spec":       map[string]interface{}{"replicas": int64(3)},
+			}}
+		})
+
+		DescribeTable("Should increment the counter with the matching result label",
+			func(rule string, expectedResult string, expectErr bool) {
+				counter := metrics.CELEvaluationsTotal.WithLabelValues(expectedResult)
+				before := testutil.ToFloat64(counter)
+
+				err := validator.validateCELRule(rule, obj, 0, "")
+				if expectErr {
+					Expect(err).To(HaveOccurred())
+				} else {
+					Expect(err).NotTo(HaveOccurred())
+				}
+				Expect(testutil.ToFloat64(counter)).To(Equal(before + 1))
+			},
+			Entry("passing rule", "object.spec.replicas <= 5", metrics.CELResultPass, false),
+			Entry("failing rule", "object.spec.replicas > 5", metrics.CELResultFail, true),
+			Entry("rule with a parse error", "object.spec.replicas <=", metrics.CELResultError, true),
+			Entry("rule dereferencing a missing field", "object.spec.strategy.type == 'Recreate'", metrics.CELResultError, true),
+			Entry("rule not returning a bool", "object.spec.replicas", metrics.CELResultError, true),
+		)
+
+		It("Should report a rule that can't be evaluated as a policy error, not as a violation", func() {
+			err := validator.validateCELRule("object.spec.replicas > 5", obj, 0, "")
+			Expect(err).To(MatchError("template[0]: resource apps/v1, Kind=Deployment/test-deployment failed CEL validation rule: object.spec.replicas > 5"))
+			Expect(policy.IsRuleError(err)).To(BeFalse())
+
+			err = validator.validateCELRule("object.spec.strategy.type == 'Recreate'", obj, 0, "")
+			Expect(err).To(MatchError(`template[0]: policy error: CEL rule "object.spec.strategy.type == 'Recreate'" is broken: failed to evaluate CEL rule: no such key: strategy`))
+			Expect(policy.IsRuleError(err)).To(BeTrue())
+		})
+
+		It("Should not replace a policy error with the validation message", func() {
+			validation := kubetemplateriov1alpha1.FieldValidation{
+				Name:    "strategy",
+				Type:    kubetemplateriov1alpha1.FieldValidationTypeCEL,
+				CEL:     "object.spec.strategy.type == 'Recreate'",
+				Message: "Deployments must use the Recreate strategy",
+			}
+			err := validator.validateFieldCEL(validation, obj, 0)
+			Expect(policy.IsRuleError(err)).To(BeTrue())
+			Expect(err).To(MatchError(ContainSubstring("fieldValidation (strategy): policy error")))
+
+			validation.CEL = "has(object.spec.strategy) && object.spec.strategy.type == 'Recreate'"
+			Expect(validator.validateFieldCEL(validation, obj, 0)).To(MatchError("template[0]: fieldValidation (strategy): Deployments must use the Recreate strategy"))
+		})
+	})
+
+	Context("When recording per-policy validation metrics", func() {
+		BeforeEach(func() {
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "metrics-policy", Namespace: operatorNamespace},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{Kind: "ConfigMap", Version: "v1", TargetNamespaces: []string{"default"}},
+					},
+				},
+			}
+			Expect(validator.Client.Create(ctx, policy)).To(Succeed())
+		})
+
+		DescribeTable("Should increment the counter of the matched policy",
+			func(kind string, expectedResult string) {
+				counter := metrics.PolicyValidationsTotal.WithLabelValues("metrics-policy", metrics.PolicySourceWebhook, expectedResult)
+				before := testutil.ToFloat64(counter)
+
+				kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default"},
+					Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+						Templates: []kubetemplateriov1alpha1.Template{
+							{Object: runtime.RawExtension{Raw: []byte(`{"apiVersion": "v1", "kind": "` + kind + `", "metadata": {"name": "test"}}`)}},
+						},
+					},
+				}
+				_, err := validator.ValidateCreate(ctx, kubeTemplate)
+				if expectedResult == metrics.PolicyResultDenied {
+					Expect(err).To(HaveOccurred())
+				} else {
+					Expect(err).NotTo(HaveOccurred())
+				}
+				Expect(testutil.ToFloat64(counter)).To(Equal(before + 1))
+			},
+			Entry("allowed template", "ConfigMap", metrics.PolicyResultAllowed),
+			Entry("denied template", "Secret", metrics.PolicyResultDenied),
+		)
+
+		It("Should count a merged policy toward each policy it was merged from", func() {
+			Expect(validator.Client.Create(ctx, &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "other-policy", Namespace: operatorNamespace},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{Kind: "Secret", Version: "v1", TargetNamespaces: []string{"default"}},
+					},
+				},
+			})).To(Succeed())
+			validator.Cache.SetDuplicateResolution(cache.DuplicateResolutionMerge)
+			DeferCleanup(metrics.ForgetPolicy, "metrics-policy")
+
+			kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default"},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{Object: runtime.RawExtension{Raw: []byte(`{"apiVersion": "v1", "kind": "Secret", "metadata": {"name": "test"}}`)}},
+					},
+				},
+			}
+			_, err := validator.ValidateCreate(ctx, kubeTemplate)
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, name := range []string{"metrics-policy", "other-policy"} {
+				Expect(testutil.ToFloat64(metrics.PolicyValidationsTotal.WithLabelValues(
+					name, metrics.PolicySourceWebhook, metrics.PolicyResultAllowed))).To(BeNumerically(">=", 1), name)
+			}
+			Expect(testutil.ToFloat64(metrics.PolicyValidationsTotal.WithLabelValues(
+				"metrics-policy+other-policy", metrics.PolicySourceWebhook, metrics.PolicyResultAllowed))).To(BeZero())
+
+			// Deleting a merged policy frees its series
+			metrics.ForgetPolicy("other-policy")
+			Expect(testutil.ToFloat64(metrics.PolicyValidationsTotal.WithLabelValues(
+				"other-policy", metrics.PolicySourceWebhook, metrics.PolicyResultAllowed))).To(BeZero())
+		})
+	})
+
+	Context("When a template waits for its object to become ready", func() {
+		BeforeEach(func() {
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: operatorNamespace},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{Kind: "ConfigMap", Version: "v1", TargetNamespaces: []string{"default"}},
+					},
+				},
+			}
+			Expect(validator.Client.Create(ctx, policy)).To(Succeed())
+		})
+
+		newTemplate := func(condition string, timeout ...time.Duration) *kubetemplateriov1alpha1.KubeTemplate {
+			wait := &kubetemplateriov1alpha1.WaitForReady{Condition: condition}
+			if len(timeout) > 0 {
+				wait.Timeout = &metav1.Duration{Duration: timeout[0]}
+			}
+			return &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default"},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{
+							Object:       runtime.RawExtension{Raw: []byte(`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "database"}}`)},
+							WaitForReady: wait,
+						},
+					},
+				},
+			}
+		}
+
+		It("Should accept a valid readiness condition", func() {
+			_, err := validator.ValidateCreate(ctx, newTemplate("object.data.phase == 'ready'"))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should reject a readiness condition that doesn't compile", func() {
+			_, err := validator.ValidateCreate(ctx, newTemplate("object.data.phase =="))
+			Expect(err).To(MatchError(ContainSubstring("template[0]: waitForReady.condition:")))
+		})
+
+		It("Should reject a readiness condition that counts resources", func() {
+			_, err := validator.ValidateCreate(ctx, newTemplate("countResources('v1', 'ConfigMap', 'default') > 0"))
+			Expect(err).To(MatchError(ContainSubstring("template[0]: waitForReady.condition: CEL expression calls countResources, which waitForReady conditions can't use")))
+		})
+
+		It("Should accept a timeout of up to an hour", func() {
+			_, err := validator.ValidateCreate(ctx, newTemplate("object.data.phase == 'ready'", time.Hour))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should reject a timeout over an hour", func() {
+			_, err := validator.ValidateCreate(ctx, newTemplate("object.data.phase == 'ready'", 2*time.Hour))
+			Expect(err).To(MatchError("template[0]: waitForReady.timeout 2h0m0s is out of range, it must be greater than 0s and at most 1h0m0s"))
+		})
+
+		It("Should reject a timeout that isn't positive", func() {
+			_, err := validator.ValidateCreate(ctx, newTemplate("object.data.phase == 'ready'", 0))
+			Expect(err).To(MatchError(ContainSubstring("template[0]: waitForReady.timeout 0s is out of range")))
+		})
+	})
+
+	Context("When evaluating a CEL rule repeatedly", func() {
+		var obj *unstructured.Unstructured
+
+		BeforeEach(func() {
+			obj = &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata":   map[string]interface{}{"name": "test-deployment", "namespace": "default"},
+				"spec":       map[string]interface{}{"replicas": int64(3)},
+			}}
+		})
+
+		It("Should compile the rule once per variable", func() {
+			for i := 0; i < 3; i++ {
+				Expect(validator.validateCELRule("object.spec.replicas <= 5", obj, 0, "")).To(Succeed())
+				Expect(validator.validateCELRule("value <= 5", obj, 0, "replicas", "value", int64(3))).To(Succeed())
+			}
+			Expect(validator.celPrograms.Len()).To(Equal(2))
+
+			// The cached program is evaluated against each object
+			obj.Object["spec"] = map[string]interface{}{"replicas": int64(7)}
+			Expect(validator.validateCELRule("object.spec.replicas <= 5", obj, 0, "")).To(MatchError(ContainSubstring("failed CEL validation rule")))
+		})
+
+		It("Should not cache rules that fail to compile", func() {
+			Expect(validator.validateCELRule("object.spec.replicas <=", obj, 0, "")).To(MatchError(ContainSubstring("failed to parse CEL rule")))
+			Expect(validator.celPrograms.Len()).To(BeZero())
+		})
+
+		It("Should not cache rules that count resources", func() {
+			err := validator.validateCELRule("countResources('apps/v1', 'Deployment', 'default') < 5", obj, 0, "")
+			Expect(err).To(MatchError(ContainSubstring("countResources is not available")))
+			Expect(validator.celPrograms.Len()).To(BeZero())
+		})
+	})
+
+	Context("When validating container images with an image policy", func() {
+		BeforeEach(func() {
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-policy",
+					Namespace: operatorNamespace,
+				},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{
+							Kind:             "Deployment",
+							Group:            "apps",
+							Version:          "v1",
+							TargetNamespaces: []string{"default"},
+							ImagePolicy: &kubetemplateriov1alpha1.ImagePolicy{
+								ForbidLatest:  true,
+								RequireDigest: true,
+							},
+						},
+					},
+				},
+			}
+			Expect(validator.Client.Create(ctx, policy)).To(Succeed())
+		})
+
+		deploymentWithImage := func(image string) *kubetemplateriov1alpha1.KubeTemplate {
+			return &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-template",
+					Namespace: "default",
+				},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{
+							Object: runtime.RawExtension{
+								Raw: []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: test-deploy
+  namespace: default
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        image: ` + image),
+							},
+						},
+					},
+				},
+			}
+		}
+
+		It("Should reject an image tagged latest", func() {
+			_, err := validator.ValidateCreate(ctx, deploymentWithImage("nginx:latest"))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("image nginx:latest in spec.template.spec.containers[0]"))
+		})
+
+		It("Should reject an untagged image", func() {
+			_, err := validator.ValidateCreate(ctx, deploymentWithImage("registry.local:5000/nginx"))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("registry.local:5000/nginx"))
+		})
+
+		It("Should reject a tagged image that is not pinned by digest", func() {
+			_, err := validator.ValidateCreate(ctx, deploymentWithImage("nginx:1.27"))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("is not pinned by digest"))
+		})
+
+		It("Should accept a digest-pinned image", func() {
+			_, err := validator.ValidateCreate(ctx, deploymentWithImage("nginx@sha256:0d17b565c37bcbd895e9d92315a05c1c3c9a29f762b011a10c54a66cd53c9b31"))
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("When templates embed objects as YAML or JSON", func() {
+		BeforeEach(func() {
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-policy",
+					Namespace: operatorNamespace,
+				},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{
+							Kind:             "ConfigMap",
+							Group:            "",
+							Version:          "v1",
+							TargetNamespaces: []string{"default"},
+							FieldValidations: []kubetemplateriov1alpha1.FieldValidation{
+								{
+									Name:      "name-prefix",
+									FieldPath: "metadata.name",
+									Type:      kubetemplateriov1alpha1.FieldValidationTypeRegex,
+									Regex:     "^app-",
+								},
+							},
+						},
+					},
+				},
+			}
+			Expect(validator.Client.Create(ctx, policy)).To(Succeed())
+		})
+
+		DescribeTable("Should validate every encoding the same way",
+			func(raw string) {
+				kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-template",
+						Namespace: "default",
+					},
+					Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+						Templates: []kubetemplateriov1alpha1.Template{
+							{Object: runtime.RawExtension{Raw: []byte(raw)}},
+						},
+					},
+				}
+
+				_, err := validator.ValidateCreate(ctx, kubeTemplate)
+				Expect(err).NotTo(HaveOccurred())
+			},
+			Entry("inline YAML", "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app-config\n"),
+			Entry("JSON", `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"app-config"}}`),
+			Entry("YAML block scalar stored as a string", `"apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app-config\n"`),
+		)
+	})
+
+	Context("When a template creates KubeTemplater resources", func() {
+		BeforeEach(func() {
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-policy",
+					Namespace: operatorNamespace,
+				},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{
+							Kind:             "KubeTemplatePolicy",
+							Group:            "kubetemplater.io",
+							Version:          "v1alpha1",
+							TargetNamespaces: []string{"default"},
+						},
+					},
+				},
+			}
+			Expect(validator.Client.Create(ctx, policy)).To(Succeed())
+		})
+
+		policyTemplate := func() *kubetemplateriov1alpha1.KubeTemplate {
+			return &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-template",
+					Namespace: "default",
+				},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{
+							Object: runtime.RawExtension{
+								Raw: []byte(`apiVersion: kubetemplater.io/v1alpha1
+kind: KubeTemplatePolicy
+metadata:
+  name: escalated-policy
+spec:
+  sourceNamespace: other
+  validationRules: []`),
+							},
+						},
+					},
+				},
+			}
+		}
+
+		It("Should reject the template by default, even if the policy allows the kind", func() {
+			_, err := validator.ValidateCreate(ctx, policyTemplate())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("cannot be created from a template"))
+		})
+
+		It("Should accept the template when explicitly allowed", func() {
+			validator.AllowKubeTemplaterResources = true
+			_, err := validator.ValidateCreate(ctx, policyTemplate())
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("When SSA advisories are enabled", func() {
+		const swagger = `{
+  "swagger": "2.0",
+  "info": {"title": "Kubernetes", "version": "v1.33.0"},
+  "paths": {},
+  "definitions": {
+    "io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta": {
+      "type": "object",
+      "properties": {
+        "name": {"type": "string"},
+        "namespace": {"type": "string"}
+      }
+    },
+    "io.k8s.api.core.v1.Container": {
+      "type": "object",
+      "properties": {
+        "name": {"type": "string"},
+        "image": {"type": "string"},
+        "args": {"type": "array", "items": {"type": "string"}, "x-kubernetes-list-type": "atomic"}
+      }
+    },
+    "io.k8s.api.core.v1.Toleration": {
+      "type": "object",
+      "properties": {
+        "key": {"type": "string"},
+        "operator": {"type": "string"}
+      }
+    },
+    "io.k8s.api.core.v1.PodSpec": {
+      "type": "object",
+      "properties": {
+        "containers": {
+          "type": "array",
+          "items": {"$ref": "#/definitions/io.k8s.api.core.v1.Container"},
+          "x-kubernetes-list-map-keys": ["name"],
+          "x-kubernetes-list-type": "map",
+          "x-kubernetes-patch-merge-key": "name"
+        },
+        "tolerations": {
+          "type": "array",
+          "items": {"$ref": "#/definitions/io.k8s.api.core.v1.Toleration"},
+          "x-kubernetes-list-type": "atomic"
+        }
+      }
+    },
+    "io.k8s.api.core.v1.Pod": {
+      "type": "object",
+      "properties": {
+        "apiVersion": {"type": "string"},
+        "kind": {"type": "string"},
+        "metadata": {"$ref": "#/definitions/io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta"},
+        "spec": {"$ref": "#/definitions/io.k8s.api.core.v1.PodSpec"}
+      },
+      "x-kubernetes-group-version-kind": [{"group": "", "kind": "Pod", "version": "v1"}]
+    }
+  }
+}`
+
+		BeforeEach(func() {
+			validator.SchemaValidator = NewSchemaValidator(func() (*openapi_v2.Document, error) {
+				return openapi_v2.ParseDocument([]byte(swagger))
+			}, time.Hour)
+			validator.SSAAdvisories = true
+
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-policy",
+					Namespace: operatorNamespace,
+				},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{
+							Kind:             "Pod",
+							Version:          "v1",
+							TargetNamespaces: []string{"default"},
+						},
+					},
+				},
+			}
+			Expect(validator.Client.Create(ctx, policy)).To(Succeed())
+		})
+
+		podTemplate := func(spec string) *kubetemplateriov1alpha1.KubeTemplate {
+			return &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-template",
+					Namespace: "default",
+				},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{
+							Object: runtime.RawExtension{
+								Raw: []byte(`apiVersion: v1
+kind: Pod
+metadata:
+  name: test-pod
+spec:
+` + spec),
+							},
+						},
+					},
+				},
+			}
+		}
+
+		It("Should warn that a partially specified atomic list is replaced as a whole", func() {
+			warnings, err := validator.ValidateCreate(ctx, podTemplate(`  containers:
+  - name: app
+    image: nginx
+    args: ["--verbose"]
+  tolerations:
+  - key: dedicated
+    operator: Exists`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(
+				"template[0]: Pod test-pod: spec.tolerations is an atomic list: server-side apply replaces it as a whole, " +
+					"removing entries other field managers added and the fields this template leaves out"))
+		})
+
+		It("Should warn about list entries without their list key", func() {
+			warnings, err := validator.ValidateCreate(ctx, podTemplate(`  containers:
+  - name: app
+    image: nginx
+  - image: sidecar`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(
+				"template[0]: Pod test-pod: spec.containers[1] sets none of the list keys name: server-side apply " +
+					"can't merge it with the live entries by key"))
+		})
+
+		It("Should not warn when the advisories are disabled", func() {
+			validator.SSAAdvisories = false
+			warnings, err := validator.ValidateCreate(ctx, podTemplate(`  tolerations:
+  - key: dedicated
+    operator: Exists`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+	})
+
+	Context("When OpenAPI schema validation is enabled", func() {
+		const swagger = `{
+  "swagger": "2.0",
+  "info": {"title": "Kubernetes", "version": "v1.33.0"},
+  "paths": {},
+  "definitions": {
+    "io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta": {
+      "type": "object",
+      "properties": {
+        "name": {"type": "string"},
+        "namespace": {"type": "string"}
+      }
+    },
+    "io.k8s.api.apps.v1.DeploymentSpec": {
+      "type": "object",
+      "properties": {
+        "replicas": {"type": "integer", "format": "int32"}
+      }
+    },
+    "io.k8s.api.apps.v1.Deployment": {
+      "type": "object",
+      "properties": {
+        "apiVersion": {"type": "string"},
+        "kind": {"type": "string"},
+        "metadata": {"$ref": "#/definitions/io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta"},
+        "spec": {"$ref": "#/definitions/io.k8s.api.apps.v1.DeploymentSpec"}
+      },
+      "x-kubernetes-group-version-kind": [{"group": "apps", "kind": "Deployment", "version": "v1"}]
+    }
+  }
+}`
+
+		var fetches int
+
+		BeforeEach(func() {
+			fetches = 0
+			validator.SchemaValidator = NewSchemaValidator(func() (*openapi_v2.Document, error) {
+				fetches++
+				return openapi_v2.ParseDocument([]byte(swagger))
+			}, time.Hour)
+
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-policy",
+					Namespace: operatorNamespace,
+				},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{
+							Kind:             "Deployment",
+							Group:            "apps",
+							Version:          "v1",
+							TargetNamespaces: []string{"default"},
+						},
+					},
+				},
+			}
+			Expect(validator.Client.Create(ctx, policy)).To(Succeed())
+		})
+
+		deploymentWithReplicas := func(replicas string) *kubetemplateriov1alpha1.KubeTemplate {
+			return &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-template",
+					Namespace: "default",
+				},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{
+							Object: runtime.RawExtension{
+								Raw: []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: test-deploy
+spec:
+  replicas: ` + replicas),
+							},
+						},
+					},
+				},
+			}
+		}
+
+		It("Should reject a Deployment with a string replicas field", func() {
+			_, err := validator.ValidateCreate(ctx, deploymentWithReplicas(`"three"`))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("template[0]: schema:"))
+			Expect(err.Error()).To(ContainSubstring("Deployment.spec.replicas"))
+			Expect(err.Error()).To(ContainSubstring(`got "string", expected "integer"`))
+		})
+
+		It("Should accept a structurally valid Deployment and reuse the cached schema", func() {
+			_, err := validator.ValidateCreate(ctx, deploymentWithReplicas("3"))
+			Expect(err).NotTo(HaveOccurred())
+			_, err = validator.ValidateCreate(ctx, deploymentWithReplicas("2"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fetches).To(Equal(1))
+		})
+	})
+
+	Context("When the policy has maintenance windows", func() {
+		BeforeEach(func() {
+			validator.Now = func() time.Time {
+				return time.Date(2025, time.January, 15, 1, 0, 0, 0, time.UTC)
+			}
+		})
+
+		createPolicy := func(window kubetemplateriov1alpha1.MaintenanceWindow) {
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-policy",
+					Namespace: operatorNamespace,
+				},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace:    "default",
+					MaintenanceWindows: []kubetemplateriov1alpha1.MaintenanceWindow{window},
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{
+							Kind:             "ConfigMap",
+							Group:            "",
+							Version:          "v1",
+							TargetNamespaces: []string{"default"},
+						},
+					},
+				},
+			}
+			Expect(validator.Client.Create(ctx, policy)).To(Succeed())
+		}
+
+		configMapTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-template",
+				Namespace: "default",
+			},
+			Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+				Templates: []kubetemplateriov1alpha1.Template{
+					{
+						Object: runtime.RawExtension{
+							Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"app-config"}}`),
+						},
+					},
+				},
+			},
+		}
+
+		It("Should warn that changes are deferred outside the window", func() {
+			createPolicy(kubetemplateriov1alpha1.MaintenanceWindow{Start: "02:00", End: "04:00"})
+
+			warnings, err := validator.ValidateCreate(ctx, configMapTemplate)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ContainElement(ContainSubstring("changes will not take effect until the next window opens at 2025-01-15T02:00:00Z")))
+		})
+
+		It("Should not warn inside the window", func() {
+			createPolicy(kubetemplateriov1alpha1.MaintenanceWindow{Start: "00:00", End: "04:00"})
+
+			warnings, err := validator.ValidateCreate(ctx, configMapTemplate)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+	})
+
+	Context("When a policy rule spells the core group as \"core\"", func() {
+		BeforeEach(func() {
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-policy",
+					Namespace: operatorNamespace,
+				},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{
+							Kind:             "ConfigMap",
+							Group:            "core",
+							Version:          "v1",
+							TargetNamespaces: []string{"default"},
+						},
+					},
+				},
+			}
+			Expect(validator.Client.Create(ctx, policy)).To(Succeed())
+		})
+
+		It("Should match the rule and warn about the group", func() {
+			kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-template",
+					Namespace: "default",
+				},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{
+							Object: runtime.RawExtension{
+								Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"app-config"}}`),
+							},
+						},
+					},
+				},
+			}
+
+			warnings, err := validator.ValidateCreate(ctx, kubeTemplate)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ContainElement(ContainSubstring(`rule for ConfigMap with group "core", which was treated as the core group`)))
+		})
+	})
+	Context("When a template uses generateName", func() {
+		BeforeEach(func() {
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-policy",
+					Namespace: operatorNamespace,
+				},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{
+							Kind:             "ConfigMap",
+							Version:          "v1",
+							TargetNamespaces: []string{"default"},
+						},
+					},
+				},
+			}
+			Expect(validator.Client.Create(ctx, policy)).To(Succeed())
+		})
+
+		It("Should reject the template and explain that metadata.name is required", func() {
+			kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-template",
+					Namespace: "default",
+				},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{
+							Object: runtime.RawExtension{
+								Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"generateName":"app-config-"}}`),
+							},
+						},
+					},
+				},
+			}
+
+			_, err := validator.ValidateCreate(ctx, kubeTemplate)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(`template[0]: ConfigMap: metadata.generateName "app-config-" is not supported`))
+			Expect(err.Error()).To(ContainSubstring("set metadata.name instead"))
+		})
+	})
+	Context("When a policy rule enables security hardening", func() {
+		BeforeEach(func() {
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-policy",
+					Namespace: operatorNamespace,
+				},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{
+							Kind:             "Pod",
+							Version:          "v1",
+							TargetNamespaces: []string{"default"},
+							FieldValidations: []kubetemplateriov1alpha1.FieldValidation{
+								{
+									Name: "no-privileged",
+									Type: kubetemplateriov1alpha1.FieldValidationTypeSecurityHardening,
+								},
+							},
+						},
+					},
+				},
+			}
+			Expect(validator.Client.Create(ctx, policy)).To(Succeed())
+		})
+
+		newPod := func(securityContext string) *kubetemplateriov1alpha1.KubeTemplate {
+			return &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-template",
+					Namespace: "default",
+				},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{
+							Object: runtime.RawExtension{
+								Raw: []byte(`{"apiVersion":"v1","kind":"Pod","metadata":{"name":"app"},` +
+									`"spec":{"containers":[{"name":"app","image":"nginx:1.27","securityContext":` + securityContext + `}]}}`),
+							},
+						},
+					},
+				},
+			}
+		}
+
+		It("Should report every privileged setting", func() {
+			_, err := validator.ValidateCreate(ctx, newPod(`{"runAsUser":0,"capabilities":{"add":["NET_ADMIN"]}}`))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("2 privileged settings on Pod/app"))
+			Expect(err.Error()).To(ContainSubstring("spec.containers[0].securityContext.runAsUser is 0 (root)"))
+			Expect(err.Error()).To(ContainSubstring("spec.containers[0].securityContext.capabilities.add adds [NET_ADMIN]"))
+		})
+
+		It("Should reject privileged containers and hostPath volumes", func() {
+			kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-template",
+					Namespace: "default",
+				},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{
+							Object: runtime.RawExtension{
+								Raw: []byte(`{"apiVersion":"v1","kind":"Pod","metadata":{"name":"app"},"spec":{` +
+									`"containers":[{"name":"app","image":"nginx:1.27","securityContext":{"privileged":true,"allowPrivilegeEscalation":true}}],` +
+									`"volumes":[{"name":"host","hostPath":{"path":"/var/run"}}]}}`),
+							},
+						},
+					},
+				},
+			}
+
+			_, err := validator.ValidateCreate(ctx, kubeTemplate)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("3 privileged settings"))
+			Expect(err.Error()).To(ContainSubstring("privileged is true"))
+			Expect(err.Error()).To(ContainSubstring("allowPrivilegeEscalation is true"))
+			Expect(err.Error()).To(ContainSubstring("spec.volumes[0] (host) mounts a hostPath"))
+		})
+
+		It("Should accept a hardened pod", func() {
+			_, err := validator.ValidateCreate(ctx, newPod(`{"runAsUser":1000,"allowPrivilegeEscalation":false,"capabilities":{"drop":["ALL"]}}`))
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+	Context("When a policy rule has a conditional requirement", func() {
+		BeforeEach(func() {
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-policy",
+					Namespace: operatorNamespace,
+				},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{
+							Kind:             "Service",
+							Version:          "v1",
+							TargetNamespaces: []string{"default"},
+							FieldValidations: []kubetemplateriov1alpha1.FieldValidation{
+								{
+									Name:              "load-balancer-needs-class",
+									Type:              kubetemplateriov1alpha1.FieldValidationTypeConditionalRequired,
+									WhenFieldPath:     "spec.type",
+									WhenEquals:        "LoadBalancer",
+									RequiredFieldPath: "spec.loadBalancerClass",
+								},
+							},
+						},
+					},
+				},
+			}
+			Expect(validator.Client.Create(ctx, policy)).To(Succeed())
+		})
+
+		newService := func(spec string) *kubetemplateriov1alpha1.KubeTemplate {
+			return &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-template",
+					Namespace: "default",
+				},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{
+							Object: runtime.RawExtension{
+								Raw: []byte(`{"apiVersion":"v1","kind":"Service","metadata":{"name":"web"},"spec":` + spec + `}`),
+							},
+						},
+					},
+				},
+			}
+		}
+
+		It("Should reject the template when the condition holds and the field is missing", func() {
+			_, err := validator.ValidateCreate(ctx, newService(`{"type":"LoadBalancer","ports":[{"port":80}]}`))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(`field spec.loadBalancerClass is required when spec.type is "LoadBalancer", but is missing or empty on Service/web`))
+		})
+
+		It("Should accept the template when the condition holds and the field is set", func() {
+			_, err := validator.ValidateCreate(ctx, newService(`{"type":"LoadBalancer","loadBalancerClass":"internal"}`))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should accept the template when the condition does not hold", func() {
+			_, err := validator.ValidateCreate(ctx, newService(`{"type":"ClusterIP"}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = validator.ValidateCreate(ctx, newService(`{"ports":[{"port":80}]}`))
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("When the policy sets a template budget", func() {
+		BeforeEach(func() {
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-policy",
+					Namespace: operatorNamespace,
+				},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					MaxTemplates:    3,
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{
+							Kind:             "ConfigMap",
+							Group:            "",
+							Version:          "v1",
+							TargetNamespaces: []string{"default"},
+						},
+					},
+				},
+			}
+			Expect(validator.Client.Create(ctx, policy)).To(Succeed())
+		})
+
+		configMapTemplates := func(count int) *kubetemplateriov1alpha1.KubeTemplate {
+			kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-template",
+					Namespace: "default",
+				},
+			}
+			for i := 0; i < count; i++ {
+				kubeTemplate.Spec.Templates = append(kubeTemplate.Spec.Templates, kubetemplateriov1alpha1.Template{
+					Object: runtime.RawExtension{
+						Raw: []byte(fmt.Sprintf(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"config-%d"}}`, i)),
+					},
+				})
+			}
+			return kubeTemplate
+		}
+
+		It("Should report the remaining budget", func() {
+			warnings, err := validator.ValidateCreate(ctx, configMapTemplates(2))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ContainElement("using 2 of 3 allowed templates (policy test-policy)"))
+		})
+
+		It("Should reject a KubeTemplate over the budget", func() {
+			_, err := validator.ValidateCreate(ctx, configMapTemplates(4))
+			Expect(err).To(MatchError("too many templates: attempted 4, but only 3 are allowed"))
+		})
+	})
+
+	Context("When target namespaces must carry a label", func() {
+		BeforeEach(func() {
+			scheme := runtime.NewScheme()
+			Expect(kubetemplateriov1alpha1.AddToScheme(scheme)).To(Succeed())
+			Expect(corev1.AddToScheme(scheme)).To(Succeed())
+
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-policy",
+					Namespace: operatorNamespace,
+				},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{
+							Kind:             "ConfigMap",
+							Group:            "",
+							Version:          "v1",
+							TargetNamespaces: []string{"labeled", "unlabeled"},
+						},
+					},
+				},
+			}
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(
+					policy,
+					&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "labeled", Labels: map[string]string{"kubetemplater.io/managed": "true"}}},
+					&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "unlabeled"}},
+				).
+				WithIndex(&kubetemplateriov1alpha1.KubeTemplatePolicy{}, "spec.sourceNamespace", func(obj client.Object) []string {
+					return []string{obj.(*kubetemplateriov1alpha1.KubeTemplatePolicy).Spec.SourceNamespace}
+				}).
+				Build()
+
+			validator.Client = fakeClient
+			validator.Cache = cache.NewPolicyCache(fakeClient, 0)
+			validator.RequiredNamespaceLabel = "kubetemplater.io/managed=true"
+		})
+
+		configMapIn := func(namespace string) *kubetemplateriov1alpha1.KubeTemplate {
+			return &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-template",
+					Namespace: "default",
+				},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{
+							Object: runtime.RawExtension{
+								Raw: []byte(fmt.Sprintf(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"app-config","namespace":%q}}`, namespace)),
+							},
+						},
+					},
+				},
+			}
+		}
+
+		It("Should accept a labeled target namespace without warnings", func() {
+			warnings, err := validator.ValidateCreate(ctx, configMapIn("labeled"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("Should warn about a target namespace missing the label", func() {
+			warnings, err := validator.ValidateCreate(ctx, configMapIn("unlabeled"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ContainElement("template[0]: target namespace unlabeled is missing the required label kubetemplater.io/managed=true, the operator may not manage resources in it"))
+		})
+
+		It("Should reject a target namespace missing the label when configured to", func() {
+			validator.RejectUnlabeledNamespaces = true
+
+			_, err := validator.ValidateCreate(ctx, configMapIn("unlabeled"))
+			Expect(err).To(MatchError("template[0]: target namespace unlabeled is missing the required label kubetemplater.io/managed=true"))
+		})
+
+		It("Should flag a label with the wrong value", func() {
+			validator.RequiredNamespaceLabel = "kubetemplater.io/managed=enabled"
+			validator.RejectUnlabeledNamespaces = true
+
+			_, err := validator.ValidateCreate(ctx, configMapIn("labeled"))
+			Expect(err).To(MatchError(ContainSubstring("target namespace labeled has label kubetemplater.io/managed=true, but kubetemplater.io/managed=enabled is required")))
+		})
+	})
+
+	Context("When a policy rule selects target namespaces by label", func() {
+		BeforeEach(func() {
+			scheme := runtime.NewScheme()
+			Expect(kubetemplateriov1alpha1.AddToScheme(scheme)).To(Succeed())
+			Expect(corev1.AddToScheme(scheme)).To(Succeed())
+
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-policy",
+					Namespace: operatorNamespace,
+				},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{
+							Kind:                    "ConfigMap",
+							Group:                   "",
+							Version:                 "v1",
+							TargetNamespaces:        []string{"shared"},
+							TargetNamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "payments"}},
+						},
+					},
+				},
+			}
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(
+					policy,
+					&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "payments-eu", Labels: map[string]string{"team": "payments"}}},
+					&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "search", Labels: map[string]string{"team": "search"}}},
+				).
+				WithIndex(&kubetemplateriov1alpha1.KubeTemplatePolicy{}, "spec.sourceNamespace", func(obj client.Object) []string {
+					return []string{obj.(*kubetemplateriov1alpha1.KubeTemplatePolicy).Spec.SourceNamespace}
+				}).
+				Build()
+
+			validator.Client = fakeClient
+			validator.Cache = cache.NewPolicyCache(fakeClient, 0)
+		})
+
+		configMapIn := func(namespace string) *kubetemplateriov1alpha1.KubeTemplate {
+			return &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-template",
+					Namespace: "default",
+				},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{
+							Object: runtime.RawExtension{
+								Raw: []byte(fmt.Sprintf(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"app-config","namespace":%q}}`, namespace)),
+							},
+						},
+					},
+				},
+			}
+		}
+
+		It("Should accept a namespace matching the selector", func() {
+			warnings, err := validator.ValidateCreate(ctx, configMapIn("payments-eu"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("Should accept a listed namespace that doesn't match the selector", func() {
+			_, err := validator.ValidateCreate(ctx, configMapIn("shared"))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should reject a namespace matching neither", func() {
+			_, err := validator.ValidateCreate(ctx, configMapIn("search"))
+			Expect(err).To(MatchError("template[0]: resource namespace search is not in the allowed target namespaces [shared] or matching team=payments for resource type /v1, Kind=ConfigMap"))
+		})
+
+		It("Should warn about a target namespace that doesn't exist yet", func() {
+			warnings, err := validator.ValidateCreate(ctx, configMapIn("payments-us"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ContainElement("template[0]: target namespace payments-us does not exist yet, so it could not be matched against the targetNamespaceSelector of policy test-policy"))
+		})
+	})
+
+	Context("When an audit log is configured", func() {
+		var buf *bytes.Buffer
+
+		BeforeEach(func() {
+			buf = &bytes.Buffer{}
+			validator.Audit = audit.NewLogger(buf)
+
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: operatorNamespace},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{Kind: "ConfigMap", Group: "", Version: "v1", TargetNamespaces: []string{"default"}},
+					},
+				},
+			}
+			Expect(validator.Client.Create(ctx, policy)).To(Succeed())
+		})
+
+		newTemplate := func(targetNamespace string) *kubetemplateriov1alpha1.KubeTemplate {
+			return &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default"},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{Object: runtime.RawExtension{Raw: []byte(fmt.Sprintf(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"test-cm","namespace":"%s"}}`, targetNamespace))}},
+					},
+				},
+			}
+		}
+
+		lastRecord := func() audit.Record {
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			var r audit.Record
+			Expect(json.Unmarshal([]byte(lines[len(lines)-1]), &r)).To(Succeed())
+			return r
+		}
+
+		It("Should record admitted resources with the requesting user", func() {
+			reqCtx := admission.NewContextWithRequest(ctx, admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				UserInfo: authenticationv1.UserInfo{Username: "alice"},
+			}})
+
+			_, err := validator.ValidateCreate(reqCtx, newTemplate("default"))
+			Expect(err).NotTo(HaveOccurred())
+
+			r := lastRecord()
+			Expect(r.Source).To(Equal(audit.SourceWebhook))
+			Expect(r.User).To(Equal("alice"))
+			Expect(r.Template).To(Equal("default/test-template"))
+			Expect(r.Kind).To(Equal("ConfigMap"))
+			Expect(r.Name).To(Equal("test-cm"))
+			Expect(r.Decision).To(Equal(audit.DecisionAdmitted))
+			Expect(r.Reason).To(BeEmpty())
+		})
+
+		It("Should record denied resources with the reason", func() {
+			_, err := validator.ValidateCreate(ctx, newTemplate("other"))
+			Expect(err).To(HaveOccurred())
+
+			r := lastRecord()
+			Expect(r.Namespace).To(Equal("other"))
+			Expect(r.Decision).To(Equal(audit.DecisionDenied))
+			Expect(r.Reason).To(Equal(err.Error()))
+		})
+	})
+
+	Context("When a rule counts existing resources", func() {
+		BeforeEach(func() {
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: operatorNamespace},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{
+							Kind:             "Widget",
+							Group:            "example.com",
+							Version:          "v1",
+							TargetNamespaces: []string{"default"},
+							Rule:             "countResources('example.com/v1', 'Widget', object.metadata.namespace) < 5",
+						},
+					},
+				},
+			}
+			Expect(validator.Client.Create(ctx, policy)).To(Succeed())
+		})
+
+		// useExistingWidgets answers countResources from a cluster holding count Widgets
+		useExistingWidgets := func(count int) {
+			scheme := runtime.NewScheme()
+			gv := schema.GroupVersion{Group: "example.com", Version: "v1"}
+			scheme.AddKnownTypeWithName(gv.WithKind("Widget"), &unstructured.Unstructured{})
+			scheme.AddKnownTypeWithName(gv.WithKind("WidgetList"), &unstructured.UnstructuredList{})
+
+			builder := fake.NewClientBuilder().WithScheme(scheme)
+			for i := 1; i <= count; i++ {
+				widget := &unstructured.Unstructured{}
+				widget.SetGroupVersionKind(gv.WithKind("Widget"))
+				widget.SetNamespace("default")
+				widget.SetName(fmt.Sprintf("widget-%d", i))
+				builder = builder.WithObjects(widget)
+			}
+			validator.ResourceCounter = celquery.NewCounter(builder.Build(), celquery.DefaultQPS, celquery.DefaultBurst, celquery.DefaultTimeout)
+		}
+
+		newWidgetTemplate := func(name string) *kubetemplateriov1alpha1.KubeTemplate {
+			return &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default"},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{Object: runtime.RawExtension{Raw: []byte(fmt.Sprintf(`{"apiVersion":"example.com/v1","kind":"Widget","metadata":{"name":"%s","namespace":"default"}}`, name))}},
+					},
+				},
+			}
+		}
+
+		It("Should reject a 6th instance when 5 already exist", func() {
+			useExistingWidgets(5)
+
+			_, err := validator.ValidateCreate(ctx, newWidgetTemplate("widget-6"))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("failed CEL validation rule"))
+		})
+
+		It("Should accept a 5th instance", func() {
+			useExistingWidgets(4)
+
+			_, err := validator.ValidateCreate(ctx, newWidgetTemplate("widget-5"))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should keep accepting an instance that is already counted", func() {
+			useExistingWidgets(5)
+
+			_, err := validator.ValidateUpdate(ctx, nil, newWidgetTemplate("widget-5"))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should reject the template when resources can't be counted", func() {
+			_, err := validator.ValidateCreate(ctx, newWidgetTemplate("widget-1"))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("countResources is not available"))
+		})
+	})
+
+	Context("When a policy rule uses an unknown validation type", func() {
+		BeforeEach(func() {
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-policy",
+					Namespace: operatorNamespace,
+				},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{
+							Kind:             "ConfigMap",
+							Version:          "v1",
+							TargetNamespaces: []string{"default"},
+							FieldValidations: []kubetemplateriov1alpha1.FieldValidation{
+								{
+									Name: "future-check",
+									Type: "futureType",
+								},
+							},
+						},
+					},
+				},
+			}
+			Expect(validator.Client.Create(ctx, policy)).To(Succeed())
+		})
+
+		newConfigMap := func() *kubetemplateriov1alpha1.KubeTemplate {
+			return &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-template",
+					Namespace: "default",
+				},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{
+							Object: runtime.RawExtension{
+								Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"settings"}}`),
+							},
+						},
+					},
+				},
+			}
+		}
+
+		It("Should accept the template with a warning by default", func() {
+			warnings, err := validator.ValidateCreate(ctx, newConfigMap())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ContainElement(ContainSubstring("fieldValidation[0] (future-check): unknown validation type futureType was not checked")))
+		})
+
+		It("Should accept the template without a warning when configured to ignore", func() {
+			validator.UnknownValidationTypes = UnknownValidationTypeIgnore
+			warnings, err := validator.ValidateCreate(ctx, newConfigMap())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).NotTo(ContainElement(ContainSubstring("unknown validation type")))
+		})
+
+		It("Should reject the template when configured to fail", func() {
+			validator.UnknownValidationTypes = UnknownValidationTypeFail
+			_, err := validator.ValidateCreate(ctx, newConfigMap())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("template[0]: fieldValidation[0] (future-check): unknown validation type: futureType"))
+		})
+
+		It("Should only accept known policies", func() {
+			for _, name := range []string{"ignore", "warn", "fail"} {
+				policy, err := ParseUnknownValidationTypePolicy(name)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(policy)).To(Equal(name))
+			}
+			_, err := ParseUnknownValidationTypePolicy("reject")
+			Expect(err).To(MatchError(ContainSubstring("must be ignore, warn or fail")))
+		})
+	})
+
+	Context("When policy rules match several kinds", func() {
+		BeforeEach(func() {
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-policy",
+					Namespace: operatorNamespace,
+				},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{
+							Kind:             "*",
+							Version:          "v1",
+							TargetNamespaces: []string{"default"},
+						},
+						{
+							Kind:             "Secret",
+							Version:          "v1",
+							TargetNamespaces: []string{"secrets"},
+						},
+						{
+							Kinds:            []string{"Deployment", "StatefulSet"},
+							Group:            "apps",
+							Version:          "v1",
+							TargetNamespaces: []string{"default"},
+						},
+					},
+				},
+			}
+			Expect(validator.Client.Create(ctx, policy)).To(Succeed())
+		})
+
+		newTemplate := func(object string) *kubetemplateriov1alpha1.KubeTemplate {
+			return &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-template",
+					Namespace: "default",
+				},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{Object: runtime.RawExtension{Raw: []byte(object)}},
+					},
+				},
+			}
+		}
+
+		It("Should accept any kind of the group with a wildcard rule", func() {
+			_, err := validator.ValidateCreate(ctx, newTemplate(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"settings"}}`))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should accept the kinds of a list", func() {
+			_, err := validator.ValidateCreate(ctx, newTemplate(`{"apiVersion":"apps/v1","kind":"StatefulSet","metadata":{"name":"db"}}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = validator.ValidateCreate(ctx, newTemplate(`{"apiVersion":"apps/v1","kind":"DaemonSet","metadata":{"name":"agent"}}`))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("not allowed by policy"))
+		})
+
+		It("Should apply the target namespaces of the exact rule over the wildcard rule", func() {
+			_, err := validator.ValidateCreate(ctx, newTemplate(`{"apiVersion":"v1","kind":"Secret","metadata":{"name":"credentials"}}`))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("resource namespace default is not in the allowed target namespaces [secrets]"))
+
+			_, err = validator.ValidateCreate(ctx, newTemplate(`{"apiVersion":"v1","kind":"Secret","metadata":{"name":"credentials","namespace":"secrets"}}`))
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("When a template object sets status", func() {
+		var policy *kubetemplateriov1alpha1.KubeTemplatePolicy
+
+		BeforeEach(func() {
+			policy = &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-policy",
+					Namespace: operatorNamespace,
+				},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{
+							Kind:             "Service",
+							Version:          "v1",
+							TargetNamespaces: []string{"default"},
+						},
+					},
+				},
+			}
+		})
+
+		newService := func(applyStatus bool) *kubetemplateriov1alpha1.KubeTemplate {
+			return &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-template",
+					Namespace: "default",
+				},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{
+							Object: runtime.RawExtension{
+								Raw: []byte(`{"apiVersion":"v1","kind":"Service","metadata":{"name":"web"},"spec":{"ports":[{"port":80}]},"status":{"loadBalancer":{}}}`),
+							},
+							ApplyStatus: applyStatus,
+						},
+					},
+				},
+			}
+		}
+
+		It("Should warn that the status is dropped", func() {
+			Expect(validator.Client.Create(ctx, policy)).To(Succeed())
+
+			warnings, err := validator.ValidateCreate(ctx, newService(false))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ContainElement("template[0]: Service web sets status, which will be dropped; set applyStatus: true to apply it"))
+		})
+
+		It("Should reject the template when the policy rejects status", func() {
+			policy.Spec.RejectTemplateStatus = true
+			Expect(validator.Client.Create(ctx, policy)).To(Succeed())
+
+			_, err := validator.ValidateCreate(ctx, newService(false))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("template[0]: Service web sets status, which policy test-policy does not allow; remove it or set applyStatus: true"))
+		})
+
+		It("Should accept the status without a warning when applyStatus is set", func() {
+			policy.Spec.RejectTemplateStatus = true
+			Expect(validator.Client.Create(ctx, policy)).To(Succeed())
+
+			warnings, err := validator.ValidateCreate(ctx, newService(true))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).NotTo(ContainElement(ContainSubstring("sets status")))
+		})
+	})
+
+	Context("When a template object sets server-managed metadata", func() {
+		var policy *kubetemplateriov1alpha1.KubeTemplatePolicy
+
+		BeforeEach(func() {
+			policy = &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-policy",
+					Namespace: operatorNamespace,
+				},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{
+							Kind:             "ConfigMap",
+							Version:          "v1",
+							TargetNamespaces: []string{"default"},
+						},
+					},
+				},
+			}
+		})
+
+		newConfigMap := func(metadata string) *kubetemplateriov1alpha1.KubeTemplate {
+			return &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-template",
+					Namespace: "default",
+				},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{
+							Object: runtime.RawExtension{
+								Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"app-config"` + metadata + `},"data":{"key":"value"}}`),
+							},
+						},
+					},
+				},
+			}
+		}
+
+		It("Should warn that the metadata is dropped", func() {
+			Expect(validator.Client.Create(ctx, policy)).To(Succeed())
+
+			warnings, err := validator.ValidateCreate(ctx, newConfigMap(`,"resourceVersion":"4711","uid":"0b1c"`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ContainElement("template[0]: ConfigMap app-config sets server-managed metadata metadata.resourceVersion, metadata.uid, which will be dropped"))
+		})
+
+		It("Should reject the template when the policy rejects server-managed metadata", func() {
+			policy.Spec.RejectServerManagedMetadata = true
+			Expect(validator.Client.Create(ctx, policy)).To(Succeed())
+
+			_, err := validator.ValidateCreate(ctx, newConfigMap(`,"resourceVersion":"4711"`))
+			Expect(err).To(MatchError("template[0]: ConfigMap app-config sets server-managed metadata metadata.resourceVersion, which policy test-policy does not allow; remove it"))
+		})
+
+		It("Should ignore a null creationTimestamp", func() {
+			policy.Spec.RejectServerManagedMetadata = true
+			Expect(validator.Client.Create(ctx, policy)).To(Succeed())
+
+			warnings, err := validator.ValidateCreate(ctx, newConfigMap(`,"creationTimestamp":null`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).NotTo(ContainElement(ContainSubstring("server-managed metadata")))
+		})
+	})
+
+	Context("When a template uses parameters", func() {
+		BeforeEach(func() {
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-policy",
+					Namespace: operatorNamespace,
+				},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{
+							Kind:             "ConfigMap",
+							Version:          "v1",
+							TargetNamespaces: []string{"default"},
+							FieldValidations: []kubetemplateriov1alpha1.FieldValidation{
+								{
+									Name:      "name-prefix",
+									FieldPath: "metadata.name",
+									Type:      kubetemplateriov1alpha1.FieldValidationTypeRegex,
+									Regex:     "^prod-",
+								},
+							},
+						},
+					},
+				},
+			}
+			Expect(validator.Client.Create(ctx, policy)).To(Succeed())
+		})
+
+		newConfigMap := func(name string, params map[string]string) *kubetemplateriov1alpha1.KubeTemplate {
+			return &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-template",
+					Namespace: "default",
+				},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Parameters: params,
+					Templates: []kubetemplateriov1alpha1.Template{
+						{
+							Object: runtime.RawExtension{
+								Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"` + name + `"},"data":{"key":"value"}}`),
+							},
+						},
+					},
+				},
+			}
+		}
+
+		It("Should validate the objects with the parameters substituted", func() {
+			_, err := validator.ValidateCreate(ctx, newConfigMap("${env}-config", map[string]string{"env": "prod"}))
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = validator.ValidateCreate(ctx, newConfigMap("${env}-config", map[string]string{"env": "dev"}))
+			Expect(err).To(MatchError(ContainSubstring("name-prefix")))
+		})
+
+		It("Should reject a placeholder naming no parameter", func() {
+			_, err := validator.ValidateCreate(ctx, newConfigMap("${stage}-config", map[string]string{"env": "prod"}))
+			Expect(err).To(MatchError("template[0]: undefined parameter stage: define it in spec.parameters, or write $${ for a literal ${"))
+		})
+	})
+
+	Context("When the admission request is a dry run", func() {
+		var (
+			buf    *bytes.Buffer
+			writes int
+		)
+
+		BeforeEach(func() {
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: operatorNamespace},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{Kind: "ConfigMap", Group: "", Version: "v1", TargetNamespaces: []string{"default"}},
+					},
+				},
+			}
+			Expect(validator.Client.Create(ctx, policy)).To(Succeed())
+
+			// Count every write validation makes to the cluster
+			writes = 0
+			countWrite := func() error {
+				writes++
+				return fmt.Errorf("unexpected write during validation")
+			}
+			validator.Client = interceptor.NewClient(validator.Client.(client.WithWatch), interceptor.Funcs{
+				Create: func(context.Context, client.WithWatch, client.Object, ...client.CreateOption) error {
+					return countWrite()
+				},
+				Update: func(context.Context, client.WithWatch, client.Object, ...client.UpdateOption) error {
+					return countWrite()
+				},
+				Patch: func(context.Context, client.WithWatch, client.Object, client.Patch, ...client.PatchOption) error {
+					return countWrite()
+				},
+				Delete: func(context.Context, client.WithWatch, client.Object, ...client.DeleteOption) error {
+					return countWrite()
+				},
+			})
+
+			buf = &bytes.Buffer{}
+			validator.Audit = audit.NewLogger(buf)
+		})
+
+		dryRunContext := func() context.Context {
+			return admission.NewContextWithRequest(ctx, admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				DryRun: ptr.To(true),
+			}})
+		}
+
+		newTemplate := func(targetNamespace string) *kubetemplateriov1alpha1.KubeTemplate {
+			return &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default"},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{Object: runtime.RawExtension{Raw: []byte(fmt.Sprintf(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"test-cm","namespace":"%s"}}`, targetNamespace))}},
+					},
+				},
+			}
+		}
+
+		It("Should validate the template without writing to the cluster", func() {
+			warnings, err := validator.ValidateCreate(dryRunContext(), newTemplate("default"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf("dry run: validated against policy test-policy; the KubeTemplate was not stored and none of its resources will be applied"))
+			Expect(writes).To(BeZero())
+		})
+
+		It("Should reject an invalid template like a real request", func() {
+			warnings, err := validator.ValidateUpdate(dryRunContext(), newTemplate("default"), newTemplate("other"))
+			Expect(err).To(MatchError(ContainSubstring("resource namespace other is not in the allowed target namespaces")))
+			Expect(warnings).To(ContainElement(HavePrefix("dry run: ")))
+			Expect(writes).To(BeZero())
+		})
+
+		It("Should mark the audit record as a dry run", func() {
+			_, err := validator.ValidateCreate(dryRunContext(), newTemplate("default"))
+			Expect(err).NotTo(HaveOccurred())
+
+			var r audit.Record
+			Expect(json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &r)).To(Succeed())
+			Expect(r.Decision).To(Equal(audit.DecisionAdmitted))
+			Expect(r.DryRun).To(BeTrue())
+		})
+
+		It("Should not add the dry-run warning to a real request", func() {
+			reqCtx := admission.NewContextWithRequest(ctx, admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				DryRun: ptr.To(false),
+			}})
+
+			warnings, err := validator.ValidateCreate(reqCtx, newTemplate("default"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+			Expect(buf.String()).NotTo(ContainSubstring("dryRun"))
+		})
+	})
+
+	Context("When the policy propagates KubeTemplate labels", func() {
+		BeforeEach(func() {
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: operatorNamespace},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					PropagateLabels: []string{"team", "cost-center"},
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{Kind: "ConfigMap", Group: "", Version: "v1", TargetNamespaces: []string{"default"}},
+					},
+				},
+			}
+			Expect(validator.Client.Create(ctx, policy)).To(Succeed())
+		})
+
+		It("should warn about propagated labels the KubeTemplate doesn't set", func() {
+			kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default", Labels: map[string]string{"team": "payments"}},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"test-cm"}}`)}},
+					},
+				},
+			}
+
+			warnings, err := validator.ValidateCreate(ctx, kubeTemplate)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf("policy test-policy propagates label cost-center to the resources of the KubeTemplate, which doesn't set it"))
+		})
+	})
+
+	Context("When a policy rule requires container probes", func() {
+		BeforeEach(func() {
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-policy",
+					Namespace: operatorNamespace,
+				},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{
+							Kind:             "Deployment",
+							Group:            "apps",
+							Version:          "v1",
+							TargetNamespaces: []string{"default"},
+							FieldValidations: []kubetemplateriov1alpha1.FieldValidation{
+								{
+									Name:           "probes",
+									Type:           kubetemplateriov1alpha1.FieldValidationTypeContainerProbes,
+									RequiredProbes: []string{"readinessProbe", "livenessProbe"},
+								},
+							},
+						},
+					},
+				},
+			}
+			Expect(validator.Client.Create(ctx, policy)).To(Succeed())
+		})
+
+		newDeployment := func(podSpec string) *kubetemplateriov1alpha1.KubeTemplate {
+			return &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-template",
+					Namespace: "default",
+				},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{
+							Object: runtime.RawExtension{
+								Raw: []byte(`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"web"},"spec":{"template":{"spec":` + podSpec + `}}}`),
+							},
+						},
+					},
+				},
+			}
+		}
+
+		probe := `{"httpGet":{"path":"/healthz","port":8080}}`
+
+		It("Should accept containers that define the required probes", func() {
+			_, err := validator.ValidateCreate(ctx, newDeployment(`{"initContainers":[{"name":"migrate","image":"migrate:1.0"}],`+
+				`"containers":[{"name":"web","image":"web:1.0","readinessProbe":`+probe+`,"livenessProbe":`+probe+`}]}`))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should reject a Deployment missing a readiness probe", func() {
+			_, err := validator.ValidateCreate(ctx, newDeployment(`{"containers":[`+
+				`{"name":"web","image":"web:1.0","readinessProbe":`+probe+`,"livenessProbe":`+probe+`},`+
+				`{"name":"sidecar","image":"sidecar:1.0","livenessProbe":`+probe+`}]}`))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("template[0]: fieldValidation (probes): Deployment/web has containers missing required probes"))
+			Expect(err.Error()).To(ContainSubstring("spec.template.spec.containers[1] (sidecar) has no readinessProbe"))
+			Expect(err.Error()).NotTo(ContainSubstring("containers[0]"))
+		})
+
+		It("Should list every missing probe of a container", func() {
+			_, err := validator.ValidateCreate(ctx, newDeployment(`{"containers":[{"name":"web","image":"web:1.0"}]}`))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.template.spec.containers[0] (web) has no readinessProbe, livenessProbe"))
+		})
+	})
+
+	Context("When an update only pauses or resumes a KubeTemplate", func() {
+		// No policy exists, so any other update of the template is rejected
+		newTemplate := func(annotations map[string]string) *kubetemplateriov1alpha1.KubeTemplate {
+			return &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default", Annotations: annotations},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"test-cm"}}`)}},
+					},
+				},
+			}
+		}
+
+		It("Should allow setting and removing the pause annotation", func() {
+			oldTemplate := newTemplate(map[string]string{policy.AdmittedVersionAnnotation: "1"})
+			paused := newTemplate(map[string]string{pause.Annotation: "true"})
+
+			_, err := validator.ValidateUpdate(ctx, oldTemplate, paused)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = validator.ValidateUpdate(ctx, paused, newTemplate(nil))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should allow adding the resume annotation", func() {
+			_, err := validator.ValidateUpdate(ctx, newTemplate(nil), newTemplate(map[string]string{pause.ResumeAnnotation: "true"}))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should validate updates that change anything else", func() {
+			changed := newTemplate(map[string]string{pause.Annotation: "true", "team": "payments"})
+			_, err := validator.ValidateUpdate(ctx, newTemplate(nil), changed)
+			Expect(err).To(MatchError(ContainSubstring("no KubeTemplatePolicy found")))
+
+			changed = newTemplate(map[string]string{pause.Annotation: "true"})
+			changed.Spec.ApplyPriority = 5
+			_, err = validator.ValidateUpdate(ctx, newTemplate(nil), changed)
+			Expect(err).To(MatchError(ContainSubstring("no KubeTemplatePolicy found")))
+
+			_, err = validator.ValidateUpdate(ctx, newTemplate(nil), newTemplate(nil))
+			Expect(err).To(MatchError(ContainSubstring("no KubeTemplatePolicy found")))
+		})
+	})
+
+	Context("When the policy dry-runs applies on admission", func() {
+		var (
+			testPolicy *kubetemplateriov1alpha1.KubeTemplatePolicy
+			patches    []*client.PatchOptions
+			patchErr   func(obj client.Object) error
+		)
+
+		BeforeEach(func() {
+			patches = nil
+			patchErr = func(client.Object) error { return nil }
+			testPolicy = &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: operatorNamespace},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace:   "default",
+					DryRunOnAdmission: true,
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{Kind: "ConfigMap", Group: "", Version: "v1", TargetNamespaces: []string{"default"}},
+					},
+				},
+			}
+		})
+
+		validate := func(names ...string) (admission.Warnings, error) {
+			scheme := runtime.NewScheme()
+			Expect(kubetemplateriov1alpha1.AddToScheme(scheme)).To(Succeed())
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(testPolicy).
+				WithIndex(&kubetemplateriov1alpha1.KubeTemplatePolicy{}, "spec.sourceNamespace", func(obj client.Object) []string {
+					return []string{obj.(*kubetemplateriov1alpha1.KubeTemplatePolicy).Spec.SourceNamespace}
+				}).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+						Expect(patch.Type()).To(Equal(client.Apply.Type()))
+						patchOpts := &client.PatchOptions{}
+						patchOpts.ApplyOptions(opts)
+						patches = append(patches, patchOpts)
+						return patchErr(obj)
+					},
+				}).
+				Build()
+			validator.Client = fakeClient
+			validator.Cache = cache.NewPolicyCache(fakeClient, 0)
+
+			kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default"},
+			}
+			for _, name := range names {
+				kubeTemplate.Spec.Templates = append(kubeTemplate.Spec.Templates, kubetemplateriov1alpha1.Template{
+					Object: runtime.RawExtension{Raw: []byte(fmt.Sprintf(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":%q},"data":{"key":"value"}}`, name))},
+				})
+			}
+			return validator.ValidateCreate(ctx, kubeTemplate)
+		}
+
+		conflict := func(name string) error {
+			return apierrors.NewApplyConflict(nil, fmt.Sprintf(`Apply failed with 1 conflict: conflict with "other-controller": .data.key (ConfigMap %s)`, name))
+		}
+
+		It("Should dry-run every object with the operator's field manager", func() {
+			validator.FieldManager = "platform-operator"
+
+			warnings, err := validate("first", "second")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+			Expect(patches).To(HaveLen(2))
+			for _, opts := range patches {
+				Expect(opts.DryRun).To(Equal([]string{metav1.DryRunAll}))
+				Expect(opts.FieldManager).To(Equal("platform-operator"))
+				Expect(opts.Force).To(BeNil())
+			}
+		})
+
+		It("Should warn about a conflicting object without rejecting the template", func() {
+			patchErr = func(obj client.Object) error {
+				if obj.GetName() == "contested" {
+					return conflict(obj.GetName())
+				}
+				return nil
+			}
+
+			warnings, err := validate("free", "contested")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(HaveLen(1))
+			Expect(warnings[0]).To(HavePrefix("template[1]: applying ConfigMap contested as field manager kubetemplater conflicts with another field manager"))
+			Expect(warnings[0]).To(ContainSubstring(`conflict with "other-controller": .data.key`))
+		})
+
+		It("Should ignore dry-run failures other than conflicts", func() {
+			patchErr = func(obj client.Object) error {
+				return apierrors.NewNotFound(schema.GroupResource{Resource: "namespaces"}, "default")
+			}
+
+			warnings, err := validate("first")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("Should not dry-run templates that fail validation", func() {
+			testPolicy.Spec.ValidationRules[0].FieldValidations = []kubetemplateriov1alpha1.FieldValidation{
+				{Name: "forbid-data", Type: kubetemplateriov1alpha1.FieldValidationTypeForbidden, FieldPath: "data"},
+			}
+
+			_, err := validate("first")
+			Expect(err).To(HaveOccurred())
+			Expect(patches).To(BeEmpty())
+		})
+
+		It("Should not dry-run without the policy flag", func() {
+			testPolicy.Spec.DryRunOnAdmission = false
+
+			_, err := validate("first")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(patches).To(BeEmpty())
+		})
+	})
+
+	Context("When checking workloads against cluster capacity", func() {
+		BeforeEach(func() {
+			scheme := runtime.NewScheme()
+			Expect(kubetemplateriov1alpha1.AddToScheme(scheme)).To(Succeed())
+			Expect(corev1.AddToScheme(scheme)).To(Succeed())
+
+			node := func(name string, unschedulable bool) *corev1.Node {
+				return &corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{Name: name},
+					Spec:       corev1.NodeSpec{Unschedulable: unschedulable},
+					Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("4"),
+						corev1.ResourceMemory: resource.MustParse("16Gi"),
+					}},
+				}
+			}
+			testPolicy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: operatorNamespace},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{Kinds: []string{"Deployment", "DaemonSet"}, Group: "apps", Version: "v1", TargetNamespaces: []string{"default"}},
+						{Kind: "Job", Group: "batch", Version: "v1", TargetNamespaces: []string{"default"}},
+					},
+				},
+			}
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(testPolicy, node("node-a", false), node("node-b", false), node("node-c", false), node("cordoned", true)).
+				WithIndex(&kubetemplateriov1alpha1.KubeTemplatePolicy{}, "spec.sourceNamespace", func(obj client.Object) []string {
+					return []string{obj.(*kubetemplateriov1alpha1.KubeTemplatePolicy).Spec.SourceNamespace}
+				}).
+				Build()
+
+			validator.Client = fakeClient
+			validator.Cache = cache.NewPolicyCache(fakeClient, 0)
+			validator.CheckClusterCapacity = true
+		})
+
+		workload := func(kind, apiVersion, spec string) kubetemplateriov1alpha1.Template {
+			return kubetemplateriov1alpha1.Template{Object: runtime.RawExtension{
+				Raw: []byte(fmt.Sprintf(`{"apiVersion":%q,"kind":%q,"metadata":{"name":"app"},"spec":%s}`, apiVersion, kind, spec)),
+			}}
+		}
+		deployment := func(replicas int, cpu, memory string) kubetemplateriov1alpha1.Template {
+			return workload("Deployment", "apps/v1", fmt.Sprintf(`{"replicas":%d,"template":{"spec":{"containers":[{"name":"app","image":"app:1","resources":{"requests":{"cpu":%q,"memory":%q}}}]}}}`, replicas, cpu, memory))
+		}
+		validate := func(templates ...kubetemplateriov1alpha1.Template) admission.Warnings {
+			warnings, err := validator.ValidateCreate(ctx, &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default"},
+				Spec:       kubetemplateriov1alpha1.KubeTemplateSpec{Templates: templates},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			return warnings
+		}
+
+		It("Should not warn about workloads that fit", func() {
+			Expect(validate(deployment(3, "2", "4Gi"))).To(BeEmpty())
+		})
+
+		It("Should warn when the workloads request more than the schedulable nodes can allocate", func() {
+			warnings := validate(deployment(10, "2", "1Gi"))
+			Expect(warnings).To(ConsistOf("the workloads of the KubeTemplate request 20 cpu in total, more than the 12 allocatable on all 3 schedulable nodes; this check is advisory"))
+		})
+
+		It("Should sum the requests of every workload of the KubeTemplate", func() {
+			job := workload("Job", "batch/v1", `{"parallelism":4,"template":{"spec":{"containers":[{"name":"batch","image":"batch:1","resources":{"limits":{"memory":"8Gi"}}}]}}}`)
+			warnings := validate(deployment(2, "1", "10Gi"), job)
+			Expect(warnings).To(ConsistOf("the workloads of the KubeTemplate request 52Gi memory in total, more than the 48Gi allocatable on all 3 schedulable nodes; this check is advisory"))
+		})
+
+		It("Should warn about a pod larger than any node", func() {
+			initHeavy := workload("Deployment", "apps/v1", `{"replicas":1,"template":{"spec":{
+				"initContainers":[{"name":"migrate","image":"migrate:1","resources":{"requests":{"cpu":"6"}}}],
+				"containers":[{"name":"app","image":"app:1","resources":{"requests":{"cpu":"500m"}}}]}}}`)
+			warnings := validate(initHeavy)
+			Expect(warnings).To(ConsistOf("template[0]: each pod of Deployment app requests 6 cpu, more than any node can allocate (largest: 4), so its pods cannot be scheduled"))
+		})
+
+		It("Should skip DaemonSets and workloads without requests", func() {
+			daemonSet := workload("DaemonSet", "apps/v1", `{"template":{"spec":{"containers":[{"name":"agent","image":"agent:1","resources":{"requests":{"cpu":"100"}}}]}}}`)
+			noRequests := workload("Deployment", "apps/v1", `{"replicas":100,"template":{"spec":{"containers":[{"name":"app","image":"app:1"}]}}}`)
+			Expect(validate(daemonSet, noRequests)).To(BeEmpty())
+		})
+
+		It("Should not check capacity unless enabled", func() {
+			validator.CheckClusterCapacity = false
+			Expect(validate(deployment(10, "2", "1Gi"))).To(BeEmpty())
+		})
+	})
+
+	Context("When a policy rule restricts Ingress hosts to allowed domains", func() {
+		BeforeEach(func() {
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-policy",
+					Namespace: operatorNamespace,
+				},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{
+							Kind:             "Ingress",
+							Group:            "networking.k8s.io",
+							Version:          "v1",
+							TargetNamespaces: []string{"default"},
+							FieldValidations: []kubetemplateriov1alpha1.FieldValidation{
+								{
+									Name:           "corp-hosts",
+									Type:           kubetemplateriov1alpha1.FieldValidationTypeAllowedDomains,
+									AllowedDomains: []string{"apps.corp.example.com"},
+								},
+							},
+						},
+					},
+				},
+			}
+			Expect(validator.Client.Create(ctx, policy)).To(Succeed())
+		})
+
+		newIngress := func(spec string) *kubetemplateriov1alpha1.KubeTemplate {
+			return &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-template",
+					Namespace: "default",
+				},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{
+							Object: runtime.RawExtension{
+								Raw: []byte(`{"apiVersion":"networking.k8s.io/v1","kind":"Ingress","metadata":{"name":"web"},"spec":` + spec + `}`),
+							},
+						},
+					},
+				},
+			}
+		}
+
+		It("Should accept hosts in a subdomain of an allowed domain", func() {
+			_, err := validator.ValidateCreate(ctx, newIngress(`{"rules":[{"host":"shop.team-a.apps.corp.example.com"}],`+
+				`"tls":[{"hosts":["shop.team-a.apps.corp.example.com","*.apps.corp.example.com"],"secretName":"web-tls"}]}`))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should reject a host outside the allowed domains", func() {
+			_, err := validator.ValidateCreate(ctx, newIngress(`{"rules":[{"host":"shop.apps.corp.example.com"},{"host":"shop.example.org"}]}`))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("template[0]: fieldValidation (corp-hosts): Ingress/web has hosts outside the allowed domains apps.corp.example.com"))
+			Expect(err.Error()).To(ContainSubstring("spec.rules[1].host shop.example.org"))
+			Expect(err.Error()).NotTo(ContainSubstring("spec.rules[0]"))
+		})
+
+		It("Should check TLS hosts and only match whole domain labels", func() {
+			_, err := validator.ValidateCreate(ctx, newIngress(`{"rules":[{"host":"web.apps.corp.example.com"}],`+
+				`"tls":[{"hosts":["web.evilapps.corp.example.com"]}]}`))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.tls[0].hosts[0] web.evilapps.corp.example.com"))
+		})
+
+		It("Should reject a rule without a host", func() {
+			_, err := validator.ValidateCreate(ctx, newIngress(`{"rules":[{"http":{"paths":[]}}]}`))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.rules[0].host is not set, so the rule matches every host"))
+		})
+	})
+
+	Context("When validating against a policy offline", func() {
+		var (
+			offline *KubeTemplateValidator
+			policy  *kubetemplateriov1alpha1.KubeTemplatePolicy
+		)
+
+		BeforeEach(func() {
+			offline = &KubeTemplateValidator{
+				RequiredNamespaceLabel: "kubetemplater.io/managed",
+				CheckClusterCapacity:   true,
+			}
+			policy = &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: operatorNamespace},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace:   "default",
+					DryRunOnAdmission: true,
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{
+							Kind:                    "ConfigMap",
+							Version:                 "v1",
+							TargetNamespaces:        []string{"default"},
+							TargetNamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "payments"}},
+							FieldValidations: []kubetemplateriov1alpha1.FieldValidation{
+								{Name: "team-label", FieldPath: "metadata.labels.team", Type: kubetemplateriov1alpha1.FieldValidationTypeRequired},
+							},
+						},
+					},
+				},
+			}
+		})
+
+		configMapIn := func(namespace, labels string) *kubetemplateriov1alpha1.KubeTemplate {
+			return &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default"},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{
+							Object: runtime.RawExtension{
+								Raw: []byte(fmt.Sprintf(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"app-config","namespace":%q,"labels":%s}}`, namespace, labels)),
+							},
+						},
+					},
+				},
+			}
+		}
+
+		It("Should accept a valid template without a client", func() {
+			warnings, err := offline.ValidateAgainstPolicy(ctx, configMapIn("default", `{"team":"payments"}`), policy)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("Should run the field validations of the policy", func() {
+			_, err := offline.ValidateAgainstPolicy(ctx, configMapIn("default", `{}`), policy)
+			Expect(err).To(MatchError(ContainSubstring("fieldValidation (team-label)")))
+		})
+
+		It("Should warn about a namespace only the selector could allow", func() {
+			warnings, err := offline.ValidateAgainstPolicy(ctx, configMapIn("payments-eu", `{"team":"payments"}`), policy)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf("template[0]: target namespace payments-eu could not be matched against the targetNamespaceSelector of policy test-policy without a cluster"))
+		})
+
+		It("Should skip rules counting resources with a warning", func() {
+			policy.Spec.ValidationRules[0].Rule = `countResources("v1", "ConfigMap", "default") < 10`
+			policy.Spec.ValidationRules[0].FieldValidations = append(policy.Spec.ValidationRules[0].FieldValidations,
+				kubetemplateriov1alpha1.FieldValidation{
+					Name: "quota",
+					Type: kubetemplateriov1alpha1.FieldValidationTypeCEL,
+					CEL:  `countResources("v1", "ConfigMap", object.metadata.namespace) < 10`,
+				},
+				kubetemplateriov1alpha1.FieldValidation{
+					Name:      "team",
+					Type:      kubetemplateriov1alpha1.FieldValidationTypeCEL,
+					FieldPath: "metadata.labels.team",
+					CEL:       `value == "payments"`,
+				})
+
+			warnings, err := offline.ValidateAgainstPolicy(ctx, configMapIn("default", `{"team":"payments"}`), policy)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(
+				"template[0]: the rule of policy test-policy for /v1, Kind=ConfigMap calls countResources, which needs a cluster, and was not checked",
+				"template[0]: fieldValidation[1] (quota): calls countResources, which needs a cluster, and was not checked",
+			))
+
+			_, err = offline.ValidateAgainstPolicy(ctx, configMapIn("default", `{"team":"search"}`), policy)
+			Expect(err).To(MatchError(ContainSubstring("fieldValidation (team)")))
+		})
+
+		It("Should match the webhook's decision", func() {
+			matchedPolicy := policy.DeepCopy()
+			matchedPolicy.Spec.DryRunOnAdmission = false
+			matchedPolicy.Spec.ValidationRules[0].TargetNamespaceSelector = nil
+			Expect(validator.Client.Create(ctx, matchedPolicy)).To(Succeed())
+
+			for _, labels := range []string{`{"team":"payments"}`, `{}`} {
+				kubeTemplate := configMapIn("default", labels)
+				_, webhookErr := validator.ValidateCreate(ctx, kubeTemplate)
+				_, offlineErr := (&KubeTemplateValidator{}).ValidateAgainstPolicy(ctx, kubeTemplate, matchedPolicy)
+				if webhookErr == nil {
+					Expect(offlineErr).NotTo(HaveOccurred())
+				} else {
+					Expect(offlineErr).To(MatchError(webhookErr.Error()))
+				}
+			}
+		})
+	})
 })
 
 // Helper function to create int64 pointers
@@ -0,0 +1,251 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	"github.com/lpeano/KubeTemplater/internal/celquery"
+	"github.com/lpeano/KubeTemplater/internal/manifest"
+	"github.com/lpeano/KubeTemplater/internal/policy"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// +kubebuilder:webhook:path=/validate-kubetemplater-io-v1alpha1-kubetemplatepolicy,mutating=false,failurePolicy=fail,sideEffects=None,groups=kubetemplater.io,resources=kubetemplatepolicies,verbs=create;update,versions=v1alpha1,name=vkubetemplatepolicy.kb.io,admissionReviewVersions=v1
+
+// KubeTemplatePolicyValidator validates KubeTemplatePolicy resources, so that mistakes in
+// their rules are reported when the policy is written rather than when templates use it
+type KubeTemplatePolicyValidator struct{}
+
+var _ webhook.CustomValidator = &KubeTemplatePolicyValidator{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (v *KubeTemplatePolicyValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	policy, ok := obj.(*kubetemplateriov1alpha1.KubeTemplatePolicy)
+	if !ok {
+		return nil, fmt.Errorf("expected a KubeTemplatePolicy but got a %T", obj)
+	}
+
+	logf.FromContext(ctx).Info("Validating KubeTemplatePolicy", "name", policy.Name, "namespace", policy.Namespace)
+	return nil, validatePolicy(policy)
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (v *KubeTemplatePolicyValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	policy, ok := newObj.(*kubetemplateriov1alpha1.KubeTemplatePolicy)
+	if !ok {
+		return nil, fmt.Errorf("expected a KubeTemplatePolicy but got a %T", newObj)
+	}
+
+	logf.FromContext(ctx).Info("Validating KubeTemplatePolicy update", "name", policy.Name, "namespace", policy.Namespace)
+	return nil, validatePolicy(policy)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (v *KubeTemplatePolicyValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	// No validation needed on delete
+	return nil, nil
+}
+
+// validatePolicy compiles every CEL expression of the policy against the variable it will be
+// evaluated with, checks that rules name a kind no earlier rule names and a valid namespace
+// selector, that field validation names are unique within a rule and conditional requirements,
+// enums and allowed domains are complete, and reports every problem
+func validatePolicy(kubeTemplatePolicy *kubetemplateriov1alpha1.KubeTemplatePolicy) error {
+	var problems []string
+
+	if name := kubeTemplatePolicy.Spec.FieldManager; name != "" {
+		if err := manifest.ValidateFieldManagerName(name); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	for i, key := range kubeTemplatePolicy.Spec.PropagateLabels {
+		if err := manifest.ValidatePropagatedLabel(key); err != nil {
+			problems = append(problems, fmt.Sprintf("propagateLabels[%d]: %v", i, err))
+		}
+	}
+
+	// Of two rules for the same kind only the first is ever used, see policy.FindRule
+	ruleFor := make(map[schema.GroupVersionKind]int)
+	for i, rule := range kubeTemplatePolicy.Spec.ValidationRules {
+		if rule.Kind == "" && len(rule.Kinds) == 0 {
+			problems = append(problems, fmt.Sprintf("validationRules[%d]: kind or kinds is required", i))
+		}
+		for j, kind := range rule.Kinds {
+			if kind == "" {
+				problems = append(problems, fmt.Sprintf("validationRules[%d].kinds[%d]: kind must not be empty", i, j))
+			}
+		}
+		for _, kind := range append([]string{rule.Kind}, rule.Kinds...) {
+			if kind == "" {
+				continue
+			}
+			gvk := schema.GroupVersionKind{Group: policy.NormalizeGroup(rule.Group), Version: rule.Version, Kind: kind}
+			if first, seen := ruleFor[gvk]; !seen {
+				ruleFor[gvk] = i
+			} else if first != i {
+				problems = append(problems, fmt.Sprintf("validationRules[%d]: %s %s is already matched by validationRules[%d]", i, gvk.GroupVersion(), kind, first))
+			}
+		}
+
+		if rule.TargetNamespaceSelector != nil {
+			if _, err := metav1.LabelSelectorAsSelector(rule.TargetNamespaceSelector); err != nil {
+				problems = append(problems, fmt.Sprintf("validationRules[%d].targetNamespaceSelector: %v", i, err))
+			}
+		}
+
+		validationNamed := make(map[string]int, len(rule.FieldValidations))
+		for j, validation := range rule.FieldValidations {
+			if first, seen := validationNamed[validation.Name]; seen {
+				problems = append(problems, fmt.Sprintf("validationRules[%d].fieldValidations[%d] (%s): name is already used by fieldValidations[%d]", i, j, validation.Name, first))
+				continue
+			}
+			validationNamed[validation.Name] = j
+		}
+
+		if rule.Rule != "" {
+			if err := checkCELExpression(rule.Rule, "object"); err != nil {
+				problems = append(problems, fmt.Sprintf("validationRules[%d].rule: %v", i, err))
+			}
+		}
+
+		for j, validation := range rule.FieldValidations {
+			if err := checkFieldPaths(validation); err != nil {
+				problems = append(problems, fmt.Sprintf("validationRules[%d].fieldValidations[%d] (%s): %v", i, j, validation.Name, err))
+				continue
+			}
+			if validation.Type == kubetemplateriov1alpha1.FieldValidationTypeConditionalRequired &&
+				(validation.WhenFieldPath == "" || validation.RequiredFieldPath == "") {
+				problems = append(problems, fmt.Sprintf("validationRules[%d].fieldValidations[%d] (%s): whenFieldPath and requiredFieldPath are required for type 'conditionalRequired'", i, j, validation.Name))
+				continue
+			}
+			if validation.Type == kubetemplateriov1alpha1.FieldValidationTypeEnum && len(validation.AllowedValues) == 0 {
+				problems = append(problems, fmt.Sprintf("validationRules[%d].fieldValidations[%d] (%s): allowedValues is required for type 'enum'", i, j, validation.Name))
+				continue
+			}
+			if validation.Type == kubetemplateriov1alpha1.FieldValidationTypeLength && validation.MinLength == nil && validation.MaxLength == nil {
+				problems = append(problems, fmt.Sprintf("validationRules[%d].fieldValidations[%d] (%s): minLength or maxLength is required for type 'length'", i, j, validation.Name))
+				continue
+			}
+			if validation.Type == kubetemplateriov1alpha1.FieldValidationTypeAllowedDomains && len(validation.AllowedDomains) == 0 {
+				problems = append(problems, fmt.Sprintf("validationRules[%d].fieldValidations[%d] (%s): allowedDomains is required for type 'allowedDomains'", i, j, validation.Name))
+				continue
+			}
+			if validation.Type == kubetemplateriov1alpha1.FieldValidationTypeContainerProbes && len(validation.RequiredProbes) == 0 {
+				problems = append(problems, fmt.Sprintf("validationRules[%d].fieldValidations[%d] (%s): requiredProbes is required for type 'containerProbes'", i, j, validation.Name))
+				continue
+			}
+			if validation.Type != kubetemplateriov1alpha1.FieldValidationTypeCEL || validation.CEL == "" {
+				continue
+			}
+			if err := checkCELExpression(validation.CEL, celVariableFor(validation.FieldPath)); err != nil {
+				problems = append(problems, fmt.Sprintf("validationRules[%d].fieldValidations[%d] (%s): %v", i, j, validation.Name, err))
+			}
+		}
+	}
+
+	switch len(problems) {
+	case 0:
+		return nil
+	case 1:
+		return fmt.Errorf("invalid KubeTemplatePolicy %s: %s", kubeTemplatePolicy.Name, problems[0])
+	}
+	return fmt.Errorf("invalid KubeTemplatePolicy %s, %d problems:\n  - %s", kubeTemplatePolicy.Name, len(problems), strings.Join(problems, "\n  - "))
+}
+
+// checkCELExpression parses and type-checks expr with the variables and functions of varName declared.
+// When the expression would only compile with another variable set, the error says so, since
+// using "value" in an object-level rule (or countResources in a field-level one) is the usual mistake.
+func checkCELExpression(expr, varName string) error {
+	env, err := newCELEnvForCheck(varName)
+	if err != nil {
+		return fmt.Errorf("failed to create CEL environment: %w", err)
+	}
+
+	parsed, issues := env.Parse(expr)
+	if issues != nil && issues.Err() != nil {
+		return fmt.Errorf("failed to parse CEL expression: %w", issues.Err())
+	}
+
+	if _, issues := env.Check(parsed); issues != nil && issues.Err() != nil {
+		if varName == "value" {
+			if queryEnv, err := newCELEnv(varName, celquery.Declarations()); err == nil {
+				if _, queryIssues := queryEnv.Check(parsed); queryIssues == nil || queryIssues.Err() == nil {
+					return fmt.Errorf("CEL expression calls %s, which is only available when fieldPath is empty or \"object\"", celquery.CountResourcesFunction)
+				}
+			}
+		} else if valueEnv, err := newCELEnvForCheck("value"); err == nil {
+			if _, valueIssues := valueEnv.Check(parsed); valueIssues == nil || valueIssues.Err() == nil {
+				return fmt.Errorf("CEL expression references 'value' but only 'object' is available when fieldPath is empty or \"object\"")
+			}
+		}
+		return fmt.Errorf("failed to check CEL expression: %w", issues.Err())
+	}
+
+	return nil
+}
+
+// newCELEnvForCheck creates the CEL environment of varName with the functions rules using it
+// may call declared, but not answered
+func newCELEnvForCheck(varName string) (*cel.Env, error) {
+	if varName == "value" {
+		return newCELEnv(varName)
+	}
+	return newCELEnv(varName, celquery.Declarations())
+}
+
+// checkReadyCondition checks that a waitForReady condition compiles in the environment the
+// worker evaluates it in, which has the live object but no countResources
+func checkReadyCondition(condition string) error {
+	env, err := newCELEnv("object")
+	if err != nil {
+		return fmt.Errorf("failed to create CEL environment: %w", err)
+	}
+
+	parsed, issues := env.Parse(condition)
+	if issues != nil && issues.Err() != nil {
+		return fmt.Errorf("failed to parse CEL expression: %w", issues.Err())
+	}
+
+	if _, issues := env.Check(parsed); issues != nil && issues.Err() != nil {
+		if queryEnv, err := newCELEnvForCheck("object"); err == nil {
+			if _, queryIssues := queryEnv.Check(parsed); queryIssues == nil || queryIssues.Err() == nil {
+				return fmt.Errorf("CEL expression calls %s, which waitForReady conditions can't use", celquery.CountResourcesFunction)
+			}
+		}
+		return fmt.Errorf("failed to check CEL expression: %w", issues.Err())
+	}
+	return nil
+}
+
+// SetupWebhookWithManager registers the webhook with the manager
+func (v *KubeTemplatePolicyValidator) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&kubetemplateriov1alpha1.KubeTemplatePolicy{}).
+		WithValidator(v).
+		Complete()
+}
@@ -0,0 +1,306 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	"github.com/lpeano/KubeTemplater/internal/manifest"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("KubeTemplatePolicy Webhook", func() {
+	var (
+		validator *KubeTemplatePolicyValidator
+		ctx       context.Context
+	)
+
+	BeforeEach(func() {
+		validator = &KubeTemplatePolicyValidator{}
+		ctx = context.Background()
+	})
+
+	newPolicy := func(rule kubetemplateriov1alpha1.ValidationRule) *kubetemplateriov1alpha1.KubeTemplatePolicy {
+		rule.Kind = "ConfigMap"
+		rule.Version = "v1"
+		return &kubetemplateriov1alpha1.KubeTemplatePolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: "kubetemplater-system"},
+			Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+				SourceNamespace: "default",
+				ValidationRules: []kubetemplateriov1alpha1.ValidationRule{rule},
+			},
+		}
+	}
+
+	It("should accept rules that use the variable of their scope", func() {
+		policy := newPolicy(kubetemplateriov1alpha1.ValidationRule{
+			Rule: "object.metadata.name != ''",
+			FieldValidations: []kubetemplateriov1alpha1.FieldValidation{
+				{
+					Name:      "name-prefix-check",
+					FieldPath: "metadata.name",
+					Type:      kubetemplateriov1alpha1.FieldValidationTypeCEL,
+					CEL:       "value.startsWith('prod-')",
+				},
+				{
+					Name: "whole-object-check",
+					Type: kubetemplateriov1alpha1.FieldValidationTypeCEL,
+					CEL:  "has(object.data)",
+				},
+			},
+		})
+
+		_, err := validator.ValidateCreate(ctx, policy)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should accept a field-level rule that compares value to object", func() {
+		policy := newPolicy(kubetemplateriov1alpha1.ValidationRule{
+			FieldValidations: []kubetemplateriov1alpha1.FieldValidation{
+				{
+					Name:      "replicas-within-max",
+					FieldPath: "spec.replicas",
+					Type:      kubetemplateriov1alpha1.FieldValidationTypeCEL,
+					CEL:       "value <= object.spec.maxReplicas",
+				},
+			},
+		})
+
+		_, err := validator.ValidateCreate(ctx, policy)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should reject an object-level rule that references value", func() {
+		policy := newPolicy(kubetemplateriov1alpha1.ValidationRule{
+			Rule: "value.metadata.name != ''",
+		})
+
+		_, err := validator.ValidateUpdate(ctx, nil, policy)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("validationRules[0].rule"))
+		Expect(err.Error()).To(ContainSubstring("references 'value'"))
+	})
+
+	It("should report every invalid expression", func() {
+		policy := newPolicy(kubetemplateriov1alpha1.ValidationRule{
+			Rule: "undefinedVar == 1",
+			FieldValidations: []kubetemplateriov1alpha1.FieldValidation{
+				{
+					Name:      "broken",
+					FieldPath: "metadata.name",
+					Type:      kubetemplateriov1alpha1.FieldValidationTypeCEL,
+					CEL:       "value.startsWith(",
+				},
+			},
+		})
+
+		_, err := validator.ValidateCreate(ctx, policy)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("2 problems"))
+		Expect(err.Error()).To(ContainSubstring("failed to check CEL expression"))
+		Expect(err.Error()).To(ContainSubstring("failed to parse CEL expression"))
+	})
+
+	It("should reject an incomplete conditional requirement", func() {
+		policy := newPolicy(kubetemplateriov1alpha1.ValidationRule{
+			FieldValidations: []kubetemplateriov1alpha1.FieldValidation{
+				{
+					Name:          "needs-target",
+					Type:          kubetemplateriov1alpha1.FieldValidationTypeConditionalRequired,
+					WhenFieldPath: "spec.type",
+					WhenEquals:    "LoadBalancer",
+				},
+			},
+		})
+
+		_, err := validator.ValidateCreate(ctx, policy)
+		Expect(err).To(MatchError(ContainSubstring("whenFieldPath and requiredFieldPath are required")))
+	})
+
+	It("should reject a malformed field path", func() {
+		policy := newPolicy(kubetemplateriov1alpha1.ValidationRule{
+			FieldValidations: []kubetemplateriov1alpha1.FieldValidation{
+				{
+					Name:      "images",
+					FieldPath: "spec.containers[first].image",
+					Type:      kubetemplateriov1alpha1.FieldValidationTypeRegex,
+					Regex:     "^registry/",
+				},
+			},
+		})
+
+		_, err := validator.ValidateCreate(ctx, policy)
+		Expect(err).To(MatchError(ContainSubstring("validationRules[0].fieldValidations[0] (images): field path spec.containers[first].image selects list elements with [first], use an index or *")))
+	})
+
+	It("should reject an enum validation without allowed values", func() {
+		policy := newPolicy(kubetemplateriov1alpha1.ValidationRule{
+			FieldValidations: []kubetemplateriov1alpha1.FieldValidation{
+				{
+					Name:      "service-type",
+					FieldPath: "spec.type",
+					Type:      kubetemplateriov1alpha1.FieldValidationTypeEnum,
+				},
+			},
+		})
+
+		_, err := validator.ValidateCreate(ctx, policy)
+		Expect(err).To(MatchError(ContainSubstring("validationRules[0].fieldValidations[0] (service-type): allowedValues is required")))
+	})
+
+	It("should reject a length validation without bounds", func() {
+		policy := newPolicy(kubetemplateriov1alpha1.ValidationRule{
+			FieldValidations: []kubetemplateriov1alpha1.FieldValidation{
+				{
+					Name:      "name-length",
+					FieldPath: "metadata.name",
+					Type:      kubetemplateriov1alpha1.FieldValidationTypeLength,
+				},
+			},
+		})
+
+		_, err := validator.ValidateCreate(ctx, policy)
+		Expect(err).To(MatchError(ContainSubstring("validationRules[0].fieldValidations[0] (name-length): minLength or maxLength is required")))
+	})
+
+	It("should reject an allowedDomains validation without domains", func() {
+		policy := newPolicy(kubetemplateriov1alpha1.ValidationRule{
+			FieldValidations: []kubetemplateriov1alpha1.FieldValidation{
+				{
+					Name: "corp-hosts",
+					Type: kubetemplateriov1alpha1.FieldValidationTypeAllowedDomains,
+				},
+			},
+		})
+
+		_, err := validator.ValidateCreate(ctx, policy)
+		Expect(err).To(MatchError(ContainSubstring("validationRules[0].fieldValidations[0] (corp-hosts): allowedDomains is required")))
+	})
+
+	It("should reject a containerProbes validation without probes", func() {
+		policy := newPolicy(kubetemplateriov1alpha1.ValidationRule{
+			FieldValidations: []kubetemplateriov1alpha1.FieldValidation{
+				{
+					Name: "probes",
+					Type: kubetemplateriov1alpha1.FieldValidationTypeContainerProbes,
+				},
+			},
+		})
+
+		_, err := validator.ValidateCreate(ctx, policy)
+		Expect(err).To(MatchError(ContainSubstring("validationRules[0].fieldValidations[0] (probes): requiredProbes is required")))
+	})
+
+	It("should reject field validations sharing a name within a rule", func() {
+		policy := newPolicy(kubetemplateriov1alpha1.ValidationRule{
+			FieldValidations: []kubetemplateriov1alpha1.FieldValidation{
+				{
+					Name:      "name-check",
+					FieldPath: "metadata.name",
+					Type:      kubetemplateriov1alpha1.FieldValidationTypeRegex,
+					Regex:     "^prod-",
+				},
+				{
+					Name:      "name-check",
+					FieldPath: "metadata.name",
+					Type:      kubetemplateriov1alpha1.FieldValidationTypeCEL,
+					CEL:       "size(value) <= 20",
+				},
+			},
+		})
+
+		_, err := validator.ValidateCreate(ctx, policy)
+		Expect(err).To(MatchError(ContainSubstring("validationRules[0].fieldValidations[1] (name-check): name is already used by fieldValidations[0]")))
+	})
+
+	It("should accept field validations sharing a name across rules", func() {
+		policy := newPolicy(kubetemplateriov1alpha1.ValidationRule{
+			FieldValidations: []kubetemplateriov1alpha1.FieldValidation{
+				{Name: "name-check", FieldPath: "metadata.name", Type: kubetemplateriov1alpha1.FieldValidationTypeRegex, Regex: "^prod-"},
+			},
+		})
+		policy.Spec.ValidationRules = append(policy.Spec.ValidationRules, kubetemplateriov1alpha1.ValidationRule{
+			Kind:    "Secret",
+			Version: "v1",
+			FieldValidations: []kubetemplateriov1alpha1.FieldValidation{
+				{Name: "name-check", FieldPath: "metadata.name", Type: kubetemplateriov1alpha1.FieldValidationTypeRegex, Regex: "^prod-"},
+			},
+		})
+
+		_, err := validator.ValidateCreate(ctx, policy)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should reject rules for a kind an earlier rule already matches", func() {
+		policy := newPolicy(kubetemplateriov1alpha1.ValidationRule{})
+		policy.Spec.ValidationRules = append(policy.Spec.ValidationRules,
+			kubetemplateriov1alpha1.ValidationRule{Kind: "Secret", Version: "v1"},
+			kubetemplateriov1alpha1.ValidationRule{Kinds: []string{"Secret", "ConfigMap"}, Group: "core", Version: "v1"},
+			kubetemplateriov1alpha1.ValidationRule{Kind: "ConfigMap", Version: "v2"},
+		)
+
+		_, err := validator.ValidateCreate(ctx, policy)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("2 problems"))
+		Expect(err.Error()).To(ContainSubstring("validationRules[2]: v1 Secret is already matched by validationRules[1]"))
+		Expect(err.Error()).To(ContainSubstring("validationRules[2]: v1 ConfigMap is already matched by validationRules[0]"))
+	})
+
+	It("should reject an invalid target namespace selector", func() {
+		policy := newPolicy(kubetemplateriov1alpha1.ValidationRule{
+			TargetNamespaceSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "team", Operator: metav1.LabelSelectorOpIn},
+			}},
+		})
+
+		_, err := validator.ValidateCreate(ctx, policy)
+		Expect(err).To(MatchError(ContainSubstring("validationRules[0].targetNamespaceSelector: ")))
+	})
+
+	It("should reject an invalid field manager", func() {
+		policy := newPolicy(kubetemplateriov1alpha1.ValidationRule{})
+		policy.Spec.FieldManager = "team a"
+
+		_, err := validator.ValidateCreate(ctx, policy)
+		Expect(err).To(MatchError(ContainSubstring(`fieldManager "team a" must start with a letter or digit`)))
+	})
+
+	It("should reject propagated labels that aren't valid label keys", func() {
+		policy := newPolicy(kubetemplateriov1alpha1.ValidationRule{})
+		policy.Spec.PropagateLabels = []string{"team", "cost center", manifest.TemplateNameLabel}
+
+		_, err := validator.ValidateCreate(ctx, policy)
+		Expect(err).To(MatchError(ContainSubstring(`propagateLabels[1]: invalid label key "cost center"`)))
+		Expect(err).To(MatchError(ContainSubstring("propagateLabels[2]: label " + manifest.TemplateNameLabel + " is set by the operator")))
+		Expect(err).NotTo(MatchError(ContainSubstring("propagateLabels[0]")))
+	})
+
+	It("should reject a rule without a kind", func() {
+		policy := newPolicy(kubetemplateriov1alpha1.ValidationRule{})
+		policy.Spec.ValidationRules[0].Kind = ""
+
+		_, err := validator.ValidateCreate(ctx, policy)
+		Expect(err).To(MatchError(ContainSubstring("validationRules[0]: kind or kinds is required")))
+
+		policy.Spec.ValidationRules[0].Kinds = []string{"ConfigMap", ""}
+		_, err = validator.ValidateCreate(ctx, policy)
+		Expect(err).To(MatchError(ContainSubstring("validationRules[0].kinds[1]: kind must not be empty")))
+	})
+})
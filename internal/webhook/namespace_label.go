@@ -0,0 +1,51 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// missingNamespaceLabel reports why namespace does not carry the RequiredNamespaceLabel, or ""
+// when it does. The label is given as "key" (any value) or "key=value". Namespaces that don't
+// exist yet are not flagged: the apply fails on its own in that case.
+func (v *KubeTemplateValidator) missingNamespaceLabel(ctx context.Context, namespace string) (string, error) {
+	key, value, hasValue := strings.Cut(v.RequiredNamespaceLabel, "=")
+
+	var ns corev1.Namespace
+	if err := v.Client.Get(ctx, types.NamespacedName{Name: namespace}, &ns); err != nil {
+		if errors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get namespace %s: %w", namespace, err)
+	}
+
+	actual, found := ns.Labels[key]
+	switch {
+	case !found:
+		return fmt.Sprintf("target namespace %s is missing the required label %s", namespace, v.RequiredNamespaceLabel), nil
+	case hasValue && actual != value:
+		return fmt.Sprintf("target namespace %s has label %s=%s, but %s is required", namespace, key, actual, v.RequiredNamespaceLabel), nil
+	}
+	return "", nil
+}
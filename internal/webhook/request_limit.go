@@ -0,0 +1,144 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// DefaultMaxRequestBytes is the default size limit of admission requests. An update of a KubeTemplate
+// close to etcd's 1.5MiB object limit carries two copies of it, the object and the old object.
+const DefaultMaxRequestBytes = 4 * 1024 * 1024
+
+// uidPrefixBytes is the part of an oversized request read to find its UID
+const uidPrefixBytes = 4096
+
+// LimitRequestSize returns a webhook server whose webhooks reject admission requests with a body
+// larger than limit bytes before it is decoded. A limit of 0 or less returns server unchanged, which
+// leaves controller-runtime's own limit of 7MiB.
+func LimitRequestSize(server webhook.Server, limit int64) webhook.Server {
+	if limit <= 0 {
+		return server
+	}
+	return &limitedServer{Server: server, limit: limit}
+}
+
+// limitedServer wraps every webhook registered with it in a request size limit
+type limitedServer struct {
+	webhook.Server
+	limit int64
+}
+
+// Register implements webhook.Server
+func (s *limitedServer) Register(path string, hook http.Handler) {
+	s.Server.Register(path, &limitedHandler{next: hook, limit: s.limit})
+}
+
+// limitedHandler rejects requests whose body exceeds limit bytes, and passes the others on
+type limitedHandler struct {
+	next  http.Handler
+	limit int64
+}
+
+// ServeHTTP implements http.Handler
+func (h *limitedHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Body == nil {
+		h.next.ServeHTTP(w, r)
+		return
+	}
+	// A body of a declared length beyond the limit is only read as far as needed to find its UID;
+	// any other body is read up to one byte past the limit
+	if r.ContentLength > h.limit {
+		prefix, _ := io.ReadAll(io.LimitReader(r.Body, uidPrefixBytes))
+		h.reject(w, r, prefix, fmt.Sprintf("%d bytes", r.ContentLength))
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, h.limit+1))
+	if err != nil {
+		logf.FromContext(r.Context()).Error(err, "Unable to read the body of the admission request")
+		writeAdmissionReview(w, "", admission.Errored(http.StatusBadRequest, err))
+		return
+	}
+	if int64(len(body)) > h.limit {
+		h.reject(w, r, body, fmt.Sprintf("more than %d bytes", h.limit))
+		return
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	h.next.ServeHTTP(w, r)
+}
+
+// reject denies the request of the given size. The UID is taken from the start of the body, if read,
+// since the API server only accepts a response carrying the UID of its request.
+func (h *limitedHandler) reject(w http.ResponseWriter, r *http.Request, body []byte, size string) {
+	err := fmt.Errorf("admission request of %s exceeds the webhook's limit of %d bytes, reduce the size of the resource", size, h.limit)
+	logf.FromContext(r.Context()).Info("Rejecting oversized admission request", "path", r.URL.Path, "size", size, "limit", h.limit)
+	writeAdmissionReview(w, requestUID(body), admission.Errored(http.StatusRequestEntityTooLarge, err))
+}
+
+// writeAdmissionReview writes response as a v1 AdmissionReview answering the request with uid
+func writeAdmissionReview(w http.ResponseWriter, uid types.UID, response admission.Response) {
+	response.UID = uid
+	review := admissionv1.AdmissionReview{Response: &response.AdmissionResponse}
+	review.SetGroupVersionKind(admissionv1.SchemeGroupVersion.WithKind("AdmissionReview"))
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(review)
+}
+
+// requestUID returns request.uid of a possibly truncated AdmissionReview, reading its JSON tokens until
+// the UID is found. The API server writes the UID first, so the object that follows is not decoded.
+func requestUID(body []byte) types.UID {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	if !findKey(decoder, "request") || !findKey(decoder, "uid") {
+		return ""
+	}
+	var uid string
+	if err := decoder.Decode(&uid); err != nil {
+		return ""
+	}
+	return types.UID(uid)
+}
+
+// findKey enters the JSON object at the decoder's position and skips its values until key
+func findKey(decoder *json.Decoder, key string) bool {
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return false
+	}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return false
+		}
+		if token == key {
+			return true
+		}
+		var skipped json.RawMessage
+		if err := decoder.Decode(&skipped); err != nil {
+			return false
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+var _ = Describe("Admission request size limit", func() {
+	const limit = 1024
+
+	var (
+		mux      *http.ServeMux
+		received []byte
+		served   bool
+	)
+
+	BeforeEach(func() {
+		received, served = nil, false
+		mux = http.NewServeMux()
+		server := LimitRequestSize(webhook.NewServer(webhook.Options{WebhookMux: mux}), limit)
+		server.Register("/validate", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			served = true
+			received, _ = io.ReadAll(r.Body)
+		}))
+	})
+
+	// admissionReview returns an AdmissionReview request whose object holds size bytes of data
+	admissionReview := func(size int) string {
+		return `{"kind":"AdmissionReview","apiVersion":"admission.k8s.io/v1","request":{"uid":"4f1b7a5e-request",` +
+			`"kind":{"group":"kubetemplater.io","version":"v1alpha1","kind":"KubeTemplate"},` +
+			`"object":{"data":"` + strings.Repeat("x", size) + `"}}}`
+	}
+
+	// send posts body, hiding its length when chunked, and returns the response
+	send := func(body string, chunked bool) *httptest.ResponseRecorder {
+		var reader io.Reader = strings.NewReader(body)
+		if chunked {
+			reader = io.MultiReader(reader)
+		}
+		request := httptest.NewRequest(http.MethodPost, "/validate", reader)
+		request.Header.Set("Content-Type", "application/json")
+		if chunked {
+			request.ContentLength = -1
+		}
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	response := func(recorder *httptest.ResponseRecorder) *admissionv1.AdmissionResponse {
+		review := admissionv1.AdmissionReview{}
+		Expect(json.Unmarshal(recorder.Body.Bytes(), &review)).To(Succeed())
+		Expect(review.Kind).To(Equal("AdmissionReview"))
+		Expect(review.Response).NotTo(BeNil())
+		return review.Response
+	}
+
+	It("should pass requests within the limit on with their body", func() {
+		body := admissionReview(100)
+		send(body, false)
+
+		Expect(served).To(BeTrue())
+		Expect(string(received)).To(Equal(body))
+	})
+
+	It("should reject a request declaring a length beyond the limit", func() {
+		recorder := send(admissionReview(2*limit), false)
+
+		Expect(served).To(BeFalse())
+		denied := response(recorder)
+		Expect(denied.Allowed).To(BeFalse())
+		Expect(denied.UID).To(BeEquivalentTo("4f1b7a5e-request"))
+		Expect(denied.Result.Code).To(BeEquivalentTo(http.StatusRequestEntityTooLarge))
+		Expect(denied.Result.Message).To(MatchRegexp(`admission request of \d+ bytes exceeds the webhook's limit of 1024 bytes`))
+	})
+
+	It("should reject a request of unknown length once it exceeds the limit", func() {
+		recorder := send(admissionReview(2*limit), true)
+
+		Expect(served).To(BeFalse())
+		denied := response(recorder)
+		Expect(denied.Allowed).To(BeFalse())
+		Expect(denied.UID).To(BeEquivalentTo("4f1b7a5e-request"))
+		Expect(denied.Result.Message).To(ContainSubstring("admission request of more than 1024 bytes exceeds the webhook's limit"))
+	})
+
+	It("should leave the server unchanged without a limit", func() {
+		server := webhook.NewServer(webhook.Options{})
+		Expect(LimitRequestSize(server, 0)).To(BeIdenticalTo(server))
+	})
+
+	It("should find the UID of a truncated request", func() {
+		body := admissionReview(2 * limit)
+		Expect(requestUID([]byte(body[:200]))).To(BeEquivalentTo("4f1b7a5e-request"))
+		Expect(requestUID([]byte(`{"request":{"object":{"data":"xx`))).To(BeEmpty())
+		Expect(requestUID(nil)).To(BeEmpty())
+	})
+})
@@ -0,0 +1,138 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	openapi_v2 "github.com/google/gnostic-models/openapiv2"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/kube-openapi/pkg/util/proto"
+	"k8s.io/kube-openapi/pkg/util/proto/validation"
+)
+
+// gvkExtension is the OpenAPI vendor extension mapping a model to its GroupVersionKind
+const gvkExtension = "x-kubernetes-group-version-kind"
+
+// OpenAPISchemaSource returns the cluster's OpenAPI v2 document,
+// e.g. (*discovery.DiscoveryClient).OpenAPISchema
+type OpenAPISchemaSource func() (*openapi_v2.Document, error)
+
+// SchemaValidator validates objects against the cluster's published OpenAPI schema.
+// The parsed schema is cached and refreshed after the TTL so that newly installed
+// CRDs are picked up.
+type SchemaValidator struct {
+	source OpenAPISchemaSource
+	ttl    time.Duration
+
+	mu        sync.Mutex
+	models    map[schema.GroupVersionKind]proto.Schema
+	fetchedAt time.Time
+}
+
+// NewSchemaValidator creates a SchemaValidator reading schemas from source
+func NewSchemaValidator(source OpenAPISchemaSource, ttl time.Duration) *SchemaValidator {
+	return &SchemaValidator{
+		source: source,
+		ttl:    ttl,
+	}
+}
+
+// Validate returns the structural errors of obj with their field paths. Objects whose
+// GroupVersionKind has no published schema are not validated. The returned error is
+// set only if the schema could not be loaded.
+func (s *SchemaValidator) Validate(obj *unstructured.Unstructured) ([]error, error) {
+	models, err := s.getModels()
+	if err != nil {
+		return nil, err
+	}
+
+	gvk := obj.GroupVersionKind()
+	model, ok := models[gvk]
+	if !ok {
+		return nil, nil
+	}
+	return validation.ValidateModel(obj.Object, model, gvk.Kind), nil
+}
+
+// getModels returns the cached GVK index, refreshing it once the TTL has expired
+func (s *SchemaValidator) getModels() (map[schema.GroupVersionKind]proto.Schema, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.models != nil && time.Since(s.fetchedAt) < s.ttl {
+		return s.models, nil
+	}
+
+	doc, err := s.source()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch OpenAPI schema: %w", err)
+	}
+	parsed, err := proto.NewOpenAPIData(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI schema: %w", err)
+	}
+
+	models := make(map[schema.GroupVersionKind]proto.Schema)
+	for _, name := range parsed.ListModels() {
+		model := parsed.LookupModel(name)
+		for _, gvk := range modelGVKs(model) {
+			models[gvk] = model
+		}
+	}
+
+	s.models = models
+	s.fetchedAt = time.Now()
+	return models, nil
+}
+
+// modelGVKs reads the GroupVersionKinds a model is published for
+func modelGVKs(model proto.Schema) []schema.GroupVersionKind {
+	values, ok := model.GetExtensions()[gvkExtension].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	var gvks []schema.GroupVersionKind
+	for _, value := range values {
+		fields := map[string]string{}
+		switch v := value.(type) {
+		case map[interface{}]interface{}:
+			for k, val := range v {
+				ks, _ := k.(string)
+				vs, _ := val.(string)
+				fields[ks] = vs
+			}
+		case map[string]interface{}:
+			for k, val := range v {
+				vs, _ := val.(string)
+				fields[k] = vs
+			}
+		default:
+			continue
+		}
+		gvks = append(gvks, schema.GroupVersionKind{
+			Group:   fields["group"],
+			Version: fields["version"],
+			Kind:    fields["kind"],
+		})
+	}
+	return gvks
+}
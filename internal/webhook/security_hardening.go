@@ -0,0 +1,109 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	"github.com/lpeano/KubeTemplater/internal/policy"
+)
+
+// findPrivilegedSettings lists the privileged settings of all pod specs of the object,
+// each described with the path of the offending field
+func findPrivilegedSettings(obj *unstructured.Unstructured) []string {
+	var violations []string
+	for _, specPath := range policy.PodSpecPaths {
+		podSpec, found, err := unstructured.NestedMap(obj.Object, specPath...)
+		if err != nil || !found {
+			continue
+		}
+		prefix := strings.Join(specPath, ".")
+
+		if isRootUser(podSpec, "securityContext", "runAsUser") {
+			violations = append(violations, fmt.Sprintf("%s.securityContext.runAsUser is 0 (root)", prefix))
+		}
+
+		volumes, _, _ := unstructured.NestedSlice(podSpec, "volumes")
+		for i, v := range volumes {
+			volume, ok := v.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if _, ok := volume["hostPath"]; ok {
+				violations = append(violations, fmt.Sprintf("%s.volumes[%d] (%v) mounts a hostPath", prefix, i, volume["name"]))
+			}
+		}
+
+		for _, field := range policy.ContainerListFields {
+			containers, _, _ := unstructured.NestedSlice(podSpec, field)
+			for i, c := range containers {
+				container, ok := c.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				path := fmt.Sprintf("%s.%s[%d].securityContext", prefix, field, i)
+
+				if privileged, _, _ := unstructured.NestedBool(container, "securityContext", "privileged"); privileged {
+					violations = append(violations, path+".privileged is true")
+				}
+				if escalation, _, _ := unstructured.NestedBool(container, "securityContext", "allowPrivilegeEscalation"); escalation {
+					violations = append(violations, path+".allowPrivilegeEscalation is true")
+				}
+				if isRootUser(container, "securityContext", "runAsUser") {
+					violations = append(violations, path+".runAsUser is 0 (root)")
+				}
+				if added, _, _ := unstructured.NestedSlice(container, "securityContext", "capabilities", "add"); len(added) > 0 {
+					violations = append(violations, fmt.Sprintf("%s.capabilities.add adds %v", path, added))
+				}
+			}
+		}
+	}
+	return violations
+}
+
+// isRootUser reports whether the numeric field at fields is set to 0
+func isRootUser(obj map[string]interface{}, fields ...string) bool {
+	value, found, err := unstructured.NestedFieldNoCopy(obj, fields...)
+	if err != nil || !found {
+		return false
+	}
+	switch uid := value.(type) {
+	case int64:
+		return uid == 0
+	case float64:
+		return uid == 0
+	}
+	return false
+}
+
+// validateFieldSecurityHardening rejects resources whose pod specs use privileged settings,
+// reporting every violation in a single error
+func (v *KubeTemplateValidator) validateFieldSecurityHardening(validation kubetemplateriov1alpha1.FieldValidation, obj *unstructured.Unstructured, templateIdx int) error {
+	violations := findPrivilegedSettings(obj)
+	if len(violations) == 0 {
+		return nil
+	}
+
+	if validation.Message != "" {
+		return fmt.Errorf("template[%d]: fieldValidation (%s): %s (%s)", templateIdx, validation.Name, validation.Message, strings.Join(violations, "; "))
+	}
+	return fmt.Errorf("template[%d]: fieldValidation (%s): %d privileged settings on %s/%s: %s", templateIdx, validation.Name, len(violations), obj.GetKind(), obj.GetName(), strings.Join(violations, "; "))
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/kube-openapi/pkg/util/proto"
+)
+
+// OpenAPI vendor extensions describing how server-side apply merges a list
+const (
+	listTypeExtension      = "x-kubernetes-list-type"
+	listMapKeysExtension   = "x-kubernetes-list-map-keys"
+	patchMergeKeyExtension = "x-kubernetes-patch-merge-key"
+)
+
+// SSAAdvisories returns advice about the list fields of obj that server-side apply merges in
+// surprising ways: atomic lists of objects, which apply replaces as a whole, and entries of
+// keyed lists that set none of the keys, which can't be merged with the live entries. Objects
+// whose GroupVersionKind has no published schema get none. The returned error is set only if
+// the schema could not be loaded.
+func (s *SchemaValidator) SSAAdvisories(obj *unstructured.Unstructured) ([]string, error) {
+	models, err := s.getModels()
+	if err != nil {
+		return nil, err
+	}
+
+	model, ok := models[obj.GroupVersionKind()]
+	if !ok {
+		return nil, nil
+	}
+	var advisories []string
+	collectSSAAdvisories(model, obj.Object, "", &advisories)
+	return advisories, nil
+}
+
+// collectSSAAdvisories walks value along its schema and appends the advisories of its lists
+func collectSSAAdvisories(model proto.Schema, value interface{}, path string, advisories *[]string) {
+	switch m := resolveRef(model).(type) {
+	case *proto.Kind:
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			return
+		}
+		for _, name := range sortedKeys(fields) {
+			if fieldModel, ok := m.Fields[name]; ok {
+				collectSSAAdvisories(fieldModel, fields[name], joinFieldPath(path, name), advisories)
+			}
+		}
+	case *proto.Map:
+		entries, ok := value.(map[string]interface{})
+		if !ok {
+			return
+		}
+		for _, key := range sortedKeys(entries) {
+			collectSSAAdvisories(m.SubType, entries[key], joinFieldPath(path, key), advisories)
+		}
+	case *proto.Array:
+		items, ok := value.([]interface{})
+		if !ok || len(items) == 0 {
+			return
+		}
+		collectListAdvisories(m, items, path, advisories)
+	}
+}
+
+// collectListAdvisories appends the advisories of a list and of its entries
+func collectListAdvisories(list *proto.Array, items []interface{}, path string, advisories *[]string) {
+	if !isObjectSchema(list.SubType) {
+		return
+	}
+
+	extensions := list.GetExtensions()
+	listType, _ := extensions[listTypeExtension].(string)
+	keys := stringList(extensions[listMapKeysExtension])
+	mergeKey, _ := extensions[patchMergeKeyExtension].(string)
+
+	switch {
+	case listType == "set":
+		return
+	case listType == "atomic" || (listType == "" && mergeKey == ""):
+		// Like the API server, lists of built-in types without SSA markers are atomic. The entries
+		// are not walked: everything below an atomic list is replaced with it.
+		*advisories = append(*advisories, fmt.Sprintf("%s is an atomic list: server-side apply replaces it as a whole, "+
+			"removing entries other field managers added and the fields this template leaves out", path))
+		return
+	case len(keys) == 0 && mergeKey == "":
+		return
+	case len(keys) == 0:
+		// A patch merge key without list map keys is the key server-side apply merges by
+		keys = []string{mergeKey}
+	}
+
+	for i, item := range items {
+		entryPath := fmt.Sprintf("%s[%d]", path, i)
+		if entry, ok := item.(map[string]interface{}); ok && !setsAnyKey(entry, keys) {
+			*advisories = append(*advisories, fmt.Sprintf("%s sets none of the list keys %s: server-side apply "+
+				"can't merge it with the live entries by key", entryPath, strings.Join(keys, ", ")))
+		}
+		collectSSAAdvisories(list.SubType, item, entryPath, advisories)
+	}
+}
+
+// resolveRef follows references to the schema they point to
+func resolveRef(model proto.Schema) proto.Schema {
+	for {
+		ref, ok := model.(proto.Reference)
+		if !ok {
+			return model
+		}
+		model = ref.SubSchema()
+	}
+}
+
+// isObjectSchema reports whether model describes an object with fields or entries
+func isObjectSchema(model proto.Schema) bool {
+	switch resolveRef(model).(type) {
+	case *proto.Kind, *proto.Map:
+		return true
+	}
+	return false
+}
+
+// setsAnyKey reports whether entry sets at least one of keys
+func setsAnyKey(entry map[string]interface{}, keys []string) bool {
+	for _, key := range keys {
+		if _, ok := entry[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// stringList reads a list of strings from an OpenAPI extension value
+func stringList(value interface{}) []string {
+	values, _ := value.([]interface{})
+	var result []string
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// sortedKeys returns the keys of fields in order, so advisories are reported in a stable order
+func sortedKeys(fields map[string]interface{}) []string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// joinFieldPath appends a field to a dotted path
+func joinFieldPath(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
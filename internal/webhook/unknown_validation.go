@@ -0,0 +1,41 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import "fmt"
+
+// UnknownValidationTypePolicy decides how the webhook handles field validations of a type
+// this operator version doesn't know, e.g. in a policy written for a newer version
+type UnknownValidationTypePolicy string
+
+const (
+	// UnknownValidationTypeIgnore skips unknown field validations
+	UnknownValidationTypeIgnore UnknownValidationTypePolicy = "ignore"
+	// UnknownValidationTypeWarn skips unknown field validations with an admission warning
+	UnknownValidationTypeWarn UnknownValidationTypePolicy = "warn"
+	// UnknownValidationTypeFail rejects templates matching rules with unknown field validations
+	UnknownValidationTypeFail UnknownValidationTypePolicy = "fail"
+)
+
+// ParseUnknownValidationTypePolicy parses s, which must be "ignore", "warn" or "fail"
+func ParseUnknownValidationTypePolicy(s string) (UnknownValidationTypePolicy, error) {
+	switch policy := UnknownValidationTypePolicy(s); policy {
+	case UnknownValidationTypeIgnore, UnknownValidationTypeWarn, UnknownValidationTypeFail:
+		return policy, nil
+	}
+	return "", fmt.Errorf("invalid unknown validation type policy %q: must be ignore, warn or fail", s)
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// templateFailure holds the validation errors reported for a single template
+type templateFailure struct {
+	index  int
+	kind   string
+	name   string
+	errors []error
+}
+
+// templateValidationErrors aggregates validation failures grouped by template
+type templateValidationErrors struct {
+	failures []templateFailure
+}
+
+// add records a validation error for the template at the given index
+func (e *templateValidationErrors) add(templateIdx int, obj *unstructured.Unstructured, err error) {
+	for i := range e.failures {
+		if e.failures[i].index == templateIdx {
+			e.failures[i].errors = append(e.failures[i].errors, err)
+			return
+		}
+	}
+	e.failures = append(e.failures, templateFailure{
+		index:  templateIdx,
+		kind:   obj.GetKind(),
+		name:   obj.GetName(),
+		errors: []error{err},
+	})
+}
+
+// count returns the total number of recorded errors
+func (e *templateValidationErrors) count() int {
+	total := 0
+	for _, f := range e.failures {
+		total += len(f.errors)
+	}
+	return total
+}
+
+// err returns nil when no failures were recorded, the single error when only one
+// was recorded, and otherwise an error listing all failures grouped by template
+func (e *templateValidationErrors) err() error {
+	switch e.count() {
+	case 0:
+		return nil
+	case 1:
+		return e.failures[0].errors[0]
+	}
+	return e
+}
+
+// Error implements the error interface
+func (e *templateValidationErrors) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d validation failures in %d template(s):", e.count(), len(e.failures))
+	for _, f := range e.failures {
+		fmt.Fprintf(&b, "\ntemplate[%d] %s/%s:", f.index, f.kind, f.name)
+		for _, err := range f.errors {
+			fmt.Fprintf(&b, "\n  - %s", err.Error())
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ApplyTimeoutAnnotation bounds how long applying a single object of a KubeTemplate may take,
+// as a duration such as "30s". A timed-out apply fails the template like any other apply
+// error, and the queue retries it on its own backoff schedule.
+const ApplyTimeoutAnnotation = "kubetemplater.io/apply-timeout"
+
+// applyTimeout returns the apply timeout set on kubeTemplate (0 = none). An invalid
+// annotation is reported and ignored.
+func applyTimeout(kubeTemplate *kubetemplateriov1alpha1.KubeTemplate) (time.Duration, error) {
+	value, ok := kubeTemplate.Annotations[ApplyTimeoutAnnotation]
+	if !ok {
+		return 0, nil
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout <= 0 {
+		return 0, fmt.Errorf("invalid %s annotation %q: must be a positive duration such as 30s", ApplyTimeoutAnnotation, value)
+	}
+	return timeout, nil
+}
+
+// applyTimeoutError is returned for an apply that did not finish within the apply timeout
+type applyTimeoutError struct {
+	timeout time.Duration
+	err     error
+}
+
+func (e *applyTimeoutError) Error() string {
+	return fmt.Sprintf("apply timed out after %s: %v", e.timeout, e.err)
+}
+
+func (e *applyTimeoutError) Unwrap() error {
+	return e.err
+}
+
+// isApplyTimeout reports whether err is an apply that timed out
+func isApplyTimeout(err error) bool {
+	var timeoutErr *applyTimeoutError
+	return errors.As(err, &timeoutErr)
+}
+
+// timeoutClient bounds every patch of the wrapped client, which applies objects and their
+// status, by the apply timeout
+type timeoutClient struct {
+	client.Client
+	timeout time.Duration
+}
+
+// withApplyTimeout returns c with its patches bounded by timeout, or c itself if timeout is 0
+func withApplyTimeout(c client.Client, timeout time.Duration) client.Client {
+	if timeout == 0 {
+		return c
+	}
+	return &timeoutClient{Client: c, timeout: timeout}
+}
+
+func (c *timeoutClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	return boundByTimeout(ctx, c.timeout, func(ctx context.Context) error {
+		return c.Client.Patch(ctx, obj, patch, opts...)
+	})
+}
+
+func (c *timeoutClient) Status() client.SubResourceWriter {
+	return &timeoutStatusWriter{SubResourceWriter: c.Client.Status(), timeout: c.timeout}
+}
+
+// timeoutStatusWriter bounds status patches by the apply timeout
+type timeoutStatusWriter struct {
+	client.SubResourceWriter
+	timeout time.Duration
+}
+
+func (w *timeoutStatusWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	return boundByTimeout(ctx, w.timeout, func(ctx context.Context) error {
+		return w.SubResourceWriter.Patch(ctx, obj, patch, opts...)
+	})
+}
+
+// boundByTimeout runs call with a context that expires after timeout, and reports its error
+// as an applyTimeoutError if the timeout, not ctx, ended it
+func boundByTimeout(ctx context.Context, timeout time.Duration, call func(context.Context) error) error {
+	callCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := call(callCtx)
+	if err != nil && ctx.Err() == nil && errors.Is(callCtx.Err(), context.DeadlineExceeded) {
+		return &applyTimeoutError{timeout: timeout, err: err}
+	}
+	return err
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Apply timeout", func() {
+	Context("applyTimeout", func() {
+		timeout := func(annotation string) (time.Duration, error) {
+			kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{}
+			if annotation != "" {
+				kubeTemplate.ObjectMeta = metav1.ObjectMeta{Annotations: map[string]string{ApplyTimeoutAnnotation: annotation}}
+			}
+			return applyTimeout(kubeTemplate)
+		}
+
+		It("should parse the annotation as a duration", func() {
+			Expect(timeout("")).To(Equal(time.Duration(0)))
+			Expect(timeout("30s")).To(Equal(30 * time.Second))
+			Expect(timeout("2m")).To(Equal(2 * time.Minute))
+		})
+
+		It("should ignore an invalid annotation", func() {
+			for _, annotation := range []string{"slow", "30", "0s", "-1m"} {
+				t, err := timeout(annotation)
+				Expect(err).To(MatchError(ContainSubstring("invalid kubetemplater.io/apply-timeout annotation")), "annotation %q", annotation)
+				Expect(t).To(BeZero(), "annotation %q", annotation)
+			}
+		})
+	})
+
+	Context("boundByTimeout", func() {
+		block := func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+
+		It("should report a call ended by the timeout as timed out", func() {
+			err := boundByTimeout(context.Background(), 10*time.Millisecond, block)
+			Expect(isApplyTimeout(err)).To(BeTrue())
+			Expect(errors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+			Expect(err).To(MatchError("apply timed out after 10ms: context deadline exceeded"))
+		})
+
+		It("should not report a call ended by its caller as timed out", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			err := boundByTimeout(ctx, time.Minute, block)
+			Expect(isApplyTimeout(err)).To(BeFalse())
+			Expect(errors.Is(err, context.Canceled)).To(BeTrue())
+		})
+
+		It("should pass other errors through", func() {
+			failure := errors.New("conflict")
+			err := boundByTimeout(context.Background(), time.Minute, func(context.Context) error { return failure })
+			Expect(err).To(BeIdenticalTo(failure))
+		})
+	})
+})
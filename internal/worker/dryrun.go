@@ -0,0 +1,130 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// maxChangedFields limits the changed fields reported per resource, to keep the status small
+const maxChangedFields = 20
+
+// serverManagedFields are changed by every write and never reported as changes
+var serverManagedFields = map[string]bool{
+	"metadata.creationTimestamp": true,
+	"metadata.generation":        true,
+	"metadata.managedFields":     true,
+	"metadata.resourceVersion":   true,
+	"metadata.uid":               true,
+	"status":                     true,
+}
+
+// dryRunApply applies obj with server-side dry-run and describes what the apply would do to
+// existing, the live object (nil if it does not exist yet)
+func dryRunApply(ctx context.Context, c client.Client, obj, existing *unstructured.Unstructured, fieldManager string, replace bool) kubetemplateriov1alpha1.DryRunResult {
+	gvk := obj.GroupVersionKind()
+	result := kubetemplateriov1alpha1.DryRunResult{
+		APIVersion: gvk.GroupVersion().String(),
+		Kind:       gvk.Kind,
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+	}
+
+	if err := c.Patch(ctx, obj, client.Apply, client.FieldOwner(fieldManager), client.DryRunAll); err != nil {
+		result.Error = err.Error()
+		if errors.IsInvalid(err) && replace {
+			result.Error += " (replace is set, so the resource would be deleted and re-created)"
+		}
+		return result
+	}
+
+	if existing == nil {
+		result.Action = kubetemplateriov1alpha1.ApplyActionCreated
+		return result
+	}
+	result.ChangedFields = changedFields(existing.Object, obj.Object, "")
+	result.Action = kubetemplateriov1alpha1.ApplyActionUnchanged
+	if len(result.ChangedFields) > 0 {
+		result.Action = kubetemplateriov1alpha1.ApplyActionUpdated
+	}
+	if len(result.ChangedFields) > maxChangedFields {
+		more := len(result.ChangedFields) - maxChangedFields
+		result.ChangedFields = append(result.ChangedFields[:maxChangedFields], fmt.Sprintf("... and %d more", more))
+	}
+	return result
+}
+
+// changedFields returns the sorted paths below prefix at which before and after differ.
+// Maps are compared key by key; any other values, including lists, as a whole.
+func changedFields(before, after map[string]interface{}, prefix string) []string {
+	keys := make(map[string]bool, len(before)+len(after))
+	for key := range before {
+		keys[key] = true
+	}
+	for key := range after {
+		keys[key] = true
+	}
+
+	var changed []string
+	for key := range keys {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		if serverManagedFields[path] {
+			continue
+		}
+
+		beforeMap, beforeIsMap := before[key].(map[string]interface{})
+		afterMap, afterIsMap := after[key].(map[string]interface{})
+		if beforeIsMap && afterIsMap {
+			changed = append(changed, changedFields(beforeMap, afterMap, path)...)
+			continue
+		}
+		if !equality.Semantic.DeepEqual(before[key], after[key]) {
+			changed = append(changed, path)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// dryRunSummary describes the dry-run results in the status message of the template
+func dryRunSummary(results []kubetemplateriov1alpha1.DryRunResult) string {
+	counts := make(map[kubetemplateriov1alpha1.ApplyAction]int)
+	failed := 0
+	for _, result := range results {
+		if result.Error != "" {
+			failed++
+			continue
+		}
+		counts[result.Action]++
+	}
+	return fmt.Sprintf("Dry run completed: %d to create, %d to update, %d unchanged, %d failed",
+		counts[kubetemplateriov1alpha1.ApplyActionCreated],
+		counts[kubetemplateriov1alpha1.ApplyActionUpdated],
+		counts[kubetemplateriov1alpha1.ApplyActionUnchanged],
+		failed)
+}
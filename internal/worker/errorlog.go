@@ -0,0 +1,99 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"sync"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// maxErrorLogInterval is the largest number of identical errors between two that are logged
+const maxErrorLogInterval = 64
+
+// repeatedError counts the occurrences in a row of the same error at one log call site
+type repeatedError struct {
+	err         string
+	occurrences int
+}
+
+// ErrorLogLimiter keeps templates that fail over and over with the same error from flooding
+// the log: the 1st, 2nd, 4th, 8th, ... occurrence of an error is logged, and every 64th
+// once the interval reaches 64. A different error, or a successful run, starts over.
+// It is shared by all workers, since retries of a template may land on any of them.
+type ErrorLogLimiter struct {
+	mu     sync.Mutex
+	errors map[types.NamespacedName]map[string]*repeatedError
+}
+
+// NewErrorLogLimiter creates an ErrorLogLimiter that has seen no errors
+func NewErrorLogLimiter() *ErrorLogLimiter {
+	return &ErrorLogLimiter{errors: make(map[types.NamespacedName]map[string]*repeatedError)}
+}
+
+// Allow records an occurrence of err at the log message msg for template and reports whether
+// to log it, and how often the error occurred in a row. A nil ErrorLogLimiter allows every error.
+func (l *ErrorLogLimiter) Allow(template types.NamespacedName, msg string, err error) (bool, int) {
+	if l == nil {
+		return true, 1
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	sites, ok := l.errors[template]
+	if !ok {
+		sites = make(map[string]*repeatedError)
+		l.errors[template] = sites
+	}
+	last, ok := sites[msg]
+	if !ok || last.err != err.Error() {
+		last = &repeatedError{err: err.Error()}
+		sites[msg] = last
+	}
+	last.occurrences++
+
+	n := last.occurrences
+	if n <= maxErrorLogInterval {
+		return n&(n-1) == 0, n
+	}
+	return n%maxErrorLogInterval == 0, n
+}
+
+// Reset forgets the errors of template, once it was processed successfully
+func (l *ErrorLogLimiter) Reset(template types.NamespacedName) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.errors, template)
+}
+
+// logError logs err with msg unless template failed with the same error at msg too recently,
+// see ErrorLogLimiter. Repeated errors carry their number of occurrences.
+func (p *TemplateProcessor) logError(log logr.Logger, template types.NamespacedName, err error, msg string, keysAndValues ...interface{}) {
+	logged, occurrences := p.ErrorLog.Allow(template, msg, err)
+	if !logged {
+		return
+	}
+	if occurrences > 1 {
+		keysAndValues = append(keysAndValues, "occurrences", occurrences)
+	}
+	log.Error(err, msg, keysAndValues...)
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"context"
+	goerrors "errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr/funcr"
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	"github.com/lpeano/KubeTemplater/internal/cache"
+	"github.com/lpeano/KubeTemplater/internal/queue"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("ErrorLogLimiter", func() {
+	template := types.NamespacedName{Namespace: "default", Name: "test-template"}
+
+	// logged returns the occurrences of err out of n in a row that are logged
+	logged := func(limiter *ErrorLogLimiter, n int, err error) []int {
+		var occurrences []int
+		for i := 0; i < n; i++ {
+			if ok, occurrence := limiter.Allow(template, "Failed to process item", err); ok {
+				occurrences = append(occurrences, occurrence)
+			}
+		}
+		return occurrences
+	}
+
+	It("should log repeated identical errors at a decreasing rate", func() {
+		limiter := NewErrorLogLimiter()
+		Expect(logged(limiter, 200, goerrors.New("denied"))).To(Equal([]int{1, 2, 4, 8, 16, 32, 64, 128, 192}))
+	})
+
+	It("should start over for a different error or after a success", func() {
+		limiter := NewErrorLogLimiter()
+		Expect(logged(limiter, 3, goerrors.New("denied"))).To(Equal([]int{1, 2}))
+		Expect(logged(limiter, 1, goerrors.New("timeout"))).To(Equal([]int{1}))
+
+		limiter.Reset(template)
+		Expect(logged(limiter, 1, goerrors.New("timeout"))).To(Equal([]int{1}))
+	})
+
+	It("should count errors per template and log message", func() {
+		limiter := NewErrorLogLimiter()
+		err := goerrors.New("denied")
+		Expect(logged(limiter, 1, err)).To(Equal([]int{1}))
+
+		ok, occurrence := limiter.Allow(template, "Failed to apply object", err)
+		Expect(ok).To(BeTrue())
+		Expect(occurrence).To(Equal(1))
+		ok, occurrence = limiter.Allow(types.NamespacedName{Namespace: "default", Name: "other"}, "Failed to process item", err)
+		Expect(ok).To(BeTrue())
+		Expect(occurrence).To(Equal(1))
+	})
+
+	It("should allow every error when nil", func() {
+		var limiter *ErrorLogLimiter
+		Expect(logged(limiter, 3, goerrors.New("denied"))).To(Equal([]int{1, 1, 1}))
+		limiter.Reset(template)
+	})
+
+	It("should log a template that keeps failing to apply at a reduced rate but update its status every time", func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(kubetemplateriov1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		failedUpdates := 0
+		fakeClient := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+					return errors.NewForbidden(corev1.Resource("configmaps"), obj.GetName(), fmt.Errorf("denied"))
+				},
+				SubResourceUpdate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+					if obj.(*kubetemplateriov1alpha1.KubeTemplate).Status.ProcessingPhase == "Failed" {
+						failedUpdates++
+					}
+					return c.SubResource(subResourceName).Update(ctx, obj, opts...)
+				},
+			}).
+			WithStatusSubresource(&kubetemplateriov1alpha1.KubeTemplate{}).
+			WithIndex(&kubetemplateriov1alpha1.KubeTemplatePolicy{}, "spec.sourceNamespace", func(obj client.Object) []string {
+				return []string{obj.(*kubetemplateriov1alpha1.KubeTemplatePolicy).Spec.SourceNamespace}
+			}).
+			Build()
+
+		ctx := context.Background()
+		Expect(fakeClient.Create(ctx, &kubetemplateriov1alpha1.KubeTemplatePolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: operatorNamespace},
+			Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+				SourceNamespace: "default",
+				ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+					{Kind: "ConfigMap", Version: "v1", TargetNamespaces: []string{"default"}},
+				},
+			},
+		})).To(Succeed())
+		Expect(fakeClient.Create(ctx, &kubetemplateriov1alpha1.KubeTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: template.Name, Namespace: template.Namespace},
+			Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+				Templates: []kubetemplateriov1alpha1.Template{{Object: runtime.RawExtension{
+					Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"forbidden"}}`),
+				}}},
+			},
+		})).To(Succeed())
+
+		var errorLogs []string
+		ctx = logf.IntoContext(ctx, funcr.New(func(prefix, args string) {
+			errorLogs = append(errorLogs, args)
+		}, funcr.Options{}))
+
+		processor := &TemplateProcessor{
+			Client:            fakeClient,
+			Cache:             cache.NewPolicyCache(fakeClient, 0),
+			Queue:             queue.NewWorkQueue(),
+			Recorder:          record.NewFakeRecorder(100),
+			OperatorNamespace: operatorNamespace,
+			ErrorLog:          NewErrorLogLimiter(),
+		}
+		for i := 0; i < 10; i++ {
+			Expect(processor.processItem(ctx, &queue.WorkItem{NamespacedName: template})).To(HaveOccurred())
+		}
+
+		var applyErrors []string
+		for _, line := range errorLogs {
+			if strings.Contains(line, `"msg"="Failed to apply object"`) {
+				applyErrors = append(applyErrors, line)
+			}
+		}
+		Expect(applyErrors).To(HaveLen(4))
+		Expect(applyErrors[3]).To(ContainSubstring(`"occurrences"=8`))
+		Expect(failedUpdates).To(Equal(10))
+	})
+})
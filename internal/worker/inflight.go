@@ -0,0 +1,103 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lpeano/KubeTemplater/internal/metrics"
+	"github.com/lpeano/KubeTemplater/internal/queue"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// InFlightPath is the path of the debug endpoint listing the templates being processed
+const InFlightPath = "/debug/processing"
+
+// InFlightItem describes a KubeTemplate a worker is currently processing
+type InFlightItem struct {
+	Namespace  string    `json:"namespace"`
+	Name       string    `json:"name"`
+	WorkerID   int       `json:"workerID"`
+	RetryCount int       `json:"retryCount"`
+	Verify     bool      `json:"verify,omitempty"`
+	StartedAt  time.Time `json:"startedAt"`
+}
+
+// InFlightTracker records the work items the worker pool is processing right now.
+// It is an http.Handler serving the list as JSON.
+type InFlightTracker struct {
+	mu    sync.Mutex
+	items map[types.NamespacedName]InFlightItem
+}
+
+// NewInFlightTracker creates an empty InFlightTracker
+func NewInFlightTracker() *InFlightTracker {
+	return &InFlightTracker{items: make(map[types.NamespacedName]InFlightItem)}
+}
+
+// Start registers an item as being processed by the given worker
+func (t *InFlightTracker) Start(workerID int, item *queue.WorkItem) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.items[item.NamespacedName] = InFlightItem{
+		Namespace:  item.NamespacedName.Namespace,
+		Name:       item.NamespacedName.Name,
+		WorkerID:   workerID,
+		RetryCount: item.RetryCount,
+		Verify:     item.Verify,
+		StartedAt:  time.Now(),
+	}
+	metrics.ObserveProcessingStarted(item.NamespacedName.Namespace, item.NamespacedName.Name)
+}
+
+// Finish deregisters an item once its processing has ended
+func (t *InFlightTracker) Finish(item *queue.WorkItem) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.items, item.NamespacedName)
+	metrics.ObserveProcessingFinished(item.NamespacedName.Namespace, item.NamespacedName.Name)
+}
+
+// List returns the items being processed, ordered by namespace and name
+func (t *InFlightTracker) List() []InFlightItem {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	items := make([]InFlightItem, 0, len(t.items))
+	for _, item := range t.items {
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Namespace != items[j].Namespace {
+			return items[i].Namespace < items[j].Namespace
+		}
+		return items[i].Name < items[j].Name
+	})
+	return items
+}
+
+// ServeHTTP writes the items being processed as a JSON array
+func (t *InFlightTracker) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(t.List())
+}
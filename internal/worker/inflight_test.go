@@ -0,0 +1,143 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	"github.com/lpeano/KubeTemplater/internal/cache"
+	"github.com/lpeano/KubeTemplater/internal/metrics"
+	"github.com/lpeano/KubeTemplater/internal/queue"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+var _ = Describe("InFlightTracker", func() {
+	var (
+		ctx        context.Context
+		tracker    *InFlightTracker
+		processor  *TemplateProcessor
+		fakeClient client.Client
+		// seen is the tracker content observed while the template's resources were applied
+		seen []InFlightItem
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		tracker = NewInFlightTracker()
+		seen = nil
+
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(kubetemplateriov1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+					seen = tracker.List()
+					return applyAsCreateOrUpdate(ctx, c, obj, patch, opts...)
+				},
+			}).
+			WithStatusSubresource(&kubetemplateriov1alpha1.KubeTemplate{}).
+			WithIndex(&kubetemplateriov1alpha1.KubeTemplatePolicy{}, "spec.sourceNamespace", func(obj client.Object) []string {
+				return []string{obj.(*kubetemplateriov1alpha1.KubeTemplatePolicy).Spec.SourceNamespace}
+			}).
+			Build()
+
+		processor = &TemplateProcessor{
+			Client:            fakeClient,
+			Cache:             cache.NewPolicyCache(fakeClient, 0),
+			Queue:             queue.NewWorkQueue(),
+			Recorder:          record.NewFakeRecorder(10),
+			OperatorNamespace: operatorNamespace,
+			WorkerID:          3,
+		}
+		WithInFlightTracker(tracker)(processor)
+
+		policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: operatorNamespace},
+			Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+				SourceNamespace: "default",
+				ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+					{
+						Kind:             "ConfigMap",
+						Group:            "",
+						Version:          "v1",
+						TargetNamespaces: []string{"default"},
+					},
+				},
+			},
+		}
+		Expect(fakeClient.Create(ctx, policy)).To(Succeed())
+
+		kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default"},
+			Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+				Templates: []kubetemplateriov1alpha1.Template{
+					{
+						Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"app-config"},"data":{"key":"value"}}`)},
+					},
+				},
+			},
+		}
+		Expect(fakeClient.Create(ctx, kubeTemplate)).To(Succeed())
+	})
+
+	It("should list an item while it is processed and remove it on completion", func() {
+		item := &queue.WorkItem{NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-template"}}
+		Expect(processor.trackedProcessItem(ctx, item)).To(Succeed())
+
+		Expect(seen).To(HaveLen(1))
+		Expect(seen[0].Namespace).To(Equal("default"))
+		Expect(seen[0].Name).To(Equal("test-template"))
+		Expect(seen[0].WorkerID).To(Equal(3))
+
+		Expect(tracker.List()).To(BeEmpty())
+		Expect(testutil.CollectAndCount(metrics.ProcessingItemsInfo)).To(BeZero())
+	})
+
+	It("should expose in-flight items as metric series and as JSON", func() {
+		item := &queue.WorkItem{NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-template"}}
+		tracker.Start(1, item)
+		defer tracker.Finish(item)
+
+		Expect(testutil.ToFloat64(metrics.ProcessingItemsInfo.WithLabelValues("default", "test-template"))).To(Equal(1.0))
+
+		recorder := httptest.NewRecorder()
+		tracker.ServeHTTP(recorder, httptest.NewRequest("GET", InFlightPath, nil))
+		Expect(recorder.Header().Get("Content-Type")).To(Equal("application/json"))
+
+		var items []InFlightItem
+		Expect(json.Unmarshal(recorder.Body.Bytes(), &items)).To(Succeed())
+		Expect(items).To(HaveLen(1))
+		Expect(items[0].Name).To(Equal("test-template"))
+		Expect(items[0].WorkerID).To(Equal(1))
+	})
+})
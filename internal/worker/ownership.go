@@ -0,0 +1,96 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"context"
+	"strings"
+
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// maxOwnerDepth bounds the walk up the owner chain of a KubeTemplate
+const maxOwnerDepth = 10
+
+// ownershipCycle returns the cycle that making kubeTemplate an owner of obj would close, from
+// the KubeTemplate up its owner chain to obj and back, or nil if obj doesn't own it. Owners that
+// can't be read end the walk along their branch, so a cycle through them goes unnoticed.
+func (p *TemplateProcessor) ownershipCycle(ctx context.Context, kubeTemplate *kubetemplateriov1alpha1.KubeTemplate, obj *unstructured.Unstructured) []string {
+	owners := kubeTemplate.GetOwnerReferences()
+	if len(owners) == 0 {
+		return nil
+	}
+	visited := map[types.UID]bool{kubeTemplate.UID: true}
+	path := p.ownerPath(ctx, kubeTemplate.Namespace, owners, obj, 1, visited)
+	if path == nil {
+		return nil
+	}
+	self := "KubeTemplate/" + kubeTemplate.Name
+	return append(append([]string{self}, path...), self)
+}
+
+// ownerPath returns the owner chain from owners up to target, or nil if target is not among
+// the transitive owners
+func (p *TemplateProcessor) ownerPath(ctx context.Context, namespace string, owners []metav1.OwnerReference, target *unstructured.Unstructured, depth int, visited map[types.UID]bool) []string {
+	log := logf.FromContext(ctx).WithName("template-processor").WithValues("workerID", p.WorkerID)
+
+	for _, ref := range owners {
+		if refersTo(ref, target) {
+			return []string{ref.Kind + "/" + ref.Name}
+		}
+		if depth >= maxOwnerDepth || visited[ref.UID] {
+			continue
+		}
+		visited[ref.UID] = true
+
+		// Owner references can only point to resources in the same namespace, or cluster-scoped ones
+		owner := &unstructured.Unstructured{}
+		owner.SetAPIVersion(ref.APIVersion)
+		owner.SetKind(ref.Kind)
+		if err := p.Client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, owner); err != nil {
+			log.V(1).Info("Skipping owner in ownership cycle check", "kind", ref.Kind, "name", ref.Name, "reason", err.Error())
+			continue
+		}
+		if path := p.ownerPath(ctx, namespace, owner.GetOwnerReferences(), target, depth+1, visited); path != nil {
+			return append([]string{ref.Kind + "/" + ref.Name}, path...)
+		}
+	}
+	return nil
+}
+
+// refersTo reports whether ref points to obj. Versions are ignored, since the same resource
+// can be referenced through any version of its group.
+func refersTo(ref metav1.OwnerReference, obj *unstructured.Unstructured) bool {
+	if uid := obj.GetUID(); uid != "" && ref.UID == uid {
+		return true
+	}
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return false
+	}
+	return gv.Group == obj.GroupVersionKind().Group && ref.Kind == obj.GetKind() && ref.Name == obj.GetName()
+}
+
+// formatCycle describes an ownership cycle, each resource being owned by the next
+func formatCycle(cycle []string) string {
+	return strings.Join(cycle, " -> ")
+}
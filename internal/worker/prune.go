@@ -0,0 +1,106 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"context"
+	"fmt"
+
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	"github.com/lpeano/KubeTemplater/internal/audit"
+	"github.com/lpeano/KubeTemplater/internal/manifest"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// staleResources returns the resources of previous that are not in current
+func staleResources(previous, current []kubetemplateriov1alpha1.AppliedResource) []kubetemplateriov1alpha1.AppliedResource {
+	type key struct{ apiVersion, kind, namespace, name string }
+	keep := make(map[key]bool, len(current))
+	for _, r := range current {
+		keep[key{r.APIVersion, r.Kind, r.Namespace, r.Name}] = true
+	}
+
+	var stale []kubetemplateriov1alpha1.AppliedResource
+	for _, r := range previous {
+		if !keep[key{r.APIVersion, r.Kind, r.Namespace, r.Name}] {
+			stale = append(stale, r)
+		}
+	}
+	return stale
+}
+
+// ownedBy reports whether obj was applied from kubeTemplate, either by its tracking labels
+// or by an owner reference to it (templates with referenced: true)
+func ownedBy(obj *unstructured.Unstructured, kubeTemplate *kubetemplateriov1alpha1.KubeTemplate) bool {
+	labels := obj.GetLabels()
+	if labels[manifest.TemplateNameLabel] == kubeTemplate.Name && labels[manifest.TemplateNamespaceLabel] == kubeTemplate.Namespace {
+		return true
+	}
+	for _, owner := range obj.GetOwnerReferences() {
+		if owner.UID != "" && owner.UID == kubeTemplate.UID {
+			return true
+		}
+	}
+	return false
+}
+
+// pruneResources deletes the resources the template applied before but no longer contains.
+// Resources another template or a user has taken over since are left in place.
+func (p *TemplateProcessor) pruneResources(ctx context.Context, deleteClient client.Client, kubeTemplate *kubetemplateriov1alpha1.KubeTemplate, stale []kubetemplateriov1alpha1.AppliedResource) error {
+	log := logf.FromContext(ctx).WithName("template-processor").WithValues("workerID", p.WorkerID)
+
+	for _, resource := range stale {
+		gv, err := schema.ParseGroupVersion(resource.APIVersion)
+		if err != nil {
+			log.Error(err, "Skipping pruning of resource with invalid apiVersion", "apiVersion", resource.APIVersion, "name", resource.Name)
+			continue
+		}
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(gv.WithKind(resource.Kind))
+		if err := p.Client.Get(ctx, types.NamespacedName{Namespace: resource.Namespace, Name: resource.Name}, obj); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("failed to get %s %s/%s for pruning: %w", resource.Kind, resource.Namespace, resource.Name, err)
+		}
+
+		if !ownedBy(obj, kubeTemplate) {
+			log.Info("Not pruning resource that is no longer managed by the template",
+				"kind", resource.Kind, "namespace", resource.Namespace, "name", resource.Name)
+			continue
+		}
+
+		// The UID precondition keeps a resource recreated in the meantime from being deleted
+		uid := obj.GetUID()
+		if err := deleteClient.Delete(ctx, obj, client.Preconditions{UID: &uid}, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to prune %s %s/%s: %w", resource.Kind, resource.Namespace, resource.Name, err)
+		}
+
+		log.Info("Pruned resource removed from the template", "kind", resource.Kind, "namespace", resource.Namespace, "name", resource.Name)
+		p.Recorder.Event(kubeTemplate, corev1.EventTypeNormal, "ResourcePruned",
+			fmt.Sprintf("Deleted %s %s/%s, which is no longer in the template", resource.Kind, resource.Namespace, resource.Name))
+		p.Audit.Record(audit.NewRecord(audit.SourceWorker, kubeTemplate, obj, audit.DecisionPruned))
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	"github.com/lpeano/KubeTemplater/internal/conditions"
+	"github.com/lpeano/KubeTemplater/internal/queue"
+	"github.com/lpeano/KubeTemplater/internal/summary"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// ResyncKubeTemplates enqueues all templates with pending work (new, Queued or interrupted
+// while Processing) in a deterministic order: higher spec.applyPriority first, then by
+// namespace and name. It is used on startup, when the in-memory queue is empty. Templates left
+// Processing were interrupted by the restart and are marked Queued again.
+func ResyncKubeTemplates(ctx context.Context, c client.Client, q *queue.WorkQueue) (int, error) {
+	log := logf.Log.WithName("resync")
+
+	var templates kubetemplateriov1alpha1.KubeTemplateList
+	if err := c.List(ctx, &templates); err != nil {
+		return 0, fmt.Errorf("failed to list KubeTemplates: %w", err)
+	}
+
+	pending := make([]kubetemplateriov1alpha1.KubeTemplate, 0, len(templates.Items))
+	for _, kt := range templates.Items {
+		switch kt.Status.ProcessingPhase {
+		case "", "Queued", "Processing":
+			pending = append(pending, kt)
+		}
+	}
+
+	sort.SliceStable(pending, func(i, j int) bool {
+		if pending[i].Spec.ApplyPriority != pending[j].Spec.ApplyPriority {
+			return pending[i].Spec.ApplyPriority > pending[j].Spec.ApplyPriority
+		}
+		if pending[i].Namespace != pending[j].Namespace {
+			return pending[i].Namespace < pending[j].Namespace
+		}
+		return pending[i].Name < pending[j].Name
+	})
+
+	interrupted := 0
+	for i := range pending {
+		kt := &pending[i]
+		if kt.Status.ProcessingPhase == "Processing" {
+			interrupted++
+			// The template is processed regardless; on a conflict it changed since the list and
+			// its next status update settles the phase
+			if err := markRequeued(ctx, c, kt); err != nil {
+				log.Info("Failed to reset interrupted KubeTemplate to Queued", "kubetemplate", client.ObjectKeyFromObject(kt), "reason", err.Error())
+			}
+		}
+		q.Enqueue(types.NamespacedName{Namespace: kt.Namespace, Name: kt.Name}, kt.Spec.ApplyPriority)
+	}
+
+	log.Info("Resync enqueued pending KubeTemplates", "enqueued", len(pending), "interrupted", interrupted, "total", len(templates.Items))
+	return len(pending), nil
+}
+
+// markRequeued sets the phase of a KubeTemplate whose processing was interrupted back to Queued
+func markRequeued(ctx context.Context, c client.Client, kt *kubetemplateriov1alpha1.KubeTemplate) error {
+	kt.Status.ProcessingPhase = "Queued"
+	conditions.MarkPending(&kt.Status, kt.Generation, kubetemplateriov1alpha1.ReasonQueued, "Queued again, processing was interrupted by an operator restart")
+	now := metav1.Now()
+	kt.Status.QueuedAt = &now
+	conditions.SyncStatus(&kt.Status)
+	kt.Status.Summary = summary.Compute(&kt.Status)
+	return c.Status().Update(ctx, kt)
+}
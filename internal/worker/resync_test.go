@@ -0,0 +1,108 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"context"
+
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	"github.com/lpeano/KubeTemplater/internal/queue"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("ResyncKubeTemplates", func() {
+	newTemplate := func(name string, priority int, phase string) *kubetemplateriov1alpha1.KubeTemplate {
+		return &kubetemplateriov1alpha1.KubeTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       kubetemplateriov1alpha1.KubeTemplateSpec{ApplyPriority: priority},
+			Status:     kubetemplateriov1alpha1.KubeTemplateStatus{ProcessingPhase: phase},
+		}
+	}
+
+	It("should enqueue pending templates by descending applyPriority", func() {
+		scheme := runtime.NewScheme()
+		Expect(kubetemplateriov1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		objs := []client.Object{
+			newTemplate("app-a", 0, ""),
+			newTemplate("app-b", 0, "Queued"),
+			newTemplate("namespaces", 100, "Processing"),
+			newTemplate("rbac", 10, ""),
+			newTemplate("done", 1000, "Completed"),
+			newTemplate("paused", 1000, "Paused"),
+		}
+		fakeClient := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(objs...).
+			WithStatusSubresource(&kubetemplateriov1alpha1.KubeTemplate{}).
+			Build()
+
+		q := queue.NewWorkQueue()
+		defer q.Shutdown()
+
+		enqueued, err := ResyncKubeTemplates(context.Background(), fakeClient, q)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(enqueued).To(Equal(4))
+
+		var order []string
+		for q.Len() > 0 {
+			item, ok := q.Dequeue()
+			Expect(ok).To(BeTrue())
+			order = append(order, item.NamespacedName.Name)
+			q.Done(item)
+		}
+		Expect(order).To(Equal([]string{"namespaces", "rbac", "app-a", "app-b"}))
+	})
+
+	It("should reset templates interrupted while Processing to Queued", func() {
+		scheme := runtime.NewScheme()
+		Expect(kubetemplateriov1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		fakeClient := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(newTemplate("interrupted", 0, "Processing"), newTemplate("waiting", 0, "Queued")).
+			WithStatusSubresource(&kubetemplateriov1alpha1.KubeTemplate{}).
+			Build()
+
+		q := queue.NewWorkQueue()
+		defer q.Shutdown()
+
+		enqueued, err := ResyncKubeTemplates(context.Background(), fakeClient, q)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(enqueued).To(Equal(2))
+		Expect(q.Contains(types.NamespacedName{Namespace: "default", Name: "interrupted"})).To(BeTrue())
+
+		var kt kubetemplateriov1alpha1.KubeTemplate
+		Expect(fakeClient.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "interrupted"}, &kt)).To(Succeed())
+		Expect(kt.Status.ProcessingPhase).To(Equal("Queued"))
+		Expect(kt.Status.QueuedAt).NotTo(BeNil())
+		ready := meta.FindStatusCondition(kt.Status.Conditions, kubetemplateriov1alpha1.ConditionReady)
+		Expect(ready).NotTo(BeNil())
+		Expect(ready.Reason).To(Equal(kubetemplateriov1alpha1.ReasonQueued))
+		Expect(kt.Status.Status).To(Equal("Queued again, processing was interrupted by an operator restart"))
+
+		Expect(fakeClient.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "waiting"}, &kt)).To(Succeed())
+		Expect(kt.Status.QueuedAt).To(BeNil())
+	})
+})
@@ -21,13 +21,28 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	goerrors "errors"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/google/cel-go/cel"
 	"github.com/google/cel-go/checker/decls"
 	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	"github.com/lpeano/KubeTemplater/internal/audit"
 	"github.com/lpeano/KubeTemplater/internal/cache"
+	"github.com/lpeano/KubeTemplater/internal/celcache"
+	"github.com/lpeano/KubeTemplater/internal/celquery"
+	"github.com/lpeano/KubeTemplater/internal/conditions"
+	"github.com/lpeano/KubeTemplater/internal/impersonation"
+	"github.com/lpeano/KubeTemplater/internal/maintenance"
+	"github.com/lpeano/KubeTemplater/internal/manifest"
+	"github.com/lpeano/KubeTemplater/internal/metrics"
+	"github.com/lpeano/KubeTemplater/internal/pause"
+	policyutil "github.com/lpeano/KubeTemplater/internal/policy"
 	"github.com/lpeano/KubeTemplater/internal/queue"
+	"github.com/lpeano/KubeTemplater/internal/summary"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -36,7 +51,6 @@ import (
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
-	"sigs.k8s.io/yaml"
 )
 
 // TemplateProcessor processes KubeTemplate resources asynchronously
@@ -47,9 +61,222 @@ type TemplateProcessor struct {
 	Recorder          record.EventRecorder
 	OperatorNamespace string
 	WorkerID          int
+	// AllowKubeTemplaterResources lets templates create kubetemplater.io resources
+	AllowKubeTemplaterResources bool
+	// VerifyDelay schedules a post-apply check that applied resources still exist (0 = disabled)
+	VerifyDelay time.Duration
+	// ImpersonatingClients builds clients acting as a policy's ServiceAccount (nil = disabled)
+	ImpersonatingClients impersonation.ClientFactory
+	// Now returns the current time for maintenance window checks and apply throttling (nil = time.Now)
+	Now func() time.Time
+	// Sleep waits between throttled applies, returning early when the context is done (nil = a timer)
+	Sleep func(ctx context.Context, d time.Duration) error
+	// ReadyPollInterval is how often objects of templates with waitForReady are checked (0 = DefaultReadyPollInterval)
+	ReadyPollInterval time.Duration
+	// InFlight tracks the items this worker is processing (nil = not tracked)
+	InFlight *InFlightTracker
+	// Audit records every apply decision (nil = disabled)
+	Audit *audit.Logger
+	// ResourceCounter answers countResources calls of CEL rules (nil = every call fails)
+	ResourceCounter *celquery.Counter
+	// ErrorLog rate-limits the logging of errors a template keeps failing with (nil = log every error)
+	ErrorLog *ErrorLogLimiter
+	// PauseOnRBACDenied pauses templates whose apply RBAC refuses instead of retrying them
+	PauseOnRBACDenied bool
+	// CELPrograms caches the programs compiled for CEL rules (nil = compile on every evaluation)
+	CELPrograms *celcache.Cache
+	// FieldManager is the server-side apply field manager of templates whose policy and template
+	// don't set one (empty = manifest.DefaultFieldManager)
+	FieldManager string
 }
 
-// updateStatusWithRetry updates the status with retry on conflict
+// ProcessorOption configures optional TemplateProcessor behavior
+type ProcessorOption func(*TemplateProcessor)
+
+// WithAllowKubeTemplaterResources allows templates to create kubetemplater.io resources.
+// By default they are rejected as a safety backstop against recursive templates.
+func WithAllowKubeTemplaterResources(allow bool) ProcessorOption {
+	return func(p *TemplateProcessor) {
+		p.AllowKubeTemplaterResources = allow
+	}
+}
+
+// WithFieldManager sets the server-side apply field manager of templates whose policy and
+// template don't set their own
+func WithFieldManager(name string) ProcessorOption {
+	return func(p *TemplateProcessor) {
+		p.FieldManager = name
+	}
+}
+
+// WithImpersonation makes the worker apply resources as the ServiceAccount named in the
+// policy (spec.serviceAccountName), using clients built by the given factory
+func WithImpersonation(factory impersonation.ClientFactory) ProcessorOption {
+	return func(p *TemplateProcessor) {
+		p.ImpersonatingClients = factory
+	}
+}
+
+// WithResourceCounter lets CEL rules count existing resources with countResources
+func WithResourceCounter(counter *celquery.Counter) ProcessorOption {
+	return func(p *TemplateProcessor) {
+		p.ResourceCounter = counter
+	}
+}
+
+// WithAuditLogger records every resource the worker applies, refuses or fails to apply
+func WithAuditLogger(logger *audit.Logger) ProcessorOption {
+	return func(p *TemplateProcessor) {
+		p.Audit = logger
+	}
+}
+
+// WithErrorLogLimiter logs errors a template fails with again and again at a decreasing rate.
+// The status is still updated on every failure.
+func WithErrorLogLimiter(limiter *ErrorLogLimiter) ProcessorOption {
+	return func(p *TemplateProcessor) {
+		p.ErrorLog = limiter
+	}
+}
+
+// WithPauseOnRBACDenied pauses a template when RBAC refuses to let the operator, or the policy's
+// ServiceAccount, apply one of its resources. Retries can't fix missing permissions, so the
+// template waits for the resume annotation instead of cycling through its retries.
+func WithPauseOnRBACDenied(pause bool) ProcessorOption {
+	return func(p *TemplateProcessor) {
+		p.PauseOnRBACDenied = pause
+	}
+}
+
+// WithInFlightTracker registers every item with the tracker while it is being processed
+func WithInFlightTracker(tracker *InFlightTracker) ProcessorOption {
+	return func(p *TemplateProcessor) {
+		p.InFlight = tracker
+	}
+}
+
+// WithPostApplyVerification re-checks a Completed template once after the given delay and
+// marks it as Failed if any applied resource no longer exists (e.g. removed by a quota or
+// admission controller after the apply). A zero delay disables verification.
+func WithPostApplyVerification(delay time.Duration) ProcessorOption {
+	return func(p *TemplateProcessor) {
+		p.VerifyDelay = delay
+	}
+}
+
+// rejectTemplate marks the KubeTemplate as Failed with status because one of its templates, obj,
+// was refused, records the denial in the audit log and emits a Warning event with reason
+func (p *TemplateProcessor) rejectTemplate(ctx context.Context, kubeTemplate *kubetemplateriov1alpha1.KubeTemplate, obj *unstructured.Unstructured, reason, status string) {
+	record := audit.NewRecord(audit.SourceWorker, kubeTemplate, obj, audit.DecisionDenied)
+	record.Reason = status
+	p.Audit.Record(record)
+
+	message := strings.TrimPrefix(status, "Error: ")
+	if subject := eventSubject(obj); subject != "" {
+		message = fmt.Sprintf("Refused %s: %s", subject, message)
+	}
+	p.Recorder.Event(kubeTemplate, corev1.EventTypeWarning, reason, message)
+
+	now := metav1.Now()
+	generation := kubeTemplate.Generation
+	if err := p.updateStatusWithRetry(ctx, kubeTemplate, func(kt *kubetemplateriov1alpha1.KubeTemplate) {
+		kt.Status.ProcessingPhase = "Failed"
+		conditions.MarkFailed(&kt.Status, generation, kubetemplateriov1alpha1.ConditionPolicyValidated, kubetemplateriov1alpha1.ReasonRejected, status)
+		kt.Status.ProcessedAt = &now
+	}); err != nil {
+		logf.FromContext(ctx).WithName("template-processor").Error(err, "Failed to update status")
+	}
+}
+
+// failApply marks the KubeTemplate as Failed with status because applying obj failed with err,
+// with reason ApplyTimedOut if the apply timeout ended it or NotReady if obj did not become
+// ready, and records the failure in the audit log
+func (p *TemplateProcessor) failApply(ctx context.Context, kubeTemplate *kubetemplateriov1alpha1.KubeTemplate, obj *unstructured.Unstructured, status string, err error) {
+	record := audit.NewRecord(audit.SourceWorker, kubeTemplate, obj, audit.DecisionFailed)
+	record.Reason = err.Error()
+	p.Audit.Record(record)
+
+	reason := kubetemplateriov1alpha1.ReasonApplyFailed
+	message := fmt.Sprintf("Failed to apply %s: %v", eventSubject(obj), err)
+	switch {
+	case isApplyTimeout(err):
+		reason = kubetemplateriov1alpha1.ReasonApplyTimedOut
+	case isNotReady(err):
+		reason = kubetemplateriov1alpha1.ReasonNotReady
+		message = fmt.Sprintf("%s %v", eventSubject(obj), err)
+	}
+	p.Recorder.Event(kubeTemplate, corev1.EventTypeWarning, reason, message)
+
+	now := metav1.Now()
+	generation := kubeTemplate.Generation
+	if statusErr := p.updateStatusWithRetry(ctx, kubeTemplate, func(kt *kubetemplateriov1alpha1.KubeTemplate) {
+		kt.Status.ProcessingPhase = "Failed"
+		conditions.MarkFailed(&kt.Status, generation, kubetemplateriov1alpha1.ConditionApplied, reason, status)
+		kt.Status.ProcessedAt = &now
+	}); statusErr != nil {
+		logf.FromContext(ctx).WithName("template-processor").Error(statusErr, "Failed to update status")
+	}
+}
+
+// eventSubject describes obj as "apps/v1 Deployment team-a/app" for event messages, or
+// returns "" if obj has no kind, e.g. because it could not be decoded
+func eventSubject(obj *unstructured.Unstructured) string {
+	gvk := obj.GroupVersionKind()
+	if gvk.Kind == "" {
+		return ""
+	}
+	name := obj.GetName()
+	if obj.GetNamespace() != "" {
+		name = obj.GetNamespace() + "/" + name
+	}
+	return fmt.Sprintf("%s %s %s", gvk.GroupVersion(), gvk.Kind, name)
+}
+
+// isRBACDenial reports whether err is the authorizer refusing a request, as in `User "..."
+// cannot patch resource "configmaps" in the namespace "..."`. Exceeded quotas and admission
+// plugins are Forbidden too, but usually clear up by themselves and are retried.
+func isRBACDenial(err error) bool {
+	return errors.IsForbidden(err) && strings.Contains(err.Error(), " cannot ") && strings.Contains(err.Error(), " resource ")
+}
+
+// pauseRBACDenied pauses the KubeTemplate because RBAC refused to apply obj with err, and
+// records the failure in the audit log
+func (p *TemplateProcessor) pauseRBACDenied(ctx context.Context, kubeTemplate *kubetemplateriov1alpha1.KubeTemplate, obj *unstructured.Unstructured, policy *kubetemplateriov1alpha1.KubeTemplatePolicy, err error) {
+	record := audit.NewRecord(audit.SourceWorker, kubeTemplate, obj, audit.DecisionFailed)
+	record.Reason = err.Error()
+	p.Audit.Record(record)
+
+	identity := "operator"
+	if policy.Spec.ServiceAccountName != "" {
+		identity = fmt.Sprintf("service account %s/%s", policy.Namespace, policy.Spec.ServiceAccountName)
+	}
+	target := fmt.Sprintf("target namespace %s", obj.GetNamespace())
+	if obj.GetNamespace() == "" {
+		target = "cluster-scoped resources"
+	}
+	pausedReason := fmt.Sprintf("%s lacks RBAC for %s: %v", identity, target, err)
+
+	now := metav1.Now()
+	generation := kubeTemplate.Generation
+	if statusErr := p.updateStatusWithRetry(ctx, kubeTemplate, func(kt *kubetemplateriov1alpha1.KubeTemplate) {
+		kt.Status.ProcessingPhase = "Paused"
+		kt.Status.PausedReason = pausedReason
+		kt.Status.PausedAt = &now
+		kt.Status.ProcessedAt = &now
+		conditions.Set(&kt.Status, generation, kubetemplateriov1alpha1.ConditionApplied, metav1.ConditionFalse,
+			kubetemplateriov1alpha1.ReasonRBACDenied, "Error: "+pausedReason)
+		conditions.Set(&kt.Status, generation, kubetemplateriov1alpha1.ConditionReady, metav1.ConditionFalse,
+			kubetemplateriov1alpha1.ReasonPaused, "Paused: "+pausedReason)
+	}); statusErr != nil {
+		logf.FromContext(ctx).WithName("template-processor").Error(statusErr, "Failed to update status to Paused")
+		return
+	}
+	p.Recorder.Event(kubeTemplate, corev1.EventTypeWarning, "TemplatePaused",
+		fmt.Sprintf("Template paused, %s. Grant the permission, then resume with the kubetemplater.io/resume annotation.", pausedReason))
+}
+
+// updateStatusWithRetry updates the status with retry on conflict, refreshing the legacy
+// Status from the conditions and the summary
 func (p *TemplateProcessor) updateStatusWithRetry(ctx context.Context, kubeTemplate *kubetemplateriov1alpha1.KubeTemplate, updateFn func(*kubetemplateriov1alpha1.KubeTemplate)) error {
 	log := logf.FromContext(ctx).WithName("template-processor")
 	
@@ -65,6 +292,8 @@ func (p *TemplateProcessor) updateStatusWithRetry(ctx context.Context, kubeTempl
 
 		// Apply the status update function
 		updateFn(kubeTemplate)
+		conditions.SyncStatus(&kubeTemplate.Status)
+		kubeTemplate.Status.Summary = summary.Compute(&kubeTemplate.Status)
 		
 		if err := p.Client.Status().Update(ctx, kubeTemplate); err != nil {
 			if errors.IsConflict(err) && retries < 2 {
@@ -95,26 +324,28 @@ func (p *TemplateProcessor) Start(ctx context.Context) {
 				return
 			}
 
-			if err := p.processItem(ctx, item); err != nil {
-				log.Error(err, "Failed to process item", "item", item.NamespacedName, "retryCount", item.RetryCount)
+			if err := p.trackedProcessItem(ctx, item); err != nil {
+				p.logError(log, item.NamespacedName, err, "Failed to process item", "item", item.NamespacedName, "retryCount", item.RetryCount)
 				
 				// Check if we've hit max retry cycles - if so, set to Paused instead of re-queueing
 				if p.Queue.MaxRetryCycles > 0 && item.RetryCycle >= p.Queue.MaxRetryCycles {
 					log.Info("Max retry cycles reached, setting template to Paused",
 						"item", item.NamespacedName,
 						"cycles", item.RetryCycle)
+					metrics.ObserveItemPaused(p.WorkerID)
 					
 					// Fetch the template to update its status
 					var kubeTemplate kubetemplateriov1alpha1.KubeTemplate
 					if getErr := p.Client.Get(ctx, item.NamespacedName, &kubeTemplate); getErr == nil {
 						now := metav1.Now()
-						pausedReason := fmt.Sprintf("Max retry cycles (%d) exceeded. Last error: %v", p.Queue.MaxRetryCycles, err)
+						pausedReason := fmt.Sprintf("%s (%d) exceeded. Last error: %v", pause.ReasonRetriesExhausted, p.Queue.MaxRetryCycles, err)
 						
 						if statusErr := p.updateStatusWithRetry(ctx, &kubeTemplate, func(kt *kubetemplateriov1alpha1.KubeTemplate) {
 							kt.Status.ProcessingPhase = "Paused"
 							kt.Status.PausedReason = pausedReason
 							kt.Status.PausedAt = &now
-							kt.Status.Status = "Paused due to repeated failures"
+							conditions.Set(&kt.Status, kubeTemplate.Generation, kubetemplateriov1alpha1.ConditionReady, metav1.ConditionFalse,
+								kubetemplateriov1alpha1.ReasonPaused, "Paused due to repeated failures")
 						}); statusErr != nil {
 							log.Error(statusErr, "Failed to update status to Paused")
 						} else {
@@ -129,19 +360,53 @@ func (p *TemplateProcessor) Start(ctx context.Context) {
 					p.Queue.Done(item)
 				} else {
 					// Normal retry flow
-					p.Queue.Requeue(item, err)
+					p.requeue(ctx, item, err)
 				}
 			} else {
 				log.V(1).Info("Successfully processed item", "item", item.NamespacedName)
+				p.ErrorLog.Reset(item.NamespacedName)
 				p.Queue.Done(item)
 			}
 		}
 	}
 }
 
+// requeue puts a failed item back with backoff and records in the template status when it is
+// retried, so users can tell when a failed template is tried again
+func (p *TemplateProcessor) requeue(ctx context.Context, item *queue.WorkItem, err error) {
+	p.Queue.Requeue(item, err)
+
+	var nextRetryAt *metav1.Time
+	if scheduledAt, queued := p.Queue.NextScheduled(item.NamespacedName); queued {
+		nextRetryAt = &metav1.Time{Time: scheduledAt}
+	}
+	kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{ObjectMeta: metav1.ObjectMeta{
+		Namespace: item.NamespacedName.Namespace,
+		Name:      item.NamespacedName.Name,
+	}}
+	if statusErr := p.updateStatusWithRetry(ctx, kubeTemplate, func(kt *kubetemplateriov1alpha1.KubeTemplate) {
+		kt.Status.NextRetryAt = nextRetryAt
+	}); statusErr != nil && !errors.IsNotFound(statusErr) {
+		logf.FromContext(ctx).WithName("template-processor").Error(statusErr, "Failed to record the next retry", "item", item.NamespacedName)
+	}
+}
+
+// trackedProcessItem runs processItem with the item registered as in flight
+func (p *TemplateProcessor) trackedProcessItem(ctx context.Context, item *queue.WorkItem) error {
+	if p.InFlight != nil {
+		p.InFlight.Start(p.WorkerID, item)
+		defer p.InFlight.Finish(item)
+	}
+	return p.processItem(ctx, item)
+}
+
 // processItem processes a single KubeTemplate
-func (p *TemplateProcessor) processItem(ctx context.Context, item *queue.WorkItem) error {
+func (p *TemplateProcessor) processItem(ctx context.Context, item *queue.WorkItem) (err error) {
 	log := logf.FromContext(ctx).WithName("template-processor").WithValues("workerID", p.WorkerID)
+	start := time.Now()
+	defer func() {
+		metrics.ObserveItemProcessed(p.WorkerID, err != nil, time.Since(start))
+	}()
 
 	var kubeTemplate kubetemplateriov1alpha1.KubeTemplate
 	if err := p.Client.Get(ctx, item.NamespacedName, &kubeTemplate); err != nil {
@@ -152,6 +417,16 @@ func (p *TemplateProcessor) processItem(ctx context.Context, item *queue.WorkIte
 		return fmt.Errorf("failed to get KubeTemplate: %w", err)
 	}
 
+	// A template paused by annotation after it was queued is not processed
+	if pause.Requested(&kubeTemplate) {
+		log.Info("KubeTemplate is paused by annotation, skipping", "item", item.NamespacedName)
+		return nil
+	}
+
+	if item.Verify {
+		return p.verifyAppliedResources(ctx, &kubeTemplate)
+	}
+
 	// FASE 1 FIX: Handle templates with empty or Queued phase (defensive check)
 	// This prevents templates from being stuck if controller failed to update status
 	if kubeTemplate.Status.ProcessingPhase == "" || kubeTemplate.Status.ProcessingPhase == "Queued" {
@@ -163,18 +438,35 @@ func (p *TemplateProcessor) processItem(ctx context.Context, item *queue.WorkIte
 	// Update status to Processing
 	if err := p.updateStatusWithRetry(ctx, &kubeTemplate, func(kt *kubetemplateriov1alpha1.KubeTemplate) {
 		kt.Status.ProcessingPhase = "Processing"
+		conditions.MarkPending(&kt.Status, kt.Generation, kubetemplateriov1alpha1.ReasonProcessing, "Processing")
 		kt.Status.ProcessedAt = nil
+		kt.Status.NextRetryAt = nil
 	}); err != nil {
 		log.Error(err, "Failed to update status to Processing")
 	}
+	// The generation whose spec is processed; conditions set below are observed at it
+	generation := kubeTemplate.Generation
 
 	// Get policy from cache (fast!)
 	policy, err := p.Cache.Get(ctx, kubeTemplate.Namespace, p.OperatorNamespace)
+	if goerrors.Is(err, cache.ErrPolicyNotFound) && item.Policy != nil {
+		// The policy was deleted after the template was accepted: finish against the snapshot
+		log.Info("Policy no longer exists, using the policy snapshot taken at enqueue time",
+			"item", item.NamespacedName, "policy", item.Policy.Name)
+		policy, err = item.Policy, nil
+	}
 	if err != nil {
+		// A missing policy has its own reason: the policy reconciler requeues these templates
+		// once a policy covers their namespace
+		reason := kubetemplateriov1alpha1.ReasonPolicyUnavailable
+		if goerrors.Is(err, cache.ErrPolicyNotFound) {
+			reason = kubetemplateriov1alpha1.ReasonPolicyNotFound
+		}
 		now := metav1.Now()
 		if statusErr := p.updateStatusWithRetry(ctx, &kubeTemplate, func(kt *kubetemplateriov1alpha1.KubeTemplate) {
 			kt.Status.ProcessingPhase = "Failed"
-			kt.Status.Status = fmt.Sprintf("Error: %v", err)
+			conditions.MarkFailed(&kt.Status, generation, kubetemplateriov1alpha1.ConditionPolicyValidated,
+				reason, fmt.Sprintf("Error: %v", err))
 			kt.Status.ProcessedAt = &now
 		}); statusErr != nil {
 			log.Error(statusErr, "Failed to update status")
@@ -182,11 +474,106 @@ func (p *TemplateProcessor) processItem(ctx context.Context, item *queue.WorkIte
 		return err
 	}
 
-	// Process each template
-	for _, template := range kubeTemplate.Spec.Templates {
-		var obj unstructured.Unstructured
-		if err := yaml.Unmarshal(template.Object.Raw, &obj); err != nil {
+	// Apply with the policy's ServiceAccount identity if one is configured
+	applyClient, err := impersonation.ClientForPolicy(p.Client, p.ImpersonatingClients, policy)
+	if err != nil {
+		now := metav1.Now()
+		if statusErr := p.updateStatusWithRetry(ctx, &kubeTemplate, func(kt *kubetemplateriov1alpha1.KubeTemplate) {
+			kt.Status.ProcessingPhase = "Failed"
+			conditions.MarkFailed(&kt.Status, generation, kubetemplateriov1alpha1.ConditionApplied,
+				kubetemplateriov1alpha1.ReasonApplyFailed, fmt.Sprintf("Error: %v", err))
+			kt.Status.ProcessedAt = &now
+		}); statusErr != nil {
+			log.Error(statusErr, "Failed to update status")
+		}
+		return err
+	}
+
+	// Changed templates are only applied inside the policy's maintenance windows; dry-runs
+	// don't change anything, so they run at any time
+	specHash := calculateSpecHash(kubeTemplate.Spec)
+	if !kubeTemplate.Spec.DryRun && len(policy.Spec.MaintenanceWindows) > 0 && specHash != kubeTemplate.Status.AppliedSpecHash {
+		now := p.now()
+		open, next, err := maintenance.Check(policy.Spec.MaintenanceWindows, now)
+		if err != nil {
+			processedAt := metav1.Now()
+			if statusErr := p.updateStatusWithRetry(ctx, &kubeTemplate, func(kt *kubetemplateriov1alpha1.KubeTemplate) {
+				kt.Status.ProcessingPhase = "Failed"
+				conditions.MarkFailed(&kt.Status, generation, kubetemplateriov1alpha1.ConditionPolicyValidated,
+					kubetemplateriov1alpha1.ReasonPolicyUnavailable, fmt.Sprintf("Error: policy %s: %v", policy.Name, err))
+				kt.Status.ProcessedAt = &processedAt
+			}); statusErr != nil {
+				log.Error(statusErr, "Failed to update status")
+			}
+			return err
+		}
+		if !open {
+			log.Info("Outside maintenance window, deferring apply", "item", item.NamespacedName, "nextWindow", next)
+			// Defer before the status update so the reconcile it triggers dedupes against the deferred item
+			p.Queue.Defer(item, next.Sub(now))
+			if err := p.updateStatusWithRetry(ctx, &kubeTemplate, func(kt *kubetemplateriov1alpha1.KubeTemplate) {
+				kt.Status.ProcessingPhase = "Queued"
+				conditions.MarkPending(&kt.Status, generation, kubetemplateriov1alpha1.ReasonDeferred,
+					fmt.Sprintf("Deferred: outside maintenance window, next window opens at %s", next.UTC().Format(time.RFC3339)))
+			}); err != nil {
+				log.Error(err, "Failed to update status to Queued")
+			}
+			return nil
+		}
+	}
+
+	// Large templates are applied at the rate set by the policy or the template's annotation
+	rate, err := applyRate(&kubeTemplate, policy)
+	if err != nil {
+		log.Info("Ignoring apply rate annotation", "item", item.NamespacedName, "reason", err.Error())
+	}
+	if rate > 0 {
+		log.V(1).Info("Throttling applies", "item", item.NamespacedName, "objectsPerSecond", rate)
+	}
+	throttle := p.newApplyThrottle(rate)
+
+	// Slow applies fail the template with their own reason; the queue still decides when to retry
+	timeout, err := applyTimeout(&kubeTemplate)
+	if err != nil {
+		log.Info("Ignoring apply timeout annotation", "item", item.NamespacedName, "reason", err.Error())
+	}
+	applyClient = withApplyTimeout(applyClient, timeout)
+
+	// Templates rejected below mark the KubeTemplate as Failed; that phase must not be
+	// overwritten with Completed once the remaining templates have been applied
+	rejected := 0
+	var applied []kubetemplateriov1alpha1.AppliedResource
+
+	// Pruning compares the resources of the last apply with the ones still in the spec; a
+	// template that can't be decoded can't be told apart from a removed one
+	prune := kubeTemplate.Spec.Prune
+	previouslyApplied := kubeTemplate.Status.AppliedResources
+	var inSpec []kubetemplateriov1alpha1.AppliedResource
+	undecodable := 0
+
+	// Dry-run templates go through every check, but only report what the apply would do
+	dryRun := kubeTemplate.Spec.DryRun
+	var dryRunResults []kubetemplateriov1alpha1.DryRunResult
+
+	// The rule each template was validated against, reported in the status for transparency
+	var matches []kubetemplateriov1alpha1.PolicyMatch
+
+	// Process each template in apply order. Status updates below re-fetch kubeTemplate, so
+	// index into the templates of the spec being processed.
+	templates := kubeTemplate.Spec.Templates
+	for _, idx := range manifest.ApplyOrder(templates) {
+		template := templates[idx]
+		raw, err := manifest.ResolveParameters(template.Object.Raw, kubeTemplate.Spec.Parameters)
+		if err != nil {
+			log.Info("Template references undefined parameters", "index", idx, "reason", err.Error())
+			p.rejectTemplate(ctx, &kubeTemplate, &unstructured.Unstructured{}, "TemplateRejected", fmt.Sprintf("Error: template[%d]: %v", idx, err))
+			rejected++
+			continue
+		}
+		obj, err := manifest.Decode(raw)
+		if err != nil {
 			log.Error(err, "Failed to unmarshal template object")
+			undecodable++
 			continue
 		}
 
@@ -195,6 +582,35 @@ func (p *TemplateProcessor) processItem(ctx context.Context, item *queue.WorkIte
 		}
 
 		gvk := obj.GroupVersionKind()
+		inSpec = append(inSpec, kubetemplateriov1alpha1.AppliedResource{
+			APIVersion: gvk.GroupVersion().String(),
+			Kind:       gvk.Kind,
+			Namespace:  obj.GetNamespace(),
+			Name:       obj.GetName(),
+		})
+
+		if err := manifest.ValidateName(&obj); err != nil {
+			log.Info("Refusing to apply template without a name", "gvk", gvk, "reason", err.Error())
+			p.rejectTemplate(ctx, &kubeTemplate, &obj, "TemplateRejected", fmt.Sprintf("Error: Invalid %s: %v", gvk.String(), err))
+			rejected++
+			continue
+		}
+
+		if err := manifest.ValidateFieldManager(template); err != nil {
+			log.Info("Refusing to apply template with an invalid field manager", "gvk", gvk, "reason", err.Error())
+			p.rejectTemplate(ctx, &kubeTemplate, &obj, "TemplateRejected", fmt.Sprintf("Error: Invalid %s: %v", gvk.String(), err))
+			rejected++
+			continue
+		}
+
+		// Safety backstop: never apply KubeTemplater resources unless explicitly allowed
+		if gvk.Group == kubetemplateriov1alpha1.GroupVersion.Group && !p.AllowKubeTemplaterResources {
+			log.Info("Refusing to apply KubeTemplater resource from template", "gvk", gvk)
+			p.rejectTemplate(ctx, &kubeTemplate, &obj, "TemplateRejected", fmt.Sprintf("Error: Resource %s cannot be created from a template", gvk.String()))
+			rejected++
+			continue
+		}
+
 		allowed := false
 		var matchedRule *kubetemplateriov1alpha1.ValidationRule
 
@@ -204,26 +620,26 @@ func (p *TemplateProcessor) processItem(ctx context.Context, item *queue.WorkIte
 			"kind", gvk.Kind,
 			"policyName", policy.Name)
 
-		for i := range policy.Spec.ValidationRules {
-			rule := &policy.Spec.ValidationRules[i]
-			log.Info("Checking rule",
-				"ruleIndex", i,
-				"ruleGroup", rule.Group,
-				"ruleVersion", rule.Version,
+		// Exact kinds take precedence over wildcard rules, so the loop can't stop at the first match
+		if ruleIndex := policyutil.FindRuleIndex(policy, gvk); ruleIndex >= 0 {
+			rule := &policy.Spec.ValidationRules[ruleIndex]
+			allowed = true
+			matchedRule = rule
+			matches = append(matches, kubetemplateriov1alpha1.PolicyMatch{
+				Template:   idx,
+				APIVersion: gvk.GroupVersion().String(),
+				Kind:       gvk.Kind,
+				Namespace:  obj.GetNamespace(),
+				Name:       obj.GetName(),
+				Policy:     policy.Name,
+				Rule:       ruleIndex,
+				Wildcard:   policyutil.MatchesByWildcard(rule, gvk.Kind),
+			})
+			log.Info("Rule matched successfully",
 				"ruleKind", rule.Kind,
-				"resourceGroup", gvk.Group,
-				"resourceVersion", gvk.Version,
-				"resourceKind", gvk.Kind,
-				"kindMatch", rule.Kind == gvk.Kind,
-				"groupMatch", rule.Group == gvk.Group,
-				"versionMatch", rule.Version == gvk.Version)
-			
-			if rule.Kind == gvk.Kind && rule.Group == gvk.Group && rule.Version == gvk.Version {
-				allowed = true
-				matchedRule = rule
-				log.Info("Rule matched successfully", "ruleIndex", i)
-				break
-			}
+				"ruleKinds", rule.Kinds,
+				"ruleGroup", rule.Group,
+				"ruleVersion", rule.Version)
 		}
 
 		if !allowed {
@@ -232,185 +648,528 @@ func (p *TemplateProcessor) processItem(ctx context.Context, item *queue.WorkIte
 				"version", gvk.Version,
 				"kind", gvk.Kind,
 				"policyRules", len(policy.Spec.ValidationRules))
-			now := metav1.Now()
-			if err := p.updateStatusWithRetry(ctx, &kubeTemplate, func(kt *kubetemplateriov1alpha1.KubeTemplate) {
-				kt.Status.ProcessingPhase = "Failed"
-				kt.Status.Status = fmt.Sprintf("Error: Resource %s is not allowed by policy", gvk.String())
-				kt.Status.ProcessedAt = &now
-			}); err != nil {
-				log.Error(err, "Failed to update status")
-			}
+			p.rejectTemplate(ctx, &kubeTemplate, &obj, "PolicyRejected", policyRejectionStatus(&kubeTemplate, policy, fmt.Sprintf("Resource %s is not allowed by policy", gvk.String())))
+			rejected++
 			continue
 		}
 
-		if len(matchedRule.TargetNamespaces) == 0 {
+		if !policyutil.HasTargetNamespaces(matchedRule) {
 			log.Info("Rule has no target namespaces", "gvk", gvk)
-			now := metav1.Now()
-			if err := p.updateStatusWithRetry(ctx, &kubeTemplate, func(kt *kubetemplateriov1alpha1.KubeTemplate) {
-				kt.Status.ProcessingPhase = "Failed"
-				kt.Status.Status = fmt.Sprintf("Error: Resource %s has no target namespaces", gvk.String())
-				kt.Status.ProcessedAt = &now
-			}); err != nil {
-				log.Error(err, "Failed to update status")
-			}
+			p.rejectTemplate(ctx, &kubeTemplate, &obj, "PolicyRejected", policyRejectionStatus(&kubeTemplate, policy, fmt.Sprintf("Resource %s has no target namespaces", gvk.String())))
+			rejected++
 			continue
 		}
 
-		if !contains(matchedRule.TargetNamespaces, obj.GetNamespace()) {
+		namespaceAllowed, err := policyutil.NamespaceAllowed(ctx, p.Client, matchedRule, obj.GetNamespace())
+		if err != nil {
+			// A namespace that doesn't exist yet may still be created, so retry rather than reject
+			p.logError(log, item.NamespacedName, err, "Failed to match target namespace", "gvk", gvk, "namespace", obj.GetNamespace())
+			p.failApply(ctx, &kubeTemplate, &obj, fmt.Sprintf("Error: Failed to match namespace %s against the target namespaces of %s: %v", obj.GetNamespace(), gvk.String(), err), err)
+			return err
+		}
+		if !namespaceAllowed {
 			log.Info("Namespace not in target list", "gvk", gvk, "namespace", obj.GetNamespace())
-			now := metav1.Now()
-			if err := p.updateStatusWithRetry(ctx, &kubeTemplate, func(kt *kubetemplateriov1alpha1.KubeTemplate) {
-				kt.Status.ProcessingPhase = "Failed"
-				kt.Status.Status = fmt.Sprintf("Error: namespace %s not allowed for %s", obj.GetNamespace(), gvk.String())
-				kt.Status.ProcessedAt = &now
-			}); err != nil {
-				log.Error(err, "Failed to update status")
-			}
+			p.rejectTemplate(ctx, &kubeTemplate, &obj, "PolicyRejected", policyRejectionStatus(&kubeTemplate, policy, fmt.Sprintf("namespace %s not allowed for %s", obj.GetNamespace(), gvk.String())))
+			rejected++
 			continue
 		}
 
+		// KubeTemplater manages spec, not status; a status usually comes from a dumped object
+		var statusObj *unstructured.Unstructured
+		if template.ApplyStatus {
+			statusObj = manifest.StatusOf(&obj)
+		} else if manifest.HasStatus(&obj) {
+			if policy.Spec.RejectTemplateStatus {
+				log.Info("Refusing to apply object that sets status", "gvk", gvk, "name", obj.GetName())
+				p.rejectTemplate(ctx, &kubeTemplate, &obj, "PolicyRejected", policyRejectionStatus(&kubeTemplate, policy, fmt.Sprintf("%s %s sets status, remove it or set applyStatus: true on the template", gvk.Kind, obj.GetName())))
+				rejected++
+				continue
+			}
+			log.Info("Dropping status of template object", "gvk", gvk, "name", obj.GetName())
+			manifest.StripStatus(&obj)
+		}
+
+		// Server-managed metadata pasted from a live object breaks server-side apply
+		if fields := manifest.ServerManagedMetadata(&obj); len(fields) > 0 {
+			if policy.Spec.RejectServerManagedMetadata {
+				log.Info("Refusing to apply object that sets server-managed metadata", "gvk", gvk, "name", obj.GetName(), "fields", fields)
+				p.rejectTemplate(ctx, &kubeTemplate, &obj, "PolicyRejected", policyRejectionStatus(&kubeTemplate, policy, fmt.Sprintf("%s %s sets server-managed metadata %s, remove it", gvk.Kind, obj.GetName(), strings.Join(fields, ", "))))
+				rejected++
+				continue
+			}
+			log.Info("Dropping server-managed metadata of template object", "gvk", gvk, "name", obj.GetName(), "fields", fields)
+		}
+		manifest.StripServerManagedMetadata(&obj)
+
 		// Validate with CEL rule if present
 		if matchedRule != nil && matchedRule.Rule != "" {
-			if valid, err := p.validateWithCEL(matchedRule.Rule, obj.Object); err != nil {
-				log.Error(err, "CEL validation error", "gvk", gvk)
-			now := metav1.Now()
-			if statusErr := p.updateStatusWithRetry(ctx, &kubeTemplate, func(kt *kubetemplateriov1alpha1.KubeTemplate) {
-				kt.Status.ProcessingPhase = "Failed"
-				kt.Status.Status = fmt.Sprintf("Error: CEL validation failed for %s: %v", gvk.String(), err)
-				kt.Status.ProcessedAt = &now
-			}); statusErr != nil {
-					log.Error(statusErr, "Failed to update status")
-				}
+			if valid, err := p.validateWithCEL(ctx, matchedRule.Rule, &obj); err != nil {
+				// A broken rule is the policy's problem, not a violation by the resource
+				log.Error(err, "Policy has a broken CEL rule", "gvk", gvk, "policyName", policy.Name)
+				p.rejectTemplate(ctx, &kubeTemplate, &obj, "CELRuleError", policyRejectionStatus(&kubeTemplate, policy, fmt.Sprintf("policy %s could not validate %s: %v", policy.Name, gvk.String(), err)))
+				rejected++
 				continue
 			} else if !valid {
 				log.Info("CEL validation failed", "gvk", gvk)
-			now := metav1.Now()
-			if statusErr := p.updateStatusWithRetry(ctx, &kubeTemplate, func(kt *kubetemplateriov1alpha1.KubeTemplate) {
-				kt.Status.ProcessingPhase = "Failed"
-				kt.Status.Status = fmt.Sprintf("Error: Resource %s failed CEL validation", gvk.String())
-				kt.Status.ProcessedAt = &now
-			}); statusErr != nil {
-					log.Error(statusErr, "Failed to update status")
-				}
+				p.rejectTemplate(ctx, &kubeTemplate, &obj, "CELValidationFailed", policyRejectionStatus(&kubeTemplate, policy, fmt.Sprintf("Resource %s failed CEL validation", gvk.String())))
+				rejected++
 				continue
 			}
 		}
 
-		// Add tracking labels to enable watch-based reconciliation
-		labels := obj.GetLabels()
-		if labels == nil {
-			labels = make(map[string]string)
+		// Check container images against the image policy, which may have changed since admission
+		if matchedRule.ImagePolicy != nil {
+			if violations := policyutil.ImageViolations(matchedRule.ImagePolicy, &obj); len(violations) > 0 {
+				log.Info("Image policy violated", "gvk", gvk, "name", obj.GetName(), "violations", violations)
+				p.rejectTemplate(ctx, &kubeTemplate, &obj, "ImagePolicyViolation", policyRejectionStatus(&kubeTemplate, policy, fmt.Sprintf("%s %s violates the image policy: %s", gvk.Kind, obj.GetName(), strings.Join(violations, "; "))))
+				rejected++
+				continue
+			}
 		}
-		labels["kubetemplater.io/template-name"] = kubeTemplate.Name
-		labels["kubetemplater.io/template-namespace"] = kubeTemplate.Namespace
-		obj.SetLabels(labels)
 
-		// Add KubeTemplate as OwnerReference if referenced is true
+		// Labels the policy propagates from the KubeTemplate, then the tracking labels to enable
+		// watch-based reconciliation
+		manifest.PropagateLabels(&obj, &kubeTemplate, policy.Spec.PropagateLabels, policy.Spec.OverrideTemplateLabels)
+		manifest.SetTrackingLabels(&obj, &kubeTemplate)
+
+		// Add KubeTemplate as OwnerReference if referenced is true, unless the object already
+		// owns the KubeTemplate: garbage collection can't resolve an ownership cycle
 		if template.Referenced {
-			ownerRef := metav1.OwnerReference{
-				APIVersion: "kubetemplater.io/v1alpha1",
-				Kind:       "KubeTemplate",
-				Name:       kubeTemplate.Name,
-				UID:        kubeTemplate.UID,
+			if cycle := p.ownershipCycle(ctx, &kubeTemplate, &obj); cycle != nil {
+				log.Info("Not adding KubeTemplate as OwnerReference, it would create an ownership cycle",
+					"gvk", gvk, "name", obj.GetName(), "cycle", formatCycle(cycle))
+				p.Recorder.Event(&kubeTemplate, corev1.EventTypeWarning, "OwnershipCycle",
+					fmt.Sprintf("Applied %s %s without an owner reference to the KubeTemplate, which would create the ownership cycle %s (each owned by the next)",
+						gvk.Kind, obj.GetName(), formatCycle(cycle)))
+			} else {
+				ownerRef := metav1.OwnerReference{
+					APIVersion: "kubetemplater.io/v1alpha1",
+					Kind:       "KubeTemplate",
+					Name:       kubeTemplate.Name,
+					UID:        kubeTemplate.UID,
+				}
+				owners := obj.GetOwnerReferences()
+				owners = append(owners, ownerRef)
+				obj.SetOwnerReferences(owners)
+				log.Info("Added KubeTemplate as OwnerReference",
+					"gvk", gvk,
+					"templateName", kubeTemplate.Name,
+					"templateUID", kubeTemplate.UID)
 			}
-			owners := obj.GetOwnerReferences()
-			owners = append(owners, ownerRef)
-			obj.SetOwnerReferences(owners)
-			log.Info("Added KubeTemplate as OwnerReference",
-				"gvk", gvk,
-				"templateName", kubeTemplate.Name,
-				"templateUID", kubeTemplate.UID)
+		}
+
+		// Look up the live object first so the apply can be classified as a create or an update
+		existing, err := p.lookupExisting(ctx, &obj)
+		if err != nil {
+			if policy.Spec.ProtectUnmanagedResources {
+				// Without the live object we cannot tell whether the apply would take it over
+				return fmt.Errorf("failed to look up %s %s: %w", gvk.String(), obj.GetName(), err)
+			}
+			log.Error(err, "Failed to look up existing object, apply action may be misreported", "gvk", gvk, "name", obj.GetName())
+		}
+
+		// Don't silently take over resources created outside KubeTemplater
+		if policy.Spec.ProtectUnmanagedResources && existing != nil && !template.Adopt && !isManaged(existing) {
+			log.Info("Refusing to take over unmanaged resource", "gvk", gvk, "name", obj.GetName(), "namespace", obj.GetNamespace())
+			p.rejectTemplate(ctx, &kubeTemplate, &obj, "PolicyRejected", policyRejectionStatus(&kubeTemplate, policy, fmt.Sprintf("%s %s/%s already exists and is not managed by KubeTemplater, set adopt: true on the template to take it over",
+				gvk.Kind, obj.GetNamespace(), obj.GetName())))
+			rejected++
+			continue
+		}
+		existed := existing != nil
+		var previousVersion string
+		if existed {
+			previousVersion = existing.GetResourceVersion()
+		}
+
+		fieldManager := manifest.FieldManager(template, policy, p.FieldManager)
+		if dryRun {
+			result := dryRunApply(ctx, applyClient, &obj, existing, fieldManager, template.Replace)
+			log.V(1).Info("Dry-run applied object", "gvk", gvk, "name", obj.GetName(), "action", result.Action, "error", result.Error)
+			dryRunResults = append(dryRunResults, result)
+			continue
 		}
 
 		// Apply the resource
-		fieldManager := "kubetemplater"
-		if err := p.Client.Patch(ctx, &obj, client.Apply, client.FieldOwner(fieldManager)); err != nil {
+		if err := throttle.Wait(ctx); err != nil {
+			return fmt.Errorf("interrupted while throttling applies: %w", err)
+		}
+		if err := applyClient.Patch(ctx, &obj, client.Apply, client.FieldOwner(fieldManager)); err != nil {
 			if errors.IsInvalid(err) && template.Replace {
 				log.Info("Applying with replace", "gvk", gvk, "name", obj.GetName())
-				if deleteErr := p.Client.Delete(ctx, &obj); deleteErr != nil {
+				if deleteErr := applyClient.Delete(ctx, &obj); deleteErr != nil {
 					log.Error(deleteErr, "Failed to delete for replace", "gvk", gvk)
 					continue
 				}
-				if applyErr := p.Client.Patch(ctx, &obj, client.Apply, client.FieldOwner(fieldManager)); applyErr != nil {
+				if applyErr := applyClient.Patch(ctx, &obj, client.Apply, client.FieldOwner(fieldManager)); applyErr != nil {
 					log.Error(applyErr, "Failed to apply after replace", "gvk", gvk)
 					continue
 				}
+			} else if p.PauseOnRBACDenied && isRBACDenial(err) {
+				log.Info("RBAC refused to apply object, pausing template", "gvk", gvk, "name", obj.GetName(), "reason", err.Error())
+				p.pauseRBACDenied(ctx, &kubeTemplate, &obj, policy, err)
+				return nil
 			} else {
-				log.Error(err, "Failed to apply object", "gvk", gvk)
-				now := metav1.Now()
-				if statusErr := p.updateStatusWithRetry(ctx, &kubeTemplate, func(kt *kubetemplateriov1alpha1.KubeTemplate) {
-					kt.Status.ProcessingPhase = "Failed"
-					kt.Status.Status = fmt.Sprintf("Error: Failed to apply %s/%s: %v", gvk.String(), obj.GetName(), err)
-					kt.Status.ProcessedAt = &now
-				}); statusErr != nil {
-					log.Error(statusErr, "Failed to update status")
+				p.logError(log, item.NamespacedName, err, "Failed to apply object", "gvk", gvk)
+				p.failApply(ctx, &kubeTemplate, &obj, fmt.Sprintf("Error: Failed to apply %s/%s: %v", gvk.String(), obj.GetName(), err), err)
+				return err
+			}
+		}
+
+		// Kinds without a status subresource have their status applied with the object already
+		if statusObj != nil {
+			if err := applyClient.Status().Patch(ctx, statusObj, client.Apply, client.FieldOwner(fieldManager)); err != nil && !errors.IsNotFound(err) {
+				p.logError(log, item.NamespacedName, err, "Failed to apply object status", "gvk", gvk)
+				p.failApply(ctx, &kubeTemplate, &obj, fmt.Sprintf("Error: Failed to apply status of %s/%s: %v", gvk.String(), obj.GetName(), err), err)
+				return err
+			}
+		}
+
+		action := kubetemplateriov1alpha1.ApplyActionCreated
+		if existed {
+			action = kubetemplateriov1alpha1.ApplyActionUpdated
+			if obj.GetResourceVersion() == previousVersion {
+				action = kubetemplateriov1alpha1.ApplyActionUnchanged
+			}
+		}
+		metrics.ObserveResourceApplied(string(action))
+		record := audit.NewRecord(audit.SourceWorker, &kubeTemplate, &obj, audit.DecisionApplied)
+		record.Action = string(action)
+		p.Audit.Record(record)
+		applied = append(applied, kubetemplateriov1alpha1.AppliedResource{
+			APIVersion: gvk.GroupVersion().String(),
+			Kind:       gvk.Kind,
+			Namespace:  obj.GetNamespace(),
+			Name:       obj.GetName(),
+			Action:     action,
+		})
+		log.V(1).Info("Applied object", "gvk", gvk, "name", obj.GetName(), "action", action)
+		if action != kubetemplateriov1alpha1.ApplyActionUnchanged {
+			p.Recorder.Event(&kubeTemplate, corev1.EventTypeNormal, "ResourceApplied", fmt.Sprintf("%s %s", action, eventSubject(&obj)))
+		}
+
+		// Templates ordered after this one are only applied once its object is ready. Rather
+		// than holding the worker, the item is deferred and the template applied again on the
+		// next check, which leaves the objects already applied unchanged.
+		if template.WaitForReady != nil {
+			ready, reason, err := p.awaitReady(ctx, item, &obj, template.WaitForReady)
+			if err != nil {
+				p.logError(log, item.NamespacedName, err, "Object did not become ready", "gvk", gvk)
+				status := fmt.Sprintf("Error: Failed to check readiness of %s/%s: %v", gvk.String(), obj.GetName(), err)
+				if isNotReady(err) {
+					status = fmt.Sprintf("Error: resource %s/%s %v", gvk.String(), obj.GetName(), err)
 				}
+				p.failApply(ctx, &kubeTemplate, &obj, status, err)
 				return err
 			}
+			if !ready {
+				deadline := item.ReadyWait.Deadline
+				log.V(1).Info("Object is not ready, checking again later", "gvk", gvk, "name", obj.GetName(), "reason", reason, "deadline", deadline)
+				// Defer before the status update so the reconcile it triggers dedupes against the deferred item
+				p.Queue.Defer(item, p.readyCheckDelay(item))
+				if err := p.updateStatusWithRetry(ctx, &kubeTemplate, func(kt *kubetemplateriov1alpha1.KubeTemplate) {
+					kt.Status.ProcessingPhase = "Queued"
+					conditions.MarkPending(&kt.Status, generation, kubetemplateriov1alpha1.ReasonWaitingForReady,
+						fmt.Sprintf("Waiting: resource %s/%s is not ready until %s: %s", gvk.String(), obj.GetName(), deadline.UTC().Format(time.RFC3339), reason))
+				}); err != nil {
+					log.Error(err, "Failed to update status to Queued")
+				}
+				return nil
+			}
+		}
+	}
+
+	removed := staleResources(previouslyApplied, inSpec)
+	slices.SortFunc(matches, func(a, b kubetemplateriov1alpha1.PolicyMatch) int { return a.Template - b.Template })
+	for _, name := range policyutil.MergedNames(policy) {
+		metrics.ObservePolicyValidation(name, metrics.PolicySourceWorker, rejected == 0)
+	}
+	if rejected > 0 {
+		if prune {
+			// Keep tracking removed resources so they are pruned once the template completes
+			applied = append(applied, removed...)
+		}
+		// Keep the Failed phase, but record the hash so a spec change triggers a retry
+		if err := p.updateStatusWithRetry(ctx, &kubeTemplate, func(kt *kubetemplateriov1alpha1.KubeTemplate) {
+			kt.Status.AppliedSpecHash = specHash
+			kt.Status.PolicyMatches = matches
+			if dryRun {
+				kt.Status.DryRunResults = dryRunResults
+				return
+			}
+			kt.Status.AppliedResources = applied
+		}); err != nil {
+			log.Error(err, "Failed to update AppliedSpecHash")
+			return err
+		}
+		log.Info("KubeTemplate has rejected templates, not marking as Completed", "rejected", rejected)
+		return nil
+	}
+
+	if dryRun {
+		now := metav1.NewTime(p.now())
+		if err := p.updateStatusWithRetry(ctx, &kubeTemplate, func(kt *kubetemplateriov1alpha1.KubeTemplate) {
+			kt.Status.ProcessingPhase = "DryRunCompleted"
+			message := dryRunSummary(dryRunResults)
+			conditions.Set(&kt.Status, generation, kubetemplateriov1alpha1.ConditionPolicyValidated, metav1.ConditionTrue,
+				kubetemplateriov1alpha1.ReasonValidated, "The policy allows every resource")
+			conditions.Set(&kt.Status, generation, kubetemplateriov1alpha1.ConditionApplied, metav1.ConditionFalse,
+				kubetemplateriov1alpha1.ReasonDryRun, "Dry run, nothing was applied")
+			conditions.Set(&kt.Status, generation, kubetemplateriov1alpha1.ConditionReady, metav1.ConditionTrue,
+				kubetemplateriov1alpha1.ReasonDryRunCompleted, message)
+			kt.Status.ProcessedAt = &now
+			kt.Status.AppliedSpecHash = specHash
+			kt.Status.DryRunResults = dryRunResults
+			kt.Status.PolicyMatches = matches
+		}); err != nil {
+			log.Error(err, "Failed to update status to DryRunCompleted")
+			return err
+		}
+		p.Recorder.Event(&kubeTemplate, corev1.EventTypeNormal, "DryRunCompleted", dryRunSummary(dryRunResults))
+		return nil
+	}
+
+	// Delete the resources of templates removed since the last apply
+	if prune && len(removed) > 0 {
+		if undecodable > 0 {
+			log.Info("Not pruning, templates failed to decode", "undecodable", undecodable)
+			applied = append(applied, removed...)
+		} else if err := p.pruneResources(ctx, applyClient, &kubeTemplate, removed); err != nil {
+			p.logError(log, item.NamespacedName, err, "Failed to prune removed resources")
+			now := metav1.Now()
+			if statusErr := p.updateStatusWithRetry(ctx, &kubeTemplate, func(kt *kubetemplateriov1alpha1.KubeTemplate) {
+				kt.Status.ProcessingPhase = "Failed"
+				conditions.MarkFailed(&kt.Status, generation, kubetemplateriov1alpha1.ConditionApplied,
+					kubetemplateriov1alpha1.ReasonPruneFailed, fmt.Sprintf("Error: %v", err))
+				kt.Status.ProcessedAt = &now
+			}); statusErr != nil {
+				log.Error(statusErr, "Failed to update status")
+			}
+			return err
 		}
 	}
 
-	// Calculate spec hash for versioning
-	specHash := calculateSpecHash(kubeTemplate.Spec)
-	
 	// Update status to Completed
-	now := metav1.Now()
+	now := metav1.NewTime(p.now())
+	// Captured before the status update re-fetches the template, which may carry a newer spec
+	appliedGeneration := kubeTemplate.Generation
+	resourcesTotal := len(kubeTemplate.Spec.Templates)
+	newGeneration := appliedGeneration > kubeTemplate.Status.ObservedGeneration
+	queuedAt := kubeTemplate.Status.QueuedAt
 	if err := p.updateStatusWithRetry(ctx, &kubeTemplate, func(kt *kubetemplateriov1alpha1.KubeTemplate) {
 		kt.Status.ProcessingPhase = "Completed"
-		kt.Status.Status = "Completed"
+		conditions.Set(&kt.Status, appliedGeneration, kubetemplateriov1alpha1.ConditionPolicyValidated, metav1.ConditionTrue,
+			kubetemplateriov1alpha1.ReasonValidated, "The policy allows every resource")
+		conditions.Set(&kt.Status, appliedGeneration, kubetemplateriov1alpha1.ConditionApplied, metav1.ConditionTrue,
+			kubetemplateriov1alpha1.ReasonApplied, fmt.Sprintf("Applied %d resources", resourcesTotal-undecodable))
+		conditions.Set(&kt.Status, appliedGeneration, kubetemplateriov1alpha1.ConditionReady, metav1.ConditionTrue,
+			kubetemplateriov1alpha1.ReasonCompleted, "Completed")
 		kt.Status.ProcessedAt = &now
 		kt.Status.AppliedSpecHash = specHash  // Store hash of applied spec
+		kt.Status.AppliedResources = applied
+		kt.Status.ResourcesTotal = resourcesTotal
+		kt.Status.ResourcesSynced = resourcesTotal - undecodable
+		kt.Status.ObservedGeneration = appliedGeneration
+		kt.Status.AppliedPolicyVersion = policy.ResourceVersion
+		kt.Status.DryRunResults = nil
+		kt.Status.PolicyMatches = matches
 	}); err != nil {
 		log.Error(err, "Failed to update status to Completed")
 		return err
 	}
+	p.Recorder.Event(&kubeTemplate, corev1.EventTypeNormal, "Completed", fmt.Sprintf("Applied %d resources", resourcesTotal-undecodable))
+
+	// The controller stamps QueuedAt when it picks up a spec change, so the lag covers
+	// queueing, backoff and maintenance deferrals of the new generation
+	if newGeneration && queuedAt != nil {
+		metrics.ObserveReconcileLag(now.Sub(queuedAt.Time))
+	}
+
+	if p.VerifyDelay > 0 {
+		p.Queue.EnqueueVerification(item.NamespacedName, kubeTemplate.Spec.ApplyPriority, p.VerifyDelay)
+	}
 
 	return nil
 }
 
-// validateWithCEL validates an object using a CEL expression
-func (p *TemplateProcessor) validateWithCEL(rule string, object map[string]interface{}) (bool, error) {
-	env, err := cel.NewEnv(
-		cel.Declarations(
-			decls.NewVar("object", decls.NewMapType(decls.String, decls.Dyn)),
-		),
-	)
-	if err != nil {
-		return false, fmt.Errorf("failed to create CEL environment: %w", err)
+// now returns the current time from the injected clock, if any
+func (p *TemplateProcessor) now() time.Time {
+	if p.Now != nil {
+		return p.Now()
+	}
+	return time.Now()
+}
+
+// sleep waits for d with the injected sleep function, if any, returning early when ctx is done
+func (p *TemplateProcessor) sleep(ctx context.Context, d time.Duration) error {
+	if p.Sleep != nil {
+		return p.Sleep(ctx, d)
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
 	}
+}
 
-	parsed, issues := env.Parse(rule)
-	if issues != nil && issues.Err() != nil {
-		return false, fmt.Errorf("failed to parse CEL rule: %w", issues.Err())
+// lookupExisting returns the live version of obj, or nil if it does not exist yet
+func (p *TemplateProcessor) lookupExisting(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(obj.GroupVersionKind())
+	if err := p.Client.Get(ctx, client.ObjectKeyFromObject(obj), existing); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
 	}
+	return existing, nil
+}
+
+// isManaged reports whether obj carries the tracking labels KubeTemplater sets on applied resources
+func isManaged(obj *unstructured.Unstructured) bool {
+	_, ok := obj.GetLabels()[manifest.TemplateNameLabel]
+	return ok
+}
 
-	checked, issues := env.Check(parsed)
-	if issues != nil && issues.Err() != nil {
-		return false, fmt.Errorf("failed to check CEL rule: %w", issues.Err())
+// verifyAppliedResources checks that every resource of a Completed template exists and
+// marks the template as Failed, listing the missing resources, if any are gone
+func (p *TemplateProcessor) verifyAppliedResources(ctx context.Context, kubeTemplate *kubetemplateriov1alpha1.KubeTemplate) error {
+	log := logf.FromContext(ctx).WithName("template-processor").WithValues("workerID", p.WorkerID)
+
+	// Only verify what was applied: skip if the template changed or failed in the meantime
+	if kubeTemplate.Status.ProcessingPhase != "Completed" || kubeTemplate.Status.AppliedSpecHash != calculateSpecHash(kubeTemplate.Spec) {
+		log.V(1).Info("Skipping post-apply verification, template changed since apply",
+			"name", kubeTemplate.Name,
+			"namespace", kubeTemplate.Namespace,
+			"phase", kubeTemplate.Status.ProcessingPhase)
+		return nil
 	}
 
-	prg, err := env.Program(checked)
+	var missing []string
+	for _, template := range kubeTemplate.Spec.Templates {
+		obj, err := manifest.DecodeWithParameters(template.Object.Raw, kubeTemplate.Spec.Parameters)
+		if err != nil {
+			continue
+		}
+		if obj.GetNamespace() == "" {
+			obj.SetNamespace(kubeTemplate.Namespace)
+		}
+
+		existing := &unstructured.Unstructured{}
+		existing.SetGroupVersionKind(obj.GroupVersionKind())
+		if err := p.Client.Get(ctx, client.ObjectKeyFromObject(&obj), existing); err != nil {
+			if errors.IsNotFound(err) {
+				missing = append(missing, fmt.Sprintf("%s %s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName()))
+				continue
+			}
+			return fmt.Errorf("failed to verify %s %s/%s: %w", obj.GetKind(), obj.GetNamespace(), obj.GetName(), err)
+		}
+	}
+
+	if len(missing) == 0 {
+		log.V(1).Info("Post-apply verification succeeded", "name", kubeTemplate.Name, "namespace", kubeTemplate.Namespace)
+		return nil
+	}
+
+	log.Info("Post-apply verification failed, resources are missing",
+		"name", kubeTemplate.Name,
+		"namespace", kubeTemplate.Namespace,
+		"missing", missing)
+	message := fmt.Sprintf("Error: Post-apply verification failed, missing resources: %s", strings.Join(missing, ", "))
+	p.Recorder.Event(kubeTemplate, corev1.EventTypeWarning, "VerificationFailed", message)
+	now := metav1.Now()
+	generation := kubeTemplate.Generation
+	return p.updateStatusWithRetry(ctx, kubeTemplate, func(kt *kubetemplateriov1alpha1.KubeTemplate) {
+		kt.Status.ProcessingPhase = "Failed"
+		conditions.MarkFailed(&kt.Status, generation, kubetemplateriov1alpha1.ConditionApplied, kubetemplateriov1alpha1.ReasonVerificationFailed, message)
+		kt.Status.ProcessedAt = &now
+	})
+}
+
+// policyRejectionStatus returns the status of a template the policy rejected for reason. When
+// the policy changed after the webhook admitted the template, the status says so, since the
+// webhook checked the template against the previous version.
+func policyRejectionStatus(kubeTemplate *kubetemplateriov1alpha1.KubeTemplate, policy *kubetemplateriov1alpha1.KubeTemplatePolicy, reason string) string {
+	if admitted, changed := policyutil.ChangedSinceAdmission(kubeTemplate, policy); changed {
+		return fmt.Sprintf("Error: policy changed between admission and apply (policy %s admitted the template at resourceVersion %s, applied at %s): %s",
+			policy.Name, admitted, policy.ResourceVersion, reason)
+	}
+	return "Error: " + reason
+}
+
+// validateWithCEL validates an object using a CEL expression, which may count resources. A rule
+// that can't be compiled or evaluated, or doesn't return a bool, is reported as a
+// policyutil.RuleError rather than as invalid.
+func (p *TemplateProcessor) validateWithCEL(ctx context.Context, rule string, obj *unstructured.Unstructured) (valid bool, err error) {
+	start := time.Now()
+	defer func() {
+		result := metrics.CELResultPass
+		if err != nil {
+			result = metrics.CELResultError
+		} else if !valid {
+			result = metrics.CELResultFail
+		}
+		metrics.ObserveCELEvaluation(result, time.Since(start))
+	}()
+
+	// countResources is bound to this evaluation, so programs of rules calling it aren't cached
+	countsResources := strings.Contains(rule, celquery.CountResourcesFunction)
+	compile := func() (cel.Program, error) {
+		opts := []cel.EnvOption{
+			cel.Declarations(
+				decls.NewVar("object", decls.NewMapType(decls.String, decls.Dyn)),
+			),
+		}
+		if countsResources {
+			opts = append(opts, p.ResourceCounter.Function(ctx, obj))
+		}
+		env, err := cel.NewEnv(opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create CEL environment: %w", err)
+		}
+
+		parsed, issues := env.Parse(rule)
+		if issues != nil && issues.Err() != nil {
+			return nil, fmt.Errorf("failed to parse CEL rule: %w", issues.Err())
+		}
+
+		checked, issues := env.Check(parsed)
+		if issues != nil && issues.Err() != nil {
+			return nil, fmt.Errorf("failed to check CEL rule: %w", issues.Err())
+		}
+
+		prg, err := env.Program(checked)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create CEL program: %w", err)
+		}
+		return prg, nil
+	}
+
+	var prg cel.Program
+	if countsResources {
+		prg, err = compile()
+	} else {
+		prg, err = p.CELPrograms.Program(rule, "object", compile)
+	}
 	if err != nil {
-		return false, fmt.Errorf("failed to create CEL program: %w", err)
+		return false, &policyutil.RuleError{Rule: rule, Err: err}
 	}
 
 	out, _, err := prg.Eval(map[string]interface{}{
-		"object": object,
+		"object": obj.Object,
 	})
 	if err != nil {
-		return false, fmt.Errorf("failed to evaluate CEL rule: %w", err)
+		return false, &policyutil.RuleError{Rule: rule, Err: fmt.Errorf("failed to evaluate CEL rule: %w", err)}
 	}
 
-	return out.Value() == true, nil
-}
-
-func contains(slice []string, str string) bool {
-	for _, v := range slice {
-		if v == str {
-			return true
-		}
+	passed, isBool := out.Value().(bool)
+	if !isBool {
+		return false, &policyutil.RuleError{Rule: rule, Err: fmt.Errorf("CEL rule returned %s, not a bool", out.Type().TypeName())}
 	}
-	return false
+	return passed, nil
 }
 
 // calculateSpecHash computes SHA256 hash of KubeTemplateSpec for versioning
@@ -425,7 +1184,9 @@ func calculateSpecHash(spec kubetemplateriov1alpha1.KubeTemplateSpec) string {
 }
 
 // StartWorkers starts multiple worker goroutines
-func StartWorkers(ctx context.Context, client client.Client, cache *cache.PolicyCache, queue *queue.WorkQueue, recorder record.EventRecorder, operatorNamespace string, numWorkers int) {
+func StartWorkers(ctx context.Context, client client.Client, cache *cache.PolicyCache, queue *queue.WorkQueue, recorder record.EventRecorder, operatorNamespace string, numWorkers int, opts ...ProcessorOption) {
+	// The workers evaluate the same policies' rules, so they share the compiled programs
+	programs := celcache.New()
 	for i := 0; i < numWorkers; i++ {
 		processor := &TemplateProcessor{
 			Client:            client,
@@ -434,6 +1195,10 @@ func StartWorkers(ctx context.Context, client client.Client, cache *cache.Policy
 			Recorder:          recorder,
 			OperatorNamespace: operatorNamespace,
 			WorkerID:          i,
+			CELPrograms:       programs,
+		}
+		for _, opt := range opts {
+			opt(processor)
 		}
 		go processor.Start(ctx)
 	}
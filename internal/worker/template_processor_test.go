@@ -0,0 +1,2809 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	"github.com/lpeano/KubeTemplater/internal/audit"
+	"github.com/lpeano/KubeTemplater/internal/cache"
+	"github.com/lpeano/KubeTemplater/internal/celcache"
+	"github.com/lpeano/KubeTemplater/internal/celquery"
+	"github.com/lpeano/KubeTemplater/internal/manifest"
+	"github.com/lpeano/KubeTemplater/internal/metrics"
+	"github.com/lpeano/KubeTemplater/internal/pause"
+	policyutil "github.com/lpeano/KubeTemplater/internal/policy"
+	"github.com/lpeano/KubeTemplater/internal/queue"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+const operatorNamespace = "kubetemplater-system"
+
+// applyAsCreateOrUpdate emulates Server-Side Apply, which the fake client does not support
+func applyAsCreateOrUpdate(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if patch.Type() != types.ApplyPatchType {
+		return c.Patch(ctx, obj, patch, opts...)
+	}
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(obj.GetObjectKind().GroupVersionKind())
+	if err := c.Get(ctx, client.ObjectKeyFromObject(obj), existing); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		if dryRun(opts) {
+			return nil
+		}
+		return c.Create(ctx, obj)
+	}
+	obj.SetResourceVersion(existing.GetResourceVersion())
+	if dryRun(opts) {
+		return nil
+	}
+	return c.Update(ctx, obj)
+}
+
+// dryRun reports whether the patch options request a dry-run
+func dryRun(opts []client.PatchOption) bool {
+	patchOpts := &client.PatchOptions{}
+	patchOpts.ApplyOptions(opts)
+	return len(patchOpts.DryRun) > 0
+}
+
+var _ = Describe("TemplateProcessor", func() {
+	var (
+		ctx        context.Context
+		fakeClient client.Client
+		processor  *TemplateProcessor
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(kubetemplateriov1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithInterceptorFuncs(interceptor.Funcs{Patch: applyAsCreateOrUpdate}).
+			WithStatusSubresource(&kubetemplateriov1alpha1.KubeTemplate{}).
+			WithIndex(&kubetemplateriov1alpha1.KubeTemplatePolicy{}, "spec.sourceNamespace", func(obj client.Object) []string {
+				return []string{obj.(*kubetemplateriov1alpha1.KubeTemplatePolicy).Spec.SourceNamespace}
+			}).
+			Build()
+
+		processor = &TemplateProcessor{
+			Client:            fakeClient,
+			Cache:             cache.NewPolicyCache(fakeClient, 0),
+			Queue:             queue.NewWorkQueue(),
+			Recorder:          record.NewFakeRecorder(10),
+			OperatorNamespace: operatorNamespace,
+		}
+	})
+
+	Context("When a template creates KubeTemplater resources", func() {
+		BeforeEach(func() {
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: operatorNamespace},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{
+							Kind:             "KubeTemplatePolicy",
+							Group:            "kubetemplater.io",
+							Version:          "v1alpha1",
+							TargetNamespaces: []string{"default"},
+						},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, policy)).To(Succeed())
+
+			kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default"},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{
+							Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"kubetemplater.io/v1alpha1","kind":"KubeTemplatePolicy",` +
+								`"metadata":{"name":"escalated-policy"},"spec":{"sourceNamespace":"other","validationRules":[]}}`)},
+						},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, kubeTemplate)).To(Succeed())
+		})
+
+		It("should refuse to apply the resource and mark the template as Failed", func() {
+			item := &queue.WorkItem{NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-template"}}
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+
+			var kt kubetemplateriov1alpha1.KubeTemplate
+			Expect(fakeClient.Get(ctx, item.NamespacedName, &kt)).To(Succeed())
+			Expect(kt.Status.ProcessingPhase).To(Equal("Failed"))
+			Expect(kt.Status.Status).To(ContainSubstring("cannot be created from a template"))
+			Expect(kt.Status.AppliedSpecHash).NotTo(BeEmpty())
+
+			err := fakeClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "escalated-policy"}, &kubetemplateriov1alpha1.KubeTemplatePolicy{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+
+	Context("When a template uses generateName", func() {
+		BeforeEach(func() {
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: operatorNamespace},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{Kind: "ConfigMap", Version: "v1", TargetNamespaces: []string{"default"}},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, policy)).To(Succeed())
+
+			kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default"},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{
+							Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"generateName":"app-"}}`)},
+						},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, kubeTemplate)).To(Succeed())
+		})
+
+		It("should mark the template Failed with a clear reason", func() {
+			item := &queue.WorkItem{NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-template"}}
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+
+			var kt kubetemplateriov1alpha1.KubeTemplate
+			Expect(fakeClient.Get(ctx, item.NamespacedName, &kt)).To(Succeed())
+			Expect(kt.Status.ProcessingPhase).To(Equal("Failed"))
+			Expect(kt.Status.Status).To(ContainSubstring(`metadata.generateName "app-" is not supported`))
+
+			var configMaps corev1.ConfigMapList
+			Expect(fakeClient.List(ctx, &configMaps, client.InNamespace("default"))).To(Succeed())
+			Expect(configMaps.Items).To(BeEmpty())
+		})
+	})
+
+	Context("When post-apply verification is enabled", func() {
+		var item *queue.WorkItem
+
+		BeforeEach(func() {
+			processor.VerifyDelay = 10 * time.Millisecond
+
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: operatorNamespace},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{
+							Kind:             "ConfigMap",
+							Group:            "",
+							Version:          "v1",
+							TargetNamespaces: []string{"default"},
+						},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, policy)).To(Succeed())
+
+			kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default"},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{
+							Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"app-config"},"data":{"key":"value"}}`)},
+						},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, kubeTemplate)).To(Succeed())
+
+			item = &queue.WorkItem{NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-template"}}
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+
+			var kt kubetemplateriov1alpha1.KubeTemplate
+			Expect(fakeClient.Get(ctx, item.NamespacedName, &kt)).To(Succeed())
+			Expect(kt.Status.ProcessingPhase).To(Equal("Completed"))
+		})
+
+		verify := func() *kubetemplateriov1alpha1.KubeTemplate {
+			verification, ok := processor.Queue.Dequeue()
+			Expect(ok).To(BeTrue())
+			Expect(verification.NamespacedName).To(Equal(item.NamespacedName))
+			Expect(verification.Verify).To(BeTrue())
+			Expect(processor.processItem(ctx, verification)).To(Succeed())
+
+			var kt kubetemplateriov1alpha1.KubeTemplate
+			Expect(fakeClient.Get(ctx, item.NamespacedName, &kt)).To(Succeed())
+			return &kt
+		}
+
+		It("should keep the template Completed when all resources exist", func() {
+			kt := verify()
+			Expect(kt.Status.ProcessingPhase).To(Equal("Completed"))
+		})
+
+		It("should mark the template Failed when an applied resource was deleted", func() {
+			cm := &unstructured.Unstructured{}
+			cm.SetAPIVersion("v1")
+			cm.SetKind("ConfigMap")
+			cm.SetNamespace("default")
+			cm.SetName("app-config")
+			Expect(fakeClient.Delete(ctx, cm)).To(Succeed())
+
+			kt := verify()
+			Expect(kt.Status.ProcessingPhase).To(Equal("Failed"))
+			Expect(kt.Status.Status).To(ContainSubstring("missing resources: ConfigMap default/app-config"))
+		})
+	})
+
+	Context("When recording the apply action of each resource", func() {
+		var item *queue.WorkItem
+
+		BeforeEach(func() {
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: operatorNamespace},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{
+							Kind:             "ConfigMap",
+							Group:            "",
+							Version:          "v1",
+							TargetNamespaces: []string{"default"},
+						},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, policy)).To(Succeed())
+
+			kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default"},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{
+							Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"app-config"},"data":{"key":"value"}}`)},
+						},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, kubeTemplate)).To(Succeed())
+
+			item = &queue.WorkItem{NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-template"}}
+		})
+
+		appliedResources := func() []kubetemplateriov1alpha1.AppliedResource {
+			var kt kubetemplateriov1alpha1.KubeTemplate
+			Expect(fakeClient.Get(ctx, item.NamespacedName, &kt)).To(Succeed())
+			Expect(kt.Status.ProcessingPhase).To(Equal("Completed"))
+			return kt.Status.AppliedResources
+		}
+
+		It("should report Created when the resource did not exist", func() {
+			before := testutil.ToFloat64(metrics.ResourcesAppliedTotal.WithLabelValues("Created"))
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+
+			Expect(appliedResources()).To(ConsistOf(kubetemplateriov1alpha1.AppliedResource{
+				APIVersion: "v1",
+				Kind:       "ConfigMap",
+				Namespace:  "default",
+				Name:       "app-config",
+				Action:     kubetemplateriov1alpha1.ApplyActionCreated,
+			}))
+			Expect(testutil.ToFloat64(metrics.ResourcesAppliedTotal.WithLabelValues("Created"))).To(Equal(before + 1))
+		})
+
+		It("should report Updated when the resource already existed", func() {
+			existing := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "app-config", Namespace: "default"},
+				Data:       map[string]string{"key": "old"},
+			}
+			Expect(fakeClient.Create(ctx, existing)).To(Succeed())
+
+			before := testutil.ToFloat64(metrics.ResourcesAppliedTotal.WithLabelValues("Updated"))
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+
+			resources := appliedResources()
+			Expect(resources).To(HaveLen(1))
+			Expect(resources[0].Action).To(Equal(kubetemplateriov1alpha1.ApplyActionUpdated))
+			Expect(testutil.ToFloat64(metrics.ResourcesAppliedTotal.WithLabelValues("Updated"))).To(Equal(before + 1))
+		})
+	})
+
+	Context("When recording per-policy validation metrics", func() {
+		BeforeEach(func() {
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "metrics-policy", Namespace: operatorNamespace},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{Kind: "ConfigMap", Version: "v1", TargetNamespaces: []string{"default"}},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, policy)).To(Succeed())
+		})
+
+		DescribeTable("should increment the counter of the policy the template was validated against",
+			func(kind string, expectedResult string) {
+				counter := metrics.PolicyValidationsTotal.WithLabelValues("metrics-policy", metrics.PolicySourceWorker, expectedResult)
+				before := testutil.ToFloat64(counter)
+
+				kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default"},
+					Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+						Templates: []kubetemplateriov1alpha1.Template{
+							{Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"` + kind + `","metadata":{"name":"app"}}`)}},
+						},
+					},
+				}
+				Expect(fakeClient.Create(ctx, kubeTemplate)).To(Succeed())
+
+				Expect(processor.processItem(ctx, &queue.WorkItem{NamespacedName: client.ObjectKeyFromObject(kubeTemplate)})).To(Succeed())
+				Expect(testutil.ToFloat64(counter)).To(Equal(before + 1))
+			},
+			Entry("applied template", "ConfigMap", metrics.PolicyResultAllowed),
+			Entry("rejected template", "Secret", metrics.PolicyResultDenied),
+		)
+	})
+
+	Context("When a spec change is completed", func() {
+		var (
+			item  *queue.WorkItem
+			clock time.Time
+		)
+
+		lagSamples := func() uint64 {
+			m := &dto.Metric{}
+			Expect(metrics.ReconcileLagSeconds.Write(m)).To(Succeed())
+			return m.GetHistogram().GetSampleCount()
+		}
+
+		BeforeEach(func() {
+			clock = time.Date(2025, time.January, 15, 12, 0, 0, 0, time.UTC)
+			processor.Now = func() time.Time { return clock }
+
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: operatorNamespace},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{Kind: "ConfigMap", Version: "v1", TargetNamespaces: []string{"default"}},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, policy)).To(Succeed())
+
+			kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default", Generation: 2},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{
+							Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"app-config"},"data":{"key":"value"}}`)},
+						},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, kubeTemplate)).To(Succeed())
+
+			// The generation 1 spec was completed before; the controller queued the change 30s ago
+			queuedAt := metav1.NewTime(clock.Add(-30 * time.Second))
+			kubeTemplate.Status.ObservedGeneration = 1
+			kubeTemplate.Status.ProcessingPhase = "Queued"
+			kubeTemplate.Status.QueuedAt = &queuedAt
+			Expect(fakeClient.Status().Update(ctx, kubeTemplate)).To(Succeed())
+
+			item = &queue.WorkItem{NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-template"}}
+		})
+
+		It("should record the reconcile lag and the observed generation", func() {
+			var kt kubetemplateriov1alpha1.KubeTemplate
+			Expect(fakeClient.Get(ctx, item.NamespacedName, &kt)).To(Succeed())
+
+			before := lagSamples()
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+			Expect(lagSamples()).To(Equal(before + 1))
+
+			Expect(fakeClient.Get(ctx, item.NamespacedName, &kt)).To(Succeed())
+			Expect(kt.Status.ProcessingPhase).To(Equal("Completed"))
+			Expect(kt.Status.ObservedGeneration).To(Equal(kt.Generation))
+		})
+
+		It("should not record a lag when the generation was already completed", func() {
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+			before := lagSamples()
+
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+			Expect(lagSamples()).To(Equal(before))
+		})
+	})
+
+	Context("When the policy is deleted after the template was enqueued", func() {
+		var item *queue.WorkItem
+
+		BeforeEach(func() {
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: operatorNamespace},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{
+							Kind:             "ConfigMap",
+							Group:            "",
+							Version:          "v1",
+							TargetNamespaces: []string{"default"},
+						},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, policy)).To(Succeed())
+
+			kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default"},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{
+							Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"app-config"},"data":{"key":"value"}}`)},
+						},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, kubeTemplate)).To(Succeed())
+
+			// Enqueue with a snapshot, then delete the policy and drop it from the cache
+			// the way the policy reconciler does
+			name := types.NamespacedName{Namespace: "default", Name: "test-template"}
+			processor.Queue.EnqueueWithPolicy(name, 0, policy.DeepCopy())
+			Expect(fakeClient.Delete(ctx, policy)).To(Succeed())
+			processor.Cache.Clear()
+
+			var ok bool
+			item, ok = processor.Queue.Dequeue()
+			Expect(ok).To(BeTrue())
+		})
+
+		It("should complete the template against the snapshotted policy", func() {
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+
+			var kt kubetemplateriov1alpha1.KubeTemplate
+			Expect(fakeClient.Get(ctx, item.NamespacedName, &kt)).To(Succeed())
+			Expect(kt.Status.ProcessingPhase).To(Equal("Completed"))
+
+			cm := &corev1.ConfigMap{}
+			Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "app-config"}, cm)).To(Succeed())
+		})
+
+		It("should still fail when there is no snapshot", func() {
+			item.Policy = nil
+			Expect(processor.processItem(ctx, item)).NotTo(Succeed())
+
+			var kt kubetemplateriov1alpha1.KubeTemplate
+			Expect(fakeClient.Get(ctx, item.NamespacedName, &kt)).To(Succeed())
+			Expect(kt.Status.ProcessingPhase).To(Equal("Failed"))
+			Expect(kt.Status.Status).To(ContainSubstring("no KubeTemplatePolicy found"))
+			validated := meta.FindStatusCondition(kt.Status.Conditions, kubetemplateriov1alpha1.ConditionPolicyValidated)
+			Expect(validated.Reason).To(Equal(kubetemplateriov1alpha1.ReasonPolicyNotFound))
+		})
+	})
+
+	Context("When the policy has a maintenance window", func() {
+		var (
+			item  *queue.WorkItem
+			clock time.Time
+		)
+
+		BeforeEach(func() {
+			clock = time.Date(2025, time.January, 15, 1, 0, 0, 0, time.UTC)
+			processor.Now = func() time.Time { return clock }
+
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: operatorNamespace},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					MaintenanceWindows: []kubetemplateriov1alpha1.MaintenanceWindow{
+						{Start: "02:00", End: "04:00"},
+					},
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{
+							Kind:             "ConfigMap",
+							Group:            "",
+							Version:          "v1",
+							TargetNamespaces: []string{"default"},
+						},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, policy)).To(Succeed())
+
+			kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default"},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{
+							Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"app-config"},"data":{"key":"value"}}`)},
+						},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, kubeTemplate)).To(Succeed())
+
+			item = &queue.WorkItem{NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-template"}}
+		})
+
+		It("should defer the apply until the window opens and apply it then", func() {
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+
+			var kt kubetemplateriov1alpha1.KubeTemplate
+			Expect(fakeClient.Get(ctx, item.NamespacedName, &kt)).To(Succeed())
+			Expect(kt.Status.ProcessingPhase).To(Equal("Queued"))
+			Expect(kt.Status.Status).To(ContainSubstring("next window opens at 2025-01-15T02:00:00Z"))
+			Expect(processor.Queue.Contains(item.NamespacedName)).To(BeTrue())
+
+			cm := &corev1.ConfigMap{}
+			err := fakeClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "app-config"}, cm)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			clock = time.Date(2025, time.January, 15, 2, 30, 0, 0, time.UTC)
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+
+			Expect(fakeClient.Get(ctx, item.NamespacedName, &kt)).To(Succeed())
+			Expect(kt.Status.ProcessingPhase).To(Equal("Completed"))
+			Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "app-config"}, cm)).To(Succeed())
+		})
+
+		It("should not defer templates whose spec has already been applied", func() {
+			clock = time.Date(2025, time.January, 15, 2, 30, 0, 0, time.UTC)
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+
+			clock = time.Date(2025, time.January, 15, 5, 0, 0, 0, time.UTC)
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+
+			var kt kubetemplateriov1alpha1.KubeTemplate
+			Expect(fakeClient.Get(ctx, item.NamespacedName, &kt)).To(Succeed())
+			Expect(kt.Status.ProcessingPhase).To(Equal("Completed"))
+		})
+	})
+
+	Context("When the policy protects unmanaged resources", func() {
+		var item *queue.WorkItem
+
+		BeforeEach(func() {
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: operatorNamespace},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace:           "default",
+					ProtectUnmanagedResources: true,
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{
+							Kind:             "ConfigMap",
+							Group:            "",
+							Version:          "v1",
+							TargetNamespaces: []string{"default"},
+						},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, policy)).To(Succeed())
+
+			item = &queue.WorkItem{NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-template"}}
+		})
+
+		createTemplate := func(adopt bool) {
+			kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default"},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{
+							Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"app-config"},"data":{"key":"value"}}`)},
+							Adopt:  adopt,
+						},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, kubeTemplate)).To(Succeed())
+		}
+
+		createConfigMap := func(labels map[string]string) {
+			Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "app-config", Namespace: "default", Labels: labels},
+				Data:       map[string]string{"key": "theirs"},
+			})).To(Succeed())
+		}
+
+		processAndGetTemplate := func() *kubetemplateriov1alpha1.KubeTemplate {
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+			var kt kubetemplateriov1alpha1.KubeTemplate
+			Expect(fakeClient.Get(ctx, item.NamespacedName, &kt)).To(Succeed())
+			return &kt
+		}
+
+		configMapData := func() map[string]string {
+			cm := &corev1.ConfigMap{}
+			Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "app-config"}, cm)).To(Succeed())
+			return cm.Data
+		}
+
+		It("should refuse to take over an existing unmanaged resource", func() {
+			createConfigMap(nil)
+			createTemplate(false)
+
+			kt := processAndGetTemplate()
+			Expect(kt.Status.ProcessingPhase).To(Equal("Failed"))
+			Expect(kt.Status.Status).To(ContainSubstring("ConfigMap default/app-config already exists and is not managed by KubeTemplater"))
+			Expect(configMapData()).To(HaveKeyWithValue("key", "theirs"))
+		})
+
+		It("should take over an unmanaged resource when the template adopts it", func() {
+			createConfigMap(nil)
+			createTemplate(true)
+
+			kt := processAndGetTemplate()
+			Expect(kt.Status.ProcessingPhase).To(Equal("Completed"))
+			Expect(configMapData()).To(HaveKeyWithValue("key", "value"))
+		})
+
+		It("should update resources KubeTemplater already manages", func() {
+			createConfigMap(map[string]string{"kubetemplater.io/template-name": "test-template"})
+			createTemplate(false)
+
+			kt := processAndGetTemplate()
+			Expect(kt.Status.ProcessingPhase).To(Equal("Completed"))
+			Expect(configMapData()).To(HaveKeyWithValue("key", "value"))
+		})
+
+		It("should create resources that do not exist yet", func() {
+			createTemplate(false)
+
+			kt := processAndGetTemplate()
+			Expect(kt.Status.ProcessingPhase).To(Equal("Completed"))
+			Expect(configMapData()).To(HaveKeyWithValue("key", "value"))
+		})
+	})
+
+	Context("When an audit log is configured", func() {
+		var buf *bytes.Buffer
+
+		BeforeEach(func() {
+			buf = &bytes.Buffer{}
+			processor.Audit = audit.NewLogger(buf)
+
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: operatorNamespace},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace:           "default",
+					ProtectUnmanagedResources: true,
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{Kind: "ConfigMap", Group: "", Version: "v1", TargetNamespaces: []string{"default"}},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, policy)).To(Succeed())
+
+			kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default"},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"app-config"},"data":{"key":"value"}}`)}},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, kubeTemplate)).To(Succeed())
+		})
+
+		records := func() []audit.Record {
+			var result []audit.Record
+			for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+				var r audit.Record
+				Expect(json.Unmarshal([]byte(line), &r)).To(Succeed())
+				result = append(result, r)
+			}
+			return result
+		}
+
+		It("should record each applied resource", func() {
+			item := &queue.WorkItem{NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-template"}}
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+
+			Expect(records()).To(ConsistOf(audit.Record{
+				Time:       records()[0].Time,
+				Source:     audit.SourceWorker,
+				Template:   "default/test-template",
+				APIVersion: "v1",
+				Kind:       "ConfigMap",
+				Namespace:  "default",
+				Name:       "app-config",
+				Decision:   audit.DecisionApplied,
+				Action:     "Created",
+			}))
+		})
+
+		It("should record refused resources with the reason", func() {
+			Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "app-config", Namespace: "default"},
+			})).To(Succeed())
+
+			item := &queue.WorkItem{NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-template"}}
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+
+			recorded := records()
+			Expect(recorded).To(HaveLen(1))
+			Expect(recorded[0].Decision).To(Equal(audit.DecisionDenied))
+			Expect(recorded[0].Name).To(Equal("app-config"))
+			Expect(recorded[0].Reason).To(ContainSubstring("is not managed by KubeTemplater"))
+		})
+	})
+
+	Context("When CEL programs are cached", func() {
+		configMap := func(key string) *unstructured.Unstructured {
+			return &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata":   map[string]interface{}{"name": "app-config", "namespace": "default"},
+				"data":       map[string]interface{}{key: "value"},
+			}}
+		}
+
+		BeforeEach(func() {
+			processor.CELPrograms = celcache.New()
+		})
+
+		It("should compile a rule once and evaluate it against each object", func() {
+			rule := "has(object.data.key)"
+
+			valid, err := processor.validateWithCEL(ctx, rule, configMap("key"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(valid).To(BeTrue())
+
+			valid, err = processor.validateWithCEL(ctx, rule, configMap("other"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(valid).To(BeFalse())
+			Expect(processor.CELPrograms.Len()).To(Equal(1))
+		})
+
+		It("should not cache rules that count resources", func() {
+			processor.ResourceCounter = celquery.NewCounter(fakeClient, celquery.DefaultQPS, celquery.DefaultBurst, celquery.DefaultTimeout)
+
+			valid, err := processor.validateWithCEL(ctx, "countResources('v1', 'ConfigMap', object.metadata.namespace) < 1", configMap("key"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(valid).To(BeTrue())
+			Expect(processor.CELPrograms.Len()).To(BeZero())
+		})
+
+		It("should report a rule that can't be evaluated as a rule error, not as invalid", func() {
+			valid, err := processor.validateWithCEL(ctx, "object.data.key == 'value'", configMap("key"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(valid).To(BeTrue())
+
+			valid, err = processor.validateWithCEL(ctx, "object.data.key == 'other'", configMap("key"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(valid).To(BeFalse())
+
+			// The object has no spec to dereference
+			_, err = processor.validateWithCEL(ctx, "object.spec.replicas <= 3", configMap("key"))
+			Expect(policyutil.IsRuleError(err)).To(BeTrue())
+			Expect(err).To(MatchError(`policy error: CEL rule "object.spec.replicas <= 3" is broken: failed to evaluate CEL rule: no such key: spec`))
+
+			_, err = processor.validateWithCEL(ctx, "object.metadata.name", configMap("key"))
+			Expect(policyutil.IsRuleError(err)).To(BeTrue())
+			Expect(err).To(MatchError(ContainSubstring("CEL rule returned string, not a bool")))
+		})
+	})
+
+	Context("When a CEL rule counts existing resources", func() {
+		BeforeEach(func() {
+			processor.ResourceCounter = celquery.NewCounter(fakeClient, celquery.DefaultQPS, celquery.DefaultBurst, celquery.DefaultTimeout)
+
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: operatorNamespace},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{
+							Kind:             "ConfigMap",
+							Group:            "",
+							Version:          "v1",
+							TargetNamespaces: []string{"default"},
+							Rule:             "countResources('v1', 'ConfigMap', object.metadata.namespace) < 2",
+						},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, policy)).To(Succeed())
+
+			kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default"},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"app-config"},"data":{"key":"value"}}`)}},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, kubeTemplate)).To(Succeed())
+		})
+
+		processAndGetTemplate := func() *kubetemplateriov1alpha1.KubeTemplate {
+			item := &queue.WorkItem{NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-template"}}
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+			var kt kubetemplateriov1alpha1.KubeTemplate
+			Expect(fakeClient.Get(ctx, item.NamespacedName, &kt)).To(Succeed())
+			return &kt
+		}
+
+		createConfigMaps := func(names ...string) {
+			for _, name := range names {
+				Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+				})).To(Succeed())
+			}
+		}
+
+		It("should apply the resource while the rule allows another one", func() {
+			createConfigMaps("other-1")
+
+			kt := processAndGetTemplate()
+			Expect(kt.Status.ProcessingPhase).To(Equal("Completed"))
+
+			// Re-applying the resource doesn't count it against itself
+			kt = processAndGetTemplate()
+			Expect(kt.Status.ProcessingPhase).To(Equal("Completed"))
+		})
+
+		It("should reject the resource once the limit is reached", func() {
+			createConfigMaps("other-1", "other-2")
+
+			kt := processAndGetTemplate()
+			Expect(kt.Status.ProcessingPhase).To(Equal("Failed"))
+			Expect(kt.Status.Status).To(ContainSubstring("failed CEL validation"))
+		})
+	})
+
+	Context("When the rule has an image policy", func() {
+		BeforeEach(func() {
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: operatorNamespace},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{
+							Kind:             "Pod",
+							Version:          "v1",
+							TargetNamespaces: []string{"default"},
+							ImagePolicy: &kubetemplateriov1alpha1.ImagePolicy{
+								AllowedRegistries: []string{"registry.example.com"},
+								DeniedRegistries:  []string{"registry.example.com/untrusted"},
+							},
+						},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, policy)).To(Succeed())
+		})
+
+		processPodWithImages := func(image, initImage string) *kubetemplateriov1alpha1.KubeTemplate {
+			kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default"},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"Pod","metadata":{"name":"app"},"spec":{` +
+							`"initContainers":[{"name":"init","image":"` + initImage + `"}],` +
+							`"containers":[{"name":"app","image":"` + image + `"}]}}`)}},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, kubeTemplate)).To(Succeed())
+
+			item := &queue.WorkItem{NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-template"}}
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+			var kt kubetemplateriov1alpha1.KubeTemplate
+			Expect(fakeClient.Get(ctx, item.NamespacedName, &kt)).To(Succeed())
+			return &kt
+		}
+
+		It("should apply a pod whose images are all from allowed registries", func() {
+			kt := processPodWithImages("registry.example.com/team/app:1.0", "registry.example.com/tools/init:1.0")
+			Expect(kt.Status.ProcessingPhase).To(Equal("Completed"))
+		})
+
+		It("should reject a pod with an init container image from a denied registry", func() {
+			kt := processPodWithImages("registry.example.com/team/app:1.0", "registry.example.com/untrusted/init:1.0")
+			Expect(kt.Status.ProcessingPhase).To(Equal("Failed"))
+			Expect(kt.Status.Status).To(ContainSubstring("Pod app violates the image policy: image registry.example.com/untrusted/init:1.0 " +
+				"in spec.initContainers[0] is from the denied registry registry.example.com/untrusted"))
+
+			var pod corev1.Pod
+			Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "app"}, &pod)).NotTo(Succeed())
+		})
+
+		It("should reject a pod with an image from a registry that is not allowed", func() {
+			kt := processPodWithImages("nginx:1.27", "registry.example.com/tools/init:1.0")
+			Expect(kt.Status.ProcessingPhase).To(Equal("Failed"))
+			Expect(kt.Status.Status).To(ContainSubstring("image nginx:1.27 in spec.containers[0] is not from an allowed registry (registry.example.com)"))
+		})
+	})
+
+	Context("When templates are removed from the spec", func() {
+		item := &queue.WorkItem{NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-template"}}
+
+		BeforeEach(func() {
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: operatorNamespace},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{Kind: "ConfigMap", Group: "", Version: "v1", TargetNamespaces: []string{"default"}},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, policy)).To(Succeed())
+		})
+
+		configMapTemplate := func(name string) kubetemplateriov1alpha1.Template {
+			return kubetemplateriov1alpha1.Template{
+				Object: runtime.RawExtension{Raw: []byte(fmt.Sprintf(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"%s"},"data":{"key":"value"}}`, name))},
+			}
+		}
+
+		// applyThenRemove applies both ConfigMaps, then removes the second one from the spec
+		applyThenRemove := func(prune bool, beforeRemoval func()) {
+			kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default", UID: "test-template-uid"},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Prune:     prune,
+					Templates: []kubetemplateriov1alpha1.Template{configMapTemplate("keep"), configMapTemplate("remove")},
+				},
+			}
+			Expect(fakeClient.Create(ctx, kubeTemplate)).To(Succeed())
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+			if beforeRemoval != nil {
+				beforeRemoval()
+			}
+
+			Expect(fakeClient.Get(ctx, item.NamespacedName, kubeTemplate)).To(Succeed())
+			kubeTemplate.Spec.Templates = kubeTemplate.Spec.Templates[:1]
+			Expect(fakeClient.Update(ctx, kubeTemplate)).To(Succeed())
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+		}
+
+		configMapExists := func(name string) bool {
+			err := fakeClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: name}, &corev1.ConfigMap{})
+			if errors.IsNotFound(err) {
+				return false
+			}
+			Expect(err).NotTo(HaveOccurred())
+			return true
+		}
+
+		It("should delete the removed resource when pruning is enabled", func() {
+			applyThenRemove(true, nil)
+
+			Expect(configMapExists("keep")).To(BeTrue())
+			Expect(configMapExists("remove")).To(BeFalse())
+
+			var kt kubetemplateriov1alpha1.KubeTemplate
+			Expect(fakeClient.Get(ctx, item.NamespacedName, &kt)).To(Succeed())
+			Expect(kt.Status.ProcessingPhase).To(Equal("Completed"))
+			Expect(kt.Status.AppliedResources).To(HaveLen(1))
+			Expect(kt.Status.AppliedResources[0].Name).To(Equal("keep"))
+		})
+
+		It("should leave the removed resource in place without pruning", func() {
+			applyThenRemove(false, nil)
+
+			Expect(configMapExists("keep")).To(BeTrue())
+			Expect(configMapExists("remove")).To(BeTrue())
+		})
+
+		It("should not delete a removed resource another template has taken over", func() {
+			applyThenRemove(true, func() {
+				cm := &corev1.ConfigMap{}
+				Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "remove"}, cm)).To(Succeed())
+				cm.Labels[manifest.TemplateNameLabel] = "other-template"
+				Expect(fakeClient.Update(ctx, cm)).To(Succeed())
+			})
+
+			Expect(configMapExists("remove")).To(BeTrue())
+		})
+
+		It("should delete a removed resource that only carries the owner reference", func() {
+			applyThenRemove(true, func() {
+				var kt kubetemplateriov1alpha1.KubeTemplate
+				Expect(fakeClient.Get(ctx, item.NamespacedName, &kt)).To(Succeed())
+
+				cm := &corev1.ConfigMap{}
+				Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "remove"}, cm)).To(Succeed())
+				cm.Labels = nil
+				cm.OwnerReferences = []metav1.OwnerReference{{
+					APIVersion: "kubetemplater.io/v1alpha1", Kind: "KubeTemplate", Name: kt.Name, UID: kt.UID,
+				}}
+				Expect(fakeClient.Update(ctx, cm)).To(Succeed())
+			})
+
+			Expect(configMapExists("remove")).To(BeFalse())
+		})
+	})
+
+	Context("When a template is a dry-run", func() {
+		item := &queue.WorkItem{NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-template"}}
+
+		BeforeEach(func() {
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: operatorNamespace},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{Kind: "ConfigMap", Group: "", Version: "v1", TargetNamespaces: []string{"default"}},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, policy)).To(Succeed())
+		})
+
+		processDryRun := func(objects ...string) *kubetemplateriov1alpha1.KubeTemplate {
+			var templates []kubetemplateriov1alpha1.Template
+			for _, object := range objects {
+				templates = append(templates, kubetemplateriov1alpha1.Template{Object: runtime.RawExtension{Raw: []byte(object)}})
+			}
+			Expect(fakeClient.Create(ctx, &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default"},
+				Spec:       kubetemplateriov1alpha1.KubeTemplateSpec{DryRun: true, Templates: templates},
+			})).To(Succeed())
+
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+			var kt kubetemplateriov1alpha1.KubeTemplate
+			Expect(fakeClient.Get(ctx, item.NamespacedName, &kt)).To(Succeed())
+			return &kt
+		}
+
+		It("should report the resources it would create without creating them", func() {
+			kt := processDryRun(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"app-config"},"data":{"key":"value"}}`)
+
+			Expect(kt.Status.ProcessingPhase).To(Equal("DryRunCompleted"))
+			Expect(kt.Status.Status).To(Equal("Dry run completed: 1 to create, 0 to update, 0 unchanged, 0 failed"))
+			Expect(kt.Status.DryRunResults).To(Equal([]kubetemplateriov1alpha1.DryRunResult{{
+				APIVersion: "v1",
+				Kind:       "ConfigMap",
+				Namespace:  "default",
+				Name:       "app-config",
+				Action:     kubetemplateriov1alpha1.ApplyActionCreated,
+			}}))
+			Expect(kt.Status.AppliedResources).To(BeEmpty())
+
+			err := fakeClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "app-config"}, &corev1.ConfigMap{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should report the fields it would change on existing resources", func() {
+			Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "app-config", Namespace: "default", Labels: map[string]string{
+					manifest.TemplateNameLabel:      "test-template",
+					manifest.TemplateNamespaceLabel: "default",
+				}},
+				Data: map[string]string{"key": "old"},
+			})).To(Succeed())
+
+			kt := processDryRun(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"app-config"},"data":{"key":"value"}}`)
+
+			Expect(kt.Status.ProcessingPhase).To(Equal("DryRunCompleted"))
+			Expect(kt.Status.DryRunResults).To(HaveLen(1))
+			Expect(kt.Status.DryRunResults[0].Action).To(Equal(kubetemplateriov1alpha1.ApplyActionUpdated))
+			Expect(kt.Status.DryRunResults[0].ChangedFields).To(Equal([]string{"data.key"}))
+
+			cm := &corev1.ConfigMap{}
+			Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "app-config"}, cm)).To(Succeed())
+			Expect(cm.Data).To(HaveKeyWithValue("key", "old"))
+		})
+
+		It("should still enforce the policy", func() {
+			kt := processDryRun(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"app-config","namespace":"other"}}`)
+
+			Expect(kt.Status.ProcessingPhase).To(Equal("Failed"))
+			Expect(kt.Status.Status).To(ContainSubstring("namespace other not allowed"))
+			Expect(kt.Status.DryRunResults).To(BeEmpty())
+		})
+	})
+
+	Context("When summarizing the status", func() {
+		var item *queue.WorkItem
+
+		BeforeEach(func() {
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: operatorNamespace},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{Kind: "ConfigMap", Version: "v1", TargetNamespaces: []string{"default"}},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, policy)).To(Succeed())
+
+			item = &queue.WorkItem{NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-template"}}
+		})
+
+		createTemplate := func(objects ...string) {
+			kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default"},
+			}
+			for _, object := range objects {
+				kubeTemplate.Spec.Templates = append(kubeTemplate.Spec.Templates, kubetemplateriov1alpha1.Template{
+					Object: runtime.RawExtension{Raw: []byte(object)},
+				})
+			}
+			Expect(fakeClient.Create(ctx, kubeTemplate)).To(Succeed())
+		}
+
+		statusSummary := func() string {
+			var kt kubetemplateriov1alpha1.KubeTemplate
+			Expect(fakeClient.Get(ctx, item.NamespacedName, &kt)).To(Succeed())
+			return kt.Status.Summary
+		}
+
+		It("should count the synced resources of a completed template", func() {
+			createTemplate(
+				`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"first"}}`,
+				`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"second"}}`,
+				`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"third"}}`,
+			)
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+
+			Expect(statusSummary()).To(Equal("3/3 synced, 0 drift"))
+		})
+
+		It("should give the reason of a failed template", func() {
+			createTemplate(
+				`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"first"}}`,
+				`{"apiVersion":"v1","kind":"Secret","metadata":{"name":"credentials"}}`,
+			)
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+
+			Expect(statusSummary()).To(Equal("FAILED: Resource /v1, Kind=Secret is not allowed by policy"))
+		})
+	})
+
+	Context("When templates set an apply order", func() {
+		var (
+			item    *queue.WorkItem
+			applied []string
+			// failing is the name of the object whose apply fails, if any
+			failing string
+		)
+
+		BeforeEach(func() {
+			applied, failing = nil, ""
+			processor.Client = interceptor.NewClient(fakeClient.(client.WithWatch), interceptor.Funcs{
+				Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+					if obj.GetName() == failing {
+						return errors.NewForbidden(corev1.Resource("configmaps"), obj.GetName(), fmt.Errorf("denied"))
+					}
+					applied = append(applied, obj.GetName())
+					return applyAsCreateOrUpdate(ctx, c, obj, patch, opts...)
+				},
+			})
+
+			Expect(fakeClient.Create(ctx, &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: operatorNamespace},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{Kind: "ConfigMap", Version: "v1", TargetNamespaces: []string{"default", "team-a"}},
+						{Kind: "Namespace", Version: "v1", TargetNamespaces: []string{"default"}},
+					},
+				},
+			})).To(Succeed())
+
+			item = &queue.WorkItem{NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-template"}}
+		})
+
+		createTemplate := func(orders map[string]int, names ...string) {
+			kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default"},
+			}
+			for _, name := range names {
+				kubeTemplate.Spec.Templates = append(kubeTemplate.Spec.Templates, kubetemplateriov1alpha1.Template{
+					Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"` + name + `"}}`)},
+					Order:  orders[name],
+				})
+			}
+			Expect(fakeClient.Create(ctx, kubeTemplate)).To(Succeed())
+		}
+
+		It("should apply lower orders first and keep list order among equal orders", func() {
+			createTemplate(map[string]int{"workload": 10, "namespace-config": -1}, "workload", "settings", "namespace-config", "credentials")
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+
+			Expect(applied).To(Equal([]string{"namespace-config", "settings", "credentials", "workload"}))
+		})
+
+		It("should apply a Namespace before a ConfigMap targeting it without an order", func() {
+			Expect(fakeClient.Create(ctx, &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default"},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"app-config","namespace":"team-a"}}`)}},
+						{Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"Namespace","metadata":{"name":"team-a"}}`)}},
+					},
+				},
+			})).To(Succeed())
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+
+			Expect(applied).To(Equal([]string{"team-a", "app-config"}))
+		})
+
+		It("should still fail the whole template when a later template fails to apply", func() {
+			failing = "workload"
+			createTemplate(map[string]int{"workload": 10}, "workload", "settings")
+			Expect(processor.processItem(ctx, item)).To(HaveOccurred())
+
+			Expect(applied).To(Equal([]string{"settings"}))
+			var kt kubetemplateriov1alpha1.KubeTemplate
+			Expect(fakeClient.Get(ctx, item.NamespacedName, &kt)).To(Succeed())
+			Expect(kt.Status.ProcessingPhase).To(Equal("Failed"))
+		})
+	})
+
+	Context("When a referenced template would create an ownership cycle", func() {
+		var (
+			item     *queue.WorkItem
+			recorder *record.FakeRecorder
+		)
+
+		BeforeEach(func() {
+			recorder = record.NewFakeRecorder(10)
+			processor.Recorder = recorder
+
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: operatorNamespace},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{Kind: "ConfigMap", Version: "v1", TargetNamespaces: []string{"default"}},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, policy)).To(Succeed())
+
+			// The ConfigMap owns a Secret, which could in turn own the KubeTemplate
+			Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "owner-config", Namespace: "default", UID: "config-uid"},
+			})).To(Succeed())
+			Expect(fakeClient.Create(ctx, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "middle", Namespace: "default", UID: "secret-uid",
+					OwnerReferences: []metav1.OwnerReference{{APIVersion: "v1", Kind: "ConfigMap", Name: "owner-config", UID: "config-uid"}}},
+			})).To(Succeed())
+
+			item = &queue.WorkItem{NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-template"}}
+		})
+
+		processTemplate := func(owner metav1.OwnerReference, name string) *corev1.ConfigMap {
+			kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default", UID: "template-uid",
+					OwnerReferences: []metav1.OwnerReference{owner}},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{{
+						Referenced: true,
+						Object:     runtime.RawExtension{Raw: []byte(fmt.Sprintf(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":%q}}`, name))},
+					}},
+				},
+			}
+			Expect(fakeClient.Create(ctx, kubeTemplate)).To(Succeed())
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+
+			configMap := &corev1.ConfigMap{}
+			Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: name}, configMap)).To(Succeed())
+			return configMap
+		}
+
+		ownsTemplate := func(configMap *corev1.ConfigMap) bool {
+			for _, ref := range configMap.OwnerReferences {
+				if ref.UID == "template-uid" {
+					return true
+				}
+			}
+			return false
+		}
+
+		cycleWarnings := func() []string {
+			var warnings []string
+			for len(recorder.Events) > 0 {
+				if event := <-recorder.Events; strings.Contains(event, "OwnershipCycle") {
+					warnings = append(warnings, event)
+				}
+			}
+			return warnings
+		}
+
+		It("should warn about an object that directly owns the KubeTemplate", func() {
+			configMap := processTemplate(metav1.OwnerReference{APIVersion: "v1", Kind: "ConfigMap", Name: "owner-config", UID: "config-uid"}, "owner-config")
+
+			Expect(ownsTemplate(configMap)).To(BeFalse())
+			warnings := cycleWarnings()
+			Expect(warnings).To(HaveLen(1))
+			Expect(warnings[0]).To(HavePrefix("Warning OwnershipCycle"))
+			Expect(warnings[0]).To(ContainSubstring("KubeTemplate/test-template -> ConfigMap/owner-config -> KubeTemplate/test-template"))
+		})
+
+		It("should warn about an object that owns the KubeTemplate through another owner", func() {
+			configMap := processTemplate(metav1.OwnerReference{APIVersion: "v1", Kind: "Secret", Name: "middle", UID: "secret-uid"}, "owner-config")
+
+			Expect(ownsTemplate(configMap)).To(BeFalse())
+			warnings := cycleWarnings()
+			Expect(warnings).To(HaveLen(1))
+			Expect(warnings[0]).To(ContainSubstring("KubeTemplate/test-template -> Secret/middle -> ConfigMap/owner-config -> KubeTemplate/test-template"))
+		})
+
+		It("should add the owner reference when there is no cycle", func() {
+			configMap := processTemplate(metav1.OwnerReference{APIVersion: "v1", Kind: "Secret", Name: "middle", UID: "secret-uid"}, "child-config")
+
+			Expect(ownsTemplate(configMap)).To(BeTrue())
+			Expect(cycleWarnings()).To(BeEmpty())
+		})
+	})
+
+	Context("When a template waits for its object to become ready", func() {
+		var (
+			item *queue.WorkItem
+			now  time.Time
+			// ready makes the ConfigMap report ready, as a controller owning data.phase would
+			ready bool
+		)
+
+		BeforeEach(func() {
+			now = time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC)
+			ready = false
+			processor.ReadyPollInterval = 10 * time.Second
+			processor.Now = func() time.Time { return now }
+			// Every check applies the template again, each recording events
+			processor.Recorder = record.NewFakeRecorder(100)
+			// The fake client's apply replaces the object, so data.phase is set again after it
+			processor.Client = interceptor.NewClient(fakeClient.(client.WithWatch), interceptor.Funcs{
+				Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+					if err := c.Patch(ctx, obj, patch, opts...); err != nil || !ready || obj.GetName() != "database" {
+						return err
+					}
+					configMap := &corev1.ConfigMap{}
+					Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), configMap)).To(Succeed())
+					configMap.Data["phase"] = "ready"
+					return c.Update(ctx, configMap)
+				},
+			})
+
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: operatorNamespace},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{Kind: "ConfigMap", Version: "v1", TargetNamespaces: []string{"default"}},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, policy)).To(Succeed())
+
+			kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default"},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{
+							Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"database"},"data":{"owner":"template"}}`)},
+							WaitForReady: &kubetemplateriov1alpha1.WaitForReady{
+								Condition: "has(object.data.phase) && object.data.phase == 'ready'",
+								Timeout:   &metav1.Duration{Duration: time.Minute},
+							},
+						},
+						{
+							Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"app"}}`)},
+							Order:  1,
+						},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, kubeTemplate)).To(Succeed())
+			item = &queue.WorkItem{NamespacedName: client.ObjectKeyFromObject(kubeTemplate)}
+		})
+
+		// recheck advances the clock by the poll interval and processes the deferred item
+		recheck := func() error {
+			now = now.Add(processor.ReadyPollInterval)
+			Expect(processor.Queue.Expedite(item.NamespacedName)).To(BeTrue())
+			var ok bool
+			item, ok = processor.Queue.Dequeue()
+			Expect(ok).To(BeTrue())
+			defer processor.Queue.Done(item)
+			return processor.processItem(ctx, item)
+		}
+
+		It("should defer the template instead of waiting in the worker", func() {
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+
+			var kt kubetemplateriov1alpha1.KubeTemplate
+			Expect(fakeClient.Get(ctx, item.NamespacedName, &kt)).To(Succeed())
+			Expect(kt.Status.ProcessingPhase).To(Equal("Queued"))
+			readyCondition := meta.FindStatusCondition(kt.Status.Conditions, kubetemplateriov1alpha1.ConditionReady)
+			Expect(readyCondition.Reason).To(Equal(kubetemplateriov1alpha1.ReasonWaitingForReady))
+			Expect(readyCondition.Message).To(Equal(`Waiting: resource /v1, Kind=ConfigMap/database is not ready until 2025-06-02T12:01:00Z: condition "has(object.data.phase) && object.data.phase == 'ready'" is false`))
+
+			scheduledAt, queued := processor.Queue.NextScheduled(item.NamespacedName)
+			Expect(queued).To(BeTrue())
+			Expect(time.Until(scheduledAt)).To(BeNumerically("~", 10*time.Second, time.Second))
+
+			// The templates ordered after the object are not applied yet
+			Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "app"}, &corev1.ConfigMap{})).To(Satisfy(errors.IsNotFound))
+		})
+
+		It("should mark the template Completed once the object is ready", func() {
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+			Expect(recheck()).To(Succeed())
+			ready = true
+			Expect(recheck()).To(Succeed())
+
+			var kt kubetemplateriov1alpha1.KubeTemplate
+			Expect(fakeClient.Get(ctx, item.NamespacedName, &kt)).To(Succeed())
+			Expect(kt.Status.ProcessingPhase).To(Equal("Completed"))
+			Expect(kt.Status.AppliedResources).To(HaveLen(2))
+			Expect(processor.Queue.Contains(item.NamespacedName)).To(BeFalse())
+		})
+
+		It("should mark the template Failed when the object doesn't become ready in time", func() {
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+			for range 5 {
+				Expect(recheck()).To(Succeed())
+			}
+			Expect(recheck()).To(MatchError(`did not become ready within 1m0s: condition "has(object.data.phase) && object.data.phase == 'ready'" is false`))
+			Expect(processor.Queue.Contains(item.NamespacedName)).To(BeFalse())
+			// A retry waits afresh
+			Expect(item.ReadyWait).To(BeNil())
+
+			var kt kubetemplateriov1alpha1.KubeTemplate
+			Expect(fakeClient.Get(ctx, item.NamespacedName, &kt)).To(Succeed())
+			Expect(kt.Status.ProcessingPhase).To(Equal("Failed"))
+			Expect(kt.Status.Status).To(Equal(`Error: resource /v1, Kind=ConfigMap/database did not become ready within 1m0s: condition "has(object.data.phase) && object.data.phase == 'ready'" is false`))
+			applied := meta.FindStatusCondition(kt.Status.Conditions, kubetemplateriov1alpha1.ConditionApplied)
+			Expect(applied.Reason).To(Equal(kubetemplateriov1alpha1.ReasonNotReady))
+
+			// The templates ordered after the object are not applied
+			Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "app"}, &corev1.ConfigMap{})).To(Satisfy(errors.IsNotFound))
+		})
+
+		It("should time out waiting for a later object while an earlier one is ready", func() {
+			var kt kubetemplateriov1alpha1.KubeTemplate
+			Expect(fakeClient.Get(ctx, item.NamespacedName, &kt)).To(Succeed())
+			kt.Spec.Templates[1].WaitForReady = &kubetemplateriov1alpha1.WaitForReady{
+				Condition: "object.metadata.name == 'never'",
+				Timeout:   &metav1.Duration{Duration: time.Minute},
+			}
+			Expect(fakeClient.Update(ctx, &kt)).To(Succeed())
+			ready = true
+
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+			for range 5 {
+				Expect(recheck()).To(Succeed())
+			}
+			Expect(recheck()).To(MatchError(`did not become ready within 1m0s: condition "object.metadata.name == 'never'" is false`))
+
+			Expect(fakeClient.Get(ctx, item.NamespacedName, &kt)).To(Succeed())
+			Expect(kt.Status.ProcessingPhase).To(Equal("Failed"))
+			Expect(kt.Status.Status).To(HavePrefix("Error: resource /v1, Kind=ConfigMap/app did not become ready"))
+		})
+
+		It("should keep the deadline when the template is enqueued again while waiting", func() {
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+			deadline := item.ReadyWait.Deadline
+
+			// A reconcile enqueues the template while the worker is checking it again
+			Expect(processor.Queue.Expedite(item.NamespacedName)).To(BeTrue())
+			deferred, _ := processor.Queue.Dequeue()
+			processor.Queue.Enqueue(item.NamespacedName, 0)
+			processor.Queue.Defer(deferred, 0)
+			processor.Queue.Done(deferred)
+
+			item, _ = processor.Queue.Dequeue()
+			defer processor.Queue.Done(item)
+			Expect(item.ReadyWait).NotTo(BeNil())
+			Expect(item.ReadyWait.Deadline).To(Equal(deadline))
+		})
+
+		It("should not let a condition count resources", func() {
+			var kt kubetemplateriov1alpha1.KubeTemplate
+			Expect(fakeClient.Get(ctx, item.NamespacedName, &kt)).To(Succeed())
+			kt.Spec.Templates[0].WaitForReady.Condition = "countResources('v1', 'ConfigMap', 'default') > 0"
+			Expect(fakeClient.Update(ctx, &kt)).To(Succeed())
+
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+
+			Expect(fakeClient.Get(ctx, item.NamespacedName, &kt)).To(Succeed())
+			readyCondition := meta.FindStatusCondition(kt.Status.Conditions, kubetemplateriov1alpha1.ConditionReady)
+			Expect(readyCondition.Message).To(ContainSubstring(`can't be evaluated: ERROR: <input>:1:15: undeclared reference to 'countResources'`))
+		})
+	})
+
+	Context("When applies are throttled", func() {
+		var (
+			item    *queue.WorkItem
+			now     time.Time
+			applied []time.Time
+		)
+
+		BeforeEach(func() {
+			now = time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC)
+			applied = nil
+			processor.Now = func() time.Time { return now }
+			processor.Sleep = func(ctx context.Context, d time.Duration) error {
+				now = now.Add(d)
+				return nil
+			}
+			processor.Client = interceptor.NewClient(fakeClient.(client.WithWatch), interceptor.Funcs{
+				Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+					if patch.Type() == types.ApplyPatchType {
+						applied = append(applied, now)
+					}
+					return c.Patch(ctx, obj, patch, opts...)
+				},
+			})
+			item = &queue.WorkItem{NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-template"}}
+		})
+
+		createTemplate := func(maxApplyRate int32, annotations map[string]string, objects int) {
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: operatorNamespace},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					MaxApplyRate:    maxApplyRate,
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{Kind: "ConfigMap", Version: "v1", TargetNamespaces: []string{"default"}},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, policy)).To(Succeed())
+
+			var templates []kubetemplateriov1alpha1.Template
+			for i := 0; i < objects; i++ {
+				templates = append(templates, kubetemplateriov1alpha1.Template{Object: runtime.RawExtension{
+					Raw: []byte(fmt.Sprintf(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"throttled-%d"}}`, i)),
+				}})
+			}
+			kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default", Annotations: annotations},
+				Spec:       kubetemplateriov1alpha1.KubeTemplateSpec{Templates: templates},
+			}
+			Expect(fakeClient.Create(ctx, kubeTemplate)).To(Succeed())
+		}
+
+		// intervals returns the time between consecutive applies
+		intervals := func() []time.Duration {
+			var between []time.Duration
+			for i := 1; i < len(applied); i++ {
+				between = append(between, applied[i].Sub(applied[i-1]))
+			}
+			return between
+		}
+
+		It("should apply at the rate of the policy", func() {
+			createTemplate(2, nil, 5)
+
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+
+			Expect(applied).To(HaveLen(5))
+			Expect(intervals()).To(HaveEach(500 * time.Millisecond))
+
+			var kt kubetemplateriov1alpha1.KubeTemplate
+			Expect(fakeClient.Get(ctx, item.NamespacedName, &kt)).To(Succeed())
+			Expect(kt.Status.ProcessingPhase).To(Equal("Completed"))
+		})
+
+		It("should let the annotation lower the rate of the policy", func() {
+			createTemplate(2, map[string]string{ApplyRateAnnotation: "1"}, 3)
+
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+			Expect(intervals()).To(Equal([]time.Duration{time.Second, time.Second}))
+		})
+
+		It("should not let the annotation raise the rate of the policy", func() {
+			createTemplate(2, map[string]string{ApplyRateAnnotation: "10"}, 3)
+
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+			Expect(intervals()).To(HaveEach(500 * time.Millisecond))
+		})
+
+		It("should apply without waiting when no rate is set", func() {
+			createTemplate(0, nil, 3)
+
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+			Expect(applied).To(HaveLen(3))
+			Expect(intervals()).To(HaveEach(time.Duration(0)))
+		})
+	})
+
+	Context("When a policy rule selects target namespaces by label", func() {
+		BeforeEach(func() {
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: operatorNamespace},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{
+							Kind:                    "ConfigMap",
+							Version:                 "v1",
+							TargetNamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "payments"}},
+						},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, policy)).To(Succeed())
+			Expect(fakeClient.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "payments-eu", Labels: map[string]string{"team": "payments"}}})).To(Succeed())
+			Expect(fakeClient.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "search"}})).To(Succeed())
+		})
+
+		processConfigMapIn := func(namespace string) (*kubetemplateriov1alpha1.KubeTemplate, error) {
+			kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default"},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{Object: runtime.RawExtension{Raw: []byte(fmt.Sprintf(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"app-config","namespace":%q}}`, namespace))}},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, kubeTemplate)).To(Succeed())
+
+			item := &queue.WorkItem{NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-template"}}
+			err := processor.processItem(ctx, item)
+			var kt kubetemplateriov1alpha1.KubeTemplate
+			Expect(fakeClient.Get(ctx, item.NamespacedName, &kt)).To(Succeed())
+			return &kt, err
+		}
+
+		It("should apply to a namespace matching the selector", func() {
+			kt, err := processConfigMapIn("payments-eu")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(kt.Status.ProcessingPhase).To(Equal("Completed"))
+
+			var cm corev1.ConfigMap
+			Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: "payments-eu", Name: "app-config"}, &cm)).To(Succeed())
+		})
+
+		It("should reject a namespace not matching the selector", func() {
+			kt, err := processConfigMapIn("search")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(kt.Status.ProcessingPhase).To(Equal("Failed"))
+			Expect(kt.Status.Status).To(ContainSubstring("namespace search not allowed for /v1, Kind=ConfigMap"))
+		})
+
+		It("should retry while the target namespace doesn't exist yet", func() {
+			kt, err := processConfigMapIn("payments-us")
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+			Expect(kt.Status.ProcessingPhase).To(Equal("Failed"))
+			Expect(kt.Status.Status).To(ContainSubstring("Failed to match namespace payments-us"))
+		})
+	})
+
+	Context("When a template sets an apply timeout", func() {
+		var item *queue.WorkItem
+
+		BeforeEach(func() {
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: operatorNamespace},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{Kind: "ConfigMap", Version: "v1", TargetNamespaces: []string{"default"}},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, policy)).To(Succeed())
+
+			kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-template",
+					Namespace:   "default",
+					Annotations: map[string]string{ApplyTimeoutAnnotation: "20ms"},
+				},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"slow"}}`)}},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, kubeTemplate)).To(Succeed())
+			item = &queue.WorkItem{NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-template"}}
+		})
+
+		It("should fail the template as timed out and leave the retry to the queue", func() {
+			processor.Client = interceptor.NewClient(fakeClient.(client.WithWatch), interceptor.Funcs{
+				Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+					<-ctx.Done()
+					return ctx.Err()
+				},
+			})
+
+			err := processor.processItem(ctx, item)
+			Expect(err).To(HaveOccurred())
+			Expect(isApplyTimeout(err)).To(BeTrue())
+
+			var kt kubetemplateriov1alpha1.KubeTemplate
+			Expect(fakeClient.Get(ctx, item.NamespacedName, &kt)).To(Succeed())
+			Expect(kt.Status.ProcessingPhase).To(Equal("Failed"))
+			Expect(kt.Status.Status).To(ContainSubstring("Failed to apply /v1, Kind=ConfigMap/slow: apply timed out after 20ms"))
+			applied := meta.FindStatusCondition(kt.Status.Conditions, kubetemplateriov1alpha1.ConditionApplied)
+			Expect(applied).NotTo(BeNil())
+			Expect(applied.Reason).To(Equal(kubetemplateriov1alpha1.ReasonApplyTimedOut))
+		})
+
+		It("should apply objects that finish within the timeout", func() {
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+
+			var kt kubetemplateriov1alpha1.KubeTemplate
+			Expect(fakeClient.Get(ctx, item.NamespacedName, &kt)).To(Succeed())
+			Expect(kt.Status.ProcessingPhase).To(Equal("Completed"))
+		})
+	})
+
+	Context("When the operator lacks RBAC for a target namespace", func() {
+		var item *queue.WorkItem
+
+		BeforeEach(func() {
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: operatorNamespace},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{Kind: "ConfigMap", Version: "v1", TargetNamespaces: []string{"default"}},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, policy)).To(Succeed())
+
+			kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default"},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"denied"}}`)}},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, kubeTemplate)).To(Succeed())
+			item = &queue.WorkItem{NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-template"}}
+		})
+
+		denyPatch := func(err error) {
+			processor.Client = interceptor.NewClient(fakeClient.(client.WithWatch), interceptor.Funcs{
+				Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+					return err
+				},
+			})
+		}
+
+		rbacDenied := errors.NewForbidden(corev1.Resource("configmaps"), "denied", fmt.Errorf(
+			`User "system:serviceaccount:kubetemplater-system:kubetemplater" cannot patch resource "configmaps" in API group "" in the namespace "default"`))
+
+		It("should pause the template with the missing permission", func() {
+			processor.PauseOnRBACDenied = true
+			denyPatch(rbacDenied)
+
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+
+			var kt kubetemplateriov1alpha1.KubeTemplate
+			Expect(fakeClient.Get(ctx, item.NamespacedName, &kt)).To(Succeed())
+			Expect(kt.Status.ProcessingPhase).To(Equal("Paused"))
+			Expect(kt.Status.PausedReason).To(ContainSubstring("operator lacks RBAC for target namespace default"))
+			Expect(kt.Status.PausedReason).To(ContainSubstring(`cannot patch resource "configmaps"`))
+			Expect(kt.Status.PausedAt).NotTo(BeNil())
+
+			ready := meta.FindStatusCondition(kt.Status.Conditions, kubetemplateriov1alpha1.ConditionReady)
+			Expect(ready).NotTo(BeNil())
+			Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+			Expect(ready.Reason).To(Equal(kubetemplateriov1alpha1.ReasonPaused))
+			applied := meta.FindStatusCondition(kt.Status.Conditions, kubetemplateriov1alpha1.ConditionApplied)
+			Expect(applied).NotTo(BeNil())
+			Expect(applied.Reason).To(Equal(kubetemplateriov1alpha1.ReasonRBACDenied))
+		})
+
+		It("should keep retrying other Forbidden errors", func() {
+			processor.PauseOnRBACDenied = true
+			denyPatch(errors.NewForbidden(corev1.Resource("configmaps"), "denied",
+				fmt.Errorf("exceeded quota: compute-resources, requested: configmaps=1, used: configmaps=10, limited: configmaps=10")))
+
+			Expect(processor.processItem(ctx, item)).NotTo(Succeed())
+
+			var kt kubetemplateriov1alpha1.KubeTemplate
+			Expect(fakeClient.Get(ctx, item.NamespacedName, &kt)).To(Succeed())
+			Expect(kt.Status.ProcessingPhase).To(Equal("Failed"))
+			Expect(kt.Status.PausedReason).To(BeEmpty())
+		})
+
+		It("should keep retrying RBAC denials when pausing is disabled", func() {
+			processor.PauseOnRBACDenied = false
+			denyPatch(rbacDenied)
+
+			Expect(processor.processItem(ctx, item)).NotTo(Succeed())
+
+			var kt kubetemplateriov1alpha1.KubeTemplate
+			Expect(fakeClient.Get(ctx, item.NamespacedName, &kt)).To(Succeed())
+			Expect(kt.Status.ProcessingPhase).To(Equal("Failed"))
+		})
+	})
+
+	Context("When setting status conditions", func() {
+		var item *queue.WorkItem
+
+		BeforeEach(func() {
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: operatorNamespace},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{Kind: "ConfigMap", Version: "v1", TargetNamespaces: []string{"default"}},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, policy)).To(Succeed())
+
+			item = &queue.WorkItem{NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-template"}}
+		})
+
+		processTemplate := func(dryRun bool, object string) (kubetemplateriov1alpha1.KubeTemplate, error) {
+			kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default", Generation: 3},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					DryRun:    dryRun,
+					Templates: []kubetemplateriov1alpha1.Template{{Object: runtime.RawExtension{Raw: []byte(object)}}},
+				},
+			}
+			Expect(fakeClient.Create(ctx, kubeTemplate)).To(Succeed())
+			err := processor.processItem(ctx, item)
+
+			var kt kubetemplateriov1alpha1.KubeTemplate
+			Expect(fakeClient.Get(ctx, item.NamespacedName, &kt)).To(Succeed())
+			return kt, err
+		}
+
+		condition := func(kt kubetemplateriov1alpha1.KubeTemplate, conditionType string) metav1.Condition {
+			c := meta.FindStatusCondition(kt.Status.Conditions, conditionType)
+			Expect(c).NotTo(BeNil(), "condition %s", conditionType)
+			return *c
+		}
+
+		It("should mark a completed template as Ready", func() {
+			kt, err := processTemplate(false, `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"ready"}}`)
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, conditionType := range []string{
+				kubetemplateriov1alpha1.ConditionReady,
+				kubetemplateriov1alpha1.ConditionApplied,
+				kubetemplateriov1alpha1.ConditionPolicyValidated,
+			} {
+				c := condition(kt, conditionType)
+				Expect(c.Status).To(Equal(metav1.ConditionTrue), "condition %s", conditionType)
+				Expect(c.ObservedGeneration).To(Equal(int64(3)), "condition %s", conditionType)
+			}
+			Expect(condition(kt, kubetemplateriov1alpha1.ConditionReady).Reason).To(Equal(kubetemplateriov1alpha1.ReasonCompleted))
+			Expect(kt.Status.Status).To(Equal("Completed"))
+		})
+
+		It("should report a policy rejection on PolicyValidated and Ready", func() {
+			kt, err := processTemplate(false, `{"apiVersion":"v1","kind":"Secret","metadata":{"name":"credentials"}}`)
+			Expect(err).NotTo(HaveOccurred())
+
+			validated := condition(kt, kubetemplateriov1alpha1.ConditionPolicyValidated)
+			Expect(validated.Status).To(Equal(metav1.ConditionFalse))
+			Expect(validated.Reason).To(Equal(kubetemplateriov1alpha1.ReasonRejected))
+			Expect(validated.Message).To(Equal("Error: Resource /v1, Kind=Secret is not allowed by policy"))
+
+			ready := condition(kt, kubetemplateriov1alpha1.ConditionReady)
+			Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+			Expect(ready.Reason).To(Equal(kubetemplateriov1alpha1.ReasonRejected))
+			Expect(kt.Status.Status).To(Equal(ready.Message))
+		})
+
+		It("should report a failed apply on Applied and Ready", func() {
+			processor.Client = interceptor.NewClient(fakeClient.(client.WithWatch), interceptor.Funcs{
+				Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+					return errors.NewForbidden(corev1.Resource("configmaps"), obj.GetName(), fmt.Errorf("denied"))
+				},
+			})
+
+			kt, err := processTemplate(false, `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"forbidden"}}`)
+			Expect(err).To(HaveOccurred())
+
+			applied := condition(kt, kubetemplateriov1alpha1.ConditionApplied)
+			Expect(applied.Status).To(Equal(metav1.ConditionFalse))
+			Expect(applied.Reason).To(Equal(kubetemplateriov1alpha1.ReasonApplyFailed))
+			Expect(condition(kt, kubetemplateriov1alpha1.ConditionReady).Status).To(Equal(metav1.ConditionFalse))
+			Expect(kt.Status.Status).To(Equal(applied.Message))
+		})
+
+		It("should mark a dry-run template as Ready without applying it", func() {
+			kt, err := processTemplate(true, `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"preview"}}`)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(condition(kt, kubetemplateriov1alpha1.ConditionReady).Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition(kt, kubetemplateriov1alpha1.ConditionReady).Reason).To(Equal(kubetemplateriov1alpha1.ReasonDryRunCompleted))
+			applied := condition(kt, kubetemplateriov1alpha1.ConditionApplied)
+			Expect(applied.Status).To(Equal(metav1.ConditionFalse))
+			Expect(applied.Reason).To(Equal(kubetemplateriov1alpha1.ReasonDryRun))
+			Expect(kt.Status.Status).To(HavePrefix("Dry run completed: "))
+		})
+	})
+
+	Context("When a wildcard rule and an exact rule match the resource", func() {
+		var item *queue.WorkItem
+
+		BeforeEach(func() {
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: operatorNamespace},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{Kind: "*", Version: "v1", TargetNamespaces: []string{"default"}},
+						{Kinds: []string{"Secret"}, Version: "v1", TargetNamespaces: []string{"secrets"}},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, policy)).To(Succeed())
+
+			item = &queue.WorkItem{NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-template"}}
+		})
+
+		processTemplate := func(object string) kubetemplateriov1alpha1.KubeTemplate {
+			kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default"},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{Object: runtime.RawExtension{Raw: []byte(object)}},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, kubeTemplate)).To(Succeed())
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+
+			var kt kubetemplateriov1alpha1.KubeTemplate
+			Expect(fakeClient.Get(ctx, item.NamespacedName, &kt)).To(Succeed())
+			return kt
+		}
+
+		It("should apply kinds only the wildcard rule matches", func() {
+			kt := processTemplate(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"settings"}}`)
+			Expect(kt.Status.ProcessingPhase).To(Equal("Completed"))
+		})
+
+		It("should use the target namespaces of the exact rule", func() {
+			kt := processTemplate(`{"apiVersion":"v1","kind":"Secret","metadata":{"name":"credentials"}}`)
+			Expect(kt.Status.ProcessingPhase).To(Equal("Failed"))
+			Expect(kt.Status.Status).To(ContainSubstring("namespace default not allowed for /v1, Kind=Secret"))
+		})
+	})
+
+	Context("When a template object sets status", func() {
+		var (
+			item          *queue.WorkItem
+			policy        *kubetemplateriov1alpha1.KubeTemplatePolicy
+			appliedStatus []bool
+			statusPatches []*unstructured.Unstructured
+		)
+
+		BeforeEach(func() {
+			appliedStatus = nil
+			statusPatches = nil
+			fakeClient = fake.NewClientBuilder().
+				WithScheme(fakeClient.Scheme()).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+						if u, ok := obj.(*unstructured.Unstructured); ok {
+							appliedStatus = append(appliedStatus, manifest.HasStatus(u))
+						}
+						return applyAsCreateOrUpdate(ctx, c, obj, patch, opts...)
+					},
+					SubResourcePatch: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+						Expect(subResourceName).To(Equal("status"))
+						statusPatches = append(statusPatches, obj.(*unstructured.Unstructured).DeepCopy())
+						return nil
+					},
+				}).
+				WithStatusSubresource(&kubetemplateriov1alpha1.KubeTemplate{}).
+				WithIndex(&kubetemplateriov1alpha1.KubeTemplatePolicy{}, "spec.sourceNamespace", func(obj client.Object) []string {
+					return []string{obj.(*kubetemplateriov1alpha1.KubeTemplatePolicy).Spec.SourceNamespace}
+				}).
+				Build()
+			processor.Client = fakeClient
+			processor.Cache = cache.NewPolicyCache(fakeClient, 0)
+
+			policy = &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: operatorNamespace},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{Kind: "Service", Version: "v1", TargetNamespaces: []string{"default"}},
+					},
+				},
+			}
+			item = &queue.WorkItem{NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-template"}}
+		})
+
+		processTemplate := func(applyStatus bool) kubetemplateriov1alpha1.KubeTemplate {
+			Expect(fakeClient.Create(ctx, policy)).To(Succeed())
+			kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default"},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{
+							Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"Service","metadata":{"name":"web"},` +
+								`"spec":{"ports":[{"port":80}]},"status":{"loadBalancer":{"ingress":[{"ip":"10.0.0.1"}]}}}`)},
+							ApplyStatus: applyStatus,
+						},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, kubeTemplate)).To(Succeed())
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+
+			var kt kubetemplateriov1alpha1.KubeTemplate
+			Expect(fakeClient.Get(ctx, item.NamespacedName, &kt)).To(Succeed())
+			return kt
+		}
+
+		It("should strip the status before applying the object", func() {
+			kt := processTemplate(false)
+			Expect(kt.Status.ProcessingPhase).To(Equal("Completed"))
+			Expect(appliedStatus).To(Equal([]bool{false}))
+			Expect(statusPatches).To(BeEmpty())
+		})
+
+		It("should refuse the object when the policy rejects status", func() {
+			policy.Spec.RejectTemplateStatus = true
+			kt := processTemplate(false)
+			Expect(kt.Status.ProcessingPhase).To(Equal("Failed"))
+			Expect(kt.Status.Status).To(ContainSubstring("Service web sets status, remove it or set applyStatus: true on the template"))
+			Expect(appliedStatus).To(BeEmpty())
+		})
+
+		It("should apply the status through the status subresource when applyStatus is set", func() {
+			policy.Spec.RejectTemplateStatus = true
+			kt := processTemplate(true)
+			Expect(kt.Status.ProcessingPhase).To(Equal("Completed"))
+			Expect(statusPatches).To(HaveLen(1))
+			Expect(statusPatches[0].GetName()).To(Equal("web"))
+			ingress, _, _ := unstructured.NestedSlice(statusPatches[0].Object, "status", "loadBalancer", "ingress")
+			Expect(ingress).To(HaveLen(1))
+		})
+	})
+
+	Context("When a template object sets server-managed metadata", func() {
+		var (
+			item   *queue.WorkItem
+			policy *kubetemplateriov1alpha1.KubeTemplatePolicy
+		)
+
+		BeforeEach(func() {
+			policy = &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: operatorNamespace},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{Kind: "ConfigMap", Version: "v1", TargetNamespaces: []string{"default"}},
+					},
+				},
+			}
+			item = &queue.WorkItem{NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-template"}}
+		})
+
+		processTemplate := func() kubetemplateriov1alpha1.KubeTemplate {
+			Expect(fakeClient.Create(ctx, policy)).To(Succeed())
+			kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default"},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"pasted",` +
+							`"resourceVersion":"4711","uid":"0b1c","managedFields":[{"manager":"kubectl"}]},"data":{"key":"value"}}`)}},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, kubeTemplate)).To(Succeed())
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+
+			var kt kubetemplateriov1alpha1.KubeTemplate
+			Expect(fakeClient.Get(ctx, item.NamespacedName, &kt)).To(Succeed())
+			return kt
+		}
+
+		It("should strip the metadata before applying the object", func() {
+			kt := processTemplate()
+			Expect(kt.Status.ProcessingPhase).To(Equal("Completed"))
+
+			var cm corev1.ConfigMap
+			Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "pasted"}, &cm)).To(Succeed())
+			Expect(cm.UID).NotTo(Equal(types.UID("0b1c")))
+			Expect(cm.Data).To(HaveKeyWithValue("key", "value"))
+		})
+
+		It("should refuse the object when the policy rejects server-managed metadata", func() {
+			policy.Spec.RejectServerManagedMetadata = true
+			kt := processTemplate()
+			Expect(kt.Status.ProcessingPhase).To(Equal("Failed"))
+			Expect(kt.Status.Status).To(ContainSubstring("ConfigMap pasted sets server-managed metadata metadata.resourceVersion, metadata.uid, metadata.managedFields, remove it"))
+
+			var cm corev1.ConfigMap
+			Expect(errors.IsNotFound(fakeClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "pasted"}, &cm))).To(BeTrue())
+		})
+	})
+
+	Context("When a template uses parameters", func() {
+		var item *queue.WorkItem
+
+		BeforeEach(func() {
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: operatorNamespace},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{Kind: "ConfigMap", Version: "v1", TargetNamespaces: []string{"default"}},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, policy)).To(Succeed())
+			item = &queue.WorkItem{NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-template"}}
+		})
+
+		processTemplate := func(params map[string]string) kubetemplateriov1alpha1.KubeTemplate {
+			kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default"},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Parameters: params,
+					Templates: []kubetemplateriov1alpha1.Template{
+						{Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"${app}-config"},` +
+							`"data":{"env":"${env}","script":"echo $${HOME}"}}`)}},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, kubeTemplate)).To(Succeed())
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+
+			var kt kubetemplateriov1alpha1.KubeTemplate
+			Expect(fakeClient.Get(ctx, item.NamespacedName, &kt)).To(Succeed())
+			return kt
+		}
+
+		It("should apply the objects with the parameters substituted", func() {
+			kt := processTemplate(map[string]string{"app": "shop", "env": "prod"})
+			Expect(kt.Status.ProcessingPhase).To(Equal("Completed"))
+
+			var cm corev1.ConfigMap
+			Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "shop-config"}, &cm)).To(Succeed())
+			Expect(cm.Data).To(Equal(map[string]string{"env": "prod", "script": "echo ${HOME}"}))
+		})
+
+		It("should refuse an object with an undefined parameter", func() {
+			kt := processTemplate(map[string]string{"app": "shop"})
+			Expect(kt.Status.ProcessingPhase).To(Equal("Failed"))
+			Expect(kt.Status.Status).To(ContainSubstring("template[0]: undefined parameter env"))
+
+			var cm corev1.ConfigMap
+			Expect(errors.IsNotFound(fakeClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "shop-config"}, &cm))).To(BeTrue())
+		})
+	})
+
+	Context("When reporting the policy rules templates were validated against", func() {
+		It("should record the matching rule of each template in the status", func() {
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: operatorNamespace},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{Kind: "ConfigMap", Version: "v1", TargetNamespaces: []string{"default"}},
+						{Kind: "*", Version: "v1", TargetNamespaces: []string{"default"}},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, policy)).To(Succeed())
+			kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default"},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"Secret","metadata":{"name":"app-secret"}}`)}},
+						{Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"app-config"}}`)}},
+						{Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"app"}}`)}},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, kubeTemplate)).To(Succeed())
+
+			item := &queue.WorkItem{NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-template"}}
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+
+			var kt kubetemplateriov1alpha1.KubeTemplate
+			Expect(fakeClient.Get(ctx, item.NamespacedName, &kt)).To(Succeed())
+			Expect(kt.Status.ProcessingPhase).To(Equal("Failed"))
+			Expect(kt.Status.PolicyMatches).To(Equal([]kubetemplateriov1alpha1.PolicyMatch{
+				{Template: 0, APIVersion: "v1", Kind: "Secret", Namespace: "default", Name: "app-secret", Policy: "test-policy", Rule: 1, Wildcard: true},
+				{Template: 1, APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "app-config", Policy: "test-policy", Rule: 0},
+			}))
+		})
+	})
+
+	Context("When recording events", func() {
+		var (
+			item     *queue.WorkItem
+			recorder *record.FakeRecorder
+		)
+
+		BeforeEach(func() {
+			recorder = record.NewFakeRecorder(100)
+			processor.Recorder = recorder
+
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: operatorNamespace},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{Kind: "ConfigMap", Version: "v1", TargetNamespaces: []string{"default"}, Rule: "object.metadata.name != 'forbidden'"},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, policy)).To(Succeed())
+			item = &queue.WorkItem{NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-template"}}
+		})
+
+		processTemplate := func(objects ...string) []string {
+			kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default"},
+			}
+			for _, object := range objects {
+				kubeTemplate.Spec.Templates = append(kubeTemplate.Spec.Templates, kubetemplateriov1alpha1.Template{
+					Object: runtime.RawExtension{Raw: []byte(object)},
+				})
+			}
+			Expect(fakeClient.Create(ctx, kubeTemplate)).To(Succeed())
+			_ = processor.processItem(ctx, item)
+
+			var events []string
+			for len(recorder.Events) > 0 {
+				events = append(events, <-recorder.Events)
+			}
+			return events
+		}
+
+		It("should record every applied resource and the completion", func() {
+			events := processTemplate(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"app-config"}}`)
+			Expect(events).To(Equal([]string{
+				"Normal ResourceApplied Created v1 ConfigMap default/app-config",
+				"Normal Completed Applied 1 resources",
+			}))
+		})
+
+		It("should warn about policy rejections and CEL failures", func() {
+			events := processTemplate(
+				`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"app"}}`,
+				`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"forbidden"}}`,
+			)
+			Expect(events).To(ConsistOf(
+				"Warning PolicyRejected Refused apps/v1 Deployment default/app: Resource apps/v1, Kind=Deployment is not allowed by policy",
+				"Warning CELValidationFailed Refused v1 ConfigMap default/forbidden: Resource /v1, Kind=ConfigMap failed CEL validation",
+			))
+		})
+
+		It("should tell a broken CEL rule apart from a violated one", func() {
+			var policy kubetemplateriov1alpha1.KubeTemplatePolicy
+			Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: operatorNamespace, Name: "test-policy"}, &policy)).To(Succeed())
+			policy.Spec.ValidationRules[0].Rule = "object.data.mode == 'safe'"
+			Expect(fakeClient.Update(ctx, &policy)).To(Succeed())
+
+			events := processTemplate(
+				`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"unsafe"},"data":{"mode":"fast"}}`,
+				`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"no-data"}}`,
+			)
+			Expect(events).To(ConsistOf(
+				"Warning CELValidationFailed Refused v1 ConfigMap default/unsafe: Resource /v1, Kind=ConfigMap failed CEL validation",
+				`Warning CELRuleError Refused v1 ConfigMap default/no-data: policy test-policy could not validate /v1, Kind=ConfigMap: policy error: CEL rule "object.data.mode == 'safe'" is broken: failed to evaluate CEL rule: no such key: data`,
+			))
+		})
+
+		It("should warn about apply errors", func() {
+			processor.Client = interceptor.NewClient(fakeClient.(client.WithWatch), interceptor.Funcs{
+				Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+					return fmt.Errorf("connection refused")
+				},
+			})
+
+			events := processTemplate(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"app-config"}}`)
+			Expect(events).To(Equal([]string{
+				"Warning ApplyFailed Failed to apply v1 ConfigMap default/app-config: connection refused",
+			}))
+		})
+	})
+
+	Context("When the policy propagates KubeTemplate labels", func() {
+		var item *queue.WorkItem
+
+		createPolicy := func(override bool) {
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: operatorNamespace},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace:        "default",
+					PropagateLabels:        []string{"team", "cost-center"},
+					OverrideTemplateLabels: override,
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{Kind: "ConfigMap", Version: "v1", TargetNamespaces: []string{"default"}},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, policy)).To(Succeed())
+		}
+
+		processTemplate := func() corev1.ConfigMap {
+			kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default", Labels: map[string]string{
+					"team": "payments", "cost-center": "cc-42", "unlisted": "x",
+				}},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"app-config","labels":{"team":"search"}}}`)}},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, kubeTemplate)).To(Succeed())
+			item = &queue.WorkItem{NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-template"}}
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+
+			var cm corev1.ConfigMap
+			Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "app-config"}, &cm)).To(Succeed())
+			return cm
+		}
+
+		It("should copy the listed labels onto applied resources", func() {
+			createPolicy(false)
+			cm := processTemplate()
+			Expect(cm.Labels).To(HaveKeyWithValue("cost-center", "cc-42"))
+			Expect(cm.Labels).To(HaveKeyWithValue(manifest.TemplateNameLabel, "test-template"))
+			Expect(cm.Labels).NotTo(HaveKey("unlisted"))
+		})
+
+		It("should keep the values template objects set", func() {
+			createPolicy(false)
+			cm := processTemplate()
+			Expect(cm.Labels).To(HaveKeyWithValue("team", "search"))
+		})
+
+		It("should replace the values template objects set when the policy overrides them", func() {
+			createPolicy(true)
+			cm := processTemplate()
+			Expect(cm.Labels).To(HaveKeyWithValue("team", "payments"))
+		})
+	})
+
+	Context("When a queued template is paused by annotation", func() {
+		BeforeEach(func() {
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: operatorNamespace},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{Kind: "ConfigMap", Version: "v1", TargetNamespaces: []string{"default"}},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, policy)).To(Succeed())
+
+			kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-template",
+					Namespace:   "default",
+					Annotations: map[string]string{pause.Annotation: "true"},
+				},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"app"}}`)}},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, kubeTemplate)).To(Succeed())
+		})
+
+		It("should skip the template without applying its resources", func() {
+			item := &queue.WorkItem{NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-template"}}
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+
+			var kt kubetemplateriov1alpha1.KubeTemplate
+			Expect(fakeClient.Get(ctx, item.NamespacedName, &kt)).To(Succeed())
+			Expect(kt.Status.ProcessingPhase).To(BeEmpty())
+
+			err := fakeClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "app"}, &corev1.ConfigMap{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+
+	Context("When a failed template is retried", func() {
+		var item *queue.WorkItem
+
+		BeforeEach(func() {
+			// Without a policy the template fails every time it is processed
+			kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default"},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"app"}}`)}},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, kubeTemplate)).To(Succeed())
+
+			processor.Queue.InitialRetryDelay = 10 * time.Second
+			processor.Queue.Enqueue(types.NamespacedName{Namespace: "default", Name: "test-template"}, 0)
+			var ok bool
+			item, ok = processor.Queue.Dequeue()
+			Expect(ok).To(BeTrue())
+		})
+
+		// failAndRequeue processes the item, which fails, and puts it back the way Start does
+		failAndRequeue := func() kubetemplateriov1alpha1.KubeTemplateStatus {
+			err := processor.processItem(ctx, item)
+			Expect(err).To(HaveOccurred())
+			processor.requeue(ctx, item, err)
+
+			var kt kubetemplateriov1alpha1.KubeTemplate
+			Expect(fakeClient.Get(ctx, item.NamespacedName, &kt)).To(Succeed())
+			return kt.Status
+		}
+
+		It("should report when the queue retries the template", func() {
+			requeuedAt := time.Now()
+			status := failAndRequeue()
+
+			scheduledAt, queued := processor.Queue.NextScheduled(item.NamespacedName)
+			Expect(queued).To(BeTrue())
+			Expect(scheduledAt).To(BeTemporally("~", requeuedAt.Add(10*time.Second), time.Second))
+			Expect(status.ProcessingPhase).To(Equal("Failed"))
+			Expect(status.NextRetryAt).NotTo(BeNil())
+			Expect(status.NextRetryAt.Time).To(BeTemporally("~", scheduledAt, time.Second))
+		})
+
+		It("should follow the exponential backoff of later retries", func() {
+			failAndRequeue()
+			Expect(processor.Queue.Expedite(item.NamespacedName)).To(BeTrue())
+			item, _ = processor.Queue.Dequeue()
+
+			requeuedAt := time.Now()
+			status := failAndRequeue()
+			Expect(status.NextRetryAt.Time).To(BeTemporally("~", requeuedAt.Add(20*time.Second), time.Second))
+		})
+
+		It("should clear the retry time once the template is processed again", func() {
+			failAndRequeue()
+			Expect(processor.processItem(ctx, item)).NotTo(Succeed())
+
+			var kt kubetemplateriov1alpha1.KubeTemplate
+			Expect(fakeClient.Get(ctx, item.NamespacedName, &kt)).To(Succeed())
+			Expect(kt.Status.NextRetryAt).To(BeNil())
+		})
+	})
+
+	Context("When reporting processing metrics", func() {
+		processingSamples := func(workerID, result string) uint64 {
+			m := &dto.Metric{}
+			Expect(metrics.ItemProcessingDuration.WithLabelValues(workerID, result).(prometheus.Histogram).Write(m)).To(Succeed())
+			return m.GetHistogram().GetSampleCount()
+		}
+
+		It("should observe the processing duration by worker and result", func() {
+			processor.WorkerID = 7
+			before := processingSamples("7", metrics.ProcessingResultSuccess)
+
+			item := &queue.WorkItem{NamespacedName: types.NamespacedName{Namespace: "default", Name: "missing"}}
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+
+			Expect(processingSamples("7", metrics.ProcessingResultSuccess)).To(Equal(before + 1))
+		})
+
+		It("should count templates paused after their last retry cycle", func() {
+			processor.WorkerID = 8
+			processor.Client = interceptor.NewClient(fakeClient.(client.WithWatch), interceptor.Funcs{
+				Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+					return fmt.Errorf("API server unavailable")
+				},
+			})
+			processor.Queue = queue.NewWorkQueueWithConfig(0, time.Millisecond, time.Millisecond, 1)
+			before := testutil.ToFloat64(metrics.ItemsPausedTotal.WithLabelValues("8"))
+			beforeFailures := processingSamples("8", metrics.ProcessingResultError)
+
+			workerCtx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			go processor.Start(workerCtx)
+			defer processor.Queue.Shutdown()
+			processor.Queue.Enqueue(types.NamespacedName{Namespace: "default", Name: "test-template"}, 0)
+
+			Eventually(func() float64 {
+				return testutil.ToFloat64(metrics.ItemsPausedTotal.WithLabelValues("8"))
+			}).Should(Equal(before + 1))
+			Expect(processingSamples("8", metrics.ProcessingResultError)).To(Equal(beforeFailures + 2))
+		})
+	})
+
+	Context("When the policy changes between admission and apply", func() {
+		var (
+			policy *kubetemplateriov1alpha1.KubeTemplatePolicy
+			item   *queue.WorkItem
+		)
+
+		// createAdmittedTemplate creates a template as the mutating webhook would have admitted it
+		createAdmittedTemplate := func(admittedVersion string) {
+			kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-template",
+					Namespace:   "default",
+					Annotations: map[string]string{policyutil.AdmittedVersionAnnotation: admittedVersion},
+				},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"app-config"}}`)}},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, kubeTemplate)).To(Succeed())
+		}
+
+		BeforeEach(func() {
+			policy = &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: operatorNamespace},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{Kind: "ConfigMap", Version: "v1", TargetNamespaces: []string{"default"}},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, policy)).To(Succeed())
+
+			item = &queue.WorkItem{NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-template"}}
+		})
+
+		It("should report that the policy changed when the template no longer complies", func() {
+			admittedVersion := policy.ResourceVersion
+			createAdmittedTemplate(admittedVersion)
+
+			policy.Spec.ValidationRules[0].TargetNamespaces = []string{"other"}
+			Expect(fakeClient.Update(ctx, policy)).To(Succeed())
+
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+
+			var kt kubetemplateriov1alpha1.KubeTemplate
+			Expect(fakeClient.Get(ctx, item.NamespacedName, &kt)).To(Succeed())
+			Expect(kt.Status.ProcessingPhase).To(Equal("Failed"))
+			Expect(kt.Status.Status).To(Equal(fmt.Sprintf(
+				"Error: policy changed between admission and apply (policy test-policy admitted the template at resourceVersion %s, applied at %s): namespace default not allowed for /v1, Kind=ConfigMap",
+				admittedVersion, policy.ResourceVersion)))
+		})
+
+		It("should report a plain rejection when the policy is unchanged", func() {
+			policy.Spec.ValidationRules[0].TargetNamespaces = []string{"other"}
+			Expect(fakeClient.Update(ctx, policy)).To(Succeed())
+			createAdmittedTemplate(policy.ResourceVersion)
+
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+
+			var kt kubetemplateriov1alpha1.KubeTemplate
+			Expect(fakeClient.Get(ctx, item.NamespacedName, &kt)).To(Succeed())
+			Expect(kt.Status.Status).To(Equal("Error: namespace default not allowed for /v1, Kind=ConfigMap"))
+		})
+
+		It("should record the policy version a completed apply used", func() {
+			createAdmittedTemplate("1")
+			policy.Spec.ProtectUnmanagedResources = true
+			Expect(fakeClient.Update(ctx, policy)).To(Succeed())
+
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+
+			var kt kubetemplateriov1alpha1.KubeTemplate
+			Expect(fakeClient.Get(ctx, item.NamespacedName, &kt)).To(Succeed())
+			Expect(kt.Status.ProcessingPhase).To(Equal("Completed"))
+			Expect(kt.Status.AppliedPolicyVersion).To(Equal(policy.ResourceVersion))
+		})
+	})
+
+	Context("When a template sets its own field manager", func() {
+		var (
+			item          *queue.WorkItem
+			fieldManagers []string
+		)
+
+		createTemplate := func(fieldManager string) {
+			kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default"},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{
+							Object:       runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"app-config"},"data":{"key":"value"}}`)},
+							FieldManager: fieldManager,
+						},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, kubeTemplate)).To(Succeed())
+		}
+
+		BeforeEach(func() {
+			fieldManagers = nil
+			fakeClient = fake.NewClientBuilder().
+				WithScheme(fakeClient.Scheme()).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+						patchOpts := &client.PatchOptions{}
+						patchOpts.ApplyOptions(opts)
+						fieldManagers = append(fieldManagers, patchOpts.FieldManager)
+						return applyAsCreateOrUpdate(ctx, c, obj, patch, opts...)
+					},
+				}).
+				WithStatusSubresource(&kubetemplateriov1alpha1.KubeTemplate{}).
+				WithIndex(&kubetemplateriov1alpha1.KubeTemplatePolicy{}, "spec.sourceNamespace", func(obj client.Object) []string {
+					return []string{obj.(*kubetemplateriov1alpha1.KubeTemplatePolicy).Spec.SourceNamespace}
+				}).
+				Build()
+			processor.Client = fakeClient
+			processor.Cache = cache.NewPolicyCache(fakeClient, 0)
+
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: operatorNamespace},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace: "default",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{Kind: "ConfigMap", Version: "v1", TargetNamespaces: []string{"default"}},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, policy)).To(Succeed())
+
+			item = &queue.WorkItem{NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-template"}}
+		})
+
+		It("should apply with the template's field manager", func() {
+			createTemplate("argocd-controller")
+
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+			Expect(fieldManagers).To(Equal([]string{"argocd-controller"}))
+		})
+
+		It("should fall back to the default field manager", func() {
+			createTemplate("")
+
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+			Expect(fieldManagers).To(Equal([]string{manifest.DefaultFieldManager}))
+		})
+
+		It("should use the operator's field manager", func() {
+			processor.FieldManager = "platform"
+			createTemplate("")
+
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+			Expect(fieldManagers).To(Equal([]string{"platform"}))
+		})
+
+		It("should prefer the policy's field manager over the operator's", func() {
+			processor.FieldManager = "platform"
+			var policy kubetemplateriov1alpha1.KubeTemplatePolicy
+			Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: operatorNamespace, Name: "test-policy"}, &policy)).To(Succeed())
+			policy.Spec.FieldManager = "team-a"
+			Expect(fakeClient.Update(ctx, &policy)).To(Succeed())
+			createTemplate("")
+
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+			Expect(fieldManagers).To(Equal([]string{"team-a"}))
+		})
+
+		It("should fail the template when the field manager is too long", func() {
+			createTemplate(strings.Repeat("m", manifest.MaxFieldManagerLength+1))
+
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+			Expect(fieldManagers).To(BeEmpty())
+
+			var kt kubetemplateriov1alpha1.KubeTemplate
+			Expect(fakeClient.Get(ctx, item.NamespacedName, &kt)).To(Succeed())
+			Expect(kt.Status.ProcessingPhase).To(Equal("Failed"))
+			Expect(kt.Status.Status).To(ContainSubstring("fieldManager is 129 characters long, at most 128 are allowed"))
+		})
+	})
+
+	Context("When the policy names a ServiceAccount to impersonate", func() {
+		var (
+			item               *queue.WorkItem
+			impersonatedClient client.Client
+			impersonatedUsers  []string
+		)
+
+		BeforeEach(func() {
+			impersonatedClient = fake.NewClientBuilder().
+				WithScheme(fakeClient.Scheme()).
+				WithInterceptorFuncs(interceptor.Funcs{Patch: applyAsCreateOrUpdate}).
+				Build()
+			impersonatedUsers = nil
+
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: operatorNamespace},
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{
+					SourceNamespace:    "default",
+					ServiceAccountName: "tenant-a",
+					ValidationRules: []kubetemplateriov1alpha1.ValidationRule{
+						{
+							Kind:             "ConfigMap",
+							Group:            "",
+							Version:          "v1",
+							TargetNamespaces: []string{"default"},
+						},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, policy)).To(Succeed())
+
+			kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default"},
+				Spec: kubetemplateriov1alpha1.KubeTemplateSpec{
+					Templates: []kubetemplateriov1alpha1.Template{
+						{
+							Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"app-config"},"data":{"key":"value"}}`)},
+						},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, kubeTemplate)).To(Succeed())
+
+			item = &queue.WorkItem{NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-template"}}
+		})
+
+		It("should apply resources with the impersonated identity", func() {
+			processor.ImpersonatingClients = func(username string) (client.Client, error) {
+				impersonatedUsers = append(impersonatedUsers, username)
+				return impersonatedClient, nil
+			}
+
+			Expect(processor.processItem(ctx, item)).To(Succeed())
+			Expect(impersonatedUsers).To(Equal([]string{"system:serviceaccount:kubetemplater-system:tenant-a"}))
+
+			key := types.NamespacedName{Namespace: "default", Name: "app-config"}
+			Expect(impersonatedClient.Get(ctx, key, &corev1.ConfigMap{})).To(Succeed())
+			Expect(errors.IsNotFound(fakeClient.Get(ctx, key, &corev1.ConfigMap{}))).To(BeTrue())
+		})
+
+		It("should fail the template when impersonation is not configured", func() {
+			Expect(processor.processItem(ctx, item)).NotTo(Succeed())
+
+			var kt kubetemplateriov1alpha1.KubeTemplate
+			Expect(fakeClient.Get(ctx, item.NamespacedName, &kt)).To(Succeed())
+			Expect(kt.Status.ProcessingPhase).To(Equal("Failed"))
+			Expect(kt.Status.Status).To(ContainSubstring("impersonation is not configured"))
+		})
+	})
+})
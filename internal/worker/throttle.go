@@ -0,0 +1,82 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+)
+
+// ApplyRateAnnotation sets the number of objects per second applied from a KubeTemplate.
+// It can only lower the maxApplyRate of the template's policy.
+const ApplyRateAnnotation = "kubetemplater.io/apply-rate"
+
+// applyRate returns the number of objects per second to apply from kubeTemplate, the lower of
+// the policy's maxApplyRate and the template's annotation (0 = not throttled). An invalid
+// annotation is reported and ignored.
+func applyRate(kubeTemplate *kubetemplateriov1alpha1.KubeTemplate, policy *kubetemplateriov1alpha1.KubeTemplatePolicy) (int64, error) {
+	rate := int64(policy.Spec.MaxApplyRate)
+	value, ok := kubeTemplate.Annotations[ApplyRateAnnotation]
+	if !ok {
+		return rate, nil
+	}
+	annotated, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || annotated < 1 {
+		return rate, fmt.Errorf("invalid %s annotation %q: must be a positive number of objects per second", ApplyRateAnnotation, value)
+	}
+	if rate == 0 || annotated < rate {
+		rate = annotated
+	}
+	return rate, nil
+}
+
+// applyThrottle spaces the applies of a template evenly at a fixed rate
+type applyThrottle struct {
+	interval time.Duration
+	next     time.Time
+	now      func() time.Time
+	sleep    func(context.Context, time.Duration) error
+}
+
+// newApplyThrottle returns a throttle applying rate objects per second, or nil if rate is 0
+func (p *TemplateProcessor) newApplyThrottle(rate int64) *applyThrottle {
+	if rate <= 0 {
+		return nil
+	}
+	return &applyThrottle{interval: time.Second / time.Duration(rate), now: p.now, sleep: p.sleep}
+}
+
+// Wait blocks until the next apply is due, or ctx is done. The first apply is never delayed,
+// and a nil throttle never waits.
+func (t *applyThrottle) Wait(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+	now := t.now()
+	if wait := t.next.Sub(now); wait > 0 {
+		if err := t.sleep(ctx, wait); err != nil {
+			return err
+		}
+		now = t.next
+	}
+	t.next = now.Add(t.interval)
+	return nil
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"context"
+	"time"
+
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Apply throttling", func() {
+	Context("applyRate", func() {
+		rate := func(maxApplyRate int32, annotation string) (int64, error) {
+			kubeTemplate := &kubetemplateriov1alpha1.KubeTemplate{}
+			if annotation != "" {
+				kubeTemplate.ObjectMeta = metav1.ObjectMeta{Annotations: map[string]string{ApplyRateAnnotation: annotation}}
+			}
+			policy := &kubetemplateriov1alpha1.KubeTemplatePolicy{
+				Spec: kubetemplateriov1alpha1.KubeTemplatePolicySpec{MaxApplyRate: maxApplyRate},
+			}
+			return applyRate(kubeTemplate, policy)
+		}
+
+		It("should use the lower of the policy rate and the annotation", func() {
+			Expect(rate(0, "")).To(Equal(int64(0)))
+			Expect(rate(5, "")).To(Equal(int64(5)))
+			Expect(rate(0, "3")).To(Equal(int64(3)))
+			Expect(rate(5, "3")).To(Equal(int64(3)))
+			Expect(rate(5, "8")).To(Equal(int64(5)))
+		})
+
+		It("should ignore an invalid annotation", func() {
+			for _, annotation := range []string{"fast", "0", "-1", "1.5"} {
+				r, err := rate(5, annotation)
+				Expect(err).To(MatchError(ContainSubstring("invalid kubetemplater.io/apply-rate annotation")), "annotation %q", annotation)
+				Expect(r).To(Equal(int64(5)), "annotation %q", annotation)
+			}
+		})
+	})
+
+	Context("applyThrottle", func() {
+		It("should not delay the first apply and space the following ones", func() {
+			now := time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC)
+			var slept []time.Duration
+			processor := &TemplateProcessor{
+				Now: func() time.Time { return now },
+				Sleep: func(ctx context.Context, d time.Duration) error {
+					slept = append(slept, d)
+					now = now.Add(d)
+					return nil
+				},
+			}
+			throttle := processor.newApplyThrottle(4)
+
+			Expect(throttle.Wait(context.Background())).To(Succeed())
+			Expect(throttle.Wait(context.Background())).To(Succeed())
+			// Time spent between applies counts towards the interval
+			now = now.Add(100 * time.Millisecond)
+			Expect(throttle.Wait(context.Background())).To(Succeed())
+			// Applies that are already due don't wait
+			now = now.Add(time.Second)
+			Expect(throttle.Wait(context.Background())).To(Succeed())
+
+			Expect(slept).To(Equal([]time.Duration{250 * time.Millisecond, 150 * time.Millisecond}))
+		})
+
+		It("should stop waiting when the context is done", func() {
+			throttle := (&TemplateProcessor{}).newApplyThrottle(1)
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			Expect(throttle.Wait(ctx)).To(Succeed())
+			Expect(throttle.Wait(ctx)).To(MatchError(context.Canceled))
+		})
+
+		It("should never wait without a rate", func() {
+			throttle := (&TemplateProcessor{}).newApplyThrottle(0)
+			Expect(throttle).To(BeNil())
+			Expect(throttle.Wait(context.Background())).To(Succeed())
+		})
+	})
+})
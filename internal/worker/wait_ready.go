@@ -0,0 +1,155 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/checker/decls"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	kubetemplateriov1alpha1 "github.com/lpeano/KubeTemplater/api/kubetemplater.io/v1alpha1"
+	"github.com/lpeano/KubeTemplater/internal/queue"
+)
+
+const (
+	// DefaultReadyTimeout is how long the worker waits for a template's object to become ready
+	// when its waitForReady sets no timeout
+	DefaultReadyTimeout = 5 * time.Minute
+	// DefaultReadyPollInterval is how often the worker checks whether the object is ready
+	DefaultReadyPollInterval = 10 * time.Second
+
+	// readyConditionCostLimit and readyConditionTimeout bound the evaluation of a condition,
+	// as the webhook bounds policy rules
+	readyConditionCostLimit = 1000000
+	readyConditionTimeout   = 100 * time.Millisecond
+)
+
+// notReadyError is returned for an applied object that did not become ready within the timeout
+type notReadyError struct {
+	timeout time.Duration
+	reason  string
+}
+
+func (e *notReadyError) Error() string {
+	return fmt.Sprintf("did not become ready within %s: %s", e.timeout, e.reason)
+}
+
+// isNotReady reports whether err is an object not becoming ready in time
+func isNotReady(err error) bool {
+	var notReady *notReadyError
+	return errors.As(err, &notReady)
+}
+
+// awaitReady checks whether the live version of obj satisfies the condition of wait. While it
+// doesn't, item records the deadline for obj, counted from the first check that found it not
+// ready; awaitReady returns a notReadyError once the deadline has passed.
+func (p *TemplateProcessor) awaitReady(ctx context.Context, item *queue.WorkItem, obj *unstructured.Unstructured, wait *kubetemplateriov1alpha1.WaitForReady) (bool, string, error) {
+	timeout := DefaultReadyTimeout
+	if wait.Timeout != nil && wait.Timeout.Duration > 0 {
+		timeout = wait.Timeout.Duration
+	}
+
+	key := fmt.Sprintf("%s %s/%s", obj.GroupVersionKind().String(), obj.GetNamespace(), obj.GetName())
+	ready, reason, err := p.checkReady(ctx, obj, wait.Condition)
+	if err != nil || ready {
+		// Only the wait for this object is over; a wait for an object ordered later keeps its deadline
+		if item.ReadyWait != nil && item.ReadyWait.Object == key {
+			item.ReadyWait = nil
+		}
+		return ready, reason, err
+	}
+
+	now := p.now()
+	if item.ReadyWait == nil || item.ReadyWait.Object != key {
+		item.ReadyWait = &queue.ReadyWait{Object: key, Deadline: now.Add(timeout)}
+	}
+	if !now.Before(item.ReadyWait.Deadline) {
+		// A retry of the template waits afresh
+		item.ReadyWait = nil
+		return false, reason, &notReadyError{timeout: timeout, reason: reason}
+	}
+	return false, reason, nil
+}
+
+// readyCheckDelay returns when to check the object item waits for again: after the poll
+// interval, or at the deadline if that comes first
+func (p *TemplateProcessor) readyCheckDelay(item *queue.WorkItem) time.Duration {
+	interval := p.ReadyPollInterval
+	if interval <= 0 {
+		interval = DefaultReadyPollInterval
+	}
+	return min(interval, item.ReadyWait.Deadline.Sub(p.now()))
+}
+
+// checkReady evaluates condition against the live version of obj, and returns why it isn't
+// ready if it isn't
+func (p *TemplateProcessor) checkReady(ctx context.Context, obj *unstructured.Unstructured, condition string) (bool, string, error) {
+	live, err := p.lookupExisting(ctx, obj)
+	if err != nil {
+		return false, "", fmt.Errorf("failed to get %s %s to check readiness: %w", obj.GroupVersionKind().String(), obj.GetName(), err)
+	}
+	if live == nil {
+		return false, "the object does not exist", nil
+	}
+
+	ready, err := evaluateReadyCondition(ctx, condition, live)
+	if err != nil {
+		return false, fmt.Sprintf("condition %q can't be evaluated: %v", condition, err), nil
+	}
+	if !ready {
+		return false, fmt.Sprintf("condition %q is false", condition), nil
+	}
+	return true, "", nil
+}
+
+// evaluateReadyCondition evaluates condition with the live object as "object". Conditions are
+// written by template authors, not policy admins, so they get an environment of their own
+// without countResources, are bounded in cost and time, and are compiled on every check rather
+// than filling the program cache of policy rules.
+func evaluateReadyCondition(ctx context.Context, condition string, live *unstructured.Unstructured) (bool, error) {
+	env, err := cel.NewEnv(cel.Declarations(
+		decls.NewVar("object", decls.NewMapType(decls.String, decls.Dyn)),
+	))
+	if err != nil {
+		return false, fmt.Errorf("failed to create CEL environment: %w", err)
+	}
+	checked, issues := env.Compile(condition)
+	if issues != nil && issues.Err() != nil {
+		return false, issues.Err()
+	}
+	prg, err := env.Program(checked, cel.CostTracking(nil), cel.CostLimit(readyConditionCostLimit))
+	if err != nil {
+		return false, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readyConditionTimeout)
+	defer cancel()
+	out, _, err := prg.ContextEval(ctx, map[string]interface{}{"object": live.Object})
+	if err != nil {
+		return false, err
+	}
+	ready, isBool := out.Value().(bool)
+	if !isBool {
+		return false, fmt.Errorf("returned %s, not a bool", out.Type().TypeName())
+	}
+	return ready, nil
+}